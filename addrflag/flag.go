@@ -0,0 +1,139 @@
+// Package addrflag defines a flag.Value implementation for listen addresses
+// that name both a network and an address, suitable for net.Listen.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/addrflag"
+//	)
+//
+//	var listen = addrflag.MustParse(":8080")
+//	func init() {
+//	  flag.Var(&listen, "listen", listen.Help("Address to listen on"))
+//	}
+//
+//	  ...
+//	  lst, err := listen.Listen()
+//
+// The flag accepts the following forms:
+//
+//	tcp://host:port          -- also tcp4:// and tcp6://
+//	unix:///path/to/socket   -- the path is "/path/to/socket"
+//	unix://relative.sock     -- the path is "relative.sock"
+//	unixpacket://path
+//	host:port, :port         -- the default network, normally "tcp"
+//
+// The host of a TCP address may be empty, a name, or an IP address, with IPv6
+// addresses in brackets, e.g., "tcp://[::1]:8080".
+package addrflag
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// networks lists the networks accepted by default.
+var networks = []string{"tcp", "tcp4", "tcp6", "unix", "unixpacket"}
+
+// A Value represents a network and address. A pointer to a Value satisfies
+// the flag.Value and flag.Getter interfaces.
+type Value struct {
+	// If non-empty, the network must be one of these. Otherwise any of "tcp",
+	// "tcp4", "tcp6", "unix", and "unixpacket" is accepted.
+	Networks []string
+
+	// The network assumed when the address does not have a scheme.
+	// If empty, "tcp" is assumed.
+	DefaultNetwork string
+
+	network, address string
+}
+
+// MustParse returns a Value with the default settings for the address s, and
+// panics if s is invalid. It is intended for use in setting default values.
+func MustParse(s string) Value {
+	var v Value
+	if err := v.Set(s); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Help concatenates a human-readable string summarizing the legal values of v
+// to h, for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	return fmt.Sprintf("%s (%s://address, or host:port)", h, strings.Join(v.allowed(), "|"))
+}
+
+// Network returns the network name, e.g., "tcp" or "unix".
+func (v *Value) Network() string { return v.network }
+
+// Address returns the address within the network, e.g., ":8080" or
+// "/var/run/app.sock".
+func (v *Value) Address() string { return v.address }
+
+// Listen announces on the network and address of v, using net.Listen.
+func (v *Value) Listen() (net.Listener, error) { return net.Listen(v.network, v.address) }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string {
+	if v.network == "" {
+		return ""
+	}
+	return v.network + "://" + v.address
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	network, address, ok := strings.Cut(s, "://")
+	if !ok {
+		network, address = v.DefaultNetwork, s
+		if network == "" {
+			network = "tcp"
+		}
+	}
+	network = strings.ToLower(network)
+	if !slices.Contains(v.allowed(), network) {
+		return fmt.Errorf("addrflag: network %q not allowed, expected one of (%s)",
+			network, strings.Join(v.allowed(), "|"))
+	}
+	if err := checkAddress(network, address); err != nil {
+		return err
+	}
+	v.network, v.address = network, address
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is the string rendered by String.
+func (v *Value) Get() any { return v.String() }
+
+func (v *Value) allowed() []string {
+	if len(v.Networks) != 0 {
+		return v.Networks
+	}
+	return networks
+}
+
+func checkAddress(network, address string) error {
+	switch network {
+	case "unix", "unixpacket", "unixgram":
+		if address == "" {
+			return fmt.Errorf("addrflag: missing socket path")
+		}
+		return nil
+	}
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("addrflag: invalid address %q", address)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		return fmt.Errorf("addrflag: invalid port %q", port)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package addrflag
+
+import (
+	"bytes"
+	"flag"
+	"path/filepath"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	listen := MustParse(":8080")
+	admin := Value{Networks: []string{"unix"}, DefaultNetwork: "unix"}
+
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("addr", flag.ContinueOnError)
+	fs.Var(&listen, "listen", listen.Help("Address to listen on"))
+	fs.Var(&admin, "admin", admin.Help("Admin socket"))
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+	t.Logf("Address flag set:\n%s", buf.String())
+
+	if got, want := listen.String(), "tcp://:8080"; got != want {
+		t.Errorf("Initial value for -listen: got %q, want %q", got, want)
+	}
+	if err := fs.Parse([]string{"-listen", "tcp6://[::1]:9000", "-admin", "/run/app.sock"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if n, a := listen.Network(), listen.Address(); n != "tcp6" || a != "[::1]:9000" {
+		t.Errorf("Value for -listen: got (%q, %q), want (tcp6, [::1]:9000)", n, a)
+	}
+	if got, want := admin.Get().(string), "unix:///run/app.sock"; got != want {
+		t.Errorf("Value for -admin: got %q, want %q", got, want)
+	}
+	if err := fs.Parse([]string{"-admin", "tcp://:80"}); err == nil {
+		t.Error("Parse disallowed network: got nil, wanted error")
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		in, network, address string
+	}{
+		{":8080", "tcp", ":8080"},
+		{"localhost:0", "tcp", "localhost:0"},
+		{"TCP://0.0.0.0:80", "tcp", "0.0.0.0:80"},
+		{"tcp4://127.0.0.1:5000", "tcp4", "127.0.0.1:5000"},
+		{"unix:///var/run/app.sock", "unix", "/var/run/app.sock"},
+		{"unix://rel.sock", "unix", "rel.sock"},
+		{"unixpacket://@abstract", "unixpacket", "@abstract"},
+	}
+	for _, test := range tests {
+		var v Value
+		if err := v.Set(test.in); err != nil {
+			t.Errorf("Set(%q) failed: %v", test.in, err)
+		} else if v.Network() != test.network || v.Address() != test.address {
+			t.Errorf("Set(%q): got (%q, %q), want (%q, %q)",
+				test.in, v.Network(), v.Address(), test.network, test.address)
+		}
+	}
+
+	for _, bad := range []string{"", "8080", "tcp://", "tcp://host", "tcp://:99999", "udp://:53", "unix://", "http://x:80"} {
+		var v Value
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %q, wanted error", bad, v.String())
+		}
+	}
+}
+
+func TestListen(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1:0", "unix://" + filepath.Join(t.TempDir(), "test.sock")} {
+		v := MustParse(addr)
+		lst, err := v.Listen()
+		if err != nil {
+			t.Errorf("Listen(%q) failed: %v", addr, err)
+			continue
+		}
+		t.Logf("Listening on %v %v", lst.Addr().Network(), lst.Addr())
+		lst.Close()
+	}
+}
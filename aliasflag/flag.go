@@ -0,0 +1,112 @@
+// Package aliasflag registers a single flag.Value under several names.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/aliasflag"
+//	  "github.com/creachadair/goflags/enumflag"
+//	)
+//
+//	var color = enumflag.New("auto", "always", "never")
+//	func init() {
+//	  aliasflag.Var(flag.CommandLine, color, "color", color.Help("Use color"), "colour")
+//	}
+//
+// With this definition "-color never" and "-colour never" both set the same
+// value. The canonical name "color" carries the usage string; each alias is
+// documented as an alias of it. When an alias is used, a warning is printed
+// to the output of the flag set, unless the Warn field of the Group returned
+// by Var is changed.
+package aliasflag
+
+import (
+	"flag"
+	"fmt"
+)
+
+// A Group is a set of names registered for a single flag.Value.
+type Group struct {
+	Name    string   // the canonical name
+	Aliases []string // the other names
+
+	// If non-nil, Warn is called each time the flag is set by one of its
+	// aliases. Var sets this to a function that prints a deprecation warning
+	// to the output of the flag set; set it to nil to disable warnings.
+	Warn func(alias, name string)
+}
+
+// Var registers v in fs under name, with the given usage string, and under
+// each of the aliases. It panics if any of the names is already defined in
+// fs, as fs.Var does.
+func Var(fs *flag.FlagSet, v flag.Value, name, usage string, aliases ...string) *Group {
+	g := &Group{
+		Name:    name,
+		Aliases: aliases,
+		Warn: func(alias, name string) {
+			fmt.Fprintf(fs.Output(), "warning: flag -%s is deprecated, use -%s instead\n", alias, name)
+		},
+	}
+	fs.Var(v, name, usage)
+	for _, alias := range aliases {
+		fs.Var(&aliasValue{Value: v, g: g, alias: alias}, alias, "Deprecated alias for -"+name)
+	}
+	return g
+}
+
+// Canonical returns the canonical name of the flag named name in fs, which is
+// name itself unless it was registered as an alias by Var. It reports false
+// if fs has no flag with that name.
+func Canonical(fs *flag.FlagSet, name string) (string, bool) {
+	f := fs.Lookup(name)
+	if f == nil {
+		return "", false
+	}
+	if a, ok := f.Value.(*aliasValue); ok {
+		return a.g.Name, true
+	}
+	return name, true
+}
+
+// IsAlias reports whether f was registered as an alias by Var.
+func IsAlias(f *flag.Flag) bool {
+	_, ok := f.Value.(*aliasValue)
+	return ok
+}
+
+// aliasValue is the flag.Value registered for an alias.
+type aliasValue struct {
+	flag.Value
+	g     *Group
+	alias string
+}
+
+func (a *aliasValue) String() string {
+	if a == nil || a.Value == nil {
+		return "" // the zero value, as used by flag.PrintDefaults
+	}
+	return a.Value.String()
+}
+
+func (a *aliasValue) Set(s string) error {
+	if err := a.Value.Set(s); err != nil {
+		return err
+	}
+	if a.g.Warn != nil {
+		a.g.Warn(a.alias, a.g.Name)
+	}
+	return nil
+}
+
+func (a *aliasValue) Get() any {
+	if g, ok := a.Value.(flag.Getter); ok {
+		return g.Get()
+	}
+	return a.Value
+}
+
+func (a *aliasValue) IsBoolFlag() bool {
+	b, ok := a.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
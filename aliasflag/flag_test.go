@@ -0,0 +1,62 @@
+package aliasflag
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/goflags/countflag"
+	"github.com/creachadair/goflags/enumflag"
+)
+
+func TestFlagBits(t *testing.T) {
+	var buf bytes.Buffer
+	color := enumflag.New("auto", "always", "never")
+	var verbose countflag.Value
+
+	fs := flag.NewFlagSet("alias", flag.ContinueOnError)
+	fs.SetOutput(&buf)
+	Var(fs, color, "color", color.Help("Use color"), "colour")
+	g := Var(fs, &verbose, "verbose", "Verbosity", "v")
+
+	var warned []string
+	g.Warn = func(alias, name string) { warned = append(warned, alias+"->"+name) }
+
+	if err := fs.Parse([]string{"-colour", "never", "-v", "-verbose", "-v"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := color.Key(), "never"; got != want {
+		t.Errorf("Value for -color: got %q, want %q", got, want)
+	}
+	if got, want := verbose.Int(), 3; got != want {
+		t.Errorf("Value for -verbose: got %d, want %d", got, want)
+	}
+	if got, want := strings.Join(warned, " "), "v->verbose v->verbose"; got != want {
+		t.Errorf("Warnings: got %q, want %q", got, want)
+	}
+	if got, want := buf.String(), "warning: flag -colour is deprecated, use -color instead\n"; got != want {
+		t.Errorf("Default warning: got %q, want %q", got, want)
+	}
+
+	for _, test := range []struct {
+		name, want string
+	}{{"color", "color"}, {"colour", "color"}, {"v", "verbose"}, {"verbose", "verbose"}} {
+		if got, ok := Canonical(fs, test.name); !ok || got != test.want {
+			t.Errorf("Canonical(%q): got %q, %v; want %q, true", test.name, got, ok, test.want)
+		}
+	}
+	if _, ok := Canonical(fs, "nonesuch"); ok {
+		t.Error("Canonical(nonesuch): got true, want false")
+	}
+	if !IsAlias(fs.Lookup("colour")) || IsAlias(fs.Lookup("color")) {
+		t.Error("IsAlias reported the wrong flags")
+	}
+
+	buf.Reset()
+	fs.PrintDefaults()
+	t.Logf("Defaults:\n%s", buf.String())
+	if !strings.Contains(buf.String(), "Deprecated alias for -color") {
+		t.Errorf("Defaults do not document the alias:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,197 @@
+// Package archflag defines a flag.Value implementation for lists of Go
+// target platforms, written as GOOS/GOARCH pairs.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/archflag"
+//	)
+//
+//	var platforms archflag.Value
+//	func init() {
+//	  flag.Var(&platforms, "platforms", platforms.Help("Platforms to build"))
+//	}
+//
+//	  ...
+//	  for _, p := range platforms.Platforms {
+//	    build(p.OS, p.Arch)
+//	  }
+//
+// With this definition "-platforms linux/amd64,darwin/arm64" selects two
+// platforms. The pattern "GOOS/*" selects every known architecture for an
+// operating system, and "native" selects the platform of the running
+// program. Each platform is checked against the ports known to the Go
+// toolchain.
+package archflag
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// known lists the first-class and secondary ports reported by "go tool dist
+// list", in sorted order.
+var known = []string{
+	"aix/ppc64",
+	"android/386",
+	"android/amd64",
+	"android/arm",
+	"android/arm64",
+	"darwin/amd64",
+	"darwin/arm64",
+	"dragonfly/amd64",
+	"freebsd/386",
+	"freebsd/amd64",
+	"freebsd/arm",
+	"freebsd/arm64",
+	"illumos/amd64",
+	"ios/amd64",
+	"ios/arm64",
+	"js/wasm",
+	"linux/386",
+	"linux/amd64",
+	"linux/arm",
+	"linux/arm64",
+	"linux/loong64",
+	"linux/mips",
+	"linux/mips64",
+	"linux/mips64le",
+	"linux/mipsle",
+	"linux/ppc64",
+	"linux/ppc64le",
+	"linux/riscv64",
+	"linux/s390x",
+	"netbsd/386",
+	"netbsd/amd64",
+	"netbsd/arm",
+	"netbsd/arm64",
+	"openbsd/386",
+	"openbsd/amd64",
+	"openbsd/arm",
+	"openbsd/arm64",
+	"openbsd/ppc64",
+	"openbsd/riscv64",
+	"plan9/386",
+	"plan9/amd64",
+	"plan9/arm",
+	"solaris/amd64",
+	"wasip1/wasm",
+	"windows/386",
+	"windows/amd64",
+	"windows/arm64",
+}
+
+// A Platform is a target operating system and architecture.
+type Platform struct {
+	OS   string // a GOOS value, e.g., "linux"
+	Arch string // a GOARCH value, e.g., "amd64"
+}
+
+// String returns p in the format "GOOS/GOARCH".
+func (p Platform) String() string { return p.OS + "/" + p.Arch }
+
+// Native returns the platform of the running program.
+func Native() Platform { return Platform{OS: runtime.GOOS, Arch: runtime.GOARCH} }
+
+// Known returns the known platforms, in sorted order.
+func Known() []Platform {
+	out := make([]Platform, len(known))
+	for i, s := range known {
+		os, arch, _ := strings.Cut(s, "/")
+		out[i] = Platform{OS: os, Arch: arch}
+	}
+	return out
+}
+
+// IsKnown reports whether p is a known platform.
+func IsKnown(p Platform) bool {
+	_, ok := slices.BinarySearch(known, p.String())
+	return ok
+}
+
+// A Value represents a list of distinct platforms. A pointer to a Value
+// satisfies the flag.Value and flag.Getter interfaces. Each occurrence of the
+// flag adds to the list.
+type Value struct {
+	// The platforms parsed from the flag, in order of first occurrence.
+	Platforms []Platform
+
+	// Additional platforms to accept besides the known ones.
+	Extra []Platform
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string { return h + " (GOOS/GOARCH,...; GOOS/* or native)" }
+
+// Has reports whether p is in the list.
+func (v *Value) Has(p Platform) bool { return slices.Contains(v.Platforms, p) }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string {
+	parts := make([]string, len(v.Platforms))
+	for i, p := range v.Platforms {
+		parts[i] = p.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	var add []Platform
+	for _, elt := range strings.Split(s, ",") {
+		ps, err := v.parse(strings.TrimSpace(elt))
+		if err != nil {
+			return err
+		}
+		add = append(add, ps...)
+	}
+	for _, p := range add {
+		if !v.Has(p) {
+			v.Platforms = append(v.Platforms, p)
+		}
+	}
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type []Platform.
+func (v *Value) Get() any { return v.Platforms }
+
+func (v *Value) parse(s string) ([]Platform, error) {
+	if s == "" {
+		return nil, errors.New("archflag: empty platform")
+	}
+	if strings.EqualFold(s, "native") {
+		return []Platform{Native()}, nil
+	}
+	os, arch, ok := strings.Cut(strings.ToLower(s), "/")
+	if !ok || os == "" || arch == "" {
+		return nil, fmt.Errorf("archflag: invalid platform %q, want GOOS/GOARCH", s)
+	}
+	if arch == "*" {
+		var out []Platform
+		for _, p := range append(Known(), v.Extra...) {
+			if p.OS == os {
+				out = append(out, p)
+			}
+		}
+		if len(out) == 0 {
+			return nil, fmt.Errorf("archflag: unknown GOOS %q", os)
+		}
+		return out, nil
+	}
+	p := Platform{OS: os, Arch: arch}
+	if !IsKnown(p) && !slices.Contains(v.Extra, p) {
+		return nil, fmt.Errorf("archflag: unknown platform %q", s)
+	}
+	return []Platform{p}, nil
+}
@@ -0,0 +1,74 @@
+package archflag
+
+import (
+	"flag"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var platforms Value
+	fs := flag.NewFlagSet("arch", flag.ContinueOnError)
+	fs.Var(&platforms, "platforms", platforms.Help("Platforms"))
+
+	if err := fs.Parse([]string{
+		"-platforms", "linux/amd64, Darwin/ARM64",
+		"-platforms", "linux/amd64,native",
+	}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	want := []Platform{{"linux", "amd64"}, {"darwin", "arm64"}}
+	if n := Native(); !slices.Contains(want, n) {
+		want = append(want, n)
+	}
+	if got := platforms.Get().([]Platform); !slices.Equal(got, want) {
+		t.Errorf("Value for -platforms: got %v, want %v", got, want)
+	}
+	if !platforms.Has(Platform{runtime.GOOS, runtime.GOARCH}) {
+		t.Error("Has(native): got false, want true")
+	}
+}
+
+func TestWildcard(t *testing.T) {
+	var v Value
+	if err := v.Set("darwin/*"); err != nil {
+		t.Fatalf("Set(darwin/*): unexpected error: %v", err)
+	}
+	if got, want := v.String(), `"darwin/amd64,darwin/arm64"`; got != want {
+		t.Errorf("Set(darwin/*): got %s, want %s", got, want)
+	}
+}
+
+func TestKnown(t *testing.T) {
+	if !slices.IsSorted(known) {
+		t.Error("Known platform list is not sorted")
+	}
+	if !IsKnown(Native()) {
+		t.Errorf("Native platform %v is not known", Native())
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		v     Value
+		input string
+	}{
+		{Value{}, ""},
+		{Value{}, "linux"},
+		{Value{}, "linux/"},
+		{Value{}, "linux/amd64,"},
+		{Value{}, "linux/vax"},
+		{Value{}, "beos/*"},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.input); err == nil {
+			t.Errorf("Set(%q): got %v, wanted error", test.input, test.v.Platforms)
+		}
+	}
+
+	extra := Value{Extra: []Platform{{"tamago", "arm"}}}
+	if err := extra.Set("tamago/arm"); err != nil {
+		t.Errorf("Set with Extra platform: unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package goflags
+
+import (
+	"flag"
+	"strconv"
+
+	"github.com/creachadair/goflags/aliasflag"
+	"github.com/creachadair/goflags/multiflag"
+)
+
+// A Repeatable is a flag.Value that accumulates the arguments of a repeated
+// flag. ArgStrings returns the arguments that reproduce the current value
+// when passed to Set in order, one for each repetition of the flag, as used
+// by Args. For example, headerflag.Value reports one "Name: value" argument
+// for each field.
+type Repeatable interface {
+	flag.Value
+	ArgStrings() []string
+}
+
+// Args returns command-line arguments that reproduce the values of the flags
+// in fs that were set, as reported by fs.Visit, in lexicographical order by
+// name. A flag set by a deprecated name or an alias is reported by the name
+// it refers to. Each flag is rendered as a single argument "-name=value", so
+// the result can be passed to fs.Parse, or the Parse method of a flag set
+// with the same definitions, to reconstruct the invocation.
+//
+// The value is the result of ArgString, so the quoted strings reported by the
+// String methods of ArgStringer values, such as "\"4:25AM\"" for timeflag,
+// are reported unquoted. A boolean flag whose value is true is rendered as
+// "-name" alone. A Repeatable value, or a *multiflag.Value, is rendered as
+// one argument for each of its values.
+//
+// Values that hide secrets in their String method, such as secretflag,
+// dsnflag, cookieflag, and the sensitive fields of headerflag, are rendered
+// in their redacted forms, and do not reproduce the original value.
+func Args(fs *flag.FlagSet) []string {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[canonicalName(fs, f)] = true })
+	var out []string
+	fs.VisitAll(func(f *flag.Flag) {
+		if set[f.Name] {
+			out = append(out, flagArgs(f)...)
+		}
+	})
+	return out
+}
+
+// CurrentArgs is like Args, but reports each flag in fs whose current value
+// differs from its default, as well as each flag that was set. Deprecated
+// names and aliases are not reported separately from the flags they refer
+// to. The result reproduces the current values of all the flags in fs when
+// passed to the Parse method of a flag set with the same defaults.
+func CurrentArgs(fs *flag.FlagSet) []string {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[canonicalName(fs, f)] = true })
+	var out []string
+	fs.VisitAll(func(f *flag.Flag) {
+		if canonicalName(fs, f) != f.Name {
+			return // reported by the flag it refers to
+		}
+		if set[f.Name] || f.Value.String() != f.DefValue {
+			out = append(out, flagArgs(f)...)
+		}
+	})
+	return out
+}
+
+// canonicalName returns the name of the flag that f refers to, if it is
+// deprecated in favour of another flag or is an alias, or else its own name.
+func canonicalName(fs *flag.FlagSet, f *flag.Flag) string {
+	if d, ok := Deprecated(f); ok && d.New != "" {
+		return d.New
+	}
+	if name, ok := aliasflag.Canonical(fs, f.Name); ok {
+		return name
+	}
+	return f.Name
+}
+
+// flagArgs returns the arguments that reproduce the value of f.
+func flagArgs(f *flag.Flag) []string {
+	vals := valueArgs(f.Value)
+	isBool := isBoolFlag(f.Value)
+	out := make([]string, len(vals))
+	for i, val := range vals {
+		if isBool && val == "true" {
+			out[i] = "-" + f.Name
+		} else {
+			out[i] = "-" + f.Name + "=" + val
+		}
+	}
+	return out
+}
+
+// valueArgs returns the arguments that reproduce v when passed to its Set
+// method in order: one for each of the values of a Repeatable value or a
+// *multiflag.Value, or else its value as reported by ArgString.
+func valueArgs(v flag.Value) []string {
+	if r, ok := findValue[Repeatable](v); ok {
+		return r.ArgStrings()
+	} else if m, ok := findValue[*multiflag.Value](v); ok {
+		var vals []string
+		for _, g := range m.Values() {
+			vals = append(vals, ArgString(g))
+		}
+		return vals
+	}
+	return []string{ArgString(v)}
+}
+
+// isRepeated reports whether v accumulates the arguments of a repeated flag,
+// as a Repeatable value or a *multiflag.Value does.
+func isRepeated(v flag.Value) bool {
+	_, isRepeatable := findValue[Repeatable](v)
+	_, isMulti := findValue[*multiflag.Value](v)
+	return isRepeatable || isMulti
+}
+
+// An ArgStringer is a flag.Value whose String method reports its value as a
+// Go quoted string, as most value types in this module do, so that it is
+// legible in help text. ArgString reports the value without quotes, in the
+// form accepted by Set, so that String returns strconv.Quote(v.ArgString()).
+type ArgStringer interface {
+	flag.Value
+	ArgString() string
+}
+
+// ArgString returns the current value of v as an argument to its Set method.
+// If v, or a value it wraps, is an ArgStringer, the result is that of its
+// ArgString method; otherwise it is the result of the String method of v.
+func ArgString(v flag.Value) string {
+	if a, ok := findValue[ArgStringer](v); ok {
+		return a.ArgString()
+	}
+	return v.String()
+}
+
+// DefaultArg returns the default value of f as an argument to the Set method
+// of its value. If the value is an ArgStringer, as reported by ArgString,
+// whose String method reports the quoted form of its ArgString, the DefValue
+// of f is its quoted String form, and is unquoted. Otherwise, or if the
+// DefValue is not quoted, the result is the DefValue of f.
+func DefaultArg(f *flag.Flag) string {
+	if a, ok := findValue[ArgStringer](f.Value); ok && a.String() == strconv.Quote(a.ArgString()) {
+		if u, err := strconv.Unquote(f.DefValue); err == nil {
+			return u
+		}
+	}
+	return f.DefValue
+}
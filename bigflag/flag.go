@@ -0,0 +1,108 @@
+// Package bigflag defines flag.Value implementations for arbitrary-precision
+// numbers from the math/big package.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/bigflag"
+//	)
+//
+//	var modulus bigflag.Int
+//	var epsilon = bigflag.Float{Precision: 256}
+//
+//	func init() {
+//	  flag.Var(&modulus, "modulus", "Group modulus")
+//	  flag.Var(&epsilon, "epsilon", "Convergence threshold")
+//	}
+//
+// Integers may be written in decimal, or with a base prefix: "0x" or "0X" for
+// hexadecimal, "0b" or "0B" for binary, and "0o", "0O", or "0" for octal.
+// Underscores may separate digits after a prefix, as in Go source.
+package bigflag
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// An Int represents an arbitrary-precision integer. The methods of the
+// embedded *big.Int are available directly. A pointer to an Int satisfies the
+// flag.Value and flag.Getter interfaces. The zero value represents 0.
+type Int struct{ *big.Int }
+
+// String satisfies part of the flag.Value interface.
+func (v Int) String() string {
+	if v.Int == nil {
+		return "0"
+	}
+	return v.Int.String()
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Int) Set(s string) error {
+	z, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return fmt.Errorf("bigflag: invalid integer %q", s)
+	}
+	v.Int = z
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type *big.Int, and is 0 if no value is set.
+func (v *Int) Get() any {
+	if v.Int == nil {
+		return new(big.Int)
+	}
+	return v.Int
+}
+
+// A Float represents an arbitrary-precision floating-point number. The
+// methods of the embedded *big.Float are available directly. A pointer to a
+// Float satisfies the flag.Value and flag.Getter interfaces. The zero value
+// represents 0.
+//
+// In addition to decimal notation, a Float accepts hexadecimal mantissas with
+// binary exponents, e.g., "0x1.8p3", and the base prefixes accepted by Int.
+type Float struct {
+	*big.Float
+
+	// The precision of the parsed value in bits. If zero, the value is parsed
+	// with 64 bits of precision, the same as a float64 mantissa.
+	Precision uint
+}
+
+// String satisfies part of the flag.Value interface.
+// The value is rendered with the fewest decimal digits that represent it
+// exactly at its precision.
+func (v Float) String() string {
+	if v.Float == nil {
+		return "0"
+	}
+	return v.Float.Text('g', -1)
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Float) Set(s string) error {
+	prec := v.Precision
+	if prec == 0 {
+		prec = 64
+	}
+	z, _, err := new(big.Float).SetPrec(prec).Parse(s, 0)
+	if err != nil {
+		return fmt.Errorf("bigflag: invalid number %q", s)
+	}
+	v.Float = z
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type *big.Float, and is 0 if no value is set.
+func (v *Float) Get() any {
+	if v.Float == nil {
+		return new(big.Float)
+	}
+	return v.Float
+}
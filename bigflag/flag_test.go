@@ -0,0 +1,96 @@
+package bigflag
+
+import (
+	"flag"
+	"math/big"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var modulus Int
+	eps := Float{Precision: 200}
+
+	fs := flag.NewFlagSet("big", flag.ContinueOnError)
+	fs.Var(&modulus, "modulus", "Group modulus")
+	fs.Var(&eps, "epsilon", "Convergence threshold")
+
+	if got := modulus.String(); got != "0" {
+		t.Errorf("Initial value for -modulus: got %q, want 0", got)
+	}
+	if got := eps.Get().(*big.Float).Sign(); got != 0 {
+		t.Errorf("Initial value for -epsilon: got sign %d, want 0", got)
+	}
+
+	const p = "340282366920938463463374607431768211297" // 2^128 - 159
+	if err := fs.Parse([]string{"-modulus", p, "-epsilon", "1e-50"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got := modulus.Get().(*big.Int).String(); got != p {
+		t.Errorf("Value for -modulus: got %s, want %s", got, p)
+	}
+	if !modulus.ProbablyPrime(10) {
+		t.Errorf("Embedded method: %v.ProbablyPrime: got false, want true", modulus)
+	}
+	if got := eps.Prec(); got != 200 {
+		t.Errorf("Precision for -epsilon: got %d, want 200", got)
+	}
+	if got, want := eps.Text('e', 3), "1.000e-50"; got != want {
+		t.Errorf("Value for -epsilon: got %s, want %s", got, want)
+	}
+}
+
+func TestInt(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"0", 0},
+		{"-25", -25},
+		{"0x1F", 31},
+		{"0b1010", 10},
+		{"0o17", 15},
+		{"017", 15},
+		{"0x_ff_ff", 65535},
+	}
+	for _, test := range tests {
+		var v Int
+		if err := v.Set(test.in); err != nil {
+			t.Errorf("Set(%q) failed: %v", test.in, err)
+		} else if got := v.Int64(); got != test.want {
+			t.Errorf("Set(%q): got %d, want %d", test.in, got, test.want)
+		}
+	}
+	for _, bad := range []string{"", "0x", "12a", "1.5", "0b102"} {
+		var v Int
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %v, wanted error", bad, v)
+		}
+	}
+}
+
+func TestFloat(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{"0", 0},
+		{"-2.5", -2.5},
+		{"1e3", 1000},
+		{"0x1.8p3", 12},
+		{"0b101", 5},
+	}
+	for _, test := range tests {
+		var v Float
+		if err := v.Set(test.in); err != nil {
+			t.Errorf("Set(%q) failed: %v", test.in, err)
+		} else if got, _ := v.Float64(); got != test.want {
+			t.Errorf("Set(%q): got %v, want %v", test.in, got, test.want)
+		}
+	}
+	for _, bad := range []string{"", "1.2.3", "e5", "0x1.8q3", "one"} {
+		var v Float
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %v, wanted error", bad, v)
+		}
+	}
+}
@@ -0,0 +1,484 @@
+package goflags
+
+import (
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/creachadair/goflags/enumflag"
+	"github.com/creachadair/goflags/sizeflag"
+	"github.com/creachadair/goflags/timeflag"
+)
+
+// Bind defines a flag in fs for each tagged field of the struct pointed to by
+// cfg. The current values of the fields are the defaults of the flags, and
+// parsing fs updates the fields in place.
+//
+// A field is bound if it has a tag of the form
+//
+//	flag:"name,usage"
+//
+// where usage is the rest of the tag after the first comma. If name is empty,
+// it is derived from the field name, so that CacheSize becomes "cache-size".
+// The tag flag:"-" skips a field. A tagged field of struct type (other than
+// time.Time, or a type that implements flag.Value) binds the fields of that
+// struct with names prefixed by "name."; an untagged struct field binds its
+// fields without a prefix. Other untagged fields are ignored.
+//
+// The flag defined for a field depends on its type:
+//
+//   - A field whose address implements flag.Value is used directly. A nil
+//     pointer field whose type implements flag.Value is first allocated.
+//   - Strings, booleans, integers, floats, time.Duration, and types that
+//     implement encoding.TextUnmarshaler are parsed in the usual way.
+//   - A string field with an enum:"a,b,c" tag accepts only the listed values,
+//     using the enumflag package.
+//   - An integer field with a size:"2" or size:"10" tag accepts sizes such as
+//     "4K" or "1.5G" in powers of 2 or 10, using the sizeflag package.
+//   - A time.Time field accepts times in the layout given by a layout tag,
+//     default time.RFC3339, using the timeflag package.
+//   - A *regexp.Regexp field accepts a regular expression.
+//   - A slice of any of the scalar types above is repeatable: each occurrence
+//     of the flag appends to it, after the first replaces the default.
+//   - A map with string keys and scalar values is repeatable: each occurrence
+//     of the flag adds a "key=value" entry.
+//
+// For slices and maps, a sep:"," tag splits each occurrence at that separator
+// as well. Bind reports an error for a field of any other type, or if a flag
+// with the same name is already defined in fs; in that case some flags may
+// already have been defined.
+func Bind(fs *flag.FlagSet, cfg any) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("goflags: Bind requires a pointer to a struct, not %T", cfg)
+	}
+	return bindStruct(fs, v.Elem(), "")
+}
+
+var (
+	flagValueType = reflect.TypeFor[flag.Value]()
+	textType      = reflect.TypeFor[encoding.TextUnmarshaler]()
+	durationType  = reflect.TypeFor[time.Duration]()
+	timeType      = reflect.TypeFor[time.Time]()
+	regexpType    = reflect.TypeFor[*regexp.Regexp]()
+)
+
+func bindStruct(fs *flag.FlagSet, v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := range t.NumField() {
+		ft := t.Field(i)
+		tag, tagged := ft.Tag.Lookup("flag")
+		if !ft.IsExported() || tag == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		if !tagged {
+			if isNested(fv) {
+				if err := bindStruct(fs, fv, prefix); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		name, usage, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = kebab(ft.Name)
+		}
+		name = prefix + name
+		if isNested(fv) {
+			if err := bindStruct(fs, fv, name+"."); err != nil {
+				return err
+			}
+			continue
+		}
+		if fs.Lookup(name) != nil {
+			return fmt.Errorf("goflags: flag -%s (field %s) is already defined", name, ft.Name)
+		}
+		fval, help, err := newFieldValue(fv, ft.Tag)
+		if err != nil {
+			return fmt.Errorf("goflags: field %s: %w", ft.Name, err)
+		}
+		if help != nil {
+			usage = help(usage)
+		}
+		fs.Var(fval, name, usage)
+	}
+	return nil
+}
+
+// isNested reports whether v is a struct whose fields should be bound.
+func isNested(v reflect.Value) bool {
+	return v.Kind() == reflect.Struct && v.Type() != timeType &&
+		!v.Addr().Type().Implements(flagValueType) && !v.Addr().Type().Implements(textType)
+}
+
+// newFieldValue returns a flag.Value that updates v, and optionally a function
+// that decorates the usage string.
+func newFieldValue(v reflect.Value, tag reflect.StructTag) (flag.Value, func(string) string, error) {
+	t := v.Type()
+	if t.Kind() == reflect.Pointer && t.Implements(flagValueType) {
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		return v.Interface().(flag.Value), nil, nil
+	}
+	if v.Addr().Type().Implements(flagValueType) {
+		return v.Addr().Interface().(flag.Value), nil, nil
+	}
+
+	if keys, ok := tag.Lookup("enum"); ok {
+		return enumValue(v, keys)
+	}
+	if base, ok := tag.Lookup("size"); ok {
+		fv, err := sizeValue(v, base)
+		return fv, nil, err
+	}
+	switch t {
+	case timeType:
+		layout := tag.Get("layout")
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		tv := &timeflag.Value{Layout: layout, Time: v.Interface().(time.Time)}
+		return &fieldValue{
+			str:   func() string { return tv.String() },
+			get:   func() any { return tv.Get() },
+			inner: tv,
+			set: func(s string) error {
+				if err := tv.Set(s); err != nil {
+					return err
+				}
+				v.Set(reflect.ValueOf(tv.Time))
+				return nil
+			},
+		}, tv.Help, nil
+	case regexpType:
+		return &fieldValue{
+			str: func() string {
+				if v.IsNil() {
+					return ""
+				}
+				return v.Interface().(*regexp.Regexp).String()
+			},
+			get: v.Interface,
+			set: func(s string) error {
+				re, err := regexp.Compile(s)
+				if err != nil {
+					return err
+				}
+				v.Set(reflect.ValueOf(re))
+				return nil
+			},
+		}, nil, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Slice:
+		if !isScalar(t.Elem()) {
+			break
+		}
+		return sliceValue(v, tag.Get("sep")), nil, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String || !isScalar(t.Elem()) {
+			break
+		}
+		return mapValue(v, tag.Get("sep")), nil, nil
+	default:
+		if !isScalar(t) {
+			break
+		}
+		return &fieldValue{
+			str:    func() string { return formatScalar(v) },
+			get:    v.Interface,
+			set:    func(s string) error { return setScalar(v, s) },
+			isBool: t.Kind() == reflect.Bool,
+		}, nil, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported type %v", t)
+}
+
+func enumValue(v reflect.Value, keys string) (flag.Value, func(string) string, error) {
+	if v.Kind() != reflect.String {
+		return nil, nil, fmt.Errorf("enum tag requires a string field, not %v", v.Type())
+	}
+	all := strings.Split(keys, ",")
+	def := v.String()
+	if def == "" {
+		def = all[0]
+	}
+	var others []string
+	for _, k := range all {
+		if !strings.EqualFold(k, def) {
+			others = append(others, k)
+		}
+	}
+	if len(others) == len(all) {
+		return nil, nil, fmt.Errorf("default %q is not one of (%s)", def, keys)
+	}
+	ev := enumflag.New(def, others...)
+	v.SetString(def)
+	return &fieldValue{
+		str:   func() string { return ev.String() },
+		get:   func() any { return ev.Get() },
+		inner: ev,
+		set: func(s string) error {
+			if err := ev.Set(s); err != nil {
+				return err
+			}
+			v.SetString(ev.Key())
+			return nil
+		},
+	}, ev.Help, nil
+}
+
+func sizeValue(v reflect.Value, base string) (flag.Value, error) {
+	var parse func(string) (int64, error)
+	var format func(int64) string
+	switch base {
+	case "2":
+		parse, format = sizeflag.Parse2, func(n int64) string { return sizeflag.Value2(n).String() }
+	case "10":
+		parse, format = sizeflag.Parse10, func(n int64) string { return sizeflag.Value10(n).String() }
+	default:
+		return nil, fmt.Errorf("invalid size base %q, expected 2 or 10", base)
+	}
+	if !isInt(v.Kind()) {
+		return nil, fmt.Errorf("size tag requires an integer field, not %v", v.Type())
+	}
+	return &fieldValue{
+		str: func() string { return format(v.Int()) },
+		get: v.Interface,
+		set: func(s string) error {
+			n, err := parse(s)
+			if err != nil {
+				return err
+			}
+			if v.OverflowInt(n) {
+				return fmt.Errorf("size %q out of range for %v", s, v.Type())
+			}
+			v.SetInt(n)
+			return nil
+		},
+	}, nil
+}
+
+func sliceValue(v reflect.Value, sep string) flag.Value {
+	replaced := false
+	return &fieldValue{
+		str: func() string {
+			parts := make([]string, v.Len())
+			for i := range parts {
+				parts[i] = formatScalar(v.Index(i))
+			}
+			return strings.Join(parts, ",")
+		},
+		get: v.Interface,
+		set: func(s string) error {
+			var elts []reflect.Value
+			for _, part := range split(s, sep) {
+				e := reflect.New(v.Type().Elem()).Elem()
+				if err := setScalar(e, part); err != nil {
+					return err
+				}
+				elts = append(elts, e)
+			}
+			if !replaced {
+				// Start from a new slice, so the default does not share its
+				// array with the new value.
+				v.Set(reflect.MakeSlice(v.Type(), 0, len(elts)))
+				replaced = true
+			}
+			v.Set(reflect.Append(v, elts...))
+			return nil
+		},
+	}
+}
+
+func mapValue(v reflect.Value, sep string) flag.Value {
+	replaced := false
+	return &fieldValue{
+		str: func() string {
+			keys := v.MapKeys()
+			parts := make([]string, len(keys))
+			for i, k := range keys {
+				parts[i] = k.String() + "=" + formatScalar(v.MapIndex(k))
+			}
+			slices.Sort(parts)
+			return strings.Join(parts, ",")
+		},
+		get: v.Interface,
+		set: func(s string) error {
+			t := v.Type()
+			var keys, vals []reflect.Value
+			for _, part := range split(s, sep) {
+				key, val, ok := strings.Cut(part, "=")
+				if !ok || key == "" {
+					return fmt.Errorf("invalid entry %q, expected key=value", part)
+				}
+				k := reflect.New(t.Key()).Elem()
+				k.SetString(key)
+				e := reflect.New(t.Elem()).Elem()
+				if err := setScalar(e, val); err != nil {
+					return err
+				}
+				keys, vals = append(keys, k), append(vals, e)
+			}
+			if !replaced || v.IsNil() {
+				v.Set(reflect.MakeMap(t))
+				replaced = true
+			}
+			for i, k := range keys {
+				v.SetMapIndex(k, vals[i])
+			}
+			return nil
+		},
+	}
+}
+
+func split(s, sep string) []string {
+	if sep == "" {
+		return []string{s}
+	}
+	return strings.Split(s, sep)
+}
+
+// isScalar reports whether values of type t can be parsed by setScalar.
+func isScalar(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(textType) || t == durationType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return isInt(t.Kind()) || isUint(t.Kind())
+}
+
+func isInt(k reflect.Kind) bool { return k >= reflect.Int && k <= reflect.Int64 }
+
+func isUint(k reflect.Kind) bool { return k >= reflect.Uint && k <= reflect.Uintptr }
+
+// setScalar parses s and stores the result in v, which must be addressable
+// and satisfy isScalar.
+func setScalar(v reflect.Value, s string) error {
+	t := v.Type()
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+	if t == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch k := t.Kind(); {
+	case k == reflect.String:
+		v.SetString(s)
+	case k == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return errors.New("invalid boolean")
+		}
+		v.SetBool(b)
+	case isInt(k):
+		n, err := strconv.ParseInt(s, 0, t.Bits())
+		if err != nil {
+			return numError(err)
+		}
+		v.SetInt(n)
+	case isUint(k):
+		n, err := strconv.ParseUint(s, 0, t.Bits())
+		if err != nil {
+			return numError(err)
+		}
+		v.SetUint(n)
+	case k == reflect.Float32 || k == reflect.Float64:
+		f, err := strconv.ParseFloat(s, t.Bits())
+		if err != nil {
+			return numError(err)
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %v", t)
+	}
+	return nil
+}
+
+func numError(err error) error {
+	if ne, ok := err.(*strconv.NumError); ok {
+		return ne.Err
+	}
+	return err
+}
+
+// formatScalar renders a scalar value as a string.
+func formatScalar(v reflect.Value) string {
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		if text, err := m.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// kebab converts a Go field name to a flag name, e.g., "CacheSize" to
+// "cache-size" and "HTTPAddr" to "http-addr".
+func kebab(name string) string {
+	rs := []rune(name)
+	var sb strings.Builder
+	for i, r := range rs {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(rs[i-1]) ||
+				i+1 < len(rs) && unicode.IsLower(rs[i+1]) && unicode.IsUpper(rs[i-1])) {
+				sb.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// fieldValue is a flag.Value that updates a struct field.
+type fieldValue struct {
+	str    func() string
+	get    func() any
+	set    func(string) error
+	isBool bool
+	inner  flag.Value // if not nil, the value that parses the field
+}
+
+func (f *fieldValue) String() string {
+	if f == nil || f.str == nil {
+		return "" // the zero value, as used by flag.PrintDefaults
+	}
+	return f.str()
+}
+
+func (f *fieldValue) Set(s string) error { return f.set(s) }
+func (f *fieldValue) Get() any           { return f.get() }
+func (f *fieldValue) IsBoolFlag() bool   { return f.isBool }
+
+// ArgString satisfies the ArgStringer interface. For a field parsed by
+// another value, such as an enum or time field, it reports the ArgString of
+// that value; otherwise it reports the same string as String.
+func (f *fieldValue) ArgString() string {
+	if f.inner != nil {
+		return ArgString(f.inner)
+	}
+	return f.String()
+}
+
+// Unwrap returns the value that parses the field, such as an enumflag.Value
+// for an enum field, or nil if there is none.
+func (f *fieldValue) Unwrap() flag.Value { return f.inner }
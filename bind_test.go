@@ -0,0 +1,258 @@
+package goflags
+
+import (
+	"flag"
+	"io"
+	"maps"
+	"net/netip"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/creachadair/goflags/sizeflag"
+)
+
+type testConfig struct {
+	Name    string        `flag:"name,Service name"`
+	Verbose bool          `flag:"v,Verbose logging"`
+	Port    int           `flag:"port,Port to listen on"`
+	Ratio   float64       `flag:",Sampling ratio"`
+	Timeout time.Duration `flag:"timeout,Request timeout"`
+	Color   string        `flag:"color,Use color" enum:"auto,always,never"`
+	Cache   int64         `flag:"cache,Cache size" size:"2"`
+	Limit   sizeflag.Value10
+	Buffer  sizeflag.Value10 `flag:"buffer,Buffer size"`
+	Start   time.Time        `flag:"start,Start date" layout:"2006-01-02"`
+	Match   *regexp.Regexp   `flag:"match,Pattern to match"`
+	Addr    netip.Addr       `flag:"addr,Bind address"`
+	Tags    []string         `flag:"tag,Tags (repeatable)"`
+	IDs     []int            `flag:"ids,Identifiers" sep:","`
+	Labels  map[string]int   `flag:"label,Labels"`
+	Skip    string           `flag:"-"`
+	hidden  string           `flag:"hidden"`
+
+	DB struct {
+		Host string `flag:"host,Database host"`
+		Port int    `flag:"port,Database port"`
+	} `flag:"db"`
+
+	Common
+}
+
+type Common struct {
+	LogFile string `flag:"log-file,Log file"`
+}
+
+func TestBind(t *testing.T) {
+	cfg := testConfig{Name: "svc", Port: 8080, Tags: []string{"default"}}
+	cfg.DB.Port = 5432
+
+	fs := flag.NewFlagSet("bind", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if err := Bind(fs, &cfg); err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) { names = append(names, f.Name) })
+	want := []string{
+		"addr", "buffer", "cache", "color", "db.host", "db.port", "ids", "label",
+		"log-file", "match", "name", "port", "ratio", "start", "tag", "timeout", "v",
+	}
+	if !slices.Equal(names, want) {
+		t.Errorf("Flags: got %q, want %q", names, want)
+	}
+	if got, want := fs.Lookup("port").DefValue, "8080"; got != want {
+		t.Errorf("Default -port: got %q, want %q", got, want)
+	}
+	if got, want := fs.Lookup("color").Usage, "Use color (auto|always|never)"; got != want {
+		t.Errorf("Usage -color: got %q, want %q", got, want)
+	}
+
+	if err := fs.Parse([]string{
+		"-name", "api", "-v", "-port", "9000", "-ratio", "0.25", "-timeout", "3s",
+		"-color", "NEVER", "-cache", "4k", "-buffer", "2k", "-start", "2024-03-01",
+		"-match", "^a+$", "-addr", "10.0.0.1", "-tag", "x", "-tag", "y",
+		"-ids", "1,2", "-ids", "3", "-label", "a=1", "-label", "b=2",
+		"-db.host", "db.local", "-log-file", "/tmp/log",
+	}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	if cfg.Name != "api" || !cfg.Verbose || cfg.Port != 9000 || cfg.Ratio != 0.25 || cfg.Timeout != 3*time.Second {
+		t.Errorf("Scalars: got %+v", cfg)
+	}
+	if cfg.Color != "never" || cfg.Cache != 4096 || cfg.Buffer != 2000 {
+		t.Errorf("Color, Cache, Buffer: got %q, %d, %d", cfg.Color, cfg.Cache, cfg.Buffer)
+	}
+	if want := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC); !cfg.Start.Equal(want) {
+		t.Errorf("Start: got %v, want %v", cfg.Start, want)
+	}
+	if cfg.Match == nil || !cfg.Match.MatchString("aaa") {
+		t.Errorf("Match: got %v", cfg.Match)
+	}
+	if cfg.Addr != netip.MustParseAddr("10.0.0.1") {
+		t.Errorf("Addr: got %v", cfg.Addr)
+	}
+	if want := []string{"x", "y"}; !slices.Equal(cfg.Tags, want) {
+		t.Errorf("Tags: got %q, want %q", cfg.Tags, want)
+	}
+	if want := []int{1, 2, 3}; !slices.Equal(cfg.IDs, want) {
+		t.Errorf("IDs: got %v, want %v", cfg.IDs, want)
+	}
+	if want := map[string]int{"a": 1, "b": 2}; !maps.Equal(cfg.Labels, want) {
+		t.Errorf("Labels: got %v, want %v", cfg.Labels, want)
+	}
+	if cfg.DB.Host != "db.local" || cfg.DB.Port != 5432 || cfg.LogFile != "/tmp/log" {
+		t.Errorf("Nested: got %+v, %q", cfg.DB, cfg.LogFile)
+	}
+	if got, want := fs.Lookup("label").Value.String(), "a=1,b=2"; got != want {
+		t.Errorf("String -label: got %q, want %q", got, want)
+	}
+}
+
+func TestBindSharedDefaults(t *testing.T) {
+	defaultTags := []string{"a.example", "b.example"}
+	defaultLabels := map[string]int{"a": 1}
+	cfg := testConfig{Tags: defaultTags, Labels: defaultLabels}
+
+	fs := flag.NewFlagSet("bind", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if err := Bind(fs, &cfg); err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+	if err := fs.Parse([]string{"-tag", "evil", "-label", "b=2"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if want := []string{"evil"}; !slices.Equal(cfg.Tags, want) {
+		t.Errorf("Tags: got %q, want %q", cfg.Tags, want)
+	}
+
+	// Replacing the defaults does not modify them.
+	if want := []string{"a.example", "b.example"}; !slices.Equal(defaultTags, want) {
+		t.Errorf("Default tags: got %q, want %q", defaultTags, want)
+	}
+	if want := map[string]int{"a": 1}; !maps.Equal(defaultLabels, want) {
+		t.Errorf("Default labels: got %v, want %v", defaultLabels, want)
+	}
+}
+
+func TestBindEnum(t *testing.T) {
+	var cfg testConfig
+	fs := flag.NewFlagSet("bind", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if err := Bind(fs, &cfg); err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+	if err := fs.Parse([]string{"-color", "never"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	v := fs.Lookup("color").Value
+	if got, want := v.String(), `"never"`; got != want {
+		t.Errorf("String -color: got %s, want %s", got, want)
+	}
+	if got, want := v.(flag.Getter).Get(), any("never"); got != want {
+		t.Errorf("Get -color: got %v, want %v", got, want)
+	}
+}
+
+func TestBindArgs(t *testing.T) {
+	type config struct {
+		Name  string         `flag:"name,Name"`
+		Port  int            `flag:"port,Port"`
+		Color string         `flag:"color,Color" enum:"red,green,blue"`
+		When  time.Time      `flag:"when,When"`
+		Cache int64          `flag:"cache,Cache size" size:"2"`
+		Match *regexp.Regexp `flag:"match,Pattern"`
+		IDs   []int          `flag:"ids,Identifiers" sep:","`
+	}
+	bind := func() (*flag.FlagSet, *config) {
+		var cfg config
+		fs := flag.NewFlagSet("bind", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		if err := Bind(fs, &cfg); err != nil {
+			t.Fatalf("Bind failed: %v", err)
+		}
+		return fs, &cfg
+	}
+
+	fs, cfg := bind()
+	if err := fs.Parse([]string{
+		"-name", "a b", "-port", "80", "-color", "green", "-when", "2024-01-02T03:04:05Z",
+		"-cache", "4k", "-match", "^x$", "-ids", "1,2",
+	}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	args := Args(fs)
+	if !slices.Contains(args, "-color=green") || !slices.Contains(args, "-when=2024-01-02T03:04:05Z") {
+		t.Errorf("Args: got %q, want unquoted -color and -when", args)
+	}
+
+	cp, got := bind()
+	if err := cp.Parse(args); err != nil {
+		t.Fatalf("Parse(%q) failed: %v", args, err)
+	}
+	if got.Name != cfg.Name || got.Port != cfg.Port || got.Color != cfg.Color || !got.When.Equal(cfg.When) ||
+		got.Cache != cfg.Cache || got.Match.String() != cfg.Match.String() || !slices.Equal(got.IDs, cfg.IDs) {
+		t.Errorf("After round trip of %q: got %+v, want %+v", args, *got, *cfg)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  any
+	}{
+		{"NotPointer", testConfig{}},
+		{"NotStruct", new(int)},
+		{"Unsupported", &struct {
+			C chan int `flag:"c"`
+		}{}},
+		{"BadEnumType", &struct {
+			N int `flag:"n" enum:"a,b"`
+		}{}},
+		{"BadEnumDefault", &struct {
+			S string `flag:"s" enum:"a,b"`
+		}{S: "c"}},
+		{"BadSizeBase", &struct {
+			N int64 `flag:"n" size:"8"`
+		}{}},
+		{"Duplicate", &struct {
+			A string `flag:"x"`
+			B string `flag:"x"`
+		}{}},
+	}
+	for _, test := range tests {
+		fs := flag.NewFlagSet("bind", flag.ContinueOnError)
+		if err := Bind(fs, test.cfg); err == nil {
+			t.Errorf("Bind %s: got nil, wanted error", test.name)
+		}
+	}
+
+	var cfg testConfig
+	fs := flag.NewFlagSet("bind", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if err := Bind(fs, &cfg); err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+	for _, args := range [][]string{
+		{"-port", "x"}, {"-color", "blue"}, {"-cache", "lots"}, {"-start", "yesterday"},
+		{"-match", "("}, {"-addr", "nope"}, {"-ids", "1,x"}, {"-label", "novalue"},
+	} {
+		if err := fs.Parse(args); err == nil {
+			t.Errorf("Parse %q: got nil, wanted error", args)
+		}
+	}
+}
+
+func TestKebab(t *testing.T) {
+	tests := map[string]string{
+		"Name": "name", "CacheSize": "cache-size", "HTTPAddr": "http-addr", "ID": "id", "UserID": "user-id",
+	}
+	for in, want := range tests {
+		if got := kebab(in); got != want {
+			t.Errorf("kebab(%q): got %q, want %q", in, got, want)
+		}
+	}
+}
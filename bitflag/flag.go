@@ -0,0 +1,122 @@
+// Package bitflag defines a flag.Value implementation that accepts a
+// combination of named bits from a fixed set chosen when the flag is defined.
+// Names are compared without respect to case, as in the enumflag package.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/bitflag"
+//	)
+//
+//	// Bits are assigned in order: read=1, write=2, exec=4.
+//	var perm = bitflag.New("read", "write", "exec")
+//	func init() {
+//	  flag.Var(perm, "perm", perm.Help("Permissions to grant"))
+//	}
+//
+// With this definition "-perm read|write" sets the mask to 3. Names may be
+// separated by "|" or ",".
+package bitflag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Value represents a set of named bits. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces. Use Mask or Has to recover the
+// currently-selected bits.
+type Value struct {
+	names []string
+	mask  uint64
+}
+
+// New returns a *Value for the specified names, which are assigned to bits in
+// order, so that if:
+//
+//	v := bitflag.New("a", "b", "c")
+//
+// then "a" is bit 0 (mask 1), "b" is bit 1 (mask 2), and "c" is bit 2 (mask
+// 4). The initial mask is 0. New panics if more than 64 names are given or if
+// any name is empty or repeated.
+func New(names ...string) *Value {
+	if len(names) > 64 {
+		panic("bitflag: too many names")
+	}
+	for i, name := range names {
+		if name == "" {
+			panic("bitflag: empty name")
+		}
+		for _, prev := range names[:i] {
+			if strings.EqualFold(name, prev) {
+				panic(fmt.Sprintf("bitflag: duplicate name %q", name))
+			}
+		}
+	}
+	return &Value{names: names}
+}
+
+// Help concatenates a human-readable string summarizing the legal names of v
+// to h, for use in generating a documentation string.
+func (v Value) Help(h string) string {
+	return fmt.Sprintf("%s (any of %s)", h, strings.Join(v.names, "|"))
+}
+
+// Mask returns the currently-selected bits.
+func (v Value) Mask() uint64 { return v.mask }
+
+// Bit returns the mask bit assigned to name, or 0 if name is not known.
+func (v Value) Bit(name string) uint64 {
+	for i, key := range v.names {
+		if strings.EqualFold(name, key) {
+			return 1 << i
+		}
+	}
+	return 0
+}
+
+// Has reports whether the bit assigned to name is selected. It reports false
+// if name is not known.
+func (v Value) Has(name string) bool {
+	bit := v.Bit(name)
+	return bit != 0 && v.mask&bit != 0
+}
+
+// Names returns the names of the currently-selected bits, in bit order, with
+// the original spelling given to the constructor.
+func (v Value) Names() []string {
+	var out []string
+	for i, name := range v.names {
+		if v.mask&(1<<i) != 0 {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// String satisfies part of the flag.Value interface.
+func (v Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v Value) ArgString() string { return strings.Join(v.Names(), "|") }
+
+// Set satisfies part of the flag.Value interface. An empty string clears all
+// the bits.
+func (v *Value) Set(s string) error {
+	var mask uint64
+	for _, name := range strings.FieldsFunc(s, func(r rune) bool { return r == '|' || r == ',' }) {
+		bit := v.Bit(strings.TrimSpace(name))
+		if bit == 0 {
+			return fmt.Errorf("expected any of (%s)", strings.Join(v.names, "|"))
+		}
+		mask |= bit
+	}
+	v.mask = mask
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is the uint64 mask.
+func (v Value) Get() any { return v.mask }
@@ -0,0 +1,75 @@
+package bitflag
+
+import (
+	"bytes"
+	"flag"
+	"slices"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	perm := New("read", "write", "exec")
+
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("bits", flag.ContinueOnError)
+	fs.Var(perm, "perm", perm.Help("Permissions to grant"))
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+	t.Logf("Bit flag set:\n%s", buf.String())
+	buf.Reset()
+
+	if m := perm.Mask(); m != 0 {
+		t.Errorf("Initial value for -perm: got %d, want 0", m)
+	}
+	if err := fs.Parse([]string{"-perm", "EXEC|read"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got := perm.Get().(uint64); got != 5 {
+		t.Errorf("Value for -perm: got %b, want 101", got)
+	}
+	if !perm.Has("read") || perm.Has("write") || !perm.Has("Exec") || perm.Has("bogus") {
+		t.Errorf("Has for -perm: got read=%v write=%v exec=%v",
+			perm.Has("read"), perm.Has("write"), perm.Has("exec"))
+	}
+	if got, want := perm.Names(), []string{"read", "exec"}; !slices.Equal(got, want) {
+		t.Errorf("Names for -perm: got %q, want %q", got, want)
+	}
+	if got, want := perm.String(), `"read|exec"`; got != want {
+		t.Errorf("String for -perm: got %s, want %s", got, want)
+	}
+
+	if err := fs.Parse([]string{"-perm", "read, write,read"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	} else if got := perm.Mask(); got != 3 {
+		t.Errorf("Value for -perm: got %b, want 11", got)
+	}
+	if err := fs.Parse([]string{"-perm", ""}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	} else if got := perm.Mask(); got != 0 {
+		t.Errorf("Value for -perm: got %b, want 0", got)
+	}
+
+	if err := fs.Parse([]string{"-perm", "read|delete"}); err == nil {
+		t.Error("Expected error from bogus flag, but got none")
+	} else {
+		t.Logf("Got expected error from bogus -perm: %v", err)
+	}
+}
+
+func TestNewPanics(t *testing.T) {
+	tests := [][]string{
+		{"a", ""},
+		{"a", "b", "A"},
+		make([]string, 65),
+	}
+	for _, names := range tests {
+		func() {
+			defer func() {
+				if x := recover(); x == nil {
+					t.Errorf("New(%q): got no panic", names)
+				}
+			}()
+			New(names...)
+		}()
+	}
+}
@@ -0,0 +1,218 @@
+// Package bucketflag defines a flag.Value implementation for histogram bucket
+// boundaries, in the form used by Prometheus client libraries.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/bucketflag"
+//	)
+//
+//	var buckets = bucketflag.Value{Buckets: bucketflag.MustParse("exp(1ms,2,12)")}
+//	func init() {
+//	  flag.Var(&buckets, "latency-buckets", buckets.Help("Latency histogram buckets"))
+//	}
+//
+// A specification is a comma-separated list of terms, each of which is one of:
+//
+//	1.5            a number
+//	25ms           a duration, converted to seconds (0.025)
+//	exp(s,f,n)     n boundaries starting at s, each f times the previous
+//	lin(s,w,n)     n boundaries starting at s, each w more than the previous
+//
+// The start and width of a generator may also be durations. The resulting
+// boundaries must be strictly increasing.
+package bucketflag
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parse parses a bucket specification and returns the boundaries it denotes.
+func Parse(s string) ([]float64, error) {
+	terms, err := splitTerms(s)
+	if err != nil {
+		return nil, err
+	}
+	var out []float64
+	for _, term := range terms {
+		var vals []float64
+		if name, args, ok := cutCall(term); ok {
+			vals, err = generate(name, args)
+		} else {
+			var f float64
+			f, err = parseNumber(term)
+			vals = []float64{f}
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, vals...)
+	}
+	for i := 1; i < len(out); i++ {
+		if out[i] <= out[i-1] {
+			return nil, fmt.Errorf("bucketflag: boundaries not increasing: %v after %v", out[i], out[i-1])
+		}
+	}
+	return out, nil
+}
+
+// MustParse parses a bucket specification, and panics if it is invalid. It
+// is intended for use in defining default values.
+func MustParse(s string) []float64 {
+	b, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// splitTerms splits s at commas that are not enclosed in parentheses.
+func splitTerms(s string) ([]string, error) {
+	var terms []string
+	depth, start := 0, 0
+	for i := 0; i <= len(s); i++ {
+		if i < len(s) {
+			switch s[i] {
+			case '(':
+				depth++
+				continue
+			case ')':
+				if depth--; depth < 0 {
+					return nil, errors.New("bucketflag: unbalanced parentheses")
+				}
+				continue
+			case ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		term := strings.TrimSpace(s[start:i])
+		if term == "" {
+			return nil, errors.New("bucketflag: empty term")
+		}
+		terms = append(terms, term)
+		start = i + 1
+	}
+	if depth != 0 {
+		return nil, errors.New("bucketflag: unbalanced parentheses")
+	}
+	return terms, nil
+}
+
+// cutCall reports whether term has the form name(args), and if so returns
+// the name and the comma-separated arguments.
+func cutCall(term string) (string, []string, bool) {
+	name, rest, ok := strings.Cut(term, "(")
+	if !ok {
+		return "", nil, false
+	}
+	body, ok := strings.CutSuffix(rest, ")")
+	if !ok {
+		return "", nil, false
+	}
+	args := strings.Split(body, ",")
+	for i, a := range args {
+		args[i] = strings.TrimSpace(a)
+	}
+	return strings.ToLower(strings.TrimSpace(name)), args, true
+}
+
+func generate(name string, args []string) ([]float64, error) {
+	if name != "exp" && name != "lin" {
+		return nil, fmt.Errorf("bucketflag: unknown generator %q, want exp or lin", name)
+	}
+	if len(args) != 3 {
+		return nil, fmt.Errorf("bucketflag: %s takes 3 arguments, got %d", name, len(args))
+	}
+	start, err := parseNumber(args[0])
+	if err != nil {
+		return nil, err
+	}
+	step, err := parseNumber(args[1])
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(args[2])
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("bucketflag: invalid count %q", args[2])
+	}
+	out := make([]float64, n)
+	switch name {
+	case "exp":
+		if start <= 0 || step <= 1 {
+			return nil, errors.New("bucketflag: exp requires start > 0 and factor > 1")
+		}
+		for i := range out {
+			out[i] = start * math.Pow(step, float64(i))
+		}
+	case "lin":
+		if step <= 0 {
+			return nil, errors.New("bucketflag: lin requires width > 0")
+		}
+		for i := range out {
+			out[i] = start + step*float64(i)
+		}
+	}
+	return out, nil
+}
+
+// parseNumber parses s as a number or as a duration in seconds.
+func parseNumber(s string) (float64, error) {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, fmt.Errorf("bucketflag: invalid boundary %q", s)
+		}
+		return f, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("bucketflag: invalid boundary %q", s)
+	}
+	return d.Seconds(), nil
+}
+
+// A Value represents a list of histogram bucket boundaries. A pointer to a
+// Value satisfies the flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The boundaries parsed from the flag, in increasing order.
+	Buckets []float64
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	return h + " (list, exp(start,factor,n), or lin(start,width,n))"
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string {
+	parts := make([]string, len(v.Buckets))
+	for i, b := range v.Buckets {
+		parts[i] = strconv.FormatFloat(b, 'g', -1, 64)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	b, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	v.Buckets = b
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type []float64.
+func (v *Value) Get() any { return v.Buckets }
@@ -0,0 +1,60 @@
+package bucketflag
+
+import (
+	"flag"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	buckets := Value{Buckets: MustParse("exp(1ms,2,4)")}
+	fs := flag.NewFlagSet("bucket", flag.ContinueOnError)
+	fs.Var(&buckets, "buckets", buckets.Help("Buckets"))
+
+	if got, want := buckets.String(), "0.001,0.002,0.004,0.008"; got != want {
+		t.Errorf("Initial -buckets: got %q, want %q", got, want)
+	}
+	if err := fs.Parse([]string{"-buckets", "5ms, 10ms,25ms,0.1,lin(1,1,3)"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	want := []float64{0.005, 0.01, 0.025, 0.1, 1, 2, 3}
+	if got := buckets.Get().([]float64); !slices.Equal(got, want) {
+		t.Errorf("Value for -buckets: got %v, want %v", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []float64
+	}{
+		{"1", []float64{1}},
+		{"-1,0,1", []float64{-1, 0, 1}},
+		{"lin(0,10,5)", []float64{0, 10, 20, 30, 40}},
+		{"EXP(1, 10, 3)", []float64{1, 10, 100}},
+		{"lin(0s,500ms,3)", []float64{0, 0.5, 1}},
+		{"0.5,exp(1,2,3),10", []float64{0.5, 1, 2, 4, 10}},
+	}
+	for _, test := range tests {
+		got, err := Parse(test.input)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", test.input, err)
+			continue
+		}
+		if !slices.EqualFunc(got, test.want, func(a, b float64) bool { return math.Abs(a-b) < 1e-12 }) {
+			t.Errorf("Parse(%q): got %v, want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, bad := range []string{
+		"", "1,,2", "2,1", "1,1", "x", "NaN", "exp(0,2,3)", "exp(1,1,3)", "exp(1,2)",
+		"lin(0,0,3)", "lin(0,1,0)", "lin(0,1,x)", "pow(1,2,3)", "exp(1,2,3", "1)", "exp(1,2,3),3",
+	} {
+		if got, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q): got %v, wanted error", bad, got)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Package bytesflag defines a flag.Value implementation for binary data
+// encoded as hexadecimal or base64 text.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/bytesflag"
+//	)
+//
+//	var key = bytesflag.Value{Len: 32}
+//	func init() {
+//	  flag.Var(&key, "key", key.Help("Encryption key"))
+//	}
+//
+// The flag argument may have one of the following forms:
+//
+//	hex:DIGITS   -- hexadecimal digits
+//	b64:TEXT     -- base64, standard or URL-safe alphabet, padding optional
+//	TEXT         -- hexadecimal, or base64 if the Base64 field is true
+package bytesflag
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// A Value represents a string of bytes. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The bytes decoded from the flag.
+	Data []byte
+
+	// If true, an argument without a prefix is decoded as base64 rather than
+	// hexadecimal, and String renders the value in base64.
+	Base64 bool
+
+	// If positive, the decoded value must be exactly this many bytes.
+	Len int
+
+	// If positive, the decoded value must be at least this many bytes.
+	MinLen int
+
+	// If positive, the decoded value must be at most this many bytes.
+	MaxLen int
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	enc := "hex"
+	if v.Base64 {
+		enc = "base64"
+	}
+	if v.Len > 0 {
+		return fmt.Sprintf("%s (%s, %d bytes)", h, enc, v.Len)
+	}
+	return fmt.Sprintf("%s (%s)", h, enc)
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string {
+	if v.Base64 {
+		return base64.StdEncoding.EncodeToString(v.Data)
+	}
+	return hex.EncodeToString(v.Data)
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	var data []byte
+	var err error
+	if t, ok := strings.CutPrefix(s, "hex:"); ok {
+		data, err = decodeHex(t)
+	} else if t, ok := strings.CutPrefix(s, "b64:"); ok {
+		data, err = decodeBase64(t)
+	} else if v.Base64 {
+		data, err = decodeBase64(s)
+	} else {
+		data, err = decodeHex(s)
+	}
+	if err != nil {
+		return err
+	}
+	if err := v.checkLen(len(data)); err != nil {
+		return err
+	}
+	v.Data = data
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type []byte.
+func (v *Value) Get() any { return v.Data }
+
+func (v *Value) checkLen(n int) error {
+	switch {
+	case v.Len > 0 && n != v.Len:
+		return fmt.Errorf("bytesflag: got %d bytes, want %d", n, v.Len)
+	case v.MinLen > 0 && n < v.MinLen:
+		return fmt.Errorf("bytesflag: got %d bytes, want at least %d", n, v.MinLen)
+	case v.MaxLen > 0 && n > v.MaxLen:
+		return fmt.Errorf("bytesflag: got %d bytes, want at most %d", n, v.MaxLen)
+	}
+	return nil
+}
+
+func decodeHex(s string) ([]byte, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("bytesflag: invalid hex: %w", err)
+	}
+	return data, nil
+}
+
+// decodeBase64 decodes s using the standard or URL-safe alphabet, with or
+// without padding.
+func decodeBase64(s string) ([]byte, error) {
+	enc := base64.RawStdEncoding
+	if strings.ContainsAny(s, "-_") {
+		enc = base64.RawURLEncoding
+	}
+	data, err := enc.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, fmt.Errorf("bytesflag: invalid base64: %w", err)
+	}
+	return data, nil
+}
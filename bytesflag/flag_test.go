@@ -0,0 +1,84 @@
+package bytesflag
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	key := Value{Len: 4}
+	nonce := Value{Base64: true}
+
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("bytes", flag.ContinueOnError)
+	fs.Var(&key, "key", key.Help("The key"))
+	fs.Var(&nonce, "nonce", nonce.Help("The nonce"))
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+	t.Logf("Bytes flag set:\n%s", buf.String())
+
+	if err := fs.Parse([]string{"-key", "DEADbeef", "-nonce", "aGVsbG8"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := key.Get().([]byte), []byte{0xde, 0xad, 0xbe, 0xef}; !bytes.Equal(got, want) {
+		t.Errorf("Value for -key: got %x, want %x", got, want)
+	}
+	if got, want := key.String(), "deadbeef"; got != want {
+		t.Errorf("String for -key: got %q, want %q", got, want)
+	}
+	if got, want := nonce.Data, []byte("hello"); !bytes.Equal(got, want) {
+		t.Errorf("Value for -nonce: got %q, want %q", got, want)
+	}
+	if got, want := nonce.String(), "aGVsbG8="; got != want {
+		t.Errorf("String for -nonce: got %q, want %q", got, want)
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		v    Value
+		in   string
+		want string
+	}{
+		{Value{}, "", ""},
+		{Value{}, "68656c6c6f", "hello"},
+		{Value{}, "hex:68656C6C6F", "hello"},
+		{Value{}, "b64:aGVsbG8=", "hello"},
+		{Value{}, "b64:aGVsbG8", "hello"},
+		{Value{}, "b64:-_8", "\xfb\xff"},
+		{Value{Base64: true}, "+/8=", "\xfb\xff"},
+		{Value{Base64: true}, "hex:fbff", "\xfb\xff"},
+		{Value{MinLen: 2, MaxLen: 3}, "aabb", "\xaa\xbb"},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.in); err != nil {
+			t.Errorf("Set(%q) failed: %v", test.in, err)
+		} else if got := string(test.v.Data); got != test.want {
+			t.Errorf("Set(%q): got %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		v  Value
+		in string
+	}{
+		{Value{}, "abc"},
+		{Value{}, "xyzw"},
+		{Value{}, "b64:a$b"},
+		{Value{Base64: true}, "a"},
+		{Value{Len: 2}, "aabbcc"},
+		{Value{Len: 2}, ""},
+		{Value{MinLen: 2}, "aa"},
+		{Value{MaxLen: 2}, "aabbcc"},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.in); err == nil {
+			t.Errorf("Set(%q) with %+v: got %x, wanted error", test.in, test.v, test.v.Data)
+		} else {
+			t.Logf("Set(%q) gave expected error: %v", test.in, err)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Package chanceflag defines a flag.Value implementation for probabilities,
+// with suffixes for common units.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/chanceflag"
+//	)
+//
+//	var faultRate = chanceflag.Value{Max: 0.01}
+//	func init() {
+//	  flag.Var(&faultRate, "fault-rate", faultRate.Help("Probability of injecting a fault"))
+//	}
+//
+// The flag accepts a bare number, which is a probability in [0, 1], a ratio
+// such as "1/1000", or a number with one of these unit suffixes:
+//
+//	%          percent, 1e-2
+//	‰ or pm    per mille, 1e-3
+//	bp or bps  basis points, 1e-4
+//	ppm        parts per million, 1e-6
+//	ppb        parts per billion, 1e-9
+//
+// For example, "0.1%", "1‰", "10bp", "1000ppm", "1/1000", and "1e-3" all
+// denote the same probability. A value outside [0, 1] is an error, so that a
+// mistake such as "-fault-rate 5" (meant as 5%) is reported rather than
+// silently treated as certainty.
+package chanceflag
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// suffixes maps unit suffixes to their scale. Longer suffixes that share a
+// tail with shorter ones must come first.
+var suffixes = []struct {
+	suffix string
+	scale  float64
+}{
+	{"%", 1e-2},
+	{"‰", 1e-3},
+	{"ppm", 1e-6},
+	{"ppb", 1e-9},
+	{"pm", 1e-3},
+	{"bps", 1e-4},
+	{"bp", 1e-4},
+}
+
+// Parse parses a probability in any of the formats accepted by the flag.
+func Parse(s string) (float64, error) {
+	t := strings.TrimSpace(s)
+	p, err := parse(t)
+	if err != nil || math.IsNaN(p) || math.IsInf(p, 0) {
+		return 0, fmt.Errorf("chanceflag: invalid probability %q", s)
+	}
+	if p < 0 || p > 1 {
+		return 0, fmt.Errorf("chanceflag: probability %q is outside the range [0, 1]", s)
+	}
+	return p, nil
+}
+
+func parse(s string) (float64, error) {
+	if num, den, ok := strings.Cut(s, "/"); ok {
+		n, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+		if err != nil {
+			return 0, err
+		}
+		d, err := strconv.ParseFloat(strings.TrimSpace(den), 64)
+		if err != nil || d == 0 {
+			return 0, fmt.Errorf("invalid denominator")
+		}
+		return n / d, nil
+	}
+	lower := strings.ToLower(s)
+	for _, u := range suffixes {
+		if num, ok := strings.CutSuffix(lower, u.suffix); ok {
+			f, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+			if err != nil {
+				return 0, err
+			}
+			return f * u.scale, nil
+		}
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// A Value represents a probability. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The probability parsed from the flag, in [0, 1].
+	P float64
+
+	// If Max > Min, the probability must lie in the closed interval
+	// [Min, Max], which should be a subset of [0, 1]. Otherwise any
+	// probability in [0, 1] is accepted.
+	Min, Max float64
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	lo, hi := v.bounds()
+	return fmt.Sprintf("%s (probability %s-%s; e.g., 0.1%%, 50bp, 1/1000)", h, format(lo), format(hi))
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return format(v.P) }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	p, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	if lo, hi := v.bounds(); p < lo || p > hi {
+		return fmt.Errorf("chanceflag: %q is outside the range %s-%s", s, format(lo), format(hi))
+	}
+	v.P = p
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is the float64 probability.
+func (v *Value) Get() any { return v.P }
+
+func (v *Value) bounds() (lo, hi float64) {
+	if v.Max > v.Min {
+		return v.Min, v.Max
+	}
+	return 0, 1
+}
+
+func format(p float64) string { return strconv.FormatFloat(p, 'g', -1, 64) }
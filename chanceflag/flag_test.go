@@ -0,0 +1,83 @@
+package chanceflag
+
+import (
+	"flag"
+	"math"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	rate := Value{Max: 0.01}
+	fs := flag.NewFlagSet("chance", flag.ContinueOnError)
+	fs.Var(&rate, "rate", rate.Help("Fault rate"))
+
+	if got, want := rate.String(), "0"; got != want {
+		t.Errorf("Initial -rate: got %q, want %q", got, want)
+	}
+	if err := fs.Parse([]string{"-rate", "50bp"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := rate.Get().(float64), 0.005; got != want {
+		t.Errorf("Value for -rate: got %v, want %v", got, want)
+	}
+	if got, want := rate.String(), "0.005"; got != want {
+		t.Errorf("String for -rate: got %q, want %q", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float64
+	}{
+		{"0", 0},
+		{"1", 1},
+		{"0.25", 0.25},
+		{"1e-6", 1e-6},
+		{"0.1%", 0.001},
+		{"100%", 1},
+		{"1‰", 0.001},
+		{"5pm", 0.005},
+		{"10bp", 0.001},
+		{"10 bps", 0.001},
+		{"1000ppm", 0.001},
+		{"3ppb", 3e-9},
+		{"1/1000", 0.001},
+		{" 3 / 4 ", 0.75},
+		{"50BP", 0.005},
+	}
+	for _, test := range tests {
+		got, err := Parse(test.input)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", test.input, err)
+		} else if math.Abs(got-test.want) > 1e-15 {
+			t.Errorf("Parse(%q): got %v, want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		v     Value
+		input string
+	}{
+		{Value{}, ""},
+		{Value{}, "5"},
+		{Value{}, "-0.1"},
+		{Value{}, "101%"},
+		{Value{}, "NaN"},
+		{Value{}, "inf"},
+		{Value{}, "1/0"},
+		{Value{}, "3/2"},
+		{Value{}, "1/x"},
+		{Value{}, "5 percent"},
+		{Value{}, "%"},
+		{Value{Max: 0.01}, "2%"},
+		{Value{Min: 0.1, Max: 0.2}, "0.05"},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.input); err == nil {
+			t.Errorf("Set(%q) with %+v: got %v, wanted error", test.input, test.v, test.v.P)
+		}
+	}
+}
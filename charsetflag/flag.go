@@ -0,0 +1,202 @@
+// Package charsetflag defines a flag.Value implementation for sets of
+// allowed characters written in character-class notation.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/charsetflag"
+//	)
+//
+//	var alphabet = charsetflag.Value{Class: charsetflag.MustParse("a-zA-Z0-9")}
+//	func init() {
+//	  flag.Var(&alphabet, "alphabet", alphabet.Help("Password characters"))
+//	}
+//
+//	  ...
+//	  if !alphabet.Class.Valid(name) { ... }
+//	  runes := alphabet.Class.Runes() // for generating passwords
+//
+// The notation is that of the body of a regular expression bracket
+// expression, without the brackets: single characters and ranges "a-z". A
+// "-" at the beginning or end of the class is literal. A backslash escapes
+// the following character, so "\-" is a literal "-" and "\\" a backslash.
+package charsetflag
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// A Class is a set of characters. The zero value is empty.
+type Class struct {
+	ranges []span // sorted, disjoint, and non-adjacent
+}
+
+type span struct{ lo, hi rune }
+
+// Parse parses a character class.
+func Parse(s string) (Class, error) {
+	if s == "" {
+		return Class{}, errors.New("charsetflag: empty class")
+	}
+	if !utf8.ValidString(s) {
+		return Class{}, errors.New("charsetflag: invalid UTF-8 in class")
+	}
+	rs := []rune(s)
+	var spans []span
+	next := func(i int) (rune, int, error) {
+		if rs[i] != '\\' {
+			return rs[i], i + 1, nil
+		}
+		if i+1 == len(rs) {
+			return 0, 0, errors.New("charsetflag: trailing backslash")
+		}
+		return rs[i+1], i + 2, nil
+	}
+	for i := 0; i < len(rs); {
+		lo, j, err := next(i)
+		if err != nil {
+			return Class{}, err
+		}
+		hi := lo
+		if j+1 < len(rs) && rs[j] == '-' {
+			if hi, j, err = next(j + 1); err != nil {
+				return Class{}, err
+			}
+			if hi < lo {
+				return Class{}, fmt.Errorf("charsetflag: invalid range %q", string(rs[i:j]))
+			}
+		}
+		spans = append(spans, span{lo, hi})
+		i = j
+	}
+	return Class{ranges: merge(spans)}, nil
+}
+
+// MustParse parses a character class, and panics if it is invalid. It is
+// intended for use in defining default values.
+func MustParse(s string) Class {
+	c, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func merge(spans []span) []span {
+	slices.SortFunc(spans, func(a, b span) int { return int(a.lo - b.lo) })
+	var out []span
+	for _, s := range spans {
+		if n := len(out); n > 0 && s.lo <= out[n-1].hi+1 {
+			out[n-1].hi = max(out[n-1].hi, s.hi)
+		} else {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Contains reports whether r is in c.
+func (c Class) Contains(r rune) bool {
+	_, found := slices.BinarySearchFunc(c.ranges, r, func(s span, r rune) int {
+		if s.hi < r {
+			return -1
+		} else if s.lo > r {
+			return 1
+		}
+		return 0
+	})
+	return found
+}
+
+// Valid reports whether every character of s is in c.
+func (c Class) Valid(s string) bool { return c.Check(s) == nil }
+
+// Check reports an error describing the first character of s that is not in
+// c, or nil if there is none.
+func (c Class) Check(s string) error {
+	for i, r := range s {
+		if !c.Contains(r) {
+			return fmt.Errorf("character %q at offset %d is not allowed", r, i)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of characters in c.
+func (c Class) Len() int {
+	n := 0
+	for _, s := range c.ranges {
+		n += int(s.hi-s.lo) + 1
+	}
+	return n
+}
+
+// Runes returns the characters of c in increasing order.
+func (c Class) Runes() []rune {
+	out := make([]rune, 0, c.Len())
+	for _, s := range c.ranges {
+		for r := s.lo; r <= s.hi; r++ {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// String returns c in the notation accepted by Parse.
+func (c Class) String() string {
+	var sb strings.Builder
+	for _, s := range c.ranges {
+		writeRune(&sb, s.lo)
+		if s.hi > s.lo+1 {
+			sb.WriteByte('-')
+		}
+		if s.hi > s.lo {
+			writeRune(&sb, s.hi)
+		}
+	}
+	return sb.String()
+}
+
+func writeRune(sb *strings.Builder, r rune) {
+	if r == '-' || r == '\\' {
+		sb.WriteByte('\\')
+	}
+	sb.WriteRune(r)
+}
+
+// A Value represents a character class. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The class parsed from the flag.
+	Class Class
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string { return h + " (character class, e.g., a-z0-9_-)" }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return v.Class.String() }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	c, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	v.Class = c
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Class.
+func (v *Value) Get() any { return v.Class }
@@ -0,0 +1,74 @@
+package charsetflag
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	alphabet := Value{Class: MustParse("a-z")}
+	fs := flag.NewFlagSet("charset", flag.ContinueOnError)
+	fs.Var(&alphabet, "alphabet", alphabet.Help("Alphabet"))
+
+	if err := fs.Parse([]string{"-alphabet", "a-z0-9_-"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	c := alphabet.Get().(Class)
+	if !c.Valid("hello_world-42") {
+		t.Error("Valid(hello_world-42): got false, want true")
+	}
+	if err := c.Check("Hello"); err == nil {
+		t.Error("Check(Hello): got nil, want error")
+	} else {
+		t.Logf("Check(Hello) gave expected error: %v", err)
+	}
+	if got := c.Len(); got != 26+10+2 {
+		t.Errorf("Len: got %d, want 38", got)
+	}
+	if got, want := alphabet.String(), `"\\-0-9_a-z"`; got != want {
+		t.Errorf("String: got %s, want %s", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // canonical form
+		runes string
+	}{
+		{"abc", "a-c", "abc"},
+		{"cba", "a-c", "abc"},
+		{"ab", "ab", "ab"},
+		{"-a", `\-a`, "-a"},
+		{"a-", `\-a`, "-a"},
+		{`a\-z`, `\-az`, "-az"},
+		{`\\`, `\\`, `\`},
+		{"a-cb-e", "a-e", "abcde"},
+		{"x-x", "x", "x"},
+		{"α-γ", "α-γ", "αβγ"},
+	}
+	for _, test := range tests {
+		c, err := Parse(test.input)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", test.input, err)
+			continue
+		}
+		if got := c.String(); got != test.want {
+			t.Errorf("Parse(%q): got %q, want %q", test.input, got, test.want)
+		}
+		if got := string(c.Runes()); got != test.runes {
+			t.Errorf("Parse(%q).Runes: got %q, want %q", test.input, got, test.runes)
+		}
+		if rt, err := Parse(c.String()); err != nil || rt.String() != c.String() {
+			t.Errorf("Parse(%q) did not round-trip: got %q, %v", c.String(), rt, err)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, bad := range []string{"", "z-a", `ab\`, "\xff"} {
+		if c, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q): got %q, wanted error", bad, c)
+		}
+	}
+}
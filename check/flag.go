@@ -0,0 +1,213 @@
+// Package check defines a flag.Value wrapper that validates the values of
+// another flag, using composable checks.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/check"
+//	  "github.com/creachadair/goflags/sizeflag"
+//	  "github.com/creachadair/goflags/urlflag"
+//	)
+//
+//	var cache = check.Wrap(sizeflag.Base2(64<<20), check.Range(1<<20, 1<<30))
+//	var server = check.Wrap(new(urlflag.Value), check.NonEmpty)
+//	func init() {
+//	  flag.Var(cache, "cache-size", "Cache size in bytes")
+//	  flag.Var(server, "server", "Server base URL")
+//	}
+//
+// The checks are applied by Set, so a value that fails a check is reported by
+// flag.Parse as an invalid value for the flag. Default values are not passed
+// through Set; use CheckAll to validate them.
+package check
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/creachadair/goflags"
+)
+
+// A Check validates the concrete value of a flag, as reported by the Get
+// method of a flag.Getter, reporting an error if the value is not valid.
+type Check func(v any) error
+
+// Range returns a Check that the value lies in the closed interval [lo, hi].
+// A numeric value of a different type than T is accepted if it converts to T
+// without loss, so that Range(1, 10) can check a value of type int64.
+func Range[T cmp.Ordered](lo, hi T) Check {
+	return func(v any) error {
+		x, err := convert[T](v)
+		if err != nil {
+			return err
+		}
+		if x < lo || x > hi {
+			return fmt.Errorf("check: %v is outside the range [%v, %v]", x, lo, hi)
+		}
+		return nil
+	}
+}
+
+// OneOf returns a Check that the value is equal to one of vals.
+func OneOf[T comparable](vals ...T) Check {
+	return func(v any) error {
+		x, err := convert[T](v)
+		if err != nil {
+			return err
+		}
+		for _, want := range vals {
+			if x == want {
+				return nil
+			}
+		}
+		names := make([]string, len(vals))
+		for i, want := range vals {
+			names[i] = fmt.Sprint(want)
+		}
+		return fmt.Errorf("check: got %v, expected one of (%s)", x, strings.Join(names, "|"))
+	}
+}
+
+// NonEmpty is a Check that the value is not empty. A value is empty if it is
+// nil, a nil pointer or interface, or a string, slice, map, or array of
+// length zero.
+func NonEmpty(v any) error {
+	if v == nil {
+		return errEmpty
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		if rv.Len() == 0 {
+			return errEmpty
+		}
+	case reflect.Pointer, reflect.Interface, reflect.Func, reflect.Chan:
+		if rv.IsNil() {
+			return errEmpty
+		}
+	}
+	return nil
+}
+
+var errEmpty = errors.New("check: value must not be empty")
+
+// All returns a Check that the value passes each of checks, in order. It
+// reports the error from the first check that fails. Nil checks are ignored.
+func All(checks ...Check) Check {
+	return func(v any) error {
+		for _, c := range checks {
+			if c == nil {
+				continue
+			}
+			if err := c(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Func returns a Check that calls f with the value, which must have type T.
+// The error reported by f, if any, is reported by the Check.
+func Func[T any](f func(T) error) Check {
+	return func(v any) error {
+		x, ok := v.(T)
+		if !ok {
+			return fmt.Errorf("check: value has type %T, expected %v", v, reflect.TypeFor[T]())
+		}
+		return f(x)
+	}
+}
+
+// convert returns v as a T, converting between numeric types if the
+// conversion does not lose information.
+func convert[T any](v any) (T, error) {
+	if x, ok := v.(T); ok {
+		return x, nil
+	}
+	var zero T
+	rv, rt := reflect.ValueOf(v), reflect.TypeFor[T]()
+	if rv.IsValid() && isNumber(rv.Kind()) && isNumber(rt.Kind()) {
+		if cv := rv.Convert(rt); cv.Convert(rv.Type()).Equal(rv) {
+			return cv.Interface().(T), nil
+		}
+		return zero, fmt.Errorf("check: %v is not representable as %v", v, rt)
+	}
+	return zero, fmt.Errorf("check: value has type %T, expected %v", v, rt)
+}
+
+func isNumber(k reflect.Kind) bool {
+	return reflect.Int <= k && k <= reflect.Float64
+}
+
+// A Value wraps a flag.Getter whose values are validated by a Check. A *Value
+// satisfies the flag.Value and flag.Getter interfaces.
+type Value struct {
+	g     flag.Getter
+	check Check
+}
+
+// Wrap returns a *Value that wraps g, and validates each value set by the
+// combination of checks, as by All.
+func Wrap(g flag.Getter, checks ...Check) *Value {
+	return &Value{g: g, check: All(checks...)}
+}
+
+// Check reports whether the current value of v passes its checks.
+func (v *Value) Check() error { return v.check(v.g.Get()) }
+
+// Unwrap returns the wrapped flag.Value.
+func (v *Value) Unwrap() flag.Value { return v.g }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string {
+	if v == nil || v.g == nil {
+		return "" // the zero value, as used by flag.PrintDefaults
+	}
+	return v.g.String()
+}
+
+// Set satisfies part of the flag.Value interface. It sets the wrapped value,
+// then reports an error if the new value fails the checks of v. After a
+// failed check, the previous value is restored, as by goflags.Snapshot.
+func (v *Value) Set(s string) error {
+	restore := goflags.Snapshot(v.g)
+	if err := v.g.Set(s); err != nil {
+		return err
+	}
+	if err := v.Check(); err != nil {
+		restore()
+		return err
+	}
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is the result of Get on the wrapped value.
+func (v *Value) Get() any { return v.g.Get() }
+
+// IsBoolFlag reports whether the wrapped value is a boolean flag.
+func (v *Value) IsBoolFlag() bool {
+	b, ok := v.g.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// CheckAll reports an error for each flag in fs whose value is a *Value and
+// does not pass its checks, such as an invalid default. The errors are joined
+// with errors.Join, in lexicographical order by flag name.
+func CheckAll(fs *flag.FlagSet) error {
+	var errs []error
+	fs.VisitAll(func(f *flag.Flag) {
+		if v, ok := f.Value.(*Value); ok {
+			if err := v.Check(); err != nil {
+				errs = append(errs, fmt.Errorf("flag -%s: %w", f.Name, err))
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
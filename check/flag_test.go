@@ -0,0 +1,134 @@
+package check
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/creachadair/goflags/enumflag"
+	"github.com/creachadair/goflags/sizeflag"
+	"github.com/creachadair/goflags/urlflag"
+)
+
+func TestFlagBits(t *testing.T) {
+	size := Wrap(sizeflag.Base2(1024), Range(1024, 1<<20))
+	color := Wrap(enumflag.New("red", "green", "blue"), OneOf("red", "blue"))
+	server := Wrap(new(urlflag.Value), NonEmpty)
+
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(size, "size", "Size")
+	fs.Var(color, "color", "Color")
+	fs.Var(server, "server", "Server")
+
+	if err := fs.Parse([]string{"-size", "64K", "-color", "blue"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := size.Get().(int), 64<<10; got != want {
+		t.Errorf("Value for -size: got %v, want %v", got, want)
+	}
+	if got, want := color.Get().(string), "blue"; got != want {
+		t.Errorf("Value for -color: got %q, want %q", got, want)
+	}
+
+	err := fs.Parse([]string{"-size", "2M"})
+	if err == nil || !strings.Contains(err.Error(), "-size") {
+		t.Errorf("Parse -size 2M: got %v, wanted error naming the flag", err)
+	} else {
+		t.Logf("Parse -size 2M gave expected error: %v", err)
+	}
+	if err := fs.Parse([]string{"-color", "green"}); err == nil {
+		t.Error("Parse -color green: got nil, wanted error")
+	}
+
+	// The rejected values did not replace the previous ones.
+	if got, want := size.Get().(int), 64<<10; got != want {
+		t.Errorf("Value for -size after error: got %v, want %v", got, want)
+	}
+	if got, want := color.Get().(string), "blue"; got != want {
+		t.Errorf("Value for -color after error: got %q, want %q", got, want)
+	}
+
+	err = CheckAll(fs)
+	if err == nil || !strings.Contains(err.Error(), "flag -server:") || strings.Contains(err.Error(), "flag -size:") {
+		t.Errorf("CheckAll: got %v, wanted an error for -server only", err)
+	} else {
+		t.Logf("CheckAll gave expected error: %v", err)
+	}
+	if err := server.Set("https://example.com"); err != nil {
+		t.Errorf("Set -server: unexpected error: %v", err)
+	}
+	if err := size.Set("1K"); err != nil {
+		t.Errorf("Set -size: unexpected error: %v", err)
+	}
+	if err := color.Set("red"); err != nil {
+		t.Errorf("Set -color: unexpected error: %v", err)
+	}
+	if err := CheckAll(fs); err != nil {
+		t.Errorf("CheckAll: unexpected error: %v", err)
+	}
+}
+
+func TestChecks(t *testing.T) {
+	errOdd := errors.New("odd")
+	even := Func(func(n int) error {
+		if n%2 != 0 {
+			return errOdd
+		}
+		return nil
+	})
+	tests := []struct {
+		name  string
+		check Check
+		value any
+		ok    bool
+	}{
+		{"Range in", Range(1, 10), 5, true},
+		{"Range lo", Range(1, 10), 1, true},
+		{"Range hi", Range(1, 10), 10, true},
+		{"Range below", Range(1, 10), 0, false},
+		{"Range above", Range(1, 10), 11, false},
+		{"Range int64", Range(1, 10), int64(3), true},
+		{"Range uint8", Range(1, 10), uint8(30), false},
+		{"Range float", Range(0.0, 1.0), 0.5, true},
+		{"Range int for float", Range(0.0, 1.0), 1, true},
+		{"Range lossy", Range(1, 10), 2.5, false},
+		{"Range overflow", Range[int8](1, 10), 300, false},
+		{"Range string", Range("a", "m"), "fig", true},
+		{"Range type", Range(1, 10), "5", false},
+		{"Range duration", Range(time.Second, time.Minute), 5 * time.Second, true},
+		{"Range duration below", Range(time.Second, time.Minute), time.Millisecond, false},
+		{"OneOf", OneOf("a", "b"), "b", true},
+		{"OneOf miss", OneOf("a", "b"), "c", false},
+		{"OneOf int", OneOf(80, 443), int64(443), true},
+		{"OneOf nil", OneOf("a"), nil, false},
+		{"NonEmpty string", NonEmpty, "x", true},
+		{"NonEmpty empty", NonEmpty, "", false},
+		{"NonEmpty nil", NonEmpty, nil, false},
+		{"NonEmpty slice", NonEmpty, []string{}, false},
+		{"NonEmpty map", NonEmpty, map[string]int{"a": 1}, true},
+		{"NonEmpty pointer", NonEmpty, (*int)(nil), false},
+		{"NonEmpty zero int", NonEmpty, 0, true},
+		{"Func", even, 4, true},
+		{"Func error", even, 3, false},
+		{"Func type", even, "4", false},
+		{"All", All(Range(1, 10), even, nil), 4, true},
+		{"All first", All(Range(1, 10), even), 12, false},
+		{"All second", All(Range(1, 10), even), 5, false},
+		{"All empty", All(), "anything", true},
+	}
+	for _, test := range tests {
+		err := test.check(test.value)
+		if test.ok && err != nil {
+			t.Errorf("%s(%v): unexpected error: %v", test.name, test.value, err)
+		} else if !test.ok && err == nil {
+			t.Errorf("%s(%v): got nil, wanted error", test.name, test.value)
+		}
+	}
+	if err := even(3); !errors.Is(err, errOdd) {
+		t.Errorf("Func error: got %v, want %v", err, errOdd)
+	}
+}
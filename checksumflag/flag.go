@@ -0,0 +1,153 @@
+// Package checksumflag defines a flag.Value implementation for cryptographic
+// digests written with an algorithm prefix, such as "sha256:e3b0c442...".
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/checksumflag"
+//	)
+//
+//	var want = checksumflag.Value{Allowed: []string{"sha256", "sha512"}}
+//	func init() {
+//	  flag.Var(&want, "checksum", want.Help("Expected checksum of the download"))
+//	}
+//
+//	  ...
+//	  if err := want.Verify(resp.Body); err != nil { ... }
+//
+// The digest is written in hexadecimal, without regard to case, and its length
+// must match the output size of the algorithm.
+package checksumflag
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"slices"
+	"strings"
+
+	// Register the supported hash functions.
+	_ "crypto/md5"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+// algorithms maps the supported algorithm names to their hash functions.
+var algorithms = map[string]crypto.Hash{
+	"md5":        crypto.MD5,
+	"sha1":       crypto.SHA1,
+	"sha224":     crypto.SHA224,
+	"sha256":     crypto.SHA256,
+	"sha384":     crypto.SHA384,
+	"sha512":     crypto.SHA512,
+	"sha512_224": crypto.SHA512_224,
+	"sha512_256": crypto.SHA512_256,
+}
+
+// Algorithms returns the names of the supported algorithms, in sorted order.
+func Algorithms() []string {
+	names := make([]string, 0, len(algorithms))
+	for name := range algorithms {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// A Value represents a digest computed by a named hash algorithm. A pointer
+// to a Value satisfies the flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The name of the algorithm parsed from the flag, e.g., "sha256".
+	Algorithm string
+
+	// The digest parsed from the flag.
+	Digest []byte
+
+	// If non-empty, the algorithm must be one of these. Otherwise any of the
+	// algorithms reported by Algorithms is accepted.
+	Allowed []string
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	algs := v.Allowed
+	if len(algs) == 0 {
+		algs = Algorithms()
+	}
+	return fmt.Sprintf("%s (algorithm:hex, algorithm is %s)", h, strings.Join(algs, "|"))
+}
+
+// Hash returns a new hash.Hash for the algorithm of v, or nil if v is not set.
+func (v *Value) Hash() hash.Hash {
+	h, ok := algorithms[v.Algorithm]
+	if !ok {
+		return nil
+	}
+	return h.New()
+}
+
+// Verify reads r to completion and reports an error if the digest of its
+// contents does not match v.
+func (v *Value) Verify(r io.Reader) error {
+	h := v.Hash()
+	if h == nil {
+		return errors.New("checksumflag: no checksum specified")
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return err
+	}
+	if got := h.Sum(nil); !bytes.Equal(got, v.Digest) {
+		return fmt.Errorf("checksumflag: %s mismatch: got %x, want %x", v.Algorithm, got, v.Digest)
+	}
+	return nil
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string {
+	if v.Algorithm == "" {
+		return ""
+	}
+	return v.Algorithm + ":" + hex.EncodeToString(v.Digest)
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	alg, digest, ok := strings.Cut(s, ":")
+	if !ok {
+		return fmt.Errorf("checksumflag: missing algorithm in %q", s)
+	}
+	alg = strings.ToLower(alg)
+	h, ok := algorithms[alg]
+	if !ok || (len(v.Allowed) != 0 && !slices.Contains(v.Allowed, alg)) {
+		return fmt.Errorf("checksumflag: unsupported algorithm %q", alg)
+	}
+	d, err := hex.DecodeString(digest)
+	if err != nil {
+		return fmt.Errorf("checksumflag: invalid digest: %w", err)
+	}
+	if len(d) != h.Size() {
+		return fmt.Errorf("checksumflag: %s digest has %d bytes, want %d", alg, len(d), h.Size())
+	}
+	v.Algorithm, v.Digest = alg, d
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is the string of the checksum in "algorithm:hex" form.
+func (v *Value) Get() any {
+	if v.Algorithm == "" {
+		return ""
+	}
+	return v.Algorithm + ":" + hex.EncodeToString(v.Digest)
+}
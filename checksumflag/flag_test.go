@@ -0,0 +1,73 @@
+package checksumflag
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+)
+
+const (
+	emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	helloMD5    = "5d41402abc4b2a76b9719d911017c592"
+)
+
+func TestFlagBits(t *testing.T) {
+	want := Value{Allowed: []string{"sha256", "sha512"}}
+
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("checksum", flag.ContinueOnError)
+	fs.Var(&want, "checksum", want.Help("Expected checksum"))
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+	t.Logf("Checksum flag set:\n%s", buf.String())
+
+	if err := want.Verify(strings.NewReader("")); err == nil {
+		t.Error("Verify with no checksum: got nil, wanted error")
+	}
+	if err := fs.Parse([]string{"-checksum", "SHA256:" + strings.ToUpper(emptySHA256)}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := want.Get().(string), "sha256:"+emptySHA256; got != want {
+		t.Errorf("Value for -checksum: got %q, want %q", got, want)
+	}
+	if err := want.Verify(strings.NewReader("")); err != nil {
+		t.Errorf("Verify empty input failed: %v", err)
+	}
+	if err := want.Verify(strings.NewReader("x")); err == nil {
+		t.Error("Verify wrong input: got nil, wanted error")
+	} else {
+		t.Logf("Verify wrong input gave expected error: %v", err)
+	}
+
+	if err := fs.Parse([]string{"-checksum", "md5:" + helloMD5}); err == nil {
+		t.Error("Parse disallowed algorithm: got nil, wanted error")
+	}
+}
+
+func TestSet(t *testing.T) {
+	var v Value
+	if err := v.Set("md5:" + helloMD5); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := v.Verify(strings.NewReader("hello")); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+
+	for _, alg := range Algorithms() {
+		h := (&Value{Algorithm: alg}).Hash()
+		digest := strings.Repeat("00", h.Size())
+		if err := v.Set(alg + ":" + digest); err != nil {
+			t.Errorf("Set(%s) failed: %v", alg, err)
+		}
+	}
+
+	for _, bad := range []string{
+		"", emptySHA256, "sha256", "sha256:", "sha256:" + emptySHA256[2:],
+		"sha256:" + emptySHA256 + "00", "sha256:xyz", "crc32:00000000",
+	} {
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %s, wanted error", bad, v.String())
+		}
+	}
+}
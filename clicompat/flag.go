@@ -0,0 +1,152 @@
+// Package clicompat adapts flags between the standard flag package and the
+// github.com/urfave/cli package, so that programs using cli can use the flag
+// values of this module.
+//
+// The values of this module satisfy the cli.Generic interface, and a Flag has
+// the fields of a cli.GenericFlag, so this package does not depend on cli.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/clicompat"
+//	  "github.com/urfave/cli/v2"
+//	)
+//
+//	func cliFlags(fs *flag.FlagSet) []cli.Flag {
+//	  var out []cli.Flag
+//	  for _, f := range clicompat.Flags(fs, "MYAPP") {
+//	    out = append(out, &cli.GenericFlag{
+//	      Name: f.Name, Aliases: f.Aliases, Usage: f.Usage,
+//	      EnvVars: f.EnvVars, DefaultText: f.DefaultText, Value: f.Value,
+//	    })
+//	  }
+//	  return out
+//	}
+//
+// In the other direction, Define defines the flags described by Flag values,
+// such as those converted from cli.GenericFlag, in a flag set, and ParseEnv
+// sets them from their environment variables.
+package clicompat
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/creachadair/goflags"
+	"github.com/creachadair/goflags/aliasflag"
+	"github.com/creachadair/goflags/defaultflag"
+)
+
+// Generic is the cli.Generic interface, which every flag.Value satisfies.
+type Generic interface {
+	Set(string) error
+	String() string
+}
+
+// A Flag describes a flag, with the fields of a cli.GenericFlag.
+type Flag struct {
+	Name        string
+	Aliases     []string // other names for the flag
+	Usage       string
+	EnvVars     []string // environment variables to set the flag from, in order
+	DefaultText string   // the default value, as shown in help text
+	Value       Generic
+}
+
+// Flags returns a description of each flag in fs, in lexicographical order by
+// name. The aliases of a flag are the aliases defined for it by aliasflag.Var;
+// aliases and deprecated flags (see goflags.Deprecate) are not reported
+// separately. The usage of a flag includes the summary of accepted values
+// reported by the Help method of its value, if any. If envPrefix is not
+// empty, the EnvVars of each flag has the variable named by goflags.EnvName,
+// as used by goflags.ParseEnv.
+func Flags(fs *flag.FlagSet, envPrefix string) []Flag {
+	aliases := make(map[string][]string)
+	fs.VisitAll(func(f *flag.Flag) {
+		if aliasflag.IsAlias(f) {
+			name, _ := aliasflag.Canonical(fs, f.Name)
+			aliases[name] = append(aliases[name], f.Name)
+		}
+	})
+
+	var out []Flag
+	for _, fi := range goflags.Describe(fs) {
+		f := fs.Lookup(fi.Name)
+		if fi.Deprecated || aliasflag.IsAlias(f) {
+			continue
+		}
+		usage := fi.Usage
+		if fi.Summary != "" && !strings.Contains(usage, fi.Summary) {
+			usage = strings.TrimSpace(usage + " " + fi.Summary)
+		}
+		cf := Flag{
+			Name:        fi.Name,
+			Aliases:     aliases[fi.Name],
+			Usage:       usage,
+			DefaultText: fi.Default,
+			Value:       f.Value,
+		}
+		if envPrefix != "" {
+			cf.EnvVars = []string{goflags.EnvName(envPrefix, fi.Name)}
+		}
+		out = append(out, cf)
+	}
+	return out
+}
+
+// Define defines a flag in fs for each of flags, with the given name, usage,
+// and value, and with each of the aliases as another name for the same value.
+// Unlike the aliases defined by aliasflag.Var, using an alias does not print
+// a warning. If the DefaultText of a flag is not empty, it is used as the
+// default shown in help text. Define panics if a name is already defined in
+// fs, or if the Value of a flag is nil.
+func Define(fs *flag.FlagSet, flags []Flag) {
+	for _, cf := range flags {
+		if cf.Value == nil {
+			panic(fmt.Sprintf("clicompat: flag -%s has no value", cf.Name))
+		}
+		g := aliasflag.Var(fs, cf.Value, cf.Name, cf.Usage, cf.Aliases...)
+		g.Warn = nil
+		if cf.DefaultText != "" {
+			fs.Lookup(cf.Name).DefValue = cf.DefaultText
+		}
+	}
+}
+
+// ParseEnv sets each of flags that was not set on the command line from the
+// first of its EnvVars that is set in the environment, and returns a report
+// of the flags set, in order. It should be called after fs.Parse, and works
+// as goflags.ParseEnv does, except for the names of the variables.
+func ParseEnv(fs *flag.FlagSet, flags []Flag) ([]goflags.EnvSetting, error) {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		name, _ := aliasflag.Canonical(fs, f.Name)
+		set[name] = true
+	})
+
+	var out []goflags.EnvSetting
+	for _, cf := range flags {
+		if set[cf.Name] {
+			continue
+		}
+		for _, name := range cf.EnvVars {
+			s, ok := os.LookupEnv(name)
+			if !ok {
+				continue
+			}
+			if err := fs.Set(cf.Name, s); err != nil {
+				return out, fmt.Errorf("clicompat: invalid value %q for flag -%s from $%s: %w", s, cf.Name, name, err)
+			}
+			if dv, ok := fs.Lookup(cf.Name).Value.(*defaultflag.Value); ok {
+				dv.SetSource(defaultflag.Environment, name)
+			}
+			out = append(out, goflags.EnvSetting{Flag: cf.Name, Var: name})
+			break
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,105 @@
+package clicompat
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/creachadair/goflags"
+	"github.com/creachadair/goflags/aliasflag"
+	"github.com/creachadair/goflags/defaultflag"
+	"github.com/creachadair/goflags/enumflag"
+	"github.com/creachadair/goflags/sizeflag"
+)
+
+func TestFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	color := enumflag.New("red", "blue")
+	size := sizeflag.Base2(1024)
+	fs.Var(color, "color", "Output color")
+	aliasflag.Var(fs, size, "cache-size", "Cache size", "c")
+	fs.String("old-name", "", "Old name")
+	goflags.Deprecate(fs, "old-color", "color", "")
+
+	got := Flags(fs, "APP")
+	want := []Flag{{
+		Name:        "cache-size",
+		Aliases:     []string{"c"},
+		Usage:       "Cache size",
+		EnvVars:     []string{"APP_CACHE_SIZE"},
+		DefaultText: size.String(),
+		Value:       size,
+	}, {
+		Name:        "color",
+		Usage:       "Output color (red|blue)",
+		EnvVars:     []string{"APP_COLOR"},
+		DefaultText: `"red"`,
+		Value:       color,
+	}, {
+		Name:    "old-name",
+		Usage:   "Old name",
+		EnvVars: []string{"APP_OLD_NAME"},
+		Value:   fs.Lookup("old-name").Value,
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Flags:\n got %+v\nwant %+v", got, want)
+	}
+
+	if got := Flags(fs, ""); got[0].EnvVars != nil {
+		t.Errorf("Flags without prefix: got EnvVars %q, want none", got[0].EnvVars)
+	}
+}
+
+func TestDefine(t *testing.T) {
+	color := enumflag.New("red", "blue")
+	size := defaultflag.Wrap(sizeflag.Base2(0))
+	flags := []Flag{{
+		Name:    "color",
+		Aliases: []string{"c"},
+		Usage:   "Output color",
+		EnvVars: []string{"TEST_COLOR", "COLOR"},
+		Value:   color,
+	}, {
+		Name:        "size",
+		Usage:       "Size",
+		EnvVars:     []string{"TEST_SIZE"},
+		DefaultText: "none",
+		Value:       size,
+	}}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	Define(fs, flags)
+	if got := fs.Lookup("size").DefValue; got != "none" {
+		t.Errorf("Default of -size: got %q, want none", got)
+	}
+
+	t.Setenv("COLOR", "blue")
+	t.Setenv("TEST_SIZE", "2K")
+	if err := fs.Parse([]string{"-c", "red"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	env, err := ParseEnv(fs, flags)
+	if err != nil {
+		t.Fatalf("ParseEnv: unexpected error: %v", err)
+	}
+	if want := []goflags.EnvSetting{{Flag: "size", Var: "TEST_SIZE"}}; !reflect.DeepEqual(env, want) {
+		t.Errorf("ParseEnv: got %+v, want %+v", env, want)
+	}
+	if got := color.Key(); got != "red" {
+		t.Errorf("Value of -color: got %q, want red (from the command line)", got)
+	}
+	if got := size.Get(); got != 2048 || size.Source() != defaultflag.Environment {
+		t.Errorf("Value of -size: got %v from %v, want 2048 from env", got, size.Source())
+	}
+
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	color, size = enumflag.New("red", "blue"), defaultflag.Wrap(sizeflag.Base2(0))
+	flags[0].Value, flags[1].Value = color, size
+	Define(fs, flags)
+	t.Setenv("TEST_COLOR", "green")
+	if _, err := ParseEnv(fs, flags); err == nil {
+		t.Error("ParseEnv with an invalid value: got nil, want error")
+	}
+}
@@ -0,0 +1,174 @@
+// Package colorflag defines a flag.Value implementation for colors written in
+// CSS notation.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/colorflag"
+//	)
+//
+//	var fill = colorflag.MustParse("steelblue")
+//	func init() {
+//	  flag.Var(&fill, "fill", fill.Help("Fill color"))
+//	}
+//
+// The flag accepts the following forms, without regard to case:
+//
+//	#rgb, #rgba             -- hexadecimal, one digit per channel
+//	#rrggbb, #rrggbbaa      -- hexadecimal, two digits per channel
+//	rgb(r, g, b)            -- decimal channels 0-255 or percentages
+//	rgba(r, g, b, a)        -- as rgb, with alpha 0-1 or a percentage
+//	name                    -- a CSS named color, e.g., "salmon"
+//	transparent             -- fully transparent black
+//
+// In rgb() and rgba(), channels may be separated by commas or spaces.
+package colorflag
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// A Value represents a color. A pointer to a Value satisfies the flag.Value
+// and flag.Getter interfaces. The zero value is transparent black.
+type Value struct {
+	// The color parsed from the flag. The channels are not premultiplied by
+	// alpha, matching CSS.
+	Color color.NRGBA
+}
+
+// Parse parses a color in any of the forms accepted by the flag.
+func Parse(s string) (color.NRGBA, error) {
+	t := strings.ToLower(strings.TrimSpace(s))
+	if hex, ok := strings.CutPrefix(t, "#"); ok {
+		if c, ok := parseHex(hex); ok {
+			return c, nil
+		}
+	} else if args, ok := cutFunc(t, "rgb"); ok {
+		if c, ok := parseFunc(args, false); ok {
+			return c, nil
+		}
+	} else if args, ok := cutFunc(t, "rgba"); ok {
+		if c, ok := parseFunc(args, true); ok {
+			return c, nil
+		}
+	} else if t == "transparent" {
+		return color.NRGBA{}, nil
+	} else if rgb, ok := cssNames[t]; ok {
+		return color.NRGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 255}, nil
+	}
+	return color.NRGBA{}, fmt.Errorf("colorflag: invalid color %q", s)
+}
+
+// MustParse returns a Value for the color described by s, and panics if s is
+// not a valid color. It is intended for use in setting default values.
+func MustParse(s string) Value {
+	c, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return Value{Color: c}
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	return h + " (#rrggbb, rgb(r,g,b), or CSS color name)"
+}
+
+// String satisfies part of the flag.Value interface.
+// The color is rendered as "#rrggbb", or "#rrggbbaa" if it is not opaque.
+func (v *Value) String() string {
+	c := v.Color
+	if c.A == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	c, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	v.Color = c
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type color.NRGBA.
+func (v *Value) Get() any { return v.Color }
+
+// parseHex parses 3, 4, 6, or 8 hexadecimal digits.
+func parseHex(s string) (color.NRGBA, bool) {
+	n, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, false
+	}
+	dup := func(x uint64) uint8 { return uint8(x<<4 | x) }
+	switch len(s) {
+	case 3:
+		return color.NRGBA{R: dup(n >> 8 & 0xf), G: dup(n >> 4 & 0xf), B: dup(n & 0xf), A: 255}, true
+	case 4:
+		return color.NRGBA{R: dup(n >> 12 & 0xf), G: dup(n >> 8 & 0xf), B: dup(n >> 4 & 0xf), A: dup(n & 0xf)}, true
+	case 6:
+		return color.NRGBA{R: uint8(n >> 16), G: uint8(n >> 8), B: uint8(n), A: 255}, true
+	case 8:
+		return color.NRGBA{R: uint8(n >> 24), G: uint8(n >> 16), B: uint8(n >> 8), A: uint8(n)}, true
+	}
+	return color.NRGBA{}, false
+}
+
+// cutFunc reports whether s has the form "name(args)", and if so returns args.
+func cutFunc(s, name string) (string, bool) {
+	rest, ok := strings.CutPrefix(s, name)
+	if !ok {
+		return "", false
+	}
+	rest = strings.TrimSpace(rest)
+	if !strings.HasPrefix(rest, "(") || !strings.HasSuffix(rest, ")") {
+		return "", false
+	}
+	return rest[1 : len(rest)-1], true
+}
+
+// parseFunc parses the arguments of rgb() or rgba().
+func parseFunc(args string, alpha bool) (color.NRGBA, bool) {
+	parts := strings.FieldsFunc(args, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	if want := 3 + btoi(alpha); len(parts) != want {
+		return color.NRGBA{}, false
+	}
+	var ch [4]uint8
+	ch[3] = 255
+	for i, p := range parts {
+		var f float64
+		var err error
+		if pct, ok := strings.CutSuffix(p, "%"); ok {
+			f, err = strconv.ParseFloat(pct, 64)
+			f = f / 100 * 255
+		} else if i == 3 {
+			f, err = strconv.ParseFloat(p, 64)
+			f *= 255
+		} else {
+			f, err = strconv.ParseFloat(p, 64)
+		}
+		if err != nil || math.IsNaN(f) || f < 0 || f > 255 {
+			return color.NRGBA{}, false
+		}
+		ch[i] = uint8(math.Round(f))
+	}
+	return color.NRGBA{R: ch[0], G: ch[1], B: ch[2], A: ch[3]}, true
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,81 @@
+package colorflag
+
+import (
+	"flag"
+	"image/color"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	fill := MustParse("steelblue")
+	var stroke Value
+
+	fs := flag.NewFlagSet("color", flag.ContinueOnError)
+	fs.Var(&fill, "fill", fill.Help("Fill color"))
+	fs.Var(&stroke, "stroke", stroke.Help("Stroke color"))
+
+	if got, want := fill.String(), "#4682b4"; got != want {
+		t.Errorf("Initial value for -fill: got %q, want %q", got, want)
+	}
+	if got, want := stroke.String(), "#00000000"; got != want {
+		t.Errorf("Initial value for -stroke: got %q, want %q", got, want)
+	}
+	if err := fs.Parse([]string{"-fill", "#F0A", "-stroke", "rgba(10, 20, 30, 0.5)"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := fill.Get().(color.NRGBA), (color.NRGBA{0xff, 0x00, 0xaa, 0xff}); got != want {
+		t.Errorf("Value for -fill: got %v, want %v", got, want)
+	}
+	if got, want := stroke.String(), "#0a141e80"; got != want {
+		t.Errorf("Value for -stroke: got %q, want %q", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want color.NRGBA
+	}{
+		{"#000", color.NRGBA{0, 0, 0, 255}},
+		{"#abcd", color.NRGBA{0xaa, 0xbb, 0xcc, 0xdd}},
+		{"#1a2B3c", color.NRGBA{0x1a, 0x2b, 0x3c, 255}},
+		{"#1a2b3c4d", color.NRGBA{0x1a, 0x2b, 0x3c, 0x4d}},
+		{"rgb(255, 128, 0)", color.NRGBA{255, 128, 0, 255}},
+		{"RGB(255 128 0)", color.NRGBA{255, 128, 0, 255}},
+		{"rgb(100%, 50%, 0%)", color.NRGBA{255, 128, 0, 255}},
+		{"rgba(0,0,0,0)", color.NRGBA{0, 0, 0, 0}},
+		{"rgba(1, 2, 3, 100%)", color.NRGBA{1, 2, 3, 255}},
+		{"Salmon", color.NRGBA{0xfa, 0x80, 0x72, 255}},
+		{" rebeccapurple ", color.NRGBA{0x66, 0x33, 0x99, 255}},
+		{"transparent", color.NRGBA{}},
+	}
+	for _, test := range tests {
+		got, err := Parse(test.in)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", test.in, err)
+		} else if got != test.want {
+			t.Errorf("Parse(%q): got %v, want %v", test.in, got, test.want)
+		}
+	}
+
+	for _, bad := range []string{
+		"", "#", "#12", "#12345", "#ggg", "#-12",
+		"rgb(1,2)", "rgb(1,2,3,4)", "rgba(1,2,3)", "rgb(256,0,0)", "rgb(-1,0,0)",
+		"rgb(1,2,3", "rgba(0,0,0,2)", "rgb(x,y,z)", "notacolor",
+	} {
+		if c, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q): got %v, wanted error", bad, c)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, in := range []string{"#123456", "#12345678", "#00000000", "#ffffff"} {
+		var v Value
+		if err := v.Set(in); err != nil {
+			t.Errorf("Set(%q) failed: %v", in, err)
+		} else if got := v.String(); got != in {
+			t.Errorf("Round trip of %q: got %q", in, got)
+		}
+	}
+}
@@ -0,0 +1,396 @@
+// Package command implements a lightweight dispatcher for programs with
+// subcommands, each of which defines its own flags.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/command"
+//	  "github.com/creachadair/goflags/levelflag"
+//	)
+//
+//	var level levelflag.Value
+//	var root = &command.Command{
+//	  Name: "tool",
+//	  SetFlags: func(fs *flag.FlagSet) {
+//	    fs.Var(&level, "log-level", level.Help("Minimum level to log"))
+//	  },
+//	  Commands: []*command.Command{{
+//	    Name:  "serve",
+//	    Usage: "[flags] addr",
+//	    Help:  "Run the server.",
+//	    Run:   runServe,
+//	  }},
+//	}
+//
+//	func main() { command.Main(root) }
+//
+// The flags of a command are inherited by its subcommands, so that with this
+// definition "tool -log-level debug serve :80" and "tool serve -log-level
+// debug :80" are equivalent.
+//
+// Each command has a "-help" flag, and a command with subcommands has a
+// "help" subcommand, that print help text formatted by goflags.Usage. The
+// dispatcher also handles the hidden completion command of the goflags
+// package, so "tool __complete bash" prints a completion script for the
+// whole command tree.
+package command
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/creachadair/goflags"
+)
+
+// A Command is a command or subcommand of a program.
+type Command struct {
+	// The name of the command, as given on the command line.
+	Name string
+
+	// A synopsis of the arguments of the command, such as "[flags] file...".
+	// If empty, it is "[flags]", followed by "command ..." if the command has
+	// subcommands.
+	Usage string
+
+	// A description of the command for its help text. The first line is also
+	// shown in the list of commands of its parent.
+	Help string
+
+	// If non-nil, SetFlags is called to define the flags of the command. It
+	// may also declare flag groups and usage sections for the flag set. The
+	// flags of the parents of the command are then added to the flag set,
+	// except those with the same names as flags of the command.
+	SetFlags func(fs *flag.FlagSet)
+
+	// If non-nil, Run is called to run the command with the arguments that
+	// remain after its flags are parsed. If nil, the command must be given
+	// one of its subcommands.
+	Run func(args []string) error
+
+	// The subcommands of the command, if any.
+	Commands []*Command
+
+	// The writer for help text and warnings. If nil, the writer of the
+	// parent is used, or os.Stderr for a command with no parent.
+	Output io.Writer
+}
+
+// Main runs c as the root command of the program with the arguments in
+// os.Args, and exits. If running fails, Main prints the error and exits with
+// status 1, or 2 for an error in the command line. Help requests exit with
+// status 0.
+func Main(c *Command) {
+	err := c.Execute(os.Args[1:])
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		os.Exit(0)
+	case errors.As(err, new(*UsageError)):
+		os.Exit(2)
+	}
+	fmt.Fprintf(c.output(), "%s: %v\n", c.Name, err)
+	os.Exit(1)
+}
+
+// A UsageError is reported by Execute for an invalid command line. The help
+// text of the command has already been printed.
+type UsageError struct {
+	Path string // the path of the command, e.g., "tool serve"
+	Err  error
+}
+
+func (e *UsageError) Error() string { return e.Path + ": " + e.Err.Error() }
+
+func (e *UsageError) Unwrap() error { return e.Err }
+
+// Execute runs c with the given command-line arguments, not including the
+// program name. It parses the flags of c, then either dispatches to the
+// subcommand named by the first remaining argument, or calls c.Run with the
+// remaining arguments. Before calling Run, Execute checks the flag groups of
+// the command and its ancestors (see goflags.CheckGroups). An inherited flag
+// counts as set whether it was given before or after the subcommand name.
+//
+// If the arguments request help, Execute prints it and reports flag.ErrHelp.
+// If the arguments are invalid, it reports a *UsageError. Otherwise it
+// reports the error from Run.
+func (c *Command) Execute(args []string) error {
+	if len(args) != 0 && args[0] == goflags.CompleteCommand {
+		return c.handleCompletion(args[1:])
+	}
+	return c.execute(nil, args)
+}
+
+// node is a command on the path from the root to the command being run.
+type node struct {
+	cmd       *Command
+	parent    *node
+	fs        *flag.FlagSet
+	inherited map[string]bool // names of the flags inherited from parent
+}
+
+func (n *node) path() string {
+	if n.parent == nil {
+		return n.cmd.Name
+	}
+	return n.parent.path() + " " + n.cmd.Name
+}
+
+func (n *node) output() io.Writer {
+	for ; n != nil; n = n.parent {
+		if n.cmd.Output != nil {
+			return n.cmd.Output
+		}
+	}
+	return os.Stderr
+}
+
+func (c *Command) output() io.Writer { return (&node{cmd: c}).output() }
+
+// newNode returns the node for c as a child of parent, with its flag set.
+// The caller should call goflags.Forget for the flag set when it is done.
+func newNode(c *Command, parent *node) *node {
+	n := &node{cmd: c, parent: parent}
+	n.fs = flag.NewFlagSet(n.path(), flag.ContinueOnError)
+	n.fs.SetOutput(n.output())
+	if c.SetFlags != nil {
+		c.SetFlags(n.fs)
+	}
+	var inherited []string
+	if parent != nil {
+		n.inherited = make(map[string]bool)
+		parent.fs.VisitAll(func(f *flag.Flag) {
+			if n.fs.Lookup(f.Name) != nil {
+				return // shadowed by a flag of the command
+			}
+			n.fs.Var(f.Value, f.Name, f.Usage)
+			n.fs.Lookup(f.Name).DefValue = f.DefValue
+			n.inherited[f.Name] = true
+			inherited = append(inherited, f.Name)
+		})
+	}
+	if len(inherited) != 0 {
+		goflags.Section(n.fs, "Inherited flags", inherited...)
+	}
+	n.fs.Usage = func() { n.printHelp(n.output()) }
+	return n
+}
+
+func (c *Command) execute(parent *node, args []string) error {
+	n := newNode(c, parent)
+	defer goflags.Forget(n.fs)
+	if err := n.fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &UsageError{Path: n.path(), Err: err} // Parse printed help
+	}
+
+	rest := n.fs.Args()
+	if len(rest) != 0 {
+		if sub := c.find(rest[0]); sub != nil {
+			return sub.execute(n, rest[1:])
+		}
+		if rest[0] == "help" && len(c.Commands) != 0 {
+			return n.help(rest[1:])
+		}
+	}
+	if c.Run == nil {
+		if len(rest) == 0 {
+			n.printHelp(n.output())
+			return flag.ErrHelp
+		}
+		err := fmt.Errorf("unknown command %q", rest[0])
+		fmt.Fprintln(n.output(), err)
+		n.printHelp(n.output())
+		return &UsageError{Path: n.path(), Err: err}
+	}
+	if err := n.checkGroups(); err != nil {
+		fmt.Fprintln(n.output(), err)
+		n.printHelp(n.output())
+		return &UsageError{Path: n.path(), Err: err}
+	}
+	return c.Run(rest)
+}
+
+// checkGroups checks the flag groups of n and its ancestors. An inherited
+// flag shares its value with the flag it was inherited from, so a flag set in
+// the flag set of any command on the path counts as set for each command
+// that shares it.
+func (n *node) checkGroups() error {
+	var path []*node
+	for p := n; p != nil; p = p.parent {
+		path = append([]*node{p}, path...)
+	}
+	var errs []error
+	for i, p := range path {
+		var also []string
+		for j, q := range path {
+			q.fs.Visit(func(f *flag.Flag) {
+				if (j > i && shares(q, p, f.Name)) || (j < i && shares(p, q, f.Name)) {
+					also = append(also, f.Name)
+				}
+			})
+		}
+		if err := goflags.CheckGroupsWith(p.fs, also...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// shares reports whether the flag of n with the given name is inherited from
+// the flag of its ancestor a.
+func shares(n, a *node, name string) bool {
+	for ; n != a; n = n.parent {
+		if !n.inherited[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// help prints the help for the subcommand of n named by path.
+func (n *node) help(path []string) error {
+	for _, name := range path {
+		sub := n.cmd.find(name)
+		if sub == nil {
+			err := fmt.Errorf("unknown command %q", name)
+			fmt.Fprintln(n.output(), err)
+			return &UsageError{Path: n.path(), Err: err}
+		}
+		n = newNode(sub, n)
+		defer goflags.Forget(n.fs)
+	}
+	n.printHelp(n.output())
+	return flag.ErrHelp
+}
+
+// find returns the subcommand of c with the given name, or nil.
+func (c *Command) find(name string) *Command {
+	for _, sub := range c.Commands {
+		if sub.Name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+// printHelp writes the help text for the command of n to w.
+func (n *node) printHelp(w io.Writer) {
+	c := n.cmd
+	usage := c.Usage
+	if usage == "" {
+		usage = "[flags]"
+		if len(c.Commands) != 0 {
+			usage += " command ..."
+		}
+	}
+	var buf strings.Builder
+	buf.WriteString("Usage: " + n.path() + " " + usage + "\n")
+	if c.Help != "" {
+		buf.WriteString("\n" + strings.TrimSpace(c.Help) + "\n")
+	}
+	if len(c.Commands) != 0 {
+		buf.WriteString("\nCommands:\n")
+		width := len("help")
+		for _, sub := range c.Commands {
+			width = max(width, len(sub.Name))
+		}
+		for _, sub := range c.Commands {
+			first, _, _ := strings.Cut(strings.TrimSpace(sub.Help), "\n")
+			fmt.Fprintf(&buf, "  %-*s  %s\n", width, sub.Name, first)
+		}
+		if c.find("help") == nil {
+			fmt.Fprintf(&buf, "  %-*s  %s\n", width, "help", "Print help for a command.")
+		}
+	}
+	goflags.Usage{Header: strings.TrimSuffix(buf.String(), "\n")}.Write(w, n.fs)
+}
+
+// handleCompletion responds to the arguments of the completion command of
+// the goflags package, for the command tree rooted at c.
+func (c *Command) handleCompletion(args []string) error {
+	if len(args) == 1 && args[0] != "--" {
+		return goflags.WriteCompletionScript(os.Stdout, args[0], c.Name)
+	}
+	if len(args) != 0 && args[0] == "--" {
+		args = args[1:]
+	}
+	for _, s := range c.Complete(args) {
+		fmt.Println(s)
+	}
+	return nil
+}
+
+// Complete returns the completions for the last element of args, given the
+// preceding arguments, for the command tree rooted at c. The args should not
+// include the program name. Flags are completed as by goflags.Complete, for
+// the flags of the command named by the preceding arguments, and the names
+// of subcommands are completed where a subcommand may be given.
+func (c *Command) Complete(args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+	n := newNode(c, nil)
+	defer goflags.Forget(n.fs)
+	n.fs.SetOutput(io.Discard)
+	prev, cur := args[:len(args)-1], args[len(args)-1]
+	start := 0 // the index of the first argument for the command of n
+	for i := 0; i < len(prev); i++ {
+		arg := prev[i]
+		if strings.HasPrefix(arg, "-") && len(arg) > 1 && arg != "--" {
+			name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			if f := n.fs.Lookup(name); f != nil && !hasValue && !isBoolFlag(f.Value) {
+				i++ // skip the value of the flag
+			}
+			continue
+		}
+		sub := n.cmd.find(arg)
+		if sub == nil {
+			return nil // a positional argument, not completed
+		}
+		n = newNode(sub, n)
+		defer goflags.Forget(n.fs)
+		n.fs.SetOutput(io.Discard)
+		start = i + 1
+	}
+	words := args[start:]
+	out := goflags.Complete(n.fs, words)
+	if strings.HasPrefix(cur, "-") || isValuePosition(n.fs, words) {
+		return out
+	}
+	for _, sub := range n.cmd.Commands {
+		if strings.HasPrefix(sub.Name, cur) {
+			out = append(out, sub.Name)
+		}
+	}
+	if len(n.cmd.Commands) != 0 && n.cmd.find("help") == nil && strings.HasPrefix("help", cur) {
+		out = append(out, "help")
+	}
+	return out
+}
+
+// isValuePosition reports whether the last of words is the value of a flag
+// given by the word before it.
+func isValuePosition(fs *flag.FlagSet, words []string) bool {
+	if len(words) < 2 {
+		return false
+	}
+	arg := words[len(words)-2]
+	if !strings.HasPrefix(arg, "-") || arg == "--" {
+		return false
+	}
+	name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+	f := fs.Lookup(name)
+	return f != nil && !hasValue && !isBoolFlag(f.Value)
+}
+
+func isBoolFlag(v flag.Value) bool {
+	b, ok := v.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
@@ -0,0 +1,250 @@
+package command
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/goflags"
+	"github.com/creachadair/goflags/enumflag"
+)
+
+// testTree returns a command tree for testing, and a log of the commands run
+// and the values of their flags.
+func testTree(buf *strings.Builder) (*Command, *[]string) {
+	var log []string
+	var verbose bool
+	var addr string
+	color := enumflag.New("red", "green", "blue")
+	root := &Command{
+		Name: "tool",
+		Help: "Tool does things.",
+		SetFlags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&verbose, "v", false, "Verbose output")
+		},
+		Output: buf,
+		Commands: []*Command{{
+			Name:  "serve",
+			Usage: "[flags] dir",
+			Help:  "Run the server.\nIt serves the given directory.",
+			SetFlags: func(fs *flag.FlagSet) {
+				fs.StringVar(&addr, "addr", ":80", "Service address")
+				fs.Var(color, "color", color.Help("Color"))
+			},
+			Run: func(args []string) error {
+				log = append(log, "serve", strings.Join(args, ","), addr, color.Key())
+				if verbose {
+					log = append(log, "verbose")
+				}
+				return nil
+			},
+		}, {
+			Name: "db",
+			Help: "Manage the database.",
+			Commands: []*Command{{
+				Name: "init",
+				Help: "Initialize the database.",
+				SetFlags: func(fs *flag.FlagSet) {
+					fs.StringVar(&addr, "addr", "db:5432", "Database address")
+				},
+				Run: func(args []string) error {
+					log = append(log, "init", addr)
+					if verbose {
+						log = append(log, "verbose")
+					}
+					return nil
+				},
+			}},
+		}},
+	}
+	return root, &log
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"serve", "a", "b"}, []string{"serve", "a,b", ":80", "red"}},
+		{[]string{"-v", "serve", "-addr", ":8080", "x"}, []string{"serve", "x", ":8080", "red", "verbose"}},
+		{[]string{"serve", "-v", "-color", "blue", "x"}, []string{"serve", "x", ":80", "blue", "verbose"}},
+		{[]string{"db", "init"}, []string{"init", "db:5432"}},
+		{[]string{"db", "-v", "init", "-addr", "local"}, []string{"init", "local", "verbose"}},
+	}
+	for _, tc := range tests {
+		var buf strings.Builder
+		root, log := testTree(&buf)
+		if err := root.Execute(tc.args); err != nil {
+			t.Errorf("Execute %q: unexpected error: %v", tc.args, err)
+		}
+		if !reflect.DeepEqual(*log, tc.want) {
+			t.Errorf("Execute %q: got %q, want %q", tc.args, *log, tc.want)
+		}
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"nonesuch"}, `unknown command "nonesuch"`},
+		{[]string{"db", "drop"}, `unknown command "drop"`},
+		{[]string{"serve", "-bogus"}, "flag provided but not defined: -bogus"},
+		{[]string{"db", "init", "-color", "red"}, "flag provided but not defined: -color"},
+	}
+	for _, tc := range tests {
+		var buf strings.Builder
+		root, _ := testTree(&buf)
+		err := root.Execute(tc.args)
+		var uerr *UsageError
+		if !errors.As(err, &uerr) {
+			t.Errorf("Execute %q: got %v, want *UsageError", tc.args, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Execute %q: got %v, want %q", tc.args, err, tc.want)
+		}
+		if !strings.Contains(buf.String(), "Usage: ") {
+			t.Errorf("Execute %q: help was not printed: %q", tc.args, buf.String())
+		}
+	}
+
+	run := errors.New("run failed")
+	c := &Command{Name: "fail", Run: func([]string) error { return run }}
+	if err := c.Execute(nil); err != run {
+		t.Errorf("Execute: got %v, want %v", err, run)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	var buf strings.Builder
+	c := &Command{
+		Name:   "tool",
+		Output: &buf,
+		SetFlags: func(fs *flag.FlagSet) {
+			fs.Bool("a", false, "A")
+			fs.Bool("b", false, "B")
+			goflags.Exclusive(fs, "a", "b")
+		},
+		Run: func([]string) error { return nil },
+	}
+	if err := c.Execute([]string{"-a"}); err != nil {
+		t.Errorf("Execute -a: unexpected error: %v", err)
+	}
+	err := c.Execute([]string{"-a", "-b"})
+	if err == nil || !strings.Contains(err.Error(), "cannot be used together") {
+		t.Errorf("Execute -a -b: got %v, want group error", err)
+	}
+
+	// The groups of a command apply to the flags its subcommands inherit,
+	// wherever they are given, unless the subcommand shadows them.
+	c.Run = nil
+	c.Commands = []*Command{
+		{Name: "serve", Run: func([]string) error { return nil }},
+		{
+			Name:     "local",
+			SetFlags: func(fs *flag.FlagSet) { fs.Bool("b", false, "Local B") },
+			Run:      func([]string) error { return nil },
+		},
+	}
+	tests := []struct {
+		args []string
+		ok   bool
+	}{
+		{[]string{"-a", "serve"}, true},
+		{[]string{"serve", "-a"}, true},
+		{[]string{"-a", "-b", "serve"}, false},
+		{[]string{"serve", "-a", "-b"}, false},
+		{[]string{"-a", "serve", "-b"}, false},
+		{[]string{"-a", "local", "-b"}, true},
+	}
+	for _, tc := range tests {
+		err := c.Execute(tc.args)
+		if tc.ok && err != nil {
+			t.Errorf("Execute %q: unexpected error: %v", tc.args, err)
+		} else if !tc.ok && (err == nil || !strings.Contains(err.Error(), "cannot be used together")) {
+			t.Errorf("Execute %q: got %v, want group error", tc.args, err)
+		}
+	}
+}
+
+func TestHelp(t *testing.T) {
+	const rootHelp = `Usage: tool [flags] command ...
+
+Tool does things.
+
+Commands:
+  serve  Run the server.
+  db     Manage the database.
+  help   Print help for a command.
+
+Flags:
+  -v  Verbose output
+`
+	const serveHelp = `Usage: tool serve [flags] dir
+
+Run the server.
+It serves the given directory.
+
+Flags:
+  -addr string  Service address (default ":80")
+  -color value  Color (red|green|blue) (default "red")
+
+Inherited flags:
+  -v            Verbose output
+`
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{nil, rootHelp},
+		{[]string{"help"}, rootHelp},
+		{[]string{"-help"}, rootHelp},
+		{[]string{"help", "serve"}, serveHelp},
+		{[]string{"serve", "-h"}, serveHelp},
+		{[]string{"-v", "serve", "-help"}, serveHelp},
+	}
+	for _, tc := range tests {
+		var buf strings.Builder
+		root, log := testTree(&buf)
+		if err := root.Execute(tc.args); !errors.Is(err, flag.ErrHelp) {
+			t.Errorf("Execute %q: got %v, want %v", tc.args, err, flag.ErrHelp)
+		}
+		if len(*log) != 0 {
+			t.Errorf("Execute %q: unexpectedly ran %q", tc.args, *log)
+		}
+		if got := buf.String(); got != tc.want {
+			t.Errorf("Execute %q: wrong help:\n got: %q\nwant: %q", tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{""}, []string{"serve", "db", "help"}},
+		{[]string{"s"}, []string{"serve"}},
+		{[]string{"-"}, []string{"-v"}},
+		{[]string{"-v", "d"}, []string{"db"}},
+		{[]string{"serve", "-c"}, []string{"-color"}},
+		{[]string{"serve", "-color", "g"}, []string{"green"}},
+		{[]string{"serve", "-addr", "x", "-"}, []string{"-addr", "-color", "-v"}},
+		{[]string{"db", ""}, []string{"init", "help"}},
+		{[]string{"db", "init", "-"}, []string{"-addr", "-v"}},
+		{[]string{"serve", "dir", "x"}, nil},
+		{[]string{"nonesuch", ""}, nil},
+	}
+	for _, tc := range tests {
+		var buf strings.Builder
+		root, _ := testTree(&buf)
+		got := root.Complete(tc.args)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Complete %q: got %q, want %q", tc.args, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package goflags
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/creachadair/goflags/aliasflag"
+)
+
+// A Completer is a flag.Value that can suggest values for shell completion.
+// Complete returns the values that begin with prefix, or nil if it has no
+// suggestions. For example, enumflag.Value suggests its keys, and
+// fileflag.Value suggests the names of files.
+type Completer interface {
+	flag.Value
+	Complete(prefix string) []string
+}
+
+// CompleteCommand is the hidden command word that HandleCompletion responds
+// to. The generated completion scripts invoke the program as
+//
+//	prog __complete -- word...
+//
+// where the last word is the one being completed.
+const CompleteCommand = "__complete"
+
+// Complete returns the completions for the last element of args, given the
+// preceding arguments, with respect to the flags defined by fs. The args
+// should not include the program name. An argument "-name" is completed with
+// the names of the flags in fs; the argument after a flag that takes a value,
+// or the value of "-name=v", is completed by the value of the flag if it is a
+// Completer. A boolean flag is completed with "true" and "false". Flags that
+// are deprecated, by Deprecate or as aliases (see aliasflag), are not
+// suggested.
+//
+// Complete returns nil for arguments that are not flags or flag values, such
+// as those following "--" or the first non-flag argument, as fs.Parse would
+// treat them.
+func Complete(fs *flag.FlagSet, args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+	prev, cur := args[:len(args)-1], args[len(args)-1]
+	for i := 0; i < len(prev); i++ {
+		arg := prev[i]
+		if arg == "--" || len(arg) < 2 || arg[0] != '-' {
+			return nil // flag parsing has stopped
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		f := fs.Lookup(name)
+		if f == nil || hasValue || isBoolFlag(f.Value) {
+			continue
+		}
+		if i == len(prev)-1 {
+			return completeValue(f.Value, cur) // cur is the value of this flag
+		}
+		i++ // skip the value of this flag
+	}
+
+	if len(cur) == 0 || cur[0] != '-' {
+		return nil
+	}
+	dashes := cur[:len(cur)-len(strings.TrimLeft(cur, "-"))]
+	if len(dashes) > 2 {
+		return nil
+	}
+	rest := cur[len(dashes):]
+	if name, val, ok := strings.Cut(rest, "="); ok {
+		f := fs.Lookup(name)
+		if f == nil {
+			return nil
+		}
+		var out []string
+		for _, c := range completeValue(f.Value, val) {
+			out = append(out, dashes+name+"="+c)
+		}
+		return out
+	}
+	var out []string
+	fs.VisitAll(func(f *flag.Flag) {
+		if _, dep := Deprecated(f); strings.HasPrefix(f.Name, rest) && !dep && !aliasflag.IsAlias(f) {
+			out = append(out, dashes+f.Name)
+		}
+	})
+	return out
+}
+
+// completeValue returns the completions of prefix for the value of a flag.
+func completeValue(v flag.Value, prefix string) []string {
+	if c, ok := findValue[Completer](v); ok {
+		return c.Complete(prefix)
+	}
+	if isBoolFlag(v) {
+		var out []string
+		for _, s := range []string{"true", "false"} {
+			if strings.HasPrefix(s, prefix) {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// findValue returns v as a T, if it is one. Otherwise, if v is a wrapper that
+// provides an Unwrap method, such as defaultflag.Value, findValue looks for a T
+// in the wrapped value.
+func findValue[T any](v flag.Value) (T, bool) {
+	for {
+		if t, ok := v.(T); ok {
+			return t, true
+		}
+		u, ok := v.(interface{ Unwrap() flag.Value })
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		v = u.Unwrap()
+	}
+}
+
+func isBoolFlag(v flag.Value) bool {
+	b, ok := v.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// HandleCompletion responds to the hidden completion command, if args begins
+// with CompleteCommand, and reports whether it did so. The args should not
+// include the program name. It is intended to be called before fs.Parse:
+//
+//	if goflags.HandleCompletion(flag.CommandLine, os.Args[1:]) {
+//	  os.Exit(0)
+//	}
+//
+// The arguments "__complete -- word..." print the completions of the words
+// to stdout, one per line, as reported by Complete. The arguments
+// "__complete SHELL" print the completion script for the named shell, as
+// reported by WriteCompletionScript, using the base name of fs.Name() as the
+// program name.
+func HandleCompletion(fs *flag.FlagSet, args []string) bool {
+	return handleCompletion(os.Stdout, fs, args)
+}
+
+func handleCompletion(w io.Writer, fs *flag.FlagSet, args []string) bool {
+	if len(args) == 0 || args[0] != CompleteCommand {
+		return false
+	}
+	if len(args) == 2 && args[1] != "--" {
+		if err := WriteCompletionScript(w, args[1], filepath.Base(fs.Name())); err != nil {
+			fmt.Fprintln(fs.Output(), err)
+		}
+		return true
+	}
+	words := args[1:]
+	if len(words) != 0 && words[0] == "--" {
+		words = words[1:]
+	}
+	for _, c := range Complete(fs, words) {
+		fmt.Fprintln(w, c)
+	}
+	return true
+}
+
+// CompletionShells lists the shells supported by WriteCompletionScript.
+var CompletionShells = []string{"bash", "fish", "zsh"}
+
+// WriteCompletionScript writes to w a completion script for the program
+// named prog in the given shell, which must be one of CompletionShells. The
+// script invokes prog with CompleteCommand to find completions, so prog must
+// call HandleCompletion. Where the program offers no completions, the script
+// falls back to the default completion of the shell, typically file names.
+//
+// To load the script, for example in bash:
+//
+//	source <(prog __complete bash)
+func WriteCompletionScript(w io.Writer, shell, prog string) error {
+	if !slices.Contains(CompletionShells, shell) {
+		return fmt.Errorf("goflags: unknown shell %q, expected one of (%s)",
+			shell, strings.Join(CompletionShells, "|"))
+	}
+	if prog == "" || strings.ContainsAny(prog, " \t\n'\"\\$`;&|<>()") {
+		return fmt.Errorf("goflags: invalid program name %q", prog)
+	}
+	fn := "__" + strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			return r
+		}
+		return '_'
+	}, prog) + "_complete"
+	var script string
+	switch shell {
+	case "bash":
+		script = bashScript
+	case "fish":
+		script = fishScript
+	case "zsh":
+		script = zshScript
+	}
+	_, err := io.WriteString(w, strings.NewReplacer("PROG", prog, "FUNC", fn).Replace(script))
+	return err
+}
+
+// In bash, the words are split from the line rather than taken from
+// COMP_WORDS, which splits "-name=value" at the "=".
+const bashScript = `# bash completion for PROG
+FUNC() {
+  local line="${COMP_LINE:0:COMP_POINT}"
+  local -a words
+  read -ra words <<< "$line"
+  [[ $line =~ [[:space:]]$ ]] && words+=("")
+  local cur="${words[${#words[@]}-1]}"
+  local IFS=$'\n'
+  COMPREPLY=($(PROG __complete -- "${words[@]:1}" 2>/dev/null))
+  if [[ $cur == *=* && $COMP_WORDBREAKS == *=* ]]; then
+    COMPREPLY=("${COMPREPLY[@]#"${cur%%=*}="}")
+  fi
+}
+complete -o default -F FUNC PROG
+`
+
+const fishScript = `# fish completion for PROG
+function FUNC
+    set -l words (commandline -opc) (commandline -ct)
+    PROG __complete -- $words[2..-1] 2>/dev/null
+end
+complete -c PROG -a '(FUNC)'
+`
+
+const zshScript = `#compdef PROG
+# zsh completion for PROG
+FUNC() {
+  local -a completions
+  completions=(${(f)"$(PROG __complete -- "${(@)words[2,CURRENT]}" 2>/dev/null)"})
+  if (( ${#completions} )); then
+    compadd -Q -- $completions
+  else
+    _files
+  fi
+}
+compdef FUNC PROG
+`
@@ -0,0 +1,98 @@
+package goflags
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/goflags/aliasflag"
+	"github.com/creachadair/goflags/defaultflag"
+	"github.com/creachadair/goflags/enumflag"
+)
+
+func newCompleteFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("demo", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(enumflag.New("red", "green", "blue"), "color", "Color")
+	fs.Var(defaultflag.Wrap(enumflag.New("small", "large")), "size", "Size")
+	fs.Bool("verbose", false, "Verbose")
+	fs.String("name", "", "Name")
+	aliasflag.Var(fs, enumflag.New("json", "text"), "format", "Format", "fmt")
+	return fs
+}
+
+func TestComplete(t *testing.T) {
+	fs := newCompleteFlagSet()
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{nil, nil},
+		{[]string{""}, nil},
+		{[]string{"-"}, []string{"-color", "-format", "-name", "-size", "-verbose"}},
+		{[]string{"--f"}, []string{"--format"}},
+		{[]string{"-c"}, []string{"-color"}},
+		{[]string{"---c"}, nil},
+		{[]string{"-color", ""}, []string{"red", "green", "blue"}},
+		{[]string{"-color", "G"}, []string{"green"}},
+		{[]string{"-color=b"}, []string{"-color=blue"}},
+		{[]string{"--size=", "-"}, []string{"-color", "-format", "-name", "-size", "-verbose"}},
+		{[]string{"-size", "l"}, []string{"large"}},
+		{[]string{"-verbose", "-n"}, []string{"-name"}},
+		{[]string{"-verbose=t"}, []string{"-verbose=true"}},
+		{[]string{"-name", "x"}, nil},
+		{[]string{"-name", "-color", "-c"}, []string{"-color"}},
+		{[]string{"-nonesuch=x"}, nil},
+		{[]string{"file", "-c"}, nil},
+		{[]string{"--", "-c"}, nil},
+	}
+	for _, test := range tests {
+		if got := Complete(fs, test.args); !slices.Equal(got, test.want) {
+			t.Errorf("Complete(%q): got %q, want %q", test.args, got, test.want)
+		}
+	}
+}
+
+func TestHandleCompletion(t *testing.T) {
+	fs := newCompleteFlagSet()
+	var buf bytes.Buffer
+	if handleCompletion(&buf, fs, []string{"-color", "red"}) {
+		t.Error("handleCompletion without command: got true, want false")
+	}
+	if !handleCompletion(&buf, fs, []string{CompleteCommand, "--", "-color", "r"}) {
+		t.Error("handleCompletion: got false, want true")
+	}
+	if got, want := buf.String(), "red\n"; got != want {
+		t.Errorf("Completion output: got %q, want %q", got, want)
+	}
+
+	for _, shell := range CompletionShells {
+		buf.Reset()
+		if !handleCompletion(&buf, fs, []string{CompleteCommand, shell}) {
+			t.Errorf("handleCompletion(%q): got false, want true", shell)
+		}
+		script := buf.String()
+		if !strings.Contains(script, "demo __complete --") || !strings.Contains(script, "__demo_complete") {
+			t.Errorf("Script for %s:\n%s", shell, script)
+		}
+	}
+}
+
+func TestWriteCompletionScriptErrors(t *testing.T) {
+	tests := []struct {
+		shell, prog string
+	}{
+		{"tcsh", "demo"},
+		{"bash", ""},
+		{"bash", "de mo"},
+		{"zsh", "demo;rm"},
+	}
+	for _, test := range tests {
+		if err := WriteCompletionScript(io.Discard, test.shell, test.prog); err == nil {
+			t.Errorf("WriteCompletionScript(%q, %q): got nil, wanted error", test.shell, test.prog)
+		}
+	}
+}
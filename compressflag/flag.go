@@ -0,0 +1,237 @@
+// Package compressflag defines a flag.Value implementation that selects a
+// compression codec and level.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/compressflag"
+//	)
+//
+//	var comp compressflag.Value
+//	func init() {
+//	  flag.Var(&comp, "compress", comp.Help("Output compression"))
+//	}
+//
+//	  ...
+//	  w, err := comp.NewWriter(f)
+//
+// The flag is written "codec" or "codec:level", as "gzip" or "gzip:9". If the
+// level is omitted, the default level of the codec is used. The codecs
+// "none", "gzip", "zlib", and "flate" are built in, using the standard
+// library. Others, such as zstd, can be added with Register; until they are,
+// selecting them is an error.
+package compressflag
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A Codec describes a compression format.
+type Codec struct {
+	Name string // e.g., "gzip"
+
+	// The range of valid levels, and the level used when none is given.
+	// If MinLevel == MaxLevel, the codec has no adjustable level.
+	MinLevel, MaxLevel, DefaultLevel int
+
+	// NewWriter returns a writer that compresses to w at the given level.
+	NewWriter func(w io.Writer, level int) (io.WriteCloser, error)
+
+	// NewReader returns a reader that decompresses from r.
+	NewReader func(r io.Reader) (io.ReadCloser, error)
+}
+
+// hasLevel reports whether c has an adjustable level.
+func (c *Codec) hasLevel() bool { return c.MinLevel != c.MaxLevel }
+
+// None is a codec that does not compress.
+var None = &Codec{
+	Name: "none",
+	NewWriter: func(w io.Writer, _ int) (io.WriteCloser, error) {
+		return nopWriteCloser{w}, nil
+	},
+	NewReader: func(r io.Reader) (io.ReadCloser, error) { return io.NopCloser(r), nil },
+}
+
+// Gzip is the gzip codec from compress/gzip.
+var Gzip = &Codec{
+	Name:         "gzip",
+	MinLevel:     gzip.HuffmanOnly,
+	MaxLevel:     gzip.BestCompression,
+	DefaultLevel: gzip.DefaultCompression,
+	NewWriter: func(w io.Writer, level int) (io.WriteCloser, error) {
+		return gzip.NewWriterLevel(w, level)
+	},
+	NewReader: func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+}
+
+// Zlib is the zlib codec from compress/zlib.
+var Zlib = &Codec{
+	Name:         "zlib",
+	MinLevel:     zlib.HuffmanOnly,
+	MaxLevel:     zlib.BestCompression,
+	DefaultLevel: zlib.DefaultCompression,
+	NewWriter: func(w io.Writer, level int) (io.WriteCloser, error) {
+		return zlib.NewWriterLevel(w, level)
+	},
+	NewReader: zlib.NewReader,
+}
+
+// Flate is the raw DEFLATE codec from compress/flate.
+var Flate = &Codec{
+	Name:         "flate",
+	MinLevel:     flate.HuffmanOnly,
+	MaxLevel:     flate.BestCompression,
+	DefaultLevel: flate.DefaultCompression,
+	NewWriter: func(w io.Writer, level int) (io.WriteCloser, error) {
+		return flate.NewWriter(w, level)
+	},
+	NewReader: func(r io.Reader) (io.ReadCloser, error) { return flate.NewReader(r), nil },
+}
+
+var registry = struct {
+	sync.Mutex
+	codecs []*Codec
+}{codecs: []*Codec{None, Gzip, Zlib, Flate}}
+
+// Register makes c available by name. It panics if c is invalid or if a codec
+// with the same name is already registered.
+func Register(c *Codec) {
+	switch {
+	case c.Name == "" || strings.Contains(c.Name, ":"):
+		panic(fmt.Sprintf("compressflag: invalid codec name %q", c.Name))
+	case c.MinLevel > c.MaxLevel || c.DefaultLevel < c.MinLevel || c.DefaultLevel > c.MaxLevel:
+		panic(fmt.Sprintf("compressflag: invalid levels for codec %q", c.Name))
+	case c.NewWriter == nil || c.NewReader == nil:
+		panic(fmt.Sprintf("compressflag: codec %q is missing constructors", c.Name))
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if lookup(c.Name) != nil {
+		panic(fmt.Sprintf("compressflag: codec %q is already registered", c.Name))
+	}
+	registry.codecs = append(registry.codecs, c)
+}
+
+// Lookup returns the registered codec with the given name, or nil.
+func Lookup(name string) *Codec {
+	registry.Lock()
+	defer registry.Unlock()
+	return lookup(name)
+}
+
+func lookup(name string) *Codec {
+	i := slices.IndexFunc(registry.codecs, func(c *Codec) bool {
+		return strings.EqualFold(c.Name, name)
+	})
+	if i < 0 {
+		return nil
+	}
+	return registry.codecs[i]
+}
+
+// Codecs returns the names of the registered codecs.
+func Codecs() []string {
+	registry.Lock()
+	defer registry.Unlock()
+	names := make([]string, len(registry.codecs))
+	for i, c := range registry.codecs {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// A Value represents a choice of codec and level. A pointer to a Value
+// satisfies the flag.Value and flag.Getter interfaces. A Value with no codec
+// set behaves as None.
+type Value struct {
+	// The codec selected by the flag.
+	Codec *Codec
+
+	// The compression level selected by the flag.
+	Level int
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	return fmt.Sprintf("%s (codec[:level]; %s)", h, strings.Join(Codecs(), "|"))
+}
+
+// codec returns the selected codec, or None.
+func (v *Value) codec() *Codec {
+	if v.Codec == nil {
+		return None
+	}
+	return v.Codec
+}
+
+// NewWriter returns a writer that compresses to w using the selected codec
+// and level.
+func (v *Value) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return v.codec().NewWriter(w, v.Level)
+}
+
+// NewReader returns a reader that decompresses from r using the selected
+// codec.
+func (v *Value) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return v.codec().NewReader(r)
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string {
+	c := v.codec()
+	if !c.hasLevel() || v.Level == c.DefaultLevel {
+		return c.Name
+	}
+	return c.Name + ":" + strconv.Itoa(v.Level)
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	name, ls, hasLevel := strings.Cut(s, ":")
+	if name == "" {
+		return errors.New("compressflag: empty codec name")
+	}
+	c := Lookup(name)
+	if c == nil {
+		return fmt.Errorf("compressflag: codec %q is not compiled in, expected one of (%s)",
+			name, strings.Join(Codecs(), "|"))
+	}
+	level := c.DefaultLevel
+	if hasLevel {
+		if !c.hasLevel() {
+			return fmt.Errorf("compressflag: codec %q does not accept a level", c.Name)
+		}
+		n, err := strconv.Atoi(ls)
+		if err != nil {
+			return fmt.Errorf("compressflag: invalid level %q", ls)
+		}
+		if n < c.MinLevel || n > c.MaxLevel {
+			return fmt.Errorf("compressflag: level %d for %s out of range [%d, %d]",
+				n, c.Name, c.MinLevel, c.MaxLevel)
+		}
+		level = n
+	}
+	v.Codec, v.Level = c, level
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type *Codec; the level is available as v.Level.
+func (v *Value) Get() any { return v.codec() }
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
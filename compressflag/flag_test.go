@@ -0,0 +1,100 @@
+package compressflag
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var comp Value
+	fs := flag.NewFlagSet("compress", flag.ContinueOnError)
+	fs.Var(&comp, "compress", comp.Help("Compression"))
+
+	if got := comp.Get().(*Codec); got != None {
+		t.Errorf("Initial value for -compress: got %q, want none", got.Name)
+	}
+	if err := fs.Parse([]string{"-compress", "GZIP:9"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if comp.Codec != Gzip || comp.Level != 9 {
+		t.Errorf("Value for -compress: got %s:%d, want gzip:9", comp.Codec.Name, comp.Level)
+	}
+	if got, want := comp.String(), "gzip:9"; got != want {
+		t.Errorf("String: got %q, want %q", got, want)
+	}
+	if err := fs.Parse([]string{"-compress", "zlib"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := comp.String(), "zlib"; got != want {
+		t.Errorf("String: got %q, want %q", got, want)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	const text = "the quick brown fox jumps over the lazy dog, again and again and again"
+	for _, spec := range []string{"none", "gzip", "gzip:1", "zlib:-2", "flate:9"} {
+		var v Value
+		if err := v.Set(spec); err != nil {
+			t.Fatalf("Set(%q): unexpected error: %v", spec, err)
+		}
+		var buf bytes.Buffer
+		w, err := v.NewWriter(&buf)
+		if err != nil {
+			t.Fatalf("%s: NewWriter: %v", spec, err)
+		}
+		io.WriteString(w, text)
+		if err := w.Close(); err != nil {
+			t.Fatalf("%s: Close: %v", spec, err)
+		}
+		r, err := v.NewReader(&buf)
+		if err != nil {
+			t.Fatalf("%s: NewReader: %v", spec, err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Errorf("%s: read: %v", spec, err)
+		} else if string(got) != text {
+			t.Errorf("%s: got %q, want %q", spec, got, text)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register(&Codec{
+		Name:     "upper",
+		MinLevel: 1, MaxLevel: 3, DefaultLevel: 2,
+		NewWriter: func(w io.Writer, _ int) (io.WriteCloser, error) { return nopWriteCloser{w}, nil },
+		NewReader: func(r io.Reader) (io.ReadCloser, error) { return io.NopCloser(r), nil },
+	})
+	var v Value
+	if err := v.Set("upper:3"); err != nil {
+		t.Errorf("Set(upper:3): unexpected error: %v", err)
+	}
+	if err := v.Set("upper:4"); err == nil {
+		t.Error("Set(upper:4): out-of-range level was accepted")
+	}
+	if h := v.Help("C"); !strings.Contains(h, "upper") {
+		t.Errorf("Help does not list registered codec: %q", h)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Register of a duplicate codec did not panic")
+		}
+	}()
+	Register(&Codec{Name: "GZIP", NewWriter: Gzip.NewWriter, NewReader: Gzip.NewReader})
+}
+
+func TestErrors(t *testing.T) {
+	for _, bad := range []string{"", ":3", "zstd", "zstd:3", "gzip:10", "gzip:-3", "gzip:x", "none:1"} {
+		var v Value
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %s, wanted error", bad, v.String())
+		} else {
+			t.Logf("Set(%q) gave expected error: %v", bad, err)
+		}
+	}
+}
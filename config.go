@@ -0,0 +1,583 @@
+package goflags
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/creachadair/goflags/defaultflag"
+)
+
+// Config is the content of a configuration file, mapping flag names to the
+// values to set, in order. A flag with multiple values is set once for each.
+type Config map[string][]string
+
+// ParseConfig parses a configuration file in the given format, which is
+// "json", "toml", or "yaml".
+//
+// The file must contain a mapping from flag names to values. A value may be a
+// string, number, or boolean, which is passed to the flag as written; or a
+// list of these, whose elements are passed in order, as if the flag were
+// repeated. A nested mapping gives names joined by ".", so that
+//
+//	{"db": {"host": "localhost"}}
+//
+// sets the flag "db.host", the same name Bind uses for nested structs.
+//
+// JSON is parsed by encoding/json. For TOML and YAML, only the subset of the
+// language needed to express such a mapping is supported: in TOML, key/value
+// pairs, [table] headers, and single-line strings and arrays; in YAML, block
+// mappings and sequences, plain and quoted scalars, and flow sequences such
+// as [a, b]. Other constructs, such as YAML anchors and multi-line strings,
+// are reported as errors.
+func ParseConfig(r io.Reader, format string) (Config, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("goflags: reading config: %w", err)
+	}
+	cfg := make(Config)
+	switch strings.ToLower(format) {
+	case "json":
+		err = parseJSON(data, cfg)
+	case "toml":
+		err = parseTOML(data, cfg)
+	case "yaml", "yml":
+		err = parseYAML(data, cfg)
+	default:
+		return nil, fmt.Errorf("goflags: unknown config format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("goflags: invalid %s config: %w", format, err)
+	}
+	return cfg, nil
+}
+
+// LoadConfig reads the configuration file at path, in the format indicated by
+// its extension (.json, .toml, .yaml, or .yml), and applies it to fs as
+// ApplyConfig does.
+func LoadConfig(fs *flag.FlagSet, path string) ([]string, error) {
+	cfg, err := readConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return ApplyConfig(fs, cfg, path)
+}
+
+// readConfig reads and parses the configuration file at path.
+func readConfig(path string) (Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("goflags: %w", err)
+	}
+	defer f.Close()
+	cfg, err := ParseConfig(f, strings.TrimPrefix(filepath.Ext(path), "."))
+	if err != nil {
+		return nil, fmt.Errorf("%w (in %s)", err, path)
+	}
+	return cfg, nil
+}
+
+// ApplyConfig sets each flag of fs named in cfg that has not already been set,
+// and returns the names of the flags it set, in order. It reports an error if
+// cfg names a flag that is not defined in fs, or if a value is invalid; in
+// that case, flags earlier in order may already have been set.
+//
+// Flags set by ApplyConfig are not marked as set in fs, so a flag given later
+// on the command line, or by ParseEnv, takes precedence. Thus, for the
+// precedence command line > environment > config file > default, call
+// fs.Parse, then ParseEnv, then LoadConfig or ApplyConfig; or use ConfigFlag
+// to load the file during fs.Parse, and call ParseEnv afterward.
+//
+// If the value of a flag is a *defaultflag.Value, its source is recorded as
+// defaultflag.ConfigFile with origin as the origin. A repeatable flag that
+// can be reset, such as a multiflag.Value, is replaced rather than extended
+// by the first value given for it later, as by the command line or ParseEnv;
+// until then, the Value of its flag.Flag is a wrapper, whose Unwrap method
+// returns the original value.
+func ApplyConfig(fs *flag.FlagSet, cfg Config, origin string) ([]string, error) {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	var applied []string
+	for _, name := range slices.Sorted(maps.Keys(cfg)) {
+		f := fs.Lookup(name)
+		if f == nil {
+			return applied, fmt.Errorf("goflags: config %s sets unknown flag %q", origin, name)
+		} else if set[name] {
+			continue
+		}
+		if err := setConfigValues(f, cfg[name], origin); err != nil {
+			return applied, err
+		}
+		applied = append(applied, name)
+	}
+	return applied, nil
+}
+
+// setConfigValues sets f to each of vals in order, as loaded from the
+// configuration file at origin. If f is repeatable and can be reset, its
+// value is wrapped in a configLayer, so that a value given later by the
+// command line or the environment replaces the values from the file.
+func setConfigValues(f *flag.Flag, vals []string, origin string) error {
+	v := f.Value
+	if c, ok := v.(*configLayer); ok {
+		v = c.Value
+	}
+	for _, s := range vals {
+		var err error
+		if dv, ok := findValue[*defaultflag.Value](v); ok {
+			err = dv.SetFrom(defaultflag.ConfigFile, origin, s)
+		} else {
+			err = v.Set(s)
+		}
+		if err != nil {
+			return fmt.Errorf("goflags: invalid value %q for flag -%s in %s: %w", s, f.Name, origin, err)
+		}
+	}
+	if _, ok := f.Value.(*configLayer); !ok && isRepeated(v) && canReplace(v) {
+		f.Value = &configLayer{Value: v, f: f}
+	}
+	return nil
+}
+
+// A configLayer wraps the value of a repeatable flag set from a configuration
+// file. The first value set through it, as by the command line or ParseEnv,
+// replaces the values from the file rather than adding to them, and restores
+// the original value of the flag.
+type configLayer struct {
+	flag.Value
+	f *flag.Flag
+}
+
+func (c *configLayer) Set(s string) error {
+	if err := replaceValues(c.Value, []string{s}); err != nil {
+		return err
+	}
+	c.f.Value = c.Value
+	return nil
+}
+
+func (c *configLayer) Get() any {
+	if g, ok := c.Value.(flag.Getter); ok {
+		return g.Get()
+	}
+	return nil
+}
+
+func (c *configLayer) Unwrap() flag.Value { return c.Value }
+func (c *configLayer) IsBoolFlag() bool   { return isBoolFlag(c.Value) }
+
+// A ConfigValue is a flag.Value that loads a configuration file when it is
+// set. A *ConfigValue satisfies the flag.Value and flag.Getter interfaces.
+type ConfigValue struct {
+	fs     *flag.FlagSet
+	paths  []string
+	loaded map[string]bool // flags set by files already loaded
+}
+
+// ConfigFlag defines a flag in fs with the given name and usage, which loads
+// the named configuration file into fs with LoadConfig when it is parsed. The
+// flag may be repeated to load multiple files; a flag set by an earlier file
+// is not changed by a later one.
+//
+// Flags given on the command line before the config flag are not changed by
+// the file, and flags given after it override the file.
+func ConfigFlag(fs *flag.FlagSet, name, usage string) *ConfigValue {
+	v := &ConfigValue{fs: fs, loaded: make(map[string]bool)}
+	fs.Var(v, name, usage)
+	return v
+}
+
+// Paths returns the paths of the configuration files loaded, in order.
+func (v *ConfigValue) Paths() []string { return v.paths }
+
+// String satisfies part of the flag.Value interface.
+func (v *ConfigValue) String() string {
+	if v == nil {
+		return "" // the zero value, as used by flag.PrintDefaults
+	}
+	return strings.Join(v.paths, ",")
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *ConfigValue) Set(path string) error {
+	cfg, err := readConfig(path)
+	if err != nil {
+		return err
+	}
+	for name := range v.loaded {
+		delete(cfg, name)
+	}
+	applied, err := ApplyConfig(v.fs, cfg, path)
+	for _, name := range applied {
+		v.loaded[name] = true
+	}
+	if err != nil {
+		return err
+	}
+	v.paths = append(v.paths, path)
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type []string, the paths loaded.
+func (v *ConfigValue) Get() any { return v.paths }
+
+// add records a value for name, reporting an error for a duplicate.
+func (c Config) add(name string, vals ...string) error {
+	if name == "" {
+		return errors.New("empty key")
+	} else if _, ok := c[name]; ok {
+		return fmt.Errorf("duplicate key %q", name)
+	}
+	c[name] = vals
+	return nil
+}
+
+func parseJSON(data []byte, cfg Config) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil {
+		return err
+	}
+	return flattenJSON(obj, "", cfg)
+}
+
+func flattenJSON(obj map[string]any, prefix string, cfg Config) error {
+	for key, val := range obj {
+		name := prefix + key
+		switch t := val.(type) {
+		case map[string]any:
+			if err := flattenJSON(t, name+".", cfg); err != nil {
+				return err
+			}
+			continue
+		case []any:
+			vals := make([]string, len(t))
+			for i, e := range t {
+				s, ok := jsonScalar(e)
+				if !ok {
+					return fmt.Errorf("key %q: list elements must be scalars", name)
+				}
+				vals[i] = s
+			}
+			if err := cfg.add(name, vals...); err != nil {
+				return err
+			}
+			continue
+		}
+		s, ok := jsonScalar(val)
+		if !ok {
+			return fmt.Errorf("key %q: null is not a valid value", name)
+		}
+		if err := cfg.add(name, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func jsonScalar(v any) (string, bool) {
+	switch t := v.(type) {
+	case string:
+		return t, true
+	case json.Number:
+		return t.String(), true
+	case bool:
+		return strconv.FormatBool(t), true
+	}
+	return "", false
+}
+
+func parseTOML(data []byte, cfg Config) error {
+	var prefix string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for ln := 1; sc.Scan(); ln++ {
+		line := strings.TrimSpace(stripComment(sc.Text()))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[[") {
+			return fmt.Errorf("line %d: arrays of tables are not supported", ln)
+		} else if rest, ok := strings.CutPrefix(line, "["); ok {
+			table, ok := strings.CutSuffix(rest, "]")
+			if !ok {
+				return fmt.Errorf("line %d: invalid table header", ln)
+			}
+			key, err := tomlKey(table)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", ln, err)
+			}
+			prefix = key + "."
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("line %d: expected key = value", ln)
+		}
+		key, err := tomlKey(k)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", ln, err)
+		}
+		vals, err := parseValue(strings.TrimSpace(v), true)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", ln, err)
+		}
+		if err := cfg.add(prefix+key, vals...); err != nil {
+			return fmt.Errorf("line %d: %w", ln, err)
+		}
+	}
+	return sc.Err()
+}
+
+// tomlKey parses a possibly dotted and quoted TOML key.
+func tomlKey(s string) (string, error) {
+	var parts []string
+	for _, p := range strings.Split(s, ".") {
+		p = strings.TrimSpace(p)
+		if q, err := unquote(p); err == nil {
+			p = q
+		} else if p == "" || strings.ContainsAny(p, " \t\"'") {
+			return "", fmt.Errorf("invalid key %q", s)
+		}
+		parts = append(parts, p)
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// yamlLine is a non-blank line of a YAML document.
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+func parseYAML(data []byte, cfg Config) error {
+	var lines []yamlLine
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for ln := 1; sc.Scan(); ln++ {
+		raw := sc.Text()
+		if strings.HasPrefix(raw, "\t") {
+			return fmt.Errorf("line %d: tabs are not allowed for indentation", ln)
+		}
+		text := strings.TrimRight(stripComment(raw), " ")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		lines = append(lines, yamlLine{num: ln, indent: len(text) - len(trimmed), text: trimmed})
+	}
+	rest, err := yamlMapping(lines, 0, "", cfg)
+	if err != nil {
+		return err
+	} else if len(rest) != 0 {
+		return fmt.Errorf("line %d: unexpected indentation", rest[0].num)
+	}
+	return nil
+}
+
+// yamlMapping parses a block mapping whose keys are at the given indent, and
+// returns the remaining lines.
+func yamlMapping(lines []yamlLine, indent int, prefix string, cfg Config) ([]yamlLine, error) {
+	for len(lines) != 0 && lines[0].indent == indent {
+		ln := lines[0]
+		if strings.HasPrefix(ln.text, "- ") || ln.text == "-" {
+			return nil, fmt.Errorf("line %d: expected a mapping key", ln.num)
+		}
+		k, v, ok := strings.Cut(ln.text, ":")
+		if !ok || (v != "" && v[0] != ' ') {
+			return nil, fmt.Errorf("line %d: expected key: value", ln.num)
+		}
+		key := strings.TrimSpace(k)
+		if q, err := unquote(key); err == nil {
+			key = q
+		}
+		name := prefix + key
+		v = strings.TrimSpace(v)
+		lines = lines[1:]
+		if v != "" {
+			if strings.ContainsAny(v[:1], "&*!|>{") {
+				return nil, fmt.Errorf("line %d: unsupported YAML syntax %q", ln.num, v)
+			}
+			vals, err := parseValue(v, false)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", ln.num, err)
+			}
+			if err := cfg.add(name, vals...); err != nil {
+				return nil, fmt.Errorf("line %d: %w", ln.num, err)
+			}
+			continue
+		}
+
+		// A key with no value introduces a nested mapping or a sequence.
+		switch {
+		case len(lines) == 0 || lines[0].indent < indent:
+			return nil, fmt.Errorf("line %d: key %q has no value", ln.num, key)
+		case strings.HasPrefix(lines[0].text, "- ") && lines[0].indent >= indent:
+			var vals []string
+			seq := lines[0].indent
+			for len(lines) != 0 && lines[0].indent == seq && strings.HasPrefix(lines[0].text, "- ") {
+				item := strings.TrimSpace(lines[0].text[2:])
+				s, err := parseScalar(item, false)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", lines[0].num, err)
+				}
+				vals = append(vals, s)
+				lines = lines[1:]
+			}
+			if err := cfg.add(name, vals...); err != nil {
+				return nil, fmt.Errorf("line %d: %w", ln.num, err)
+			}
+		case lines[0].indent > indent:
+			var err error
+			lines, err = yamlMapping(lines, lines[0].indent, name+".", cfg)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("line %d: key %q has no value", ln.num, key)
+		}
+	}
+	if len(lines) != 0 && lines[0].indent > indent {
+		return nil, fmt.Errorf("line %d: unexpected indentation", lines[0].num)
+	}
+	return lines, nil
+}
+
+// parseValue parses a scalar or a single-line [a, b] list. If toml is true,
+// underscores are removed from numbers and unquoted strings other than
+// numbers, booleans, and dates are rejected.
+func parseValue(s string, toml bool) ([]string, error) {
+	if rest, ok := strings.CutPrefix(s, "["); ok {
+		inner, ok := strings.CutSuffix(rest, "]")
+		if !ok {
+			return nil, errors.New("unterminated list (multi-line lists are not supported)")
+		}
+		var vals []string
+		for _, elt := range splitList(inner) {
+			if elt = strings.TrimSpace(elt); elt == "" {
+				continue // allow a trailing comma
+			}
+			v, err := parseScalar(elt, toml)
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, v)
+		}
+		return vals, nil
+	}
+	v, err := parseScalar(s, toml)
+	if err != nil {
+		return nil, err
+	}
+	return []string{v}, nil
+}
+
+func parseScalar(s string, toml bool) (string, error) {
+	if s == "" {
+		return "", errors.New("missing value")
+	}
+	if s[0] == '"' || s[0] == '\'' {
+		if strings.HasPrefix(s, `"""`) || strings.HasPrefix(s, "'''") {
+			return "", errors.New("multi-line strings are not supported")
+		}
+		return unquote(s)
+	}
+	if s[0] == '[' || s[0] == '{' {
+		return "", fmt.Errorf("nested value %q is not supported", s)
+	}
+	if toml {
+		if s == "true" || s == "false" {
+			return s, nil
+		}
+		if c := s[0]; c == '+' || c == '-' || '0' <= c && c <= '9' || s == "inf" || s == "nan" {
+			if strings.Contains(s, "_") {
+				return strings.ReplaceAll(s, "_", ""), nil
+			}
+			return s, nil
+		}
+		return "", fmt.Errorf("invalid value %q (strings must be quoted)", s)
+	}
+	return s, nil
+}
+
+// unquote removes TOML/YAML string quotes from s. A double-quoted string may
+// contain escapes; in a single-quoted string, a doubled single quote denotes
+// a single quote.
+func unquote(s string) (string, error) {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		inner := s[1 : len(s)-1]
+		if strings.Contains(strings.ReplaceAll(inner, "''", ""), "'") {
+			return "", fmt.Errorf("invalid quoted string %s", s)
+		}
+		return strings.ReplaceAll(inner, "''", "'"), nil
+	}
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		q, err := strconv.Unquote(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid quoted string %s", s)
+		}
+		return q, nil
+	}
+	return "", fmt.Errorf("not a quoted string: %s", s)
+}
+
+// splitList splits s at commas that are not inside quotes.
+func splitList(s string) []string {
+	var parts []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// stripComment removes a "#" comment that is not inside quotes. In YAML a
+// comment must follow whitespace; requiring that in TOML too is harmless,
+// except at the start of a line.
+func stripComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || s[i-1] == ' ' || s[i-1] == '\t' || s[i-1] == '[' || s[i-1] == ',' || s[i-1] == '=' || s[i-1] == ':' {
+				quote = c
+			}
+		case c == '#':
+			if i == 0 || s[i-1] == ' ' || s[i-1] == '\t' {
+				return s[:i]
+			}
+		}
+	}
+	return s
+}
@@ -0,0 +1,260 @@
+package goflags
+
+import (
+	"flag"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/goflags/defaultflag"
+	"github.com/creachadair/goflags/enumflag"
+	"github.com/creachadair/goflags/multiflag"
+)
+
+func TestParseConfig(t *testing.T) {
+	want := Config{
+		"name":    {"svc"},
+		"port":    {"8080"},
+		"verbose": {"true"},
+		"ratio":   {"0.5"},
+		"tags":    {"a", "b c"},
+		"db.host": {"db.local"},
+		"db.port": {"5432"},
+		"motto":   {"it's # not a comment"},
+	}
+	tests := []struct {
+		format, input string
+	}{
+		{"json", `{
+  "name": "svc", "port": 8080, "verbose": true, "ratio": 0.5,
+  "tags": ["a", "b c"],
+  "db": {"host": "db.local", "port": 5432},
+  "motto": "it's # not a comment"
+}`},
+		{"toml", `# Service configuration
+name = "svc"
+port = 8_080
+verbose = true   # trailing comment
+ratio = 0.5
+tags = ["a", 'b c',]
+motto = "it's # not a comment"
+
+[db]
+host = "db.local"
+"port" = 5432
+`},
+		{"yaml", `---
+# Service configuration
+name: svc
+port: 8080
+verbose: true  # trailing comment
+ratio: "0.5"
+tags:
+  - a
+  - 'b c'
+motto: "it's # not a comment"
+db:
+  host: db.local
+  port: 5432
+`},
+		{"yaml", `name: svc
+port: 8080
+verbose: true
+ratio: 0.5
+tags: [a, "b c"]
+motto: it's # not a comment
+db:
+    host: db.local
+    port: 5432
+`},
+	}
+	for _, test := range tests {
+		cfg, err := ParseConfig(strings.NewReader(test.input), test.format)
+		if err != nil {
+			t.Errorf("ParseConfig(%s): unexpected error: %v", test.format, err)
+			continue
+		}
+		if test.format == "yaml" && !strings.Contains(test.input, `"it's`) {
+			cfg["motto"] = []string{"it's # not a comment"} // plain scalar: comment removed
+		}
+		if !maps.EqualFunc(cfg, want, slices.Equal) {
+			t.Errorf("ParseConfig(%s): got %q, want %q", test.format, cfg, want)
+		}
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		format, input string
+	}{
+		{"ini", "a=b"},
+		{"json", `["not", "an", "object"]`},
+		{"json", `{"a": null}`},
+		{"json", `{"a": [{"b": 1}]}`},
+		{"toml", `a = unquoted`},
+		{"toml", `a = """multi"""`},
+		{"toml", `[[servers]]`},
+		{"toml", `a = [1, 2`},
+		{"toml", "a = 1\na = 2"},
+		{"toml", `novalue`},
+		{"yaml", "a:\n\tb: 1"},
+		{"yaml", "a: &anchor 1"},
+		{"yaml", "a: |\n  text"},
+		{"yaml", "a:"},
+		{"yaml", "a: 1\n  b: 2"},
+		{"yaml", "- a\n- b"},
+		{"yaml", "a: {b: 1}"},
+	}
+	for _, test := range tests {
+		if cfg, err := ParseConfig(strings.NewReader(test.input), test.format); err == nil {
+			t.Errorf("ParseConfig(%s, %q): got %q, wanted error", test.format, test.input, cfg)
+		}
+	}
+}
+
+type listValue []string
+
+func (l *listValue) String() string     { return strings.Join(*l, ",") }
+func (l *listValue) Set(s string) error { *l = append(*l, s); return nil }
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Writing %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeFile(t, "app.toml", `
+name = "from-file"
+port = 9000
+tags = ["a", "b"]
+[db]
+host = "file-host"
+`)
+	t.Setenv("APP_DB_HOST", "env-host")
+
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	name := fs.String("name", "default", "Name")
+	port := defaultflag.Wrap(new(listValue))
+	fs.Var(port, "port", "Port")
+	host := fs.String("db.host", "localhost", "Database host")
+	var tags listValue
+	fs.Var(&tags, "tags", "Tags")
+
+	if err := fs.Parse([]string{"-name", "from-flag"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if _, err := ParseEnv(fs, "APP"); err != nil {
+		t.Fatalf("ParseEnv failed: %v", err)
+	}
+	applied, err := LoadConfig(fs, path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if want := []string{"port", "tags"}; !slices.Equal(applied, want) {
+		t.Errorf("Applied: got %q, want %q", applied, want)
+	}
+	if *name != "from-flag" || *host != "env-host" || port.String() != "9000" {
+		t.Errorf("Values: name=%q host=%q port=%q", *name, *host, port.String())
+	}
+	if want := []string{"a", "b"}; !slices.Equal(tags, want) {
+		t.Errorf("Tags: got %q, want %q", tags, want)
+	}
+	if port.Source() != defaultflag.ConfigFile || port.Origin() != path {
+		t.Errorf("Source of -port: got %v %q", port.Source(), port.Origin())
+	}
+
+	bad := writeFile(t, "bad.json", `{"nonesuch": 1}`)
+	if _, err := LoadConfig(fs, bad); err == nil {
+		t.Error("LoadConfig with unknown flag: got nil, wanted error")
+	}
+	if _, err := LoadConfig(fs, filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadConfig with missing file: got nil, wanted error")
+	}
+}
+
+func TestConfigFlag(t *testing.T) {
+	first := writeFile(t, "first.yaml", "name: first\nport: 1\n")
+	second := writeFile(t, "second.json", `{"name": "second", "host": "h2", "port": "2"}`)
+	t.Setenv("HOST", "env-host")
+
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	cv := ConfigFlag(fs, "config", "Configuration file")
+	name := fs.String("name", "default", "Name")
+	host := fs.String("host", "localhost", "Host")
+	port := fs.String("port", "0", "Port")
+
+	if err := fs.Parse([]string{"-port", "99", "-config", first, "-config", second, "-name", "flag"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if _, err := ParseEnv(fs, ""); err != nil {
+		t.Fatalf("ParseEnv failed: %v", err)
+	}
+	if *name != "flag" || *host != "env-host" || *port != "99" {
+		t.Errorf("Values: name=%q host=%q port=%q", *name, *host, *port)
+	}
+	if got, want := cv.Paths(), []string{first, second}; !slices.Equal(got, want) {
+		t.Errorf("Paths: got %q, want %q", got, want)
+	}
+
+	fs2 := flag.NewFlagSet("config", flag.ContinueOnError)
+	fs2.SetOutput(io.Discard)
+	ConfigFlag(fs2, "config", "Configuration file")
+	fs2.String("name", "default", "Name")
+	if err := fs2.Parse([]string{"-config", second}); err == nil {
+		t.Error("Parse with unknown flags in config: got nil, wanted error")
+	}
+}
+
+func TestConfigRepeatable(t *testing.T) {
+	path := writeFile(t, "app.json", `{"tag": ["a", "b"], "env": ["a"]}`)
+	t.Setenv("ENV", "c")
+
+	newTags := func() *multiflag.Value {
+		return multiflag.Wrap(func() flag.Getter { return enumflag.New("a", "b", "c") })
+	}
+	tests := []struct {
+		args     []string
+		tag, env []any
+	}{
+		// Values from the file are kept if no later source sets the flag.
+		{[]string{"-config", path}, []any{"a", "b"}, []any{"c"}},
+		// Later values from the command line replace the values from the file.
+		{[]string{"-config", path, "-tag", "c", "-tag", "a"}, []any{"c", "a"}, []any{"c"}},
+		// Earlier values from the command line are not changed by the file.
+		{[]string{"-tag", "c", "-config", path}, []any{"c"}, []any{"c"}},
+	}
+	for _, tc := range tests {
+		fs := flag.NewFlagSet("config", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		ConfigFlag(fs, "config", "Configuration file")
+		tag, env := newTags(), newTags()
+		fs.Var(tag, "tag", "Tags")
+		fs.Var(defaultflag.Wrap(env), "env", "Tags from the environment")
+		if err := fs.Parse(tc.args); err != nil {
+			t.Fatalf("Parse %q failed: %v", tc.args, err)
+		}
+		if _, err := ParseEnv(fs, ""); err != nil {
+			t.Fatalf("ParseEnv failed: %v", err)
+		}
+		if got := tag.Results(); !slices.Equal(got, tc.tag) {
+			t.Errorf("Parse %q: got -tag %q, want %q", tc.args, got, tc.tag)
+		}
+		if got := env.Results(); !slices.Equal(got, tc.env) {
+			t.Errorf("Parse %q: got -env %q, want %q", tc.args, got, tc.env)
+		}
+		if dv, ok := fs.Lookup("env").Value.(*defaultflag.Value); !ok {
+			t.Errorf("Parse %q: -env has value %T, want the original", tc.args, fs.Lookup("env").Value)
+		} else if dv.Source() != defaultflag.Environment {
+			t.Errorf("Parse %q: -env has source %v, want %v", tc.args, dv.Source(), defaultflag.Environment)
+		}
+	}
+}
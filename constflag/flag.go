@@ -0,0 +1,134 @@
+// Package constflag defines a flag.Value wrapper that can be locked against
+// further changes.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/constflag"
+//	  "github.com/creachadair/goflags/urlflag"
+//	)
+//
+//	var authServer = constflag.Wrap(&urlflag.Value{Schemes: []string{"https"}})
+//	func init() {
+//	  flag.Var(authServer, "auth-server", "Authentication server URL")
+//	}
+//
+//	func main() {
+//	  flag.Parse()
+//	  constflag.LockAll(flag.CommandLine)
+//	  ...
+//	}
+//
+// After the value is locked, any further call to Set, for example from an
+// administrative endpoint that updates flags at runtime or an accidental
+// second call to flag.Parse, fails with ErrLocked.
+package constflag
+
+import (
+	"errors"
+	"flag"
+	"sync"
+)
+
+// ErrLocked is the error reported by Set for a locked value.
+var ErrLocked = errors.New("constflag: value is locked and cannot be changed")
+
+// A Value wraps a flag.Value that can be locked. A *Value satisfies the
+// flag.Value and flag.Getter interfaces. Its methods are safe for concurrent
+// use, provided the wrapped value is not modified except through them.
+type Value struct {
+	mu     sync.Mutex
+	v      flag.Value
+	locked bool
+}
+
+// Wrap returns an unlocked *Value that wraps v.
+func Wrap(v flag.Value) *Value { return &Value{v: v} }
+
+// Locked returns a *Value that wraps v and is already locked, for a value
+// that must keep its default.
+func Locked(v flag.Value) *Value { return &Value{v: v, locked: true} }
+
+// Lock locks v, so that every subsequent call to Set fails. Locking is
+// permanent.
+func (v *Value) Lock() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.locked = true
+}
+
+// IsLocked reports whether v is locked.
+func (v *Value) IsLocked() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.locked
+}
+
+// Unwrap returns the wrapped flag.Value.
+func (v *Value) Unwrap() flag.Value { return v.v }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string {
+	if v == nil || v.v == nil {
+		return "" // the zero value, as used by flag.PrintDefaults
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.v.String()
+}
+
+// Set satisfies part of the flag.Value interface. It reports ErrLocked if v
+// is locked.
+func (v *Value) Set(s string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.locked {
+		return ErrLocked
+	}
+	return v.v.Set(s)
+}
+
+// Get satisfies the flag.Getter interface. If the wrapped value is a
+// flag.Getter, it returns the result of its Get method; otherwise it returns
+// the wrapped flag.Value.
+func (v *Value) Get() any {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if g, ok := v.v.(flag.Getter); ok {
+		return g.Get()
+	}
+	return v.v
+}
+
+// IsBoolFlag reports whether the wrapped value is a boolean flag.
+func (v *Value) IsBoolFlag() bool {
+	b, ok := v.v.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// LockAll locks every flag in fs whose value is, or wraps, a *Value.
+func LockAll(fs *flag.FlagSet) {
+	fs.VisitAll(func(f *flag.Flag) {
+		if v, ok := findValue(f.Value); ok {
+			v.Lock()
+		}
+	})
+}
+
+// findValue returns the *Value that is fv or is wrapped by it, as reported by
+// the Unwrap methods of fv and the values it wraps.
+func findValue(fv flag.Value) (*Value, bool) {
+	for fv != nil {
+		if v, ok := fv.(*Value); ok {
+			return v, true
+		}
+		u, ok := fv.(interface{ Unwrap() flag.Value })
+		if !ok {
+			break
+		}
+		fv = u.Unwrap()
+	}
+	return nil, false
+}
@@ -0,0 +1,58 @@
+package constflag
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/creachadair/goflags/defaultflag"
+	"github.com/creachadair/goflags/sizeflag"
+)
+
+func TestFlagBits(t *testing.T) {
+	size := Wrap(sizeflag.Base2(0))
+	fixed := Locked(sizeflag.Base10(7))
+	fs := flag.NewFlagSet("const", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(size, "size", "Size")
+	fs.Var(fixed, "fixed", "Fixed")
+
+	if err := fs.Parse([]string{"-size", "2k"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := size.Get().(int), 2048; got != want {
+		t.Errorf("Value for -size: got %v, want %v", got, want)
+	}
+	if err := fs.Parse([]string{"-fixed", "10"}); err == nil {
+		t.Error("Parse -fixed: got nil, wanted error")
+	}
+
+	LockAll(fs)
+	if !size.IsLocked() {
+		t.Error("After LockAll: -size is not locked")
+	}
+	if err := size.Set("4k"); !errors.Is(err, ErrLocked) {
+		t.Errorf("Set after Lock: got %v, want %v", err, ErrLocked)
+	}
+	if got, want := size.String(), "2K"; got != want {
+		t.Errorf("Value after failed Set: got %q, want %q", got, want)
+	}
+	if got, want := fixed.Get().(int), 7; got != want {
+		t.Errorf("Value for -fixed: got %v, want %v", got, want)
+	}
+}
+
+func TestLockAllWrapped(t *testing.T) {
+	size := Wrap(sizeflag.Base2(0))
+	fs := flag.NewFlagSet("const", flag.ContinueOnError)
+	fs.Var(defaultflag.Wrap(size), "size", "Size")
+
+	LockAll(fs)
+	if !size.IsLocked() {
+		t.Error("After LockAll: wrapped -size is not locked")
+	}
+	if err := fs.Set("size", "4k"); !errors.Is(err, ErrLocked) {
+		t.Errorf("Set after LockAll: got %v, want %v", err, ErrLocked)
+	}
+}
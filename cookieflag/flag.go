@@ -0,0 +1,201 @@
+// Package cookieflag defines a repeatable flag.Value implementation that
+// collects HTTP cookies.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/cookieflag"
+//	)
+//
+//	var cookies cookieflag.Value
+//	func init() {
+//	  flag.Var(&cookies, "cookie", cookies.Help("Send a cookie"))
+//	}
+//
+//	  ...
+//	  for _, c := range cookies.Cookies {
+//	    req.AddCookie(c)
+//	  }
+//
+// Each argument is either a single cookie in the syntax of a Set-Cookie
+// header ("session=abc; Path=/; Secure"), or "@file" naming a cookie file in
+// the Netscape format written by curl and browser extensions.
+package cookieflag
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Value represents a list of HTTP cookies. A pointer to a Value satisfies
+// the flag.Value and flag.Getter interfaces. Each occurrence of the flag adds
+// one or more cookies.
+type Value struct {
+	// The cookies parsed from the flag, in order of occurrence.
+	Cookies []*http.Cookie
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	return h + ` ("name=value; attr..." or @cookies.txt; repeatable)`
+}
+
+// Header returns the cookies formatted as the value of a Cookie request
+// header, without attributes.
+func (v *Value) Header() string {
+	parts := make([]string, len(v.Cookies))
+	for i, c := range v.Cookies {
+		parts[i] = (&http.Cookie{Name: c.Name, Value: c.Value}).String()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// SetCookies stores the cookies that apply to u in jar. A cookie without a
+// domain is treated as belonging to the host of u.
+func (v *Value) SetCookies(jar http.CookieJar, u *url.URL) { jar.SetCookies(u, v.Cookies) }
+
+// String satisfies part of the flag.Value interface.
+// It reports only the names of the cookies, not their values.
+func (v *Value) String() string {
+	names := make([]string, len(v.Cookies))
+	for i, c := range v.Cookies {
+		names[i] = c.Name
+	}
+	return fmt.Sprintf("%q", strings.Join(names, ", "))
+}
+
+// Redacted is the placeholder reported by ArgStrings for the value of each
+// cookie.
+const Redacted = "[redacted]"
+
+// ArgStrings satisfies the goflags.Repeatable interface. It returns one
+// "name=[redacted]" argument for each cookie, in the syntax accepted by Set,
+// so that the names of the cookies are reproduced but their values and
+// attributes are not.
+func (v *Value) ArgStrings() []string {
+	out := make([]string, len(v.Cookies))
+	for i, c := range v.Cookies {
+		out[i] = c.Name + "=" + Redacted
+	}
+	return out
+}
+
+// Snapshot satisfies the goflags.Snapshotter interface. It records the
+// current cookies, whose values String does not report, and returns a
+// function that restores them.
+func (v *Value) Snapshot() func() {
+	old := slices.Clone(v.Cookies)
+	return func() { v.Cookies = old }
+}
+
+// Reset discards all the cookies of v.
+func (v *Value) Reset() { v.Cookies = nil }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	if path, ok := strings.CutPrefix(s, "@"); ok {
+		cs, err := readFile(path)
+		if err != nil {
+			return err
+		}
+		v.Cookies = append(v.Cookies, cs...)
+		return nil
+	}
+	c, err := http.ParseSetCookie(s)
+	if err != nil {
+		return fmt.Errorf("cookieflag: %w", err)
+	}
+	v.Cookies = append(v.Cookies, c)
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type []*http.Cookie.
+func (v *Value) Get() any { return v.Cookies }
+
+// readFile reads the cookies from a file in the Netscape cookie file format.
+// Each non-comment line has seven tab-separated fields: domain, subdomain
+// flag, path, secure flag, expiry time in Unix seconds, name, and value. A
+// line prefixed by "#HttpOnly_" marks an HTTP-only cookie.
+func readFile(path string) ([]*http.Cookie, error) {
+	if path == "" {
+		return nil, errors.New("cookieflag: empty path")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cookieflag: %w", err)
+	}
+	defer f.Close()
+
+	var out []*http.Cookie
+	sc := bufio.NewScanner(f)
+	for ln := 1; sc.Scan(); ln++ {
+		line := strings.TrimRight(sc.Text(), "\r")
+		httpOnly := false
+		if rest, ok := strings.CutPrefix(line, "#HttpOnly_"); ok {
+			line, httpOnly = rest, true
+		} else if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		c, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("cookieflag: %s:%d: %w", path, ln, err)
+		}
+		c.HttpOnly = httpOnly
+		out = append(out, c)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("cookieflag: %w", err)
+	}
+	return out, nil
+}
+
+func parseLine(line string) (*http.Cookie, error) {
+	f := strings.Split(line, "\t")
+	if len(f) != 7 {
+		return nil, fmt.Errorf("got %d fields, want 7", len(f))
+	}
+	secure, err := parseFlag(f[3])
+	if err != nil {
+		return nil, err
+	}
+	exp, err := strconv.ParseInt(f[4], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expiry %q", f[4])
+	}
+	if f[5] == "" {
+		return nil, errors.New("empty cookie name")
+	}
+	c := &http.Cookie{
+		Domain: f[0],
+		Path:   f[2],
+		Secure: secure,
+		Name:   f[5],
+		Value:  f[6],
+	}
+	if exp > 0 { // zero means a session cookie
+		c.Expires = time.Unix(exp, 0).UTC()
+	}
+	return c, nil
+}
+
+func parseFlag(s string) (bool, error) {
+	switch strings.ToUpper(s) {
+	case "TRUE":
+		return true, nil
+	case "FALSE":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid flag %q, want TRUE or FALSE", s)
+}
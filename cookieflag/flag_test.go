@@ -0,0 +1,100 @@
+package cookieflag
+
+import (
+	"flag"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const cookieFile = `# Netscape HTTP Cookie File
+# This is a comment.
+
+.example.com	TRUE	/	TRUE	2000000000	session	abc123
+#HttpOnly_example.com	FALSE	/api	FALSE	0	token	xyz
+`
+
+func TestFlagBits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookies.txt")
+	if err := os.WriteFile(path, []byte(cookieFile), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var cookies Value
+	fs := flag.NewFlagSet("cookie", flag.ContinueOnError)
+	fs.Var(&cookies, "cookie", cookies.Help("Cookie"))
+
+	if err := fs.Parse([]string{"-cookie", "theme=dark; Path=/; Secure; HttpOnly", "-cookie", "@" + path}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	cs := cookies.Get().([]*http.Cookie)
+	if len(cs) != 3 {
+		t.Fatalf("Got %d cookies, want 3", len(cs))
+	}
+	if c := cs[0]; c.Name != "theme" || c.Value != "dark" || c.Path != "/" || !c.Secure || !c.HttpOnly {
+		t.Errorf("Cookie 0: got %+v", c)
+	}
+	if c := cs[1]; c.Domain != ".example.com" || !c.Secure || c.HttpOnly || c.Expires.Unix() != 2000000000 {
+		t.Errorf("Cookie 1: got %+v", c)
+	}
+	if c := cs[2]; c.Name != "token" || c.Path != "/api" || !c.HttpOnly || !c.Expires.IsZero() {
+		t.Errorf("Cookie 2: got %+v", c)
+	}
+
+	if got, want := cookies.Header(), "theme=dark; session=abc123; token=xyz"; got != want {
+		t.Errorf("Header: got %q, want %q", got, want)
+	}
+	if s := cookies.String(); strings.Contains(s, "abc123") {
+		t.Errorf("String leaks cookie value: %s", s)
+	}
+
+	args := cookies.ArgStrings()
+	want := []string{"theme=[redacted]", "session=[redacted]", "token=[redacted]"}
+	if !slices.Equal(args, want) {
+		t.Errorf("ArgStrings: got %q, want %q", args, want)
+	}
+	var cp Value
+	for _, arg := range args {
+		if err := cp.Set(arg); err != nil {
+			t.Errorf("Set(%q): unexpected error: %v", arg, err)
+		}
+	}
+	if got, want := cp.String(), cookies.String(); got != want {
+		t.Errorf("After round trip: got %s, want %s", got, want)
+	}
+}
+
+func TestSetCookies(t *testing.T) {
+	var v Value
+	if err := v.Set("a=1; Path=/"); err != nil {
+		t.Fatalf("Set: unexpected error: %v", err)
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse("https://example.com/x")
+	v.SetCookies(jar, u)
+	if got := jar.Cookies(u); len(got) != 1 || got[0].Name != "a" {
+		t.Errorf("Jar cookies: got %v, want [a=1]", got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.txt")
+	os.WriteFile(bad, []byte("example.com\tTRUE\t/\tMAYBE\t0\tn\tv\n"), 0600)
+
+	for _, input := range []string{"", "noequals", "@", "@" + filepath.Join(dir, "missing"), "@" + bad} {
+		var v Value
+		if err := v.Set(input); err == nil {
+			t.Errorf("Set(%q): got %v, wanted error", input, v.Cookies)
+		}
+	}
+}
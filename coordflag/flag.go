@@ -0,0 +1,209 @@
+// Package coordflag defines flag.Value implementations for screen and window
+// dimensions and X11-style geometry strings.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/coordflag"
+//	)
+//
+//	var (
+//	  size   = coordflag.Size{Width: 1920, Height: 1080}
+//	  window coordflag.Geometry
+//	)
+//	func init() {
+//	  flag.Var(&size, "size", size.Help("Output image size"))
+//	  flag.Var(&window, "geometry", window.Help("Window geometry"))
+//	}
+//
+// A size is written "WxH", as "800x600". A geometry follows the X11
+// convention "[=][WxH][{+-}X{+-}Y]", as "800x600+10+20" or "-0+0"; a negative
+// offset is measured from the right or bottom edge of the screen.
+package coordflag
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Size is a pair of positive dimensions. A pointer to a Size satisfies the
+// flag.Value and flag.Getter interfaces.
+type Size struct {
+	Width, Height int
+}
+
+// ParseSize parses a size in the format "WxH".
+func ParseSize(s string) (Size, error) {
+	ws, hs, ok := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "x")
+	if !ok {
+		return Size{}, fmt.Errorf("coordflag: invalid size %q, want WxH", s)
+	}
+	w, err := parseDim(ws)
+	if err != nil {
+		return Size{}, err
+	}
+	h, err := parseDim(hs)
+	if err != nil {
+		return Size{}, err
+	}
+	return Size{Width: w, Height: h}, nil
+}
+
+func parseDim(s string) (int, error) {
+	if s == "" || s[0] < '0' || s[0] > '9' {
+		return 0, fmt.Errorf("coordflag: invalid dimension %q", s)
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("coordflag: invalid dimension %q", s)
+	}
+	return n, nil
+}
+
+// Help concatenates a human-readable string summarizing the format of z to h,
+// for use in generating a documentation string.
+func (z *Size) Help(h string) string { return h + " (WxH)" }
+
+// Area returns the product of the width and height.
+func (z *Size) Area() int { return z.Width * z.Height }
+
+// String satisfies part of the flag.Value interface.
+func (z *Size) String() string { return fmt.Sprintf("%dx%d", z.Width, z.Height) }
+
+// Set satisfies part of the flag.Value interface.
+func (z *Size) Set(s string) error {
+	sz, err := ParseSize(s)
+	if err != nil {
+		return err
+	}
+	*z = sz
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Size.
+func (z *Size) Get() any { return *z }
+
+// A Geometry is an X11 window geometry: an optional size and an optional
+// offset. A pointer to a Geometry satisfies the flag.Value and flag.Getter
+// interfaces.
+type Geometry struct {
+	Size    Size // valid if HasSize
+	HasSize bool
+
+	// The offset of the window, valid if HasOffset. If XNeg is set, X is the
+	// distance from the right edge of the screen to the right edge of the
+	// window; likewise YNeg for the bottom edge.
+	X, Y       int
+	XNeg, YNeg bool
+	HasOffset  bool
+}
+
+// ParseGeometry parses an X11 geometry string.
+func ParseGeometry(s string) (Geometry, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(s), "=")
+	if rest == "" {
+		return Geometry{}, errors.New("coordflag: empty geometry")
+	}
+	var g Geometry
+	if i := strings.IndexAny(rest, "+-"); i != 0 {
+		sizePart := rest
+		if i > 0 {
+			sizePart, rest = rest[:i], rest[i:]
+		} else {
+			rest = ""
+		}
+		sz, err := ParseSize(sizePart)
+		if err != nil {
+			return Geometry{}, err
+		}
+		g.Size, g.HasSize = sz, true
+	}
+	if rest != "" {
+		x, xneg, tail, err := parseOffset(rest)
+		if err != nil {
+			return Geometry{}, fmt.Errorf("coordflag: invalid geometry %q: %w", s, err)
+		}
+		y, yneg, tail, err := parseOffset(tail)
+		if err != nil || tail != "" {
+			return Geometry{}, fmt.Errorf("coordflag: invalid geometry %q", s)
+		}
+		g.X, g.XNeg, g.Y, g.YNeg, g.HasOffset = x, xneg, y, yneg, true
+	}
+	return g, nil
+}
+
+// parseOffset parses a leading "+N" or "-N" from s.
+func parseOffset(s string) (n int, neg bool, rest string, err error) {
+	if s == "" || (s[0] != '+' && s[0] != '-') {
+		return 0, false, "", errors.New("missing offset")
+	}
+	neg = s[0] == '-'
+	i := 1
+	for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+		i++
+	}
+	if i == 1 {
+		return 0, false, "", errors.New("missing offset digits")
+	}
+	n, err = strconv.Atoi(s[1:i])
+	return n, neg, s[i:], err
+}
+
+// Help concatenates a human-readable string summarizing the format of g to h,
+// for use in generating a documentation string.
+func (g *Geometry) Help(h string) string { return h + " ([WxH][{+-}X{+-}Y])" }
+
+// Position returns the offset of the top-left corner of a window with
+// geometry g on a screen of the given size. If g has no size, it is taken to
+// be the size of the screen.
+func (g *Geometry) Position(screen Size) (x, y int) {
+	sz := screen
+	if g.HasSize {
+		sz = g.Size
+	}
+	x, y = g.X, g.Y
+	if g.XNeg {
+		x = screen.Width - sz.Width - g.X
+	}
+	if g.YNeg {
+		y = screen.Height - sz.Height - g.Y
+	}
+	return x, y
+}
+
+// String satisfies part of the flag.Value interface.
+func (g *Geometry) String() string {
+	var sb strings.Builder
+	if g.HasSize {
+		sb.WriteString(g.Size.String())
+	}
+	if g.HasOffset {
+		sign := func(neg bool) string {
+			if neg {
+				return "-"
+			}
+			return "+"
+		}
+		fmt.Fprintf(&sb, "%s%d%s%d", sign(g.XNeg), g.X, sign(g.YNeg), g.Y)
+	}
+	return sb.String()
+}
+
+// Set satisfies part of the flag.Value interface.
+func (g *Geometry) Set(s string) error {
+	geo, err := ParseGeometry(s)
+	if err != nil {
+		return err
+	}
+	*g = geo
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Geometry.
+func (g *Geometry) Get() any { return *g }
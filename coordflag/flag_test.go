@@ -0,0 +1,69 @@
+package coordflag
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	size := Size{Width: 1920, Height: 1080}
+	var window Geometry
+
+	fs := flag.NewFlagSet("coord", flag.ContinueOnError)
+	fs.Var(&size, "size", size.Help("Size"))
+	fs.Var(&window, "geometry", window.Help("Geometry"))
+
+	if got, want := size.String(), "1920x1080"; got != want {
+		t.Errorf("Initial -size: got %q, want %q", got, want)
+	}
+	if err := fs.Parse([]string{"-size", "640X480", "-geometry", "800x600+10-20"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := size.Get().(Size), (Size{640, 480}); got != want {
+		t.Errorf("Value for -size: got %v, want %v", got, want)
+	}
+	if got := size.Area(); got != 640*480 {
+		t.Errorf("Area: got %d, want %d", got, 640*480)
+	}
+	want := Geometry{Size: Size{800, 600}, HasSize: true, X: 10, Y: 20, YNeg: true, HasOffset: true}
+	if got := window.Get().(Geometry); got != want {
+		t.Errorf("Value for -geometry: got %+v, want %+v", got, want)
+	}
+	if x, y := window.Position(Size{1920, 1080}); x != 10 || y != 460 {
+		t.Errorf("Position: got (%d, %d), want (10, 460)", x, y)
+	}
+}
+
+func TestParseGeometry(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"800x600", "800x600"},
+		{"=800x600+0+0", "800x600+0+0"},
+		{"-0+0", "-0+0"},
+		{"+5+5", "+5+5"},
+		{"100x50-10-10", "100x50-10-10"},
+	}
+	for _, test := range tests {
+		g, err := ParseGeometry(test.input)
+		if err != nil {
+			t.Errorf("ParseGeometry(%q): unexpected error: %v", test.input, err)
+		} else if got := g.String(); got != test.want {
+			t.Errorf("ParseGeometry(%q): got %q, want %q", test.input, got, test.want)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, bad := range []string{"", "800", "x600", "800x", "0x600", "-800x600", "800x-600", "8.5x6"} {
+		if z, err := ParseSize(bad); err == nil {
+			t.Errorf("ParseSize(%q): got %v, wanted error", bad, z)
+		}
+	}
+	for _, bad := range []string{"", "=", "800x600+10", "800x600+", "+1+2+3", "800 x 600", "+a+b", "800x600x2"} {
+		if g, err := ParseGeometry(bad); err == nil {
+			t.Errorf("ParseGeometry(%q): got %+v, wanted error", bad, g)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Package countflag defines a flag.Value implementation that counts the number
+// of times a flag occurs on the command line.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/countflag"
+//	)
+//
+//	var verbose countflag.Value
+//	func init() {
+//	  flag.Var(&verbose, "v", "Verbosity level (repeat for more)")
+//	}
+//
+// With this definition "-v -v -v" sets the count to 3, and "-v=5" sets the
+// count to 5 directly.
+package countflag
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// A Value represents a count of flag occurrences. A *Value satisfies the
+// flag.Getter interface, and reports itself as a boolean flag so that the
+// standard flag package accepts bare occurrences.
+type Value int
+
+// Int returns the value of the flag as an int.
+func (v Value) Int() int { return int(v) }
+
+// String satisfies part of the flag.Value interface.
+func (v Value) String() string { return strconv.Itoa(int(v)) }
+
+// Set satisfies part of the flag.Value interface. The string "true", which is
+// what the flag package passes for a bare occurrence, increments the count;
+// "false" resets it to zero. Any other argument must be a non-negative
+// integer, which replaces the current count.
+func (v *Value) Set(s string) error {
+	switch s {
+	case "true":
+		*v++
+		return nil
+	case "false":
+		*v = 0
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return fmt.Errorf("countflag: invalid count %q", s)
+	}
+	*v = Value(n)
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type int.
+func (v Value) Get() any { return int(v) }
+
+// IsBoolFlag marks the flag as boolean, so that a bare occurrence of the flag
+// increments the count.
+func (v Value) IsBoolFlag() bool { return true }
@@ -0,0 +1,41 @@
+package countflag
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{nil, 0},
+		{[]string{"-v"}, 1},
+		{[]string{"-v", "-v", "-v"}, 3},
+		{[]string{"-v=4"}, 4},
+		{[]string{"-v=4", "-v"}, 5},
+		{[]string{"-v", "-v", "-v=false"}, 0},
+		{[]string{"-v", "-v=0", "-v"}, 1},
+	}
+	for _, test := range tests {
+		var verbose Value
+		fs := flag.NewFlagSet("count", flag.ContinueOnError)
+		fs.Var(&verbose, "v", "Verbosity level")
+
+		if err := fs.Parse(test.args); err != nil {
+			t.Errorf("Parse %q failed: %v", test.args, err)
+			continue
+		}
+		if got := verbose.Get().(int); got != test.want {
+			t.Errorf("Parse %q: got %d, want %d", test.args, got, test.want)
+		}
+	}
+
+	var v Value
+	for _, bad := range []string{"", "-1", "lots", "1.5"} {
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %v, wanted error", bad, v)
+		}
+	}
+}
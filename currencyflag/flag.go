@@ -0,0 +1,222 @@
+// Package currencyflag defines a flag.Value implementation for amounts of
+// money with an ISO 4217 currency code.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/currencyflag"
+//	)
+//
+//	var price = currencyflag.Value{DefaultCurrency: "USD"}
+//	func init() {
+//	  flag.Var(&price, "price", price.Help("Unit price"))
+//	}
+//
+// With this definition "-price '19.99 EUR'", "-price EUR19.99", and
+// "-price 19.99" (in the default currency) are all accepted. Amounts are
+// stored as an integer number of minor units, such as cents, without passing
+// through floating point. An amount with more decimal places than the minor
+// unit of its currency allows, such as "1.5 JPY", is rejected rather than
+// rounded.
+package currencyflag
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// An Amount is a quantity of money in a currency.
+type Amount struct {
+	Units    int64  // the amount in minor units, e.g., cents
+	Currency string // the ISO 4217 code, e.g., "USD"
+}
+
+// Decimals returns the number of digits after the decimal separator in the
+// minor unit of the currency with the given code, and reports whether the code
+// is known.
+func Decimals(code string) (int, bool) {
+	d, ok := currencies[strings.ToUpper(code)]
+	return d, ok
+}
+
+// Parse parses an amount with a currency code before or after it. If s has no
+// code, def is used; if def is also empty, an error is reported.
+func Parse(s, def string) (Amount, error) {
+	num, code := splitCode(strings.TrimSpace(s))
+	if code == "" {
+		code = def
+	}
+	if code == "" {
+		return Amount{}, fmt.Errorf("currencyflag: missing currency code in %q", s)
+	}
+	code = strings.ToUpper(code)
+	dec, ok := currencies[code]
+	if !ok {
+		return Amount{}, fmt.Errorf("currencyflag: unknown currency %q", code)
+	}
+	units, err := parseUnits(num, dec)
+	if err != nil {
+		return Amount{}, err
+	}
+	return Amount{Units: units, Currency: code}, nil
+}
+
+// MustParse parses an amount, and panics if it is invalid. It is intended for
+// use in defining default values.
+func MustParse(s string) Amount {
+	a, err := Parse(s, "")
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// splitCode separates a leading or trailing alphabetic code from s.
+func splitCode(s string) (num, code string) {
+	isAlpha := func(c byte) bool { return 'A' <= c&^0x20 && c&^0x20 <= 'Z' }
+	i := 0
+	for i < len(s) && isAlpha(s[i]) {
+		i++
+	}
+	if i > 0 {
+		return strings.TrimSpace(s[i:]), s[:i]
+	}
+	j := len(s)
+	for j > 0 && isAlpha(s[j-1]) {
+		j--
+	}
+	return strings.TrimSpace(s[:j]), s[j:]
+}
+
+// parseUnits parses a decimal number with at most dec digits after the
+// decimal point as an integer count of 10^-dec.
+func parseUnits(s string, dec int) (int64, error) {
+	orig := s
+	neg := false
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		s, neg = rest, true
+	} else {
+		s = strings.TrimPrefix(s, "+")
+	}
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" && frac == "" || !allDigits(whole) || !allDigits(frac) || hasFrac && frac == "" {
+		return 0, fmt.Errorf("currencyflag: invalid amount %q", orig)
+	}
+	if len(frac) > dec {
+		return 0, fmt.Errorf("currencyflag: amount %q has more than %d decimal places", orig, dec)
+	}
+	frac += strings.Repeat("0", dec-len(frac))
+	digits := strings.TrimLeft(whole+frac, "0")
+	if digits == "" {
+		return 0, nil
+	}
+	if neg {
+		digits = "-" + digits
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("currencyflag: amount %q out of range", orig)
+	}
+	return n, nil
+}
+
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String renders a in the format "19.99 USD".
+func (a Amount) String() string {
+	dec, ok := currencies[a.Currency]
+	if !ok {
+		return fmt.Sprintf("%d %s", a.Units, a.Currency) // unknown; show raw units
+	}
+	sign, abs := "", strconv.FormatInt(a.Units, 10)
+	if a.Units < 0 {
+		sign, abs = "-", abs[1:]
+	}
+	if dec == 0 {
+		return sign + abs + " " + a.Currency
+	}
+	if len(abs) <= dec {
+		abs = strings.Repeat("0", dec-len(abs)+1) + abs
+	}
+	cut := len(abs) - dec
+	return sign + abs[:cut] + "." + abs[cut:] + " " + a.Currency
+}
+
+// Float64 returns a as a floating-point number of major units, e.g., dollars.
+// It is intended for display and approximate arithmetic only.
+func (a Amount) Float64() float64 {
+	dec := currencies[a.Currency]
+	return float64(a.Units) / math.Pow10(dec)
+}
+
+// A Value represents an amount of money. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The amount parsed from the flag.
+	Amount Amount
+
+	// If non-empty, the currency used for an amount without a code.
+	DefaultCurrency string
+
+	// If non-empty, only these currency codes are accepted.
+	Currencies []string
+
+	// If true, negative amounts are rejected.
+	NonNegative bool
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	if len(v.Currencies) != 0 {
+		return fmt.Sprintf("%s (amount and currency: %s)", h, strings.Join(v.Currencies, "|"))
+	}
+	return h + " (amount and currency, e.g., 19.99 USD)"
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string {
+	if v.Amount.Currency == "" {
+		return ""
+	}
+	return v.Amount.String()
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	a, err := Parse(s, v.DefaultCurrency)
+	if err != nil {
+		return err
+	}
+	if len(v.Currencies) != 0 && !slices.ContainsFunc(v.Currencies, func(c string) bool {
+		return strings.EqualFold(c, a.Currency)
+	}) {
+		return fmt.Errorf("currencyflag: currency %q not allowed, expected one of (%s)",
+			a.Currency, strings.Join(v.Currencies, "|"))
+	}
+	if v.NonNegative && a.Units < 0 {
+		return errors.New("currencyflag: negative amount not allowed")
+	}
+	v.Amount = a
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Amount.
+func (v *Value) Get() any { return v.Amount }
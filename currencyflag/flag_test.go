@@ -0,0 +1,89 @@
+package currencyflag
+
+import (
+	"flag"
+	"math"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	price := Value{DefaultCurrency: "USD"}
+	fs := flag.NewFlagSet("currency", flag.ContinueOnError)
+	fs.Var(&price, "price", price.Help("Price"))
+
+	if got, want := price.String(), `""`; got != want {
+		t.Errorf("Initial -price: got %s, want %s", got, want)
+	}
+	if err := fs.Parse([]string{"-price", "19.99"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := price.Get().(Amount), (Amount{1999, "USD"}); got != want {
+		t.Errorf("Value for -price: got %+v, want %+v", got, want)
+	}
+	if err := fs.Parse([]string{"-price", "jpy1500"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := price.String(), `"1500 JPY"`; got != want {
+		t.Errorf("String for -price: got %s, want %s", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Amount
+		str   string
+	}{
+		{"19.99 USD", Amount{1999, "USD"}, "19.99 USD"},
+		{"USD19.99", Amount{1999, "USD"}, "19.99 USD"},
+		{"EUR 5", Amount{500, "EUR"}, "5.00 EUR"},
+		{"0.5 gbp", Amount{50, "GBP"}, "0.50 GBP"},
+		{".05 USD", Amount{5, "USD"}, "0.05 USD"},
+		{"-3.25 CAD", Amount{-325, "CAD"}, "-3.25 CAD"},
+		{"1.234 KWD", Amount{1234, "KWD"}, "1.234 KWD"},
+		{"JPY 1000", Amount{1000, "JPY"}, "1000 JPY"},
+		{"0 USD", Amount{0, "USD"}, "0.00 USD"},
+		{"92233720368547758.07 USD", Amount{math.MaxInt64, "USD"}, "92233720368547758.07 USD"},
+		{"-92233720368547758.08 USD", Amount{math.MinInt64, "USD"}, "-92233720368547758.08 USD"},
+	}
+	for _, test := range tests {
+		got, err := Parse(test.input, "")
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", test.input, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("Parse(%q): got %+v, want %+v", test.input, got, test.want)
+		}
+		if s := got.String(); s != test.str {
+			t.Errorf("String(%+v): got %q, want %q", got, s, test.str)
+		}
+	}
+	if got := MustParse("12.50 USD").Float64(); got != 12.5 {
+		t.Errorf("Float64: got %v, want 12.5", got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		v     Value
+		input string
+	}{
+		{Value{}, "19.99"},
+		{Value{}, "19.99 XXX"},
+		{Value{}, "1.5 JPY"},
+		{Value{}, "1.999 USD"},
+		{Value{}, "USD"},
+		{Value{}, "1. USD"},
+		{Value{}, "1,000 USD"},
+		{Value{}, "USD 5 EUR"},
+		{Value{}, "92233720368547758.08 USD"},
+		{Value{Currencies: []string{"EUR"}}, "5 USD"},
+		{Value{NonNegative: true}, "-1 USD"},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.input); err == nil {
+			t.Errorf("Set(%q) with %+v: got %v, wanted error", test.input, test.v, test.v.Amount)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+// Package curveflag defines a flag.Value implementation that selects a
+// public-key algorithm and its parameters, for key generation tools.
+//
+// Example:
+//
+//	import (
+//	  "crypto/rand"
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/curveflag"
+//	)
+//
+//	var keyType = curveflag.Value{Key: curveflag.MustParse("ed25519")}
+//	func init() {
+//	  flag.Var(&keyType, "key-type", keyType.Help("Type of key to generate"))
+//	}
+//
+//	  ...
+//	  key, err := keyType.Key.Generate(rand.Reader)
+//
+// The accepted forms are:
+//
+//	ed25519
+//	rsa[:bits]     bits is 2048, 3072 (default), 4096, or 8192
+//	ecdsa[:curve]  curve is p256 (default), p384, or p521
+package curveflag
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// An Algorithm is a public-key algorithm.
+type Algorithm string
+
+// The supported algorithms.
+const (
+	Ed25519 Algorithm = "ed25519"
+	RSA     Algorithm = "rsa"
+	ECDSA   Algorithm = "ecdsa"
+)
+
+// rsaSizes are the accepted RSA modulus sizes, in bits.
+var rsaSizes = []int{2048, 3072, 4096, 8192}
+
+// curves maps the accepted ECDSA curve names to curves.
+var curves = []struct {
+	name  string
+	curve func() elliptic.Curve
+}{
+	{"p256", elliptic.P256},
+	{"p384", elliptic.P384},
+	{"p521", elliptic.P521},
+}
+
+// A KeyType describes a kind of key: an algorithm, and for RSA the modulus
+// size or for ECDSA the curve name. The zero value is invalid.
+type KeyType struct {
+	Algorithm Algorithm
+	Bits      int    // for RSA, the modulus size in bits
+	Curve     string // for ECDSA, the curve name, e.g., "p256"
+}
+
+// Parse parses a key type description.
+func Parse(s string) (KeyType, error) {
+	alg, param, hasParam := strings.Cut(strings.ToLower(strings.TrimSpace(s)), ":")
+	switch Algorithm(alg) {
+	case Ed25519:
+		if hasParam {
+			return KeyType{}, errors.New("curveflag: ed25519 does not take a parameter")
+		}
+		return KeyType{Algorithm: Ed25519}, nil
+
+	case RSA:
+		if !hasParam {
+			return KeyType{Algorithm: RSA, Bits: 3072}, nil
+		}
+		bits, err := strconv.Atoi(param)
+		if err != nil || !slices.Contains(rsaSizes, bits) {
+			return KeyType{}, fmt.Errorf("curveflag: invalid RSA size %q, expected one of %v", param, rsaSizes)
+		}
+		return KeyType{Algorithm: RSA, Bits: bits}, nil
+
+	case ECDSA:
+		if !hasParam {
+			return KeyType{Algorithm: ECDSA, Curve: "p256"}, nil
+		}
+		name := strings.NewReplacer("-", "", "_", "").Replace(param)
+		name = strings.TrimPrefix(name, "secp") // e.g., secp256r1
+		name = strings.TrimSuffix(name, "r1")
+		if !strings.HasPrefix(name, "p") {
+			name = "p" + name
+		}
+		for _, c := range curves {
+			if c.name == name {
+				return KeyType{Algorithm: ECDSA, Curve: c.name}, nil
+			}
+		}
+		return KeyType{}, fmt.Errorf("curveflag: unknown curve %q, expected p256, p384, or p521", param)
+	}
+	return KeyType{}, fmt.Errorf("curveflag: unknown algorithm %q, expected ed25519, rsa, or ecdsa", alg)
+}
+
+// MustParse parses a key type description, and panics if it is invalid. It
+// is intended for use in defining default values.
+func MustParse(s string) KeyType {
+	kt, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return kt
+}
+
+// String returns k in the format accepted by Parse.
+func (k KeyType) String() string {
+	switch k.Algorithm {
+	case RSA:
+		return fmt.Sprintf("rsa:%d", k.Bits)
+	case ECDSA:
+		return "ecdsa:" + k.Curve
+	}
+	return string(k.Algorithm)
+}
+
+// EllipticCurve returns the curve for an ECDSA key type, or nil.
+func (k KeyType) EllipticCurve() elliptic.Curve {
+	for _, c := range curves {
+		if k.Algorithm == ECDSA && c.name == k.Curve {
+			return c.curve()
+		}
+	}
+	return nil
+}
+
+// Generate generates a new private key of type k using random bytes from r.
+func (k KeyType) Generate(r io.Reader) (crypto.Signer, error) {
+	switch k.Algorithm {
+	case Ed25519:
+		_, priv, err := ed25519.GenerateKey(r)
+		return priv, err
+	case RSA:
+		return rsa.GenerateKey(r, k.Bits)
+	case ECDSA:
+		if c := k.EllipticCurve(); c != nil {
+			return ecdsa.GenerateKey(c, r)
+		}
+	}
+	return nil, fmt.Errorf("curveflag: invalid key type %q", k.String())
+}
+
+// A Value represents a choice of key type. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The key type parsed from the flag.
+	Key KeyType
+
+	// If non-empty, only these algorithms are accepted.
+	Allowed []Algorithm
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	var forms []string
+	for _, alg := range []Algorithm{Ed25519, RSA, ECDSA} {
+		if len(v.Allowed) != 0 && !slices.Contains(v.Allowed, alg) {
+			continue
+		}
+		switch alg {
+		case Ed25519:
+			forms = append(forms, "ed25519")
+		case RSA:
+			forms = append(forms, "rsa[:bits]")
+		case ECDSA:
+			forms = append(forms, "ecdsa[:curve]")
+		}
+	}
+	return fmt.Sprintf("%s (%s)", h, strings.Join(forms, "|"))
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return v.Key.String() }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	kt, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	if len(v.Allowed) != 0 && !slices.Contains(v.Allowed, kt.Algorithm) {
+		return fmt.Errorf("curveflag: algorithm %q not allowed", kt.Algorithm)
+	}
+	v.Key = kt
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type KeyType.
+func (v *Value) Get() any { return v.Key }
@@ -0,0 +1,88 @@
+package curveflag
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"flag"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	keyType := Value{Key: MustParse("ed25519")}
+	fs := flag.NewFlagSet("curve", flag.ContinueOnError)
+	fs.Var(&keyType, "key-type", keyType.Help("Key type"))
+
+	if got, want := keyType.String(), `"ed25519"`; got != want {
+		t.Errorf("Initial -key-type: got %s, want %s", got, want)
+	}
+	if err := fs.Parse([]string{"-key-type", "ECDSA:P-384"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := keyType.Get().(KeyType), (KeyType{Algorithm: ECDSA, Curve: "p384"}); got != want {
+		t.Errorf("Value for -key-type: got %v, want %v", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"ed25519", "ed25519"},
+		{"rsa", "rsa:3072"},
+		{"RSA:4096", "rsa:4096"},
+		{"ecdsa", "ecdsa:p256"},
+		{"ecdsa:p521", "ecdsa:p521"},
+		{"ecdsa:secp256r1", "ecdsa:p256"},
+		{"ecdsa:384", "ecdsa:p384"},
+	}
+	for _, test := range tests {
+		kt, err := Parse(test.input)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", test.input, err)
+		} else if got := kt.String(); got != test.want {
+			t.Errorf("Parse(%q): got %q, want %q", test.input, got, test.want)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	key, err := MustParse("ed25519").Generate(rand.Reader)
+	if err != nil {
+		t.Fatalf("Generate ed25519: %v", err)
+	} else if _, ok := key.(ed25519.PrivateKey); !ok {
+		t.Errorf("Generate ed25519: got %T", key)
+	}
+
+	key, err = MustParse("ecdsa:p384").Generate(rand.Reader)
+	if err != nil {
+		t.Fatalf("Generate ecdsa: %v", err)
+	} else if k, ok := key.(*ecdsa.PrivateKey); !ok || k.Curve.Params().BitSize != 384 {
+		t.Errorf("Generate ecdsa:p384: got %T", key)
+	}
+
+	if _, err := (KeyType{}).Generate(rand.Reader); err == nil {
+		t.Error("Generate with zero KeyType: got nil error")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		v     Value
+		input string
+	}{
+		{Value{}, ""},
+		{Value{}, "dsa"},
+		{Value{}, "ed25519:1"},
+		{Value{}, "rsa:1024"},
+		{Value{}, "rsa:x"},
+		{Value{}, "ecdsa:p224"},
+		{Value{Allowed: []Algorithm{Ed25519}}, "rsa"},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.input); err == nil {
+			t.Errorf("Set(%q) with %+v: got %v, wanted error", test.input, test.v, test.v.Key)
+		}
+	}
+}
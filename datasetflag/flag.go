@@ -0,0 +1,268 @@
+// Package datasetflag defines a flag.Value implementation for selecting
+// columns or fields of tabular data.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/datasetflag"
+//	)
+//
+//	var fields datasetflag.Value
+//	func init() {
+//	  flag.Var(&fields, "fields", fields.Help("Columns to output"))
+//	}
+//
+// A selection is a comma-separated list of terms. Each term is a 1-based
+// column number ("3"), a range of column numbers ("3-5"), an open range
+// ("4-", through the last column), or a column name ("name"). A term prefixed
+// with "-" excludes the columns it matches, so "-internal" drops the column
+// named "internal". If a selection has no including terms, it begins with all
+// columns.
+//
+// To apply a selection, resolve it against a header row (or a column count)
+// and pass the resulting indices to Apply for each row:
+//
+//	idx, err := fields.Selection.Resolve(header)
+//	...
+//	out := datasetflag.Apply(row, idx)
+package datasetflag
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A Term is a single element of a Selection.
+type Term struct {
+	Name    string // if non-empty, a column name
+	Lo, Hi  int    // a 1-based column range; Hi == 0 means through the end
+	Exclude bool   // if true, the term removes columns
+}
+
+// String renders t in the syntax accepted by Parse.
+func (t Term) String() string {
+	var s string
+	switch {
+	case t.Name != "":
+		s = t.Name
+	case t.Hi == 0:
+		s = strconv.Itoa(t.Lo) + "-"
+	case t.Lo == t.Hi:
+		s = strconv.Itoa(t.Lo)
+	default:
+		s = strconv.Itoa(t.Lo) + "-" + strconv.Itoa(t.Hi)
+	}
+	if t.Exclude {
+		return "-" + s
+	}
+	return s
+}
+
+// A Selection is an ordered list of terms that select columns.
+type Selection struct {
+	Terms []Term
+}
+
+// Parse parses a comma-separated selection. An empty string parses as an
+// empty selection, which selects all columns.
+func Parse(s string) (Selection, error) {
+	var sel Selection
+	if s == "" {
+		return sel, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		t, err := parseTerm(strings.TrimSpace(part))
+		if err != nil {
+			return Selection{}, err
+		}
+		sel.Terms = append(sel.Terms, t)
+	}
+	return sel, nil
+}
+
+// MustParse parses a selection, and panics if it is invalid. It is intended
+// for use in defining default values.
+func MustParse(s string) Selection {
+	sel, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return sel
+}
+
+func parseTerm(s string) (Term, error) {
+	var t Term
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		t.Exclude, s = true, rest
+	}
+	if s == "" {
+		return Term{}, errors.New("datasetflag: empty field")
+	}
+	if s[0] < '0' || s[0] > '9' {
+		t.Name = s
+		return t, nil
+	}
+	lo, hi, isRange := strings.Cut(s, "-")
+	n, err := parseIndex(lo)
+	if err != nil {
+		return Term{}, err
+	}
+	t.Lo, t.Hi = n, n
+	if isRange {
+		t.Hi = 0
+		if hi != "" {
+			m, err := parseIndex(hi)
+			if err != nil {
+				return Term{}, err
+			}
+			if m < n {
+				return Term{}, fmt.Errorf("datasetflag: invalid range %q", s)
+			}
+			t.Hi = m
+		}
+	}
+	return t, nil
+}
+
+func parseIndex(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("datasetflag: invalid column number %q", s)
+	}
+	return n, nil
+}
+
+// IsEmpty reports whether s has no terms.
+func (s Selection) IsEmpty() bool { return len(s.Terms) == 0 }
+
+// String renders s in the syntax accepted by Parse.
+func (s Selection) String() string {
+	parts := make([]string, len(s.Terms))
+	for i, t := range s.Terms {
+		parts[i] = t.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// Resolve returns the 0-based indices of the columns of header selected by s,
+// in selection order. A column selected by more than one term is reported
+// only once, at its first position. It reports an error if a name does not
+// occur in header, or a single column number is out of range; a range is
+// truncated to the available columns.
+func (s Selection) Resolve(header []string) ([]int, error) {
+	return s.resolve(len(header), func(name string) (int, error) {
+		i := slices.Index(header, name)
+		if i < 0 {
+			return 0, fmt.Errorf("datasetflag: unknown field %q", name)
+		}
+		return i, nil
+	})
+}
+
+// ResolveN is as Resolve, for data having n unnamed columns. It reports an
+// error if s contains any names.
+func (s Selection) ResolveN(n int) ([]int, error) {
+	return s.resolve(n, func(name string) (int, error) {
+		return 0, fmt.Errorf("datasetflag: field %q requires a header", name)
+	})
+}
+
+func (s Selection) resolve(n int, lookup func(string) (int, error)) ([]int, error) {
+	var out []int
+	seen := make([]bool, n)
+	add := func(i int) {
+		if !seen[i] {
+			seen[i] = true
+			out = append(out, i)
+		}
+	}
+	include := !slices.ContainsFunc(s.Terms, func(t Term) bool { return !t.Exclude })
+	if include {
+		for i := range n {
+			add(i)
+		}
+	}
+	var drop []int
+	for _, t := range s.Terms {
+		var cols []int
+		if t.Name != "" {
+			i, err := lookup(t.Name)
+			if err != nil {
+				return nil, err
+			}
+			cols = []int{i}
+		} else {
+			if t.Lo == t.Hi && t.Lo > n {
+				return nil, fmt.Errorf("datasetflag: column %d out of range (have %d)", t.Lo, n)
+			}
+			hi := t.Hi
+			if hi == 0 || hi > n {
+				hi = n
+			}
+			for i := t.Lo; i <= hi; i++ {
+				cols = append(cols, i-1)
+			}
+		}
+		if t.Exclude {
+			drop = append(drop, cols...)
+		} else {
+			for _, c := range cols {
+				add(c)
+			}
+		}
+	}
+	if len(drop) != 0 {
+		out = slices.DeleteFunc(out, func(i int) bool { return slices.Contains(drop, i) })
+	}
+	return out, nil
+}
+
+// Apply returns the elements of row at the given indices, as produced by
+// Resolve. An index beyond the end of row selects the empty string, so that
+// short rows remain aligned.
+func Apply(row []string, idx []int) []string {
+	out := make([]string, len(idx))
+	for i, j := range idx {
+		if j < len(row) {
+			out[i] = row[j]
+		}
+	}
+	return out
+}
+
+// A Value represents a column selection. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces. Each Set replaces the selection.
+type Value struct {
+	Selection Selection
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	return h + " (fields, e.g., 1,3-5,name,-other)"
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return v.Selection.String() }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	sel, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	v.Selection = sel
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Selection.
+func (v *Value) Get() any { return v.Selection }
@@ -0,0 +1,79 @@
+package datasetflag
+
+import (
+	"flag"
+	"slices"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var fields Value
+	fs := flag.NewFlagSet("dataset", flag.ContinueOnError)
+	fs.Var(&fields, "fields", fields.Help("Fields"))
+
+	if err := fs.Parse([]string{"-fields", "1, 3-4,name,-internal,6-"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := fields.String(), `"1,3-4,name,-internal,6-"`; got != want {
+		t.Errorf("Value for -fields: got %s, want %s", got, want)
+	}
+	sel := fields.Get().(Selection)
+	if got := len(sel.Terms); got != 5 {
+		t.Errorf("Terms: got %d, want 5", got)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	header := []string{"id", "name", "email", "internal", "age", "city", "zip"}
+	tests := []struct {
+		input string
+		want  []int
+	}{
+		{"", []int{0, 1, 2, 3, 4, 5, 6}},
+		{"-internal", []int{0, 1, 2, 4, 5, 6}},
+		{"-1,-internal,-6-", []int{1, 2, 4}},
+		{"name,id", []int{1, 0}},
+		{"3-5,email,1", []int{2, 3, 4, 0}},
+		{"5-,-zip", []int{4, 5}},
+		{"6-100", []int{5, 6}},
+		{"1,3-5,name,-internal", []int{0, 2, 4, 1}},
+	}
+	for _, test := range tests {
+		got, err := MustParse(test.input).Resolve(header)
+		if err != nil {
+			t.Errorf("Resolve(%q): unexpected error: %v", test.input, err)
+		} else if !slices.Equal(got, test.want) {
+			t.Errorf("Resolve(%q): got %v, want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	idx, err := MustParse("3,1,2").ResolveN(3)
+	if err != nil {
+		t.Fatalf("ResolveN failed: %v", err)
+	}
+	if got, want := Apply([]string{"a", "b", "c"}, idx), []string{"c", "a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("Apply: got %q, want %q", got, want)
+	}
+	if got, want := Apply([]string{"a"}, idx), []string{"", "a", ""}; !slices.Equal(got, want) {
+		t.Errorf("Apply short row: got %q, want %q", got, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, input := range []string{",", "1,,2", "0", "5-3", "1-x", "-", "3-4-5"} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q): got nil, wanted error", input)
+		}
+	}
+	header := []string{"a", "b"}
+	for _, input := range []string{"c", "3", "-c"} {
+		if _, err := MustParse(input).Resolve(header); err == nil {
+			t.Errorf("Resolve(%q): got nil, wanted error", input)
+		}
+	}
+	if _, err := MustParse("a").ResolveN(2); err == nil {
+		t.Error("ResolveN with name: got nil, wanted error")
+	}
+}
@@ -0,0 +1,139 @@
+// Package decimalflag defines a flag.Value implementation for exact decimal
+// numbers, such as prices, that must not be rounded through float64.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/decimalflag"
+//	)
+//
+//	var price = decimalflag.Value{Scale: 2}
+//	func init() {
+//	  flag.Var(&price, "price", price.Help("Unit price"))
+//	}
+//
+//	  ...
+//	  cents := price.Units() // "19.99" yields 1999
+//
+// The grammar of a decimal is:
+//
+//	decimal = ['+' | '-'] digits ['.' [digits]]
+//	        | ['+' | '-'] '.' digits
+//	digits  = [0-9]+
+//
+// Exponents, fractions such as "1/3", and special values such as "Inf" are not
+// accepted.
+package decimalflag
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// A Value represents an exact decimal number. A pointer to a Value satisfies
+// the flag.Value and flag.Getter interfaces. The zero value represents 0.
+type Value struct {
+	// If positive, the value may have at most this many digits after the
+	// decimal point, and must fit in an int64 when scaled by 10^Scale.
+	Scale int
+
+	rat *big.Rat
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	if v.Scale > 0 {
+		return fmt.Sprintf("%s (decimal, up to %d places)", h, v.Scale)
+	}
+	return h + " (decimal)"
+}
+
+// Rat returns a copy of the current value.
+func (v *Value) Rat() *big.Rat {
+	if v.rat == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Set(v.rat)
+}
+
+// Units returns the current value scaled by 10^Scale, that is, as an integer
+// count of the smallest representable units. If Scale is not positive, Units
+// returns the integer part of the value. The result is meaningful only if it
+// fits in an int64, which Set guarantees when Scale is positive.
+func (v *Value) Units() int64 {
+	r := v.Rat()
+	r.Mul(r, new(big.Rat).SetInt(pow10(max(v.Scale, 0))))
+	return new(big.Int).Quo(r.Num(), r.Denom()).Int64()
+}
+
+// String satisfies part of the flag.Value interface.
+// The value is rendered with Scale places if Scale is positive, or with as
+// many places as needed to represent it exactly.
+func (v *Value) String() string {
+	r := v.Rat()
+	if v.Scale > 0 {
+		return r.FloatString(v.Scale)
+	}
+	prec, _ := r.FloatPrec()
+	return r.FloatString(prec)
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	places, ok := decimalPlaces(s)
+	if !ok {
+		return fmt.Errorf("decimalflag: invalid decimal %q", s)
+	}
+	if v.Scale > 0 && places > v.Scale {
+		return fmt.Errorf("decimalflag: %q has more than %d decimal places", s, v.Scale)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return fmt.Errorf("decimalflag: invalid decimal %q", s)
+	}
+	if v.Scale > 0 {
+		n := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(v.Scale)))
+		if !n.Num().IsInt64() {
+			return fmt.Errorf("decimalflag: %q is out of range", s)
+		}
+	}
+	v.rat = r
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type *big.Rat.
+func (v *Value) Get() any { return v.Rat() }
+
+// decimalPlaces reports whether s is a valid decimal and, if so, the number of
+// digits following the decimal point.
+func decimalPlaces(s string) (int, bool) {
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		s = s[1:]
+	}
+	whole, frac, hasPoint := strings.Cut(s, ".")
+	if !allDigits(whole) || !allDigits(frac) {
+		return 0, false
+	}
+	if whole == "" && frac == "" {
+		return 0, false
+	} else if !hasPoint && whole == "" {
+		return 0, false
+	}
+	return len(frac), true
+}
+
+func allDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func pow10(n int) *big.Int { return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil) }
@@ -0,0 +1,101 @@
+package decimalflag
+
+import (
+	"bytes"
+	"flag"
+	"math/big"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	price := Value{Scale: 2}
+	var rate Value
+
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("decimal", flag.ContinueOnError)
+	fs.Var(&price, "price", price.Help("Unit price"))
+	fs.Var(&rate, "rate", rate.Help("Exchange rate"))
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+	t.Logf("Decimal flag set:\n%s", buf.String())
+
+	if got, want := price.String(), "0.00"; got != want {
+		t.Errorf("Initial value for -price: got %q, want %q", got, want)
+	}
+	if err := fs.Parse([]string{"-price", "19.9", "-rate", "0.1"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got := price.Units(); got != 1990 {
+		t.Errorf("Units for -price: got %d, want 1990", got)
+	}
+	if got, want := price.String(), "19.90"; got != want {
+		t.Errorf("Value for -price: got %q, want %q", got, want)
+	}
+
+	// The value of 0.1 is exact, unlike the float64 0.1.
+	if got, want := rate.Get().(*big.Rat), big.NewRat(1, 10); got.Cmp(want) != 0 {
+		t.Errorf("Value for -rate: got %v, want %v", got, want)
+	}
+	if got, want := rate.String(), "0.1"; got != want {
+		t.Errorf("String for -rate: got %q, want %q", got, want)
+	}
+
+	if err := fs.Parse([]string{"-price", "0.001"}); err == nil {
+		t.Errorf("Parse -price 0.001: got %q, wanted error", price.String())
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		scale int
+		in    string
+		str   string
+		units int64
+	}{
+		{0, "0", "0", 0},
+		{0, "12", "12", 12},
+		{0, "-12.50", "-12.5", -12},
+		{0, "+.25", "0.25", 0},
+		{0, "7.", "7", 7},
+		{0, "123456789012345678901234567890.000000000000000000000001",
+			"123456789012345678901234567890.000000000000000000000001", 0},
+		{3, "1.5", "1.500", 1500},
+		{3, "-0.001", "-0.001", -1},
+		{2, "92233720368547758.07", "92233720368547758.07", 9223372036854775807},
+	}
+	for _, test := range tests {
+		v := Value{Scale: test.scale}
+		if err := v.Set(test.in); err != nil {
+			t.Errorf("Set(%q) failed: %v", test.in, err)
+			continue
+		}
+		if got := v.String(); got != test.str {
+			t.Errorf("Set(%q): got %q, want %q", test.in, got, test.str)
+		}
+		if test.units != 0 || test.scale > 0 {
+			if got := v.Units(); got != test.units {
+				t.Errorf("Set(%q).Units(): got %d, want %d", test.in, got, test.units)
+			}
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		scale int
+		in    string
+	}{
+		{0, ""}, {0, "."}, {0, "-"}, {0, "1/3"}, {0, "1e3"}, {0, "Inf"},
+		{0, "1.2.3"}, {0, "0x10"}, {0, " 1"}, {0, "--1"},
+		{2, "1.234"},
+		{2, "92233720368547758.08"},
+	}
+	for _, test := range tests {
+		v := Value{Scale: test.scale}
+		if err := v.Set(test.in); err == nil {
+			t.Errorf("Set(%q): got %q, wanted error", test.in, v.String())
+		} else {
+			t.Logf("Set(%q) gave expected error: %v", test.in, err)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Package defaultflag defines a flag.Value wrapper that records where the
+// current value of a flag came from.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/defaultflag"
+//	  "github.com/creachadair/goflags/sizeflag"
+//	)
+//
+//	var cacheSize = defaultflag.Wrap(sizeflag.Base2(64 << 20))
+//	func init() {
+//	  flag.Var(cacheSize, "cache-size", "Maximum cache size")
+//	}
+//
+// A value set by the flag package, that is, from the command line, has source
+// CommandLine. Other layers of configuration set the value with SetFrom:
+//
+//	if s, ok := os.LookupEnv("APP_CACHE_SIZE"); ok {
+//	  cacheSize.SetFrom(defaultflag.Environment, "APP_CACHE_SIZE", s)
+//	}
+//
+// After all layers are applied, Report lists the source of each wrapped flag,
+// for example to implement a "-show-config" option.
+package defaultflag
+
+import (
+	"flag"
+	"fmt"
+)
+
+// A Source identifies where the value of a flag came from.
+type Source int
+
+// Sources, in the usual order of increasing precedence.
+const (
+	Default     Source = iota // the compiled-in default
+	ConfigFile                // a configuration file
+	Environment               // an environment variable
+	CommandLine               // a command-line argument
+)
+
+var sourceNames = [...]string{"default", "config", "env", "flag"}
+
+// String returns a short name for s.
+func (s Source) String() string {
+	if s >= 0 && int(s) < len(sourceNames) {
+		return sourceNames[s]
+	}
+	return fmt.Sprintf("Source(%d)", int(s))
+}
+
+// A Value wraps a flag.Value and records the source of its current value. A
+// *Value satisfies the flag.Value and flag.Getter interfaces.
+type Value struct {
+	flag.Value
+
+	src    Source
+	origin string
+}
+
+// Wrap returns a *Value that wraps v, with source Default.
+func Wrap(v flag.Value) *Value { return &Value{Value: v} }
+
+// Source reports the source of the current value.
+func (v *Value) Source() Source { return v.src }
+
+// Origin reports a description of where the current value came from, such as
+// the name of an environment variable or the path of a configuration file, as
+// given to SetFrom. It is empty for the Default and CommandLine sources.
+func (v *Value) Origin() string { return v.origin }
+
+// IsDefault reports whether the value still has its default.
+func (v *Value) IsDefault() bool { return v.src == Default }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string {
+	if v == nil || v.Value == nil {
+		return "" // the zero value, as used by flag.PrintDefaults
+	}
+	return v.Value.String()
+}
+
+// Set satisfies part of the flag.Value interface. It sets the wrapped value
+// with source CommandLine.
+func (v *Value) Set(s string) error { return v.SetFrom(CommandLine, "", s) }
+
+// SetFrom sets the wrapped value from s, and if that succeeds, records src
+// and origin as its source.
+func (v *Value) SetFrom(src Source, origin, s string) error {
+	if err := v.Value.Set(s); err != nil {
+		return err
+	}
+	v.src, v.origin = src, origin
+	return nil
+}
+
+// SetSource records src and origin as the source of the current value,
+// without changing the value. This is for layers that set the value through
+// flag.FlagSet.Set, so that the flag set also records the flag as set.
+func (v *Value) SetSource(src Source, origin string) { v.src, v.origin = src, origin }
+
+// Get satisfies the flag.Getter interface. If the wrapped value is a
+// flag.Getter, it returns the result of its Get method; otherwise it returns
+// the wrapped flag.Value.
+func (v *Value) Get() any {
+	if g, ok := v.Value.(flag.Getter); ok {
+		return g.Get()
+	}
+	return v.Value
+}
+
+// IsBoolFlag reports whether the wrapped value is a boolean flag.
+func (v *Value) IsBoolFlag() bool {
+	b, ok := v.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// Unwrap returns the wrapped flag.Value.
+func (v *Value) Unwrap() flag.Value { return v.Value }
+
+// An Entry describes the current value and source of a flag.
+type Entry struct {
+	Name   string
+	Value  string
+	Source Source
+	Origin string
+}
+
+// String renders e as "name=value (source)", including the origin if known.
+func (e Entry) String() string {
+	if e.Origin != "" {
+		return fmt.Sprintf("%s=%s (%s %s)", e.Name, e.Value, e.Source, e.Origin)
+	}
+	return fmt.Sprintf("%s=%s (%s)", e.Name, e.Value, e.Source)
+}
+
+// Report returns an entry for each flag in fs, in lexicographical order by
+// name. Flags whose values are not wrapped by a *Value are reported with
+// source CommandLine if they were set on the command line, and otherwise
+// Default.
+func Report(fs *flag.FlagSet) []Entry {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	var out []Entry
+	fs.VisitAll(func(f *flag.Flag) {
+		e := Entry{Name: f.Name, Value: f.Value.String()}
+		if v, ok := f.Value.(*Value); ok {
+			e.Source, e.Origin = v.Source(), v.Origin()
+		} else if set[f.Name] {
+			e.Source = CommandLine
+		}
+		out = append(out, e)
+	})
+	return out
+}
@@ -0,0 +1,67 @@
+package defaultflag
+
+import (
+	"flag"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/creachadair/goflags/sizeflag"
+)
+
+func TestFlagBits(t *testing.T) {
+	size := Wrap(sizeflag.Base2(1024))
+	limit := Wrap(sizeflag.Base10(5))
+	mode := Wrap(sizeflag.Base10(0))
+	var plain bool
+
+	fs := flag.NewFlagSet("default", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(size, "size", "Size")
+	fs.Var(limit, "limit", "Limit")
+	fs.Var(mode, "mode", "Mode")
+	fs.BoolVar(&plain, "plain", false, "Plain")
+
+	if !size.IsDefault() || size.Source() != Default {
+		t.Errorf("Initial -size source: got %v, want %v", size.Source(), Default)
+	}
+	if err := size.SetFrom(ConfigFile, "app.json", "2k"); err != nil {
+		t.Fatalf("SetFrom config failed: %v", err)
+	}
+	if err := limit.SetFrom(ConfigFile, "app.json", "10"); err != nil {
+		t.Fatalf("SetFrom config failed: %v", err)
+	}
+	if err := limit.SetFrom(Environment, "APP_LIMIT", "20"); err != nil {
+		t.Fatalf("SetFrom env failed: %v", err)
+	}
+	if err := fs.Parse([]string{"-size", "4k", "-plain"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	if got, want := size.Get().(int), 4096; got != want {
+		t.Errorf("Value for -size: got %v, want %v", got, want)
+	}
+	if err := limit.SetFrom(Environment, "APP_LIMIT", "bogus"); err == nil {
+		t.Error("SetFrom invalid: got nil, wanted error")
+	}
+
+	want := []Entry{
+		{Name: "limit", Value: "20", Source: Environment, Origin: "APP_LIMIT"},
+		{Name: "mode", Value: "0", Source: Default},
+		{Name: "plain", Value: "true", Source: CommandLine},
+		{Name: "size", Value: "4K", Source: CommandLine},
+	}
+	if got := Report(fs); !slices.Equal(got, want) {
+		t.Errorf("Report: got %+v, want %+v", got, want)
+	}
+	mode.SetSource(ConfigFile, "other.json")
+	if mode.Source() != ConfigFile || mode.Origin() != "other.json" || mode.String() != "0" {
+		t.Errorf("After SetSource: got %v %q %q", mode.Source(), mode.Origin(), mode.String())
+	}
+	if got, want := want[0].String(), "limit=20 (env APP_LIMIT)"; got != want {
+		t.Errorf("Entry string: got %q, want %q", got, want)
+	}
+	if got, want := want[3].String(), "size=4K (flag)"; got != want {
+		t.Errorf("Entry string: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,163 @@
+package goflags
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+)
+
+// A Deprecation records a flag that is deprecated, and its replacement.
+type Deprecation struct {
+	Old     string // the name of the deprecated flag
+	New     string // the name of the replacement flag, or ""
+	Message string // additional explanation, or ""
+
+	// If non-nil, Warn is called each time the deprecated flag is set.
+	// Deprecate sets this to a function that prints a warning to the output
+	// of the flag set; set it to nil to disable warnings.
+	Warn func(d *Deprecation)
+}
+
+// Warning returns a human-readable warning that d.Old is deprecated, as
+// printed by the default Warn function.
+func (d *Deprecation) Warning() string {
+	msg := "warning: flag -" + d.Old + " is deprecated"
+	if d.New != "" {
+		msg += ", use -" + d.New + " instead"
+	}
+	if d.Message != "" {
+		msg += ": " + d.Message
+	}
+	return msg
+}
+
+// Deprecate marks the flag old in fs as deprecated in favour of the flag
+// named new, with an optional message explaining the change.
+//
+// If new is not empty, it must already be defined in fs, and old must not be:
+// Deprecate defines old as a flag that sets the value of new, so that both
+// names continue to work while the flag is renamed. If new is empty, old must
+// already be defined; it keeps its value, with no replacement.
+//
+// A deprecated flag is omitted by PrintDefaults. If fs uses the default usage
+// function of the flag package, Deprecate replaces it with one that uses
+// PrintDefaults, so the flag is hidden from the default help; a custom usage
+// function should call PrintDefaults to do the same. Each time the flag is
+// set, the Warn function of the returned Deprecation is called. Deprecate
+// panics if the flags are not defined as required, as fs.Var does for a
+// duplicate flag.
+func Deprecate(fs *flag.FlagSet, old, new, message string) *Deprecation {
+	d := &Deprecation{Old: old, New: new, Message: message}
+	d.Warn = func(d *Deprecation) { fmt.Fprintln(fs.Output(), d.Warning()) }
+
+	if new == "" {
+		f := fs.Lookup(old)
+		if f == nil {
+			panic(fmt.Sprintf("goflags: deprecated flag -%s is not defined", old))
+		}
+		f.Value = &deprecatedValue{Value: f.Value, d: d}
+		f.Usage = "Deprecated: " + f.Usage
+	} else {
+		f := fs.Lookup(new)
+		if f == nil {
+			panic(fmt.Sprintf("goflags: replacement flag -%s is not defined", new))
+		}
+		usage := "Deprecated: use -" + new + " instead"
+		if message != "" {
+			usage += " (" + message + ")"
+		}
+		fs.Var(&deprecatedValue{Value: f.Value, d: d}, old, usage)
+		fs.Lookup(old).DefValue = f.DefValue
+	}
+	if fs == flag.CommandLine && isFunc(flag.Usage, defaultCommandLineUsage) {
+		flag.Usage = func() { printUsage(fs) }
+	} else if fs.Usage == nil || isFunc(fs.Usage, defaultFlagSetUsage) {
+		fs.Usage = func() { printUsage(fs) }
+	}
+	return d
+}
+
+// The default usage functions of the flag package, which Deprecate replaces
+// so that deprecated flags are hidden from the default help.
+var (
+	defaultCommandLineUsage = flag.Usage
+	defaultFlagSetUsage     = flag.NewFlagSet("", flag.ContinueOnError).Usage
+)
+
+// isFunc reports whether f and g are the same function. Method values of the
+// same method, such as the default usage of different flag sets, are
+// reported as the same.
+func isFunc(f, g func()) bool {
+	return reflect.ValueOf(f).Pointer() == reflect.ValueOf(g).Pointer()
+}
+
+// printUsage prints a usage message for fs in the format of the default usage
+// function of the flag package, using PrintDefaults.
+func printUsage(fs *flag.FlagSet) {
+	if fs.Name() == "" {
+		fmt.Fprintf(fs.Output(), "Usage:\n")
+	} else {
+		fmt.Fprintf(fs.Output(), "Usage of %s:\n", fs.Name())
+	}
+	PrintDefaults(fs)
+}
+
+// Deprecated reports whether f was marked as deprecated by Deprecate, and if
+// so returns its Deprecation.
+func Deprecated(f *flag.Flag) (*Deprecation, bool) {
+	if dv, ok := f.Value.(*deprecatedValue); ok {
+		return dv.d, true
+	}
+	return nil, false
+}
+
+// PrintDefaults prints to the output of fs the default values of the flags
+// in fs, in the format of fs.PrintDefaults, omitting deprecated flags.
+func PrintDefaults(fs *flag.FlagSet) {
+	visible := flag.NewFlagSet(fs.Name(), flag.ContinueOnError)
+	visible.SetOutput(fs.Output())
+	fs.VisitAll(func(f *flag.Flag) {
+		if _, ok := Deprecated(f); !ok {
+			visible.Var(f.Value, f.Name, f.Usage)
+			visible.Lookup(f.Name).DefValue = f.DefValue
+		}
+	})
+	visible.PrintDefaults()
+}
+
+// deprecatedValue is the flag.Value registered for a deprecated flag.
+type deprecatedValue struct {
+	flag.Value
+	d *Deprecation
+}
+
+func (v *deprecatedValue) String() string {
+	if v == nil || v.Value == nil {
+		return "" // the zero value, as used by flag.PrintDefaults
+	}
+	return v.Value.String()
+}
+
+func (v *deprecatedValue) Set(s string) error {
+	if err := v.Value.Set(s); err != nil {
+		return err
+	}
+	if v.d.Warn != nil {
+		v.d.Warn(v.d)
+	}
+	return nil
+}
+
+func (v *deprecatedValue) Get() any {
+	if g, ok := v.Value.(flag.Getter); ok {
+		return g.Get()
+	}
+	return v.Value
+}
+
+func (v *deprecatedValue) IsBoolFlag() bool {
+	b, ok := v.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+func (v *deprecatedValue) Unwrap() flag.Value { return v.Value }
@@ -0,0 +1,108 @@
+package goflags
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDeprecate(t *testing.T) {
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("deprecate", flag.ContinueOnError)
+	fs.SetOutput(&buf)
+	addr := fs.String("listen-addr", "localhost:80", "Address to listen on")
+	verbose := fs.Bool("verbose", false, "Verbose logging")
+	legacy := fs.Bool("legacy", false, "Use the legacy protocol")
+
+	d := Deprecate(fs, "addr", "listen-addr", "removed in v2")
+	Deprecate(fs, "v", "verbose", "")
+	Deprecate(fs, "legacy", "", "")
+
+	if err := fs.Parse([]string{"-addr", ":8080", "-v", "-legacy"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if *addr != ":8080" || !*verbose || !*legacy {
+		t.Errorf("Values: addr=%q verbose=%v legacy=%v", *addr, *verbose, *legacy)
+	}
+	if got, want := strings.Split(strings.TrimSpace(buf.String()), "\n"), []string{
+		"warning: flag -addr is deprecated, use -listen-addr instead: removed in v2",
+		"warning: flag -v is deprecated, use -verbose instead",
+		"warning: flag -legacy is deprecated",
+	}; !slices.Equal(got, want) {
+		t.Errorf("Warnings:\ngot  %q\nwant %q", got, want)
+	}
+
+	if got, ok := Deprecated(fs.Lookup("addr")); !ok || got != d {
+		t.Errorf("Deprecated(-addr): got %v, %v; want %v, true", got, ok, d)
+	}
+	if _, ok := Deprecated(fs.Lookup("listen-addr")); ok {
+		t.Error("Deprecated(-listen-addr): got true, want false")
+	}
+	if got, want := fs.Lookup("addr").DefValue, "localhost:80"; got != want {
+		t.Errorf("Default for -addr: got %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	d.Warn = nil
+	if err := fs.Set("addr", ":9090"); err != nil {
+		t.Errorf("Set -addr failed: %v", err)
+	}
+	if buf.Len() != 0 || *addr != ":9090" {
+		t.Errorf("Set -addr without Warn: output %q, value %q", buf.String(), *addr)
+	}
+
+	buf.Reset()
+	fs.Usage()
+	help := buf.String()
+	t.Logf("Usage:\n%s", help)
+	if !strings.HasPrefix(help, "Usage of deprecate:\n") || !strings.Contains(help, "-listen-addr") || !strings.Contains(help, `(default "localhost:80")`) {
+		t.Errorf("Usage is missing expected text:\n%s", help)
+	}
+	for _, name := range []string{"-addr", "-v ", "-legacy"} {
+		if strings.Contains(help, "  "+name) {
+			t.Errorf("Usage includes deprecated flag %s:\n%s", name, help)
+		}
+	}
+	if got := Complete(fs, []string{"-"}); !slices.Equal(got, []string{"-listen-addr", "-verbose"}) {
+		t.Errorf("Complete: got %q, want only current flags", got)
+	}
+}
+
+func TestDeprecateCustomUsage(t *testing.T) {
+	fs := flag.NewFlagSet("deprecate", flag.ContinueOnError)
+	called := false
+	fs.Usage = func() { called = true }
+	fs.String("new", "", "New")
+	Deprecate(fs, "old", "new", "")
+	fs.Usage()
+	if !called {
+		t.Error("Deprecate replaced a custom usage function")
+	}
+}
+
+func TestDeprecatePanics(t *testing.T) {
+	tests := []struct {
+		old, new string
+	}{
+		{"old", "nonesuch"}, // replacement not defined
+		{"name", "other"},   // old already defined
+		{"nonesuch", ""},    // no flag to deprecate
+	}
+	for _, test := range tests {
+		fs := flag.NewFlagSet("deprecate", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		fs.String("name", "", "Name")
+		fs.String("other", "", "Other")
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Deprecate(%q, %q): did not panic", test.old, test.new)
+				}
+			}()
+			Deprecate(fs, test.old, test.new, "")
+		}()
+	}
+}
@@ -0,0 +1,98 @@
+package goflags
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/creachadair/goflags/defaultflag"
+)
+
+// A FlagInfo is a machine-readable description of a flag, as reported by
+// Describe. The default and current values are as reported by the String
+// method of the flag value.
+type FlagInfo struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`              // the Go type of the value, e.g., "time.Duration"
+	Usage   string   `json:"usage"`             // the usage string of the flag
+	Summary string   `json:"summary,omitempty"` // accepted values, from a Help method
+	Choices []string `json:"choices,omitempty"` // allowed values, if known
+	Default string   `json:"default"`
+	Value   string   `json:"value"`
+	Set     bool     `json:"set"`              // whether the flag was set, as for CheckGroups
+	Source  string   `json:"source,omitempty"` // the source of a defaultflag value
+	Origin  string   `json:"origin,omitempty"` // the origin of a defaultflag value
+
+	Deprecated bool   `json:"deprecated,omitempty"`
+	Section    string `json:"section,omitempty"` // the usage section, if any
+}
+
+// Describe returns a description of each flag in fs, in lexicographical order
+// by name. The allowed values of a flag are reported by its Choices method,
+// as provided by enumflag and setflag, and the summary of accepted values by
+// its Help method. Wrappers that provide an Unwrap method, such as
+// defaultflag.Value, are unwrapped to find these.
+func Describe(fs *flag.FlagSet) []FlagInfo {
+	isSet := setFlags(fs)
+	sectionOf := make(map[string]string)
+	sections.Lock()
+	for _, s := range sections.m[fs] {
+		for _, name := range s.names {
+			if _, ok := sectionOf[name]; !ok {
+				sectionOf[name] = s.title
+			}
+		}
+	}
+	sections.Unlock()
+
+	var out []FlagInfo
+	fs.VisitAll(func(f *flag.Flag) {
+		fi := FlagInfo{
+			Name:    f.Name,
+			Type:    valueType(f.Value),
+			Usage:   f.Usage,
+			Default: f.DefValue,
+			Value:   f.Value.String(),
+			Set:     isSet[f.Name],
+			Section: sectionOf[f.Name],
+		}
+		if h, ok := findValue[interface{ Help(string) string }](f.Value); ok {
+			fi.Summary = strings.TrimSpace(h.Help(""))
+		}
+		if c, ok := findValue[interface{ Choices() []string }](f.Value); ok {
+			fi.Choices = c.Choices()
+		}
+		if dv, ok := findValue[*defaultflag.Value](f.Value); ok {
+			fi.Source = dv.Source().String()
+			fi.Origin = dv.Origin()
+		}
+		_, fi.Deprecated = Deprecated(f)
+		out = append(out, fi)
+	})
+	return out
+}
+
+// valueType returns the name of the Go type of the concrete value of v, as
+// reported by its Get method, or else of v itself.
+func valueType(v flag.Value) string {
+	if g, ok := v.(flag.Getter); ok {
+		if val := g.Get(); val != nil {
+			return fmt.Sprintf("%T", val)
+		}
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// WriteJSON writes the descriptions of the flags in fs reported by Describe
+// to w, as an indented JSON array.
+func WriteJSON(w io.Writer, fs *flag.FlagSet) error {
+	info := Describe(fs)
+	if info == nil {
+		info = []FlagInfo{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(info)
+}
@@ -0,0 +1,73 @@
+package goflags
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/creachadair/goflags/defaultflag"
+	"github.com/creachadair/goflags/enumflag"
+)
+
+func TestDescribe(t *testing.T) {
+	fs := flag.NewFlagSet("describe", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Duration("timeout", time.Second, "Request timeout")
+	color := enumflag.New("auto", "always", "never")
+	fs.Var(defaultflag.Wrap(color), "color", "Use color")
+	fs.String("name", "", "Name")
+	fs.String("host", "", "Host")
+	Deprecate(fs, "server", "host", "")
+	Section(fs, "Output", "color")
+
+	if err := fs.Parse([]string{"-color", "never", "-server", "example.com"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	got := Describe(fs)
+	want := []FlagInfo{
+		{Name: "color", Type: "string", Usage: "Use color", Summary: "(auto|always|never)",
+			Choices: []string{"auto", "always", "never"}, Default: `"auto"`, Value: `"never"`,
+			Set: true, Source: "flag", Section: "Output"},
+		{Name: "host", Type: "string", Usage: "Host", Value: "example.com", Set: true},
+		{Name: "name", Type: "string", Usage: "Name"},
+		{Name: "server", Type: "string", Usage: "Deprecated: use -host instead", Value: "example.com",
+			Set: true, Deprecated: true},
+		{Name: "timeout", Type: "time.Duration", Usage: "Request timeout", Default: "1s", Value: "1s"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Describe: got %d flags, want %d", len(got), len(want))
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Name != w.Name || g.Type != w.Type || g.Usage != w.Usage || g.Summary != w.Summary ||
+			!slices.Equal(g.Choices, w.Choices) || g.Default != w.Default || g.Value != w.Value ||
+			g.Set != w.Set || g.Source != w.Source || g.Origin != w.Origin ||
+			g.Deprecated != w.Deprecated || g.Section != w.Section {
+			t.Errorf("Describe[%d]:\ngot  %+v\nwant %+v", i, g, w)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, fs); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	var decoded []FlagInfo
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Decoding JSON: %v\n%s", err, buf.String())
+	}
+	if len(decoded) != len(want) || decoded[0].Source != "flag" || !decoded[3].Deprecated {
+		t.Errorf("WriteJSON: got %+v", decoded)
+	}
+
+	buf.Reset()
+	if err := WriteJSON(&buf, flag.NewFlagSet("empty", flag.ContinueOnError)); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	if got, want := buf.String(), "[]\n"; got != want {
+		t.Errorf("WriteJSON with no flags: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,66 @@
+// Package goflags provides helpers for defining and populating flags, built
+// on the standard flag package and the flag.Value implementations in the
+// subpackages of this module.
+//
+// Bind defines flags for the fields of a struct, according to their types
+// and struct tags:
+//
+//	var cfg struct {
+//	  Addr    string        `flag:"addr,Service address"`
+//	  Timeout time.Duration `flag:"timeout,Request timeout"`
+//	  Cache   int64         `flag:"cache-size,Maximum cache size" size:"2"`
+//	}
+//	func init() {
+//	  if err := goflags.Bind(flag.CommandLine, &cfg); err != nil {
+//	    log.Fatal(err)
+//	  }
+//	}
+//
+// ParseEnv and LoadConfig fill in flags not set on the command line from
+// environment variables and configuration files respectively. When combined,
+// the precedence is command line, then environment, then file, then default:
+//
+//	flag.Parse()
+//	if _, err := goflags.ParseEnv(flag.CommandLine, "MYAPP"); err != nil {
+//	  log.Fatal(err)
+//	}
+//	if _, err := goflags.LoadConfig(flag.CommandLine, "myapp.toml"); err != nil {
+//	  log.Fatal(err)
+//	}
+//
+// HandleCompletion and WriteCompletionScript provide shell completion for
+// bash, fish, and zsh, using the values of flags that implement Completer.
+//
+// Exclusive, RequiresAll, and AtLeastOne declare constraints among the flags
+// of a flag set, which CheckGroups verifies after parsing.
+//
+// Deprecate keeps an old flag name working while it is renamed or removed,
+// warning when it is used and hiding it from the help printed by
+// PrintDefaults.
+//
+// Usage formats help text with flags listed in sections declared by Section,
+// and can replace the Usage function of a flag set. Forget discards the
+// constraints and sections of a flag set when it is no longer needed.
+//
+// Describe and WriteJSON report a machine-readable description of each flag,
+// including its type, default and current values, and allowed values.
+//
+// Args and CurrentArgs render the values of flags back into command-line
+// arguments that reproduce them. The value types of this module that report
+// quoted strings for help text implement ArgStringer, to report the unquoted
+// argument their Set methods accept.
+//
+// Namespace and Embed define the flags of a component under a prefix, such as
+// "db.addr", so that several components can share a flag set.
+//
+// A Reloader re-applies a configuration file to selected flags while a
+// program runs, on SIGHUP or when the file changes, and AtomicValue makes such
+// flags safe to read concurrently.
+//
+// An Exporter publishes the current values of flags as an expvar variable, or
+// as metrics in the Prometheus text format, with hooks to redact secrets.
+// Flagz serves a debug page that reports flags, and optionally updates them.
+//
+// NewValue constructs a flag value of any type from parse and format
+// functions, for one-off flag types.
+package goflags
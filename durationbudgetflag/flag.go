@@ -0,0 +1,159 @@
+// Package durationbudgetflag defines a flag.Value implementation for a total
+// time budget divided into named parts.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/durationbudgetflag"
+//	)
+//
+//	var timeout = durationbudgetflag.Value{
+//	  Parts: []string{"connect", "tls", "read"},
+//	}
+//	func init() {
+//	  flag.Var(&timeout, "timeout", timeout.Help("Request time budget"))
+//	}
+//
+// With this definition "-timeout total=30s,connect=5s,tls=5s,read=20s" sets
+// a total budget of 30 seconds, divided as given. "-timeout 10s" is shorthand
+// for "-timeout total=10s". The parts may not add up to more than the total;
+// if the total is omitted, it is the sum of the parts.
+package durationbudgetflag
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// A Budget is a total duration and named durations within it.
+type Budget struct {
+	Total time.Duration
+	Parts map[string]time.Duration
+}
+
+// Part returns the duration of the named part, or the total if that part was
+// not specified.
+func (b Budget) Part(name string) time.Duration {
+	if d, ok := b.Parts[name]; ok {
+		return d
+	}
+	return b.Total
+}
+
+// Unallocated returns the portion of the total not assigned to any part.
+func (b Budget) Unallocated() time.Duration { return b.Total - b.sum() }
+
+func (b Budget) sum() (s time.Duration) {
+	for _, d := range b.Parts {
+		s += d
+	}
+	return s
+}
+
+// String renders b in the syntax accepted by the flag, with parts in order of
+// name.
+func (b Budget) String() string {
+	parts := []string{"total=" + b.Total.String()}
+	for _, name := range slices.Sorted(maps.Keys(b.Parts)) {
+		parts = append(parts, name+"="+b.Parts[name].String())
+	}
+	return strings.Join(parts, ",")
+}
+
+const totalKey = "total"
+
+// A Value represents a time budget. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces. Each Set replaces the budget.
+type Value struct {
+	// The budget parsed from the flag.
+	Budget Budget
+
+	// If non-empty, the names of the parts that may be given.
+	Parts []string
+
+	// If true, the parts may overlap, so each part must be no longer than the
+	// total but the parts together may exceed it.
+	Overlapping bool
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	if len(v.Parts) != 0 {
+		return fmt.Sprintf("%s (total=DUR,NAME=DUR,... with names %s)", h, strings.Join(v.Parts, "|"))
+	}
+	return h + " (total=DUR,NAME=DUR,...)"
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string {
+	if v.Budget.Total == 0 && len(v.Budget.Parts) == 0 {
+		return ""
+	}
+	return v.Budget.String()
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	if s == "" {
+		return errors.New("durationbudgetflag: empty budget")
+	}
+	var b Budget
+	hasTotal := false
+	for _, elt := range strings.Split(s, ",") {
+		name, val, ok := strings.Cut(strings.TrimSpace(elt), "=")
+		if !ok {
+			name, val = totalKey, name
+		}
+		name = strings.TrimSpace(name)
+		d, err := time.ParseDuration(strings.TrimSpace(val))
+		if err != nil || d <= 0 {
+			return fmt.Errorf("durationbudgetflag: invalid duration %q for %q", val, name)
+		}
+		if name == totalKey {
+			if hasTotal {
+				return errors.New("durationbudgetflag: duplicate total")
+			}
+			b.Total, hasTotal = d, true
+			continue
+		}
+		if name == "" {
+			return fmt.Errorf("durationbudgetflag: missing name in %q", elt)
+		} else if len(v.Parts) != 0 && !slices.Contains(v.Parts, name) {
+			return fmt.Errorf("durationbudgetflag: unknown part %q, expected one of (%s)",
+				name, strings.Join(v.Parts, "|"))
+		} else if _, ok := b.Parts[name]; ok {
+			return fmt.Errorf("durationbudgetflag: duplicate part %q", name)
+		}
+		if b.Parts == nil {
+			b.Parts = make(map[string]time.Duration)
+		}
+		b.Parts[name] = d
+	}
+	if !hasTotal {
+		b.Total = b.sum()
+	}
+	for name, d := range b.Parts {
+		if d > b.Total {
+			return fmt.Errorf("durationbudgetflag: part %s=%v exceeds total %v", name, d, b.Total)
+		}
+	}
+	if sum := b.sum(); !v.Overlapping && sum > b.Total {
+		return fmt.Errorf("durationbudgetflag: parts total %v, exceeding budget %v", sum, b.Total)
+	}
+	v.Budget = b
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Budget.
+func (v *Value) Get() any { return v.Budget }
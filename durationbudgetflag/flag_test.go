@@ -0,0 +1,81 @@
+package durationbudgetflag
+
+import (
+	"flag"
+	"testing"
+	"time"
+)
+
+func TestFlagBits(t *testing.T) {
+	timeout := Value{Parts: []string{"connect", "tls", "read"}}
+	fs := flag.NewFlagSet("budget", flag.ContinueOnError)
+	fs.Var(&timeout, "timeout", timeout.Help("Budget"))
+
+	if got, want := timeout.String(), `""`; got != want {
+		t.Errorf("Initial -timeout: got %s, want %s", got, want)
+	}
+	if err := fs.Parse([]string{"-timeout", "total=30s,connect=5s,tls=5s,read=15s"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	b := timeout.Get().(Budget)
+	if b.Total != 30*time.Second || b.Part("connect") != 5*time.Second || b.Part("read") != 15*time.Second {
+		t.Errorf("Value for -timeout: got %+v", b)
+	}
+	if got, want := b.Unallocated(), 5*time.Second; got != want {
+		t.Errorf("Unallocated: got %v, want %v", got, want)
+	}
+	if got, want := timeout.String(), `"total=30s,connect=5s,read=15s,tls=5s"`; got != want {
+		t.Errorf("String for -timeout: got %s, want %s", got, want)
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		v     Value
+		input string
+		total time.Duration
+	}{
+		{Value{}, "10s", 10 * time.Second},
+		{Value{}, "connect=2s, read=3s", 5 * time.Second},
+		{Value{}, "1m,dial=10s", time.Minute},
+		{Value{Overlapping: true}, "total=10s,a=8s,b=8s", 10 * time.Second},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.input); err != nil {
+			t.Errorf("Set(%q): unexpected error: %v", test.input, err)
+		} else if got := test.v.Budget.Total; got != test.total {
+			t.Errorf("Set(%q): total is %v, want %v", test.input, got, test.total)
+		}
+	}
+
+	var v Value
+	if err := v.Set("total=4s,a=1s"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if got, want := v.Budget.Part("b"), 4*time.Second; got != want {
+		t.Errorf("Part(b): got %v, want %v", got, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		v     Value
+		input string
+	}{
+		{Value{}, ""},
+		{Value{}, "x"},
+		{Value{}, "total=-1s"},
+		{Value{}, "total=0s"},
+		{Value{}, "10s,20s"},
+		{Value{}, "=5s"},
+		{Value{}, "a=1s,a=2s"},
+		{Value{}, "total=10s,a=6s,b=6s"},
+		{Value{Overlapping: true}, "total=10s,a=11s"},
+		{Value{Parts: []string{"read"}}, "write=1s"},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.input); err == nil {
+			t.Errorf("Set(%q) with %+v: got %v, wanted error", test.input, test.v, test.v.Budget)
+		}
+	}
+}
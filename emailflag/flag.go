@@ -0,0 +1,131 @@
+// Package emailflag defines a flag.Value implementation for email addresses.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/emailflag"
+//	)
+//
+//	var owner = emailflag.Value{
+//	  NoDisplayName: true,
+//	  Domains:       []string{"example.com", "*.example.org"},
+//	}
+//	func init() {
+//	  flag.Var(&owner, "owner", owner.Help("Owner address"))
+//	}
+//
+// Addresses are parsed by net/mail, following RFC 5322. The domain of the
+// address is normalized to lower case; the local part is preserved as given.
+package emailflag
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+)
+
+// A Value represents an email address. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces. The zero value accepts any address,
+// with or without a display name.
+type Value struct {
+	// The normalized address parsed from the flag, e.g., "bob@example.com".
+	Address string
+
+	// The display name parsed from the flag, if any.
+	Name string
+
+	// If true, reject inputs having a display name, such as
+	// "Bob <bob@example.com>".
+	NoDisplayName bool
+
+	// If non-empty, the domain of the address must match one of these
+	// (compared without regard to case). A pattern of the form "*.domain"
+	// matches any subdomain of domain, but not domain itself.
+	Domains []string
+}
+
+// Help concatenates a human-readable string summarizing the constraints of v
+// to h, for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	if len(v.Domains) != 0 {
+		return fmt.Sprintf("%s (email address at %s)", h, strings.Join(v.Domains, "|"))
+	}
+	return h + " (email address)"
+}
+
+// Mail returns the current value as a *mail.Address, or nil if no address has
+// been set.
+func (v *Value) Mail() *mail.Address {
+	if v.Address == "" {
+		return nil
+	}
+	return &mail.Address{Name: v.Name, Address: v.Address}
+}
+
+// Domain returns the domain part of the current address.
+func (v *Value) Domain() string {
+	_, dom := split(v.Address)
+	return dom
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string {
+	if a := v.Mail(); a != nil && a.Name != "" {
+		return a.String()
+	}
+	return v.Address
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return errors.New("emailflag: empty address")
+	}
+	a, err := mail.ParseAddress(s)
+	if err != nil {
+		return fmt.Errorf("emailflag: invalid address %q: %w", s, err)
+	}
+	if v.NoDisplayName && (a.Name != "" || strings.ContainsAny(s, "<>")) {
+		return fmt.Errorf("emailflag: display name not allowed in %q", s)
+	}
+	local, dom := split(a.Address)
+	dom = strings.ToLower(dom)
+	if len(v.Domains) != 0 && !slices.ContainsFunc(v.Domains, func(p string) bool {
+		return matchDomain(p, dom)
+	}) {
+		return fmt.Errorf("emailflag: domain %q not allowed, expected one of (%s)",
+			dom, strings.Join(v.Domains, "|"))
+	}
+	v.Address = local + "@" + dom
+	v.Name = a.Name
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is the string of the normalized address.
+func (v *Value) Get() any { return v.Address }
+
+// split separates the local part and domain of addr at the last "@".
+func split(addr string) (local, domain string) {
+	i := strings.LastIndex(addr, "@")
+	if i < 0 {
+		return addr, ""
+	}
+	return addr[:i], addr[i+1:]
+}
+
+// matchDomain reports whether dom, which is lower case, matches pattern.
+func matchDomain(pattern, dom string) bool {
+	pattern = strings.ToLower(pattern)
+	if rest, ok := strings.CutPrefix(pattern, "*."); ok {
+		return strings.HasSuffix(dom, "."+rest)
+	}
+	return dom == pattern
+}
@@ -0,0 +1,70 @@
+package emailflag
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var from Value
+	owner := Value{NoDisplayName: true, Domains: []string{"example.com"}}
+	fs := flag.NewFlagSet("email", flag.ContinueOnError)
+	fs.Var(&from, "from", from.Help("Sender"))
+	fs.Var(&owner, "owner", owner.Help("Owner"))
+
+	if got, want := owner.String(), `""`; got != want {
+		t.Errorf("Initial -owner: got %s, want %s", got, want)
+	}
+	if err := fs.Parse([]string{
+		"-from", "Alice Jones <alice@Mail.Example.ORG>",
+		"-owner", "Bob.Smith@EXAMPLE.com",
+	}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := from.Get().(string), "alice@mail.example.org"; got != want {
+		t.Errorf("Value for -from: got %q, want %q", got, want)
+	}
+	if got, want := from.Name, "Alice Jones"; got != want {
+		t.Errorf("Name for -from: got %q, want %q", got, want)
+	}
+	if got, want := from.String(), `"\"Alice Jones\" <alice@mail.example.org>"`; got != want {
+		t.Errorf("String for -from: got %s, want %s", got, want)
+	}
+	if got, want := owner.Address, "Bob.Smith@example.com"; got != want {
+		t.Errorf("Value for -owner: got %q, want %q", got, want)
+	}
+	if got, want := owner.Domain(), "example.com"; got != want {
+		t.Errorf("Domain for -owner: got %q, want %q", got, want)
+	}
+}
+
+func TestConstraints(t *testing.T) {
+	sub := []string{"*.example.com"}
+	tests := []struct {
+		v     Value
+		input string
+		ok    bool
+	}{
+		{Value{}, "", false},
+		{Value{}, "bob", false},
+		{Value{}, "bob@", false},
+		{Value{}, "bob@@example.com", false},
+		{Value{}, "bob@example.com", true},
+		{Value{}, "<bob@example.com>", true},
+		{Value{NoDisplayName: true}, "<bob@example.com>", false},
+		{Value{NoDisplayName: true}, "Bob <bob@example.com>", false},
+		{Value{NoDisplayName: true}, "bob@example.com", true},
+		{Value{Domains: sub}, "bob@mail.example.com", true},
+		{Value{Domains: sub}, "bob@example.com", false},
+		{Value{Domains: sub}, "bob@badexample.com", false},
+		{Value{Domains: []string{"Example.COM"}}, "bob@example.com", true},
+	}
+	for _, test := range tests {
+		err := test.v.Set(test.input)
+		if test.ok && err != nil {
+			t.Errorf("Set(%q) with %+v: unexpected error: %v", test.input, test.v, err)
+		} else if !test.ok && err == nil {
+			t.Errorf("Set(%q) with %+v: got %q, wanted error", test.input, test.v, test.v.Address)
+		}
+	}
+}
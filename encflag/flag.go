@@ -0,0 +1,295 @@
+// Package encflag defines a flag.Value implementation that selects a
+// character encoding by name.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/encflag"
+//	)
+//
+//	var inputEnc = encflag.Value{Encoding: encflag.UTF8}
+//	func init() {
+//	  flag.Var(&inputEnc, "input-encoding", inputEnc.Help("Encoding of input files"))
+//	}
+//
+//	  ...
+//	  text, err := inputEnc.Encoding.Decode(data)
+//
+// The package supports UTF-8, UTF-16, US-ASCII, ISO-8859-1, and Windows-1252
+// without dependencies. Other encodings, such as those provided by
+// golang.org/x/text/encoding, can be made available with Register.
+// Names are compared without regard to case or punctuation, so "UTF-8",
+// "utf8", and "utf_8" are equivalent.
+package encflag
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// An Encoding converts text between a character encoding and UTF-8.
+type Encoding interface {
+	// Name returns the canonical name of the encoding.
+	Name() string
+
+	// Decode converts src from the encoding to UTF-8.
+	Decode(src []byte) ([]byte, error)
+
+	// Encode converts src from UTF-8 to the encoding.
+	Encode(src []byte) ([]byte, error)
+}
+
+// Built-in encodings.
+var (
+	UTF8        Encoding = utf8Enc{}
+	UTF16LE     Encoding = utf16Enc{name: "utf-16le", big: false}
+	UTF16BE     Encoding = utf16Enc{name: "utf-16be", big: true}
+	UTF16       Encoding = utf16Enc{name: "utf-16", big: true, bom: true}
+	ASCII       Encoding = byteEnc{name: "us-ascii", max: 0x7f}
+	Latin1      Encoding = byteEnc{name: "iso-8859-1", max: 0xff}
+	Windows1252 Encoding = byteEnc{name: "windows-1252", max: 0xff, high: &cp1252}
+)
+
+var registry = struct {
+	sync.Mutex
+	m     map[string]Encoding // normalized name → encoding
+	names []string            // canonical names, in order of registration
+}{m: make(map[string]Encoding)}
+
+func init() {
+	Register(UTF8)
+	Register(UTF16LE)
+	Register(UTF16BE)
+	Register(UTF16)
+	Register(ASCII, "ascii", "us")
+	Register(Latin1, "latin1", "l1", "iso-latin-1")
+	Register(Windows1252, "cp1252")
+}
+
+// Register makes enc available under its name and the given aliases. It
+// panics if any of the names is already registered.
+func Register(enc Encoding, aliases ...string) {
+	registry.Lock()
+	defer registry.Unlock()
+	for _, name := range append([]string{enc.Name()}, aliases...) {
+		key := normalize(name)
+		if _, ok := registry.m[key]; ok {
+			panic(fmt.Sprintf("encflag: encoding %q is already registered", name))
+		}
+		registry.m[key] = enc
+	}
+	registry.names = append(registry.names, enc.Name())
+}
+
+// Lookup returns the encoding registered with the given name or alias, and
+// reports whether it was found.
+func Lookup(name string) (Encoding, bool) {
+	registry.Lock()
+	defer registry.Unlock()
+	enc, ok := registry.m[normalize(name)]
+	return enc, ok
+}
+
+// Names returns the canonical names of the registered encodings.
+func Names() []string {
+	registry.Lock()
+	defer registry.Unlock()
+	return slices.Clone(registry.names)
+}
+
+func normalize(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', ' ', '.', ':':
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
+
+// A Value represents a choice of character encoding. A pointer to a Value
+// satisfies the flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The encoding selected by the flag, or nil if none has been set.
+	Encoding Encoding
+
+	// If non-empty, only these encodings (by name or alias) are accepted.
+	Allowed []string
+}
+
+// Help concatenates a human-readable string summarizing the accepted
+// encodings of v to h, for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	names := v.Allowed
+	if len(names) == 0 {
+		names = Names()
+	}
+	return fmt.Sprintf("%s (%s)", h, strings.Join(names, "|"))
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string {
+	if v.Encoding == nil {
+		return ""
+	}
+	return v.Encoding.Name()
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	enc, ok := Lookup(s)
+	if !ok {
+		return fmt.Errorf("encflag: unknown encoding %q", s)
+	}
+	if len(v.Allowed) != 0 && !slices.ContainsFunc(v.Allowed, func(a string) bool {
+		e, ok := Lookup(a)
+		return ok && e.Name() == enc.Name()
+	}) {
+		return fmt.Errorf("encflag: encoding %q not allowed, expected one of (%s)",
+			s, strings.Join(v.Allowed, "|"))
+	}
+	v.Encoding = enc
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Encoding.
+func (v *Value) Get() any { return v.Encoding }
+
+type utf8Enc struct{}
+
+func (utf8Enc) Name() string { return "utf-8" }
+
+func (utf8Enc) Decode(src []byte) ([]byte, error) {
+	if !utf8.Valid(src) {
+		return nil, errors.New("encflag: invalid UTF-8")
+	}
+	return slices.Clone(src), nil
+}
+
+func (e utf8Enc) Encode(src []byte) ([]byte, error) { return e.Decode(src) }
+
+type utf16Enc struct {
+	name string
+	big  bool // big-endian, or the default byte order if bom is true
+	bom  bool // detect and write a byte-order mark
+}
+
+func (e utf16Enc) Name() string { return e.name }
+
+func (e utf16Enc) Decode(src []byte) ([]byte, error) {
+	big := e.big
+	if e.bom && len(src) >= 2 {
+		switch {
+		case src[0] == 0xfe && src[1] == 0xff:
+			big, src = true, src[2:]
+		case src[0] == 0xff && src[1] == 0xfe:
+			big, src = false, src[2:]
+		}
+	}
+	if len(src)%2 != 0 {
+		return nil, fmt.Errorf("encflag: odd length input for %s", e.name)
+	}
+	units := make([]uint16, len(src)/2)
+	for i := range units {
+		hi, lo := src[2*i], src[2*i+1]
+		if !big {
+			hi, lo = lo, hi
+		}
+		units[i] = uint16(hi)<<8 | uint16(lo)
+	}
+	return []byte(string(utf16.Decode(units))), nil
+}
+
+func (e utf16Enc) Encode(src []byte) ([]byte, error) {
+	if !utf8.Valid(src) {
+		return nil, errors.New("encflag: invalid UTF-8")
+	}
+	units := utf16.Encode([]rune(string(src)))
+	var out []byte
+	if e.bom {
+		out = append(out, 0xfe, 0xff)
+	}
+	for _, u := range units {
+		if e.big {
+			out = append(out, byte(u>>8), byte(u))
+		} else {
+			out = append(out, byte(u), byte(u>>8))
+		}
+	}
+	return out, nil
+}
+
+// byteEnc is a single-byte encoding whose bytes up to max map to the code
+// points with the same value, except that if high != nil, bytes 0x80–0x9f
+// map to the corresponding code points in *high.
+type byteEnc struct {
+	name string
+	max  byte
+	high *[32]rune
+}
+
+func (e byteEnc) Name() string { return e.name }
+
+func (e byteEnc) Decode(src []byte) ([]byte, error) {
+	out := make([]byte, 0, len(src))
+	for i, b := range src {
+		if b > e.max {
+			return nil, fmt.Errorf("encflag: invalid %s byte %#x at offset %d", e.name, b, i)
+		}
+		r := rune(b)
+		if e.high != nil && b >= 0x80 && b < 0xa0 {
+			if r = e.high[b-0x80]; r == 0 {
+				return nil, fmt.Errorf("encflag: invalid %s byte %#x at offset %d", e.name, b, i)
+			}
+		}
+		out = utf8.AppendRune(out, r)
+	}
+	return out, nil
+}
+
+func (e byteEnc) Encode(src []byte) ([]byte, error) {
+	out := make([]byte, 0, len(src))
+	for i, r := range string(src) {
+		b, ok := e.encodeRune(r)
+		if !ok {
+			return nil, fmt.Errorf("encflag: cannot encode %q at offset %d as %s", r, i, e.name)
+		}
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+func (e byteEnc) encodeRune(r rune) (byte, bool) {
+	if e.high != nil {
+		if i := slices.Index(e.high[:], r); i >= 0 && r != 0 {
+			return byte(0x80 + i), true
+		}
+		if r >= 0x80 && r < 0xa0 {
+			return 0, false
+		}
+	}
+	if r == utf8.RuneError || r > rune(e.max) {
+		return 0, false
+	}
+	return byte(r), true
+}
+
+// cp1252 maps bytes 0x80–0x9f of Windows-1252 to code points; zero entries
+// are undefined.
+var cp1252 = [32]rune{
+	0x20ac, 0, 0x201a, 0x0192, 0x201e, 0x2026, 0x2020, 0x2021,
+	0x02c6, 0x2030, 0x0160, 0x2039, 0x0152, 0, 0x017d, 0,
+	0, 0x2018, 0x2019, 0x201c, 0x201d, 0x2022, 0x2013, 0x2014,
+	0x02dc, 0x2122, 0x0161, 0x203a, 0x0153, 0, 0x017e, 0x0178,
+}
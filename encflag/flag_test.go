@@ -0,0 +1,100 @@
+package encflag
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	in := Value{Encoding: UTF8}
+	out := Value{Allowed: []string{"utf-8", "latin1"}}
+
+	fs := flag.NewFlagSet("enc", flag.ContinueOnError)
+	fs.Var(&in, "in", in.Help("Input encoding"))
+	fs.Var(&out, "out", out.Help("Output encoding"))
+
+	if got, want := in.String(), `"utf-8"`; got != want {
+		t.Errorf("Initial -in: got %s, want %s", got, want)
+	}
+	if err := fs.Parse([]string{"-in", "CP1252", "-out", "ISO_8859-1"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got := in.Get().(Encoding); got != Windows1252 {
+		t.Errorf("Value for -in: got %v, want windows-1252", got.Name())
+	}
+	if got, want := out.String(), `"iso-8859-1"`; got != want {
+		t.Errorf("Value for -out: got %s, want %s", got, want)
+	}
+	if err := fs.Parse([]string{"-out", "utf-16"}); err == nil {
+		t.Error("Disallowed encoding was accepted")
+	}
+	if err := fs.Parse([]string{"-in", "shift_jis"}); err == nil {
+		t.Error("Unknown encoding was accepted")
+	}
+}
+
+func TestCodecs(t *testing.T) {
+	tests := []struct {
+		enc  Encoding
+		text string
+		data []byte
+	}{
+		{UTF8, "héllo", []byte("héllo")},
+		{ASCII, "plain", []byte("plain")},
+		{Latin1, "café", []byte{'c', 'a', 'f', 0xe9}},
+		{Windows1252, "€5 “hi”", []byte{0x80, '5', ' ', 0x93, 'h', 'i', 0x94}},
+		{UTF16LE, "a€", []byte{'a', 0, 0xac, 0x20}},
+		{UTF16BE, "a😀", []byte{0, 'a', 0xd8, 0x3d, 0xde, 0x00}},
+		{UTF16, "a", []byte{0xfe, 0xff, 0, 'a'}},
+	}
+	for _, test := range tests {
+		got, err := test.enc.Encode([]byte(test.text))
+		if err != nil {
+			t.Errorf("%s.Encode(%q): unexpected error: %v", test.enc.Name(), test.text, err)
+		} else if !bytes.Equal(got, test.data) {
+			t.Errorf("%s.Encode(%q): got %x, want %x", test.enc.Name(), test.text, got, test.data)
+		}
+		dec, err := test.enc.Decode(test.data)
+		if err != nil {
+			t.Errorf("%s.Decode(%x): unexpected error: %v", test.enc.Name(), test.data, err)
+		} else if string(dec) != test.text {
+			t.Errorf("%s.Decode(%x): got %q, want %q", test.enc.Name(), test.data, dec, test.text)
+		}
+	}
+
+	// A UTF-16 byte-order mark selects the byte order.
+	if got, err := UTF16.Decode([]byte{0xff, 0xfe, 'a', 0}); err != nil || string(got) != "a" {
+		t.Errorf("UTF16.Decode with little-endian BOM: got %q, %v", got, err)
+	}
+}
+
+func TestCodecErrors(t *testing.T) {
+	if _, err := UTF8.Decode([]byte{0xff}); err == nil {
+		t.Error("UTF8.Decode accepted invalid input")
+	}
+	if _, err := ASCII.Decode([]byte{0x80}); err == nil {
+		t.Error("ASCII.Decode accepted a high byte")
+	}
+	if _, err := ASCII.Encode([]byte("é")); err == nil {
+		t.Error("ASCII.Encode accepted a non-ASCII rune")
+	}
+	if _, err := Latin1.Encode([]byte("€")); err == nil {
+		t.Error("Latin1.Encode accepted the euro sign")
+	}
+	if _, err := Windows1252.Decode([]byte{0x81}); err == nil {
+		t.Error("Windows1252.Decode accepted an undefined byte")
+	}
+	if _, err := UTF16LE.Decode([]byte{1}); err == nil {
+		t.Error("UTF16LE.Decode accepted odd-length input")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Register of a duplicate name did not panic")
+		}
+	}()
+	Register(Latin1)
+}
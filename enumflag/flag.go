@@ -19,6 +19,7 @@ package enumflag
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -58,6 +59,9 @@ func (v Value) Key() string {
 	return v.keys[v.index]
 }
 
+// Choices returns the keys of the enumeration, in the order given to New.
+func (v Value) Choices() []string { return slices.Clone(v.keys) }
+
 // Get satisfies the flag.Getter interface.
 // The concrete value is the the string of the current key.
 func (v Value) Get() any { return v.Key() }
@@ -68,7 +72,22 @@ func (v Value) Get() any { return v.Key() }
 func (v Value) Index() int { return v.index }
 
 // String satisfies part of the flag.Value interface.
-func (v Value) String() string { return fmt.Sprintf("%q", v.Key()) }
+func (v Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v Value) ArgString() string { return v.Key() }
+
+// Complete returns the non-empty keys of v that begin with prefix, without
+// regard to case, for use in shell completion.
+func (v Value) Complete(prefix string) []string {
+	var out []string
+	for _, key := range v.keys {
+		if key != "" && len(key) >= len(prefix) && strings.EqualFold(key[:len(prefix)], prefix) {
+			out = append(out, key)
+		}
+	}
+	return out
+}
 
 // Set satisfies part of the flag.Value interface.
 func (v *Value) Set(s string) error {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"flag"
 	"io"
+	"slices"
 	"testing"
 )
 
@@ -54,3 +55,25 @@ func TestFlagBits(t *testing.T) {
 		t.Logf("Got expected error from bogus -taste: %v", err)
 	}
 }
+
+func TestComplete(t *testing.T) {
+	v := New("", "green", "Gray", "blue")
+	if got, want := v.Choices(), []string{"", "green", "Gray", "blue"}; !slices.Equal(got, want) {
+		t.Errorf("Choices: got %q, want %q", got, want)
+	}
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"", []string{"green", "Gray", "blue"}},
+		{"g", []string{"green", "Gray"}},
+		{"GR", []string{"green", "Gray"}},
+		{"gre", []string{"green"}},
+		{"x", nil},
+	}
+	for _, test := range tests {
+		if got := v.Complete(test.prefix); !slices.Equal(got, test.want) {
+			t.Errorf("Complete(%q): got %q, want %q", test.prefix, got, test.want)
+		}
+	}
+}
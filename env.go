@@ -0,0 +1,75 @@
+package goflags
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/creachadair/goflags/defaultflag"
+)
+
+// An EnvSetting records a flag whose value was set from the environment.
+type EnvSetting struct {
+	Flag string // the name of the flag
+	Var  string // the name of the environment variable
+}
+
+// ParseEnv sets each flag in fs that was not set on the command line from the
+// environment variable named by EnvName(prefix, name), if that variable is
+// set. It should be called after fs.Parse. The value of the variable is
+// passed to fs.Set, so flags set from the environment are then reported by
+// fs.Visit. If the value of a flag is a *defaultflag.Value, its source is
+// recorded as defaultflag.Environment.
+//
+// ParseEnv returns a report of the flags set from the environment, in
+// lexicographical order by flag name. It stops at the first value reported
+// invalid by Set, and returns the flags set before that point along with the
+// error.
+func ParseEnv(fs *flag.FlagSet, prefix string) ([]EnvSetting, error) {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	var out []EnvSetting
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || set[f.Name] {
+			return
+		}
+		name := EnvName(prefix, f.Name)
+		s, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+		if serr := fs.Set(f.Name, s); serr != nil {
+			err = fmt.Errorf("goflags: invalid value %q for flag -%s from $%s: %w", s, f.Name, name, serr)
+			return
+		}
+		if dv, ok := f.Value.(*defaultflag.Value); ok {
+			dv.SetSource(defaultflag.Environment, name)
+		}
+		out = append(out, EnvSetting{Flag: f.Name, Var: name})
+	})
+	return out, err
+}
+
+// EnvName returns the name of the environment variable for the flag with the
+// given name, as used by ParseEnv. The name is converted to upper case, and
+// each character other than a letter or digit is replaced with "_". If prefix
+// is not empty, it is joined to the result with "_", so that the flag
+// "db.host" with prefix "APP" has the variable name "APP_DB_HOST".
+func EnvName(prefix, name string) string {
+	env := strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z':
+			return r - 'a' + 'A'
+		case 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+	if prefix == "" {
+		return env
+	}
+	return strings.TrimSuffix(prefix, "_") + "_" + env
+}
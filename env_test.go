@@ -0,0 +1,83 @@
+package goflags
+
+import (
+	"flag"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/creachadair/goflags/defaultflag"
+	"github.com/creachadair/goflags/sizeflag"
+)
+
+func TestParseEnv(t *testing.T) {
+	t.Setenv("APP_NAME", "from-env")
+	t.Setenv("APP_PORT", "9000")
+	t.Setenv("APP_DB_HOST", "db.local")
+	t.Setenv("APP_CACHE_SIZE", "2k")
+	t.Setenv("APP_TIMEOUT", "")
+
+	fs := flag.NewFlagSet("env", flag.ContinueOnError)
+	name := fs.String("name", "default", "Name")
+	port := fs.Int("port", 80, "Port")
+	host := fs.String("db.host", "localhost", "Database host")
+	timeout := fs.Duration("timeout", time.Second, "Timeout")
+	cache := defaultflag.Wrap(sizeflag.Base2(0))
+	fs.Var(cache, "cache-size", "Cache size")
+	other := fs.String("other", "x", "Other")
+
+	if err := fs.Parse([]string{"-port", "8080"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	t.Setenv("APP_TIMEOUT", "5s")
+
+	got, err := ParseEnv(fs, "APP_")
+	if err != nil {
+		t.Fatalf("ParseEnv failed: %v", err)
+	}
+	want := []EnvSetting{
+		{"cache-size", "APP_CACHE_SIZE"},
+		{"db.host", "APP_DB_HOST"},
+		{"name", "APP_NAME"},
+		{"timeout", "APP_TIMEOUT"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseEnv: got %+v, want %+v", got, want)
+	}
+	if *name != "from-env" || *port != 8080 || *host != "db.local" || *timeout != 5*time.Second || *other != "x" {
+		t.Errorf("Values: name=%q port=%d host=%q timeout=%v other=%q", *name, *port, *host, *timeout, *other)
+	}
+	var set []string
+	fs.Visit(func(f *flag.Flag) { set = append(set, f.Name) })
+	if want := []string{"cache-size", "db.host", "name", "port", "timeout"}; !slices.Equal(set, want) {
+		t.Errorf("Flags set: got %q, want %q", set, want)
+	}
+	if cache.Source() != defaultflag.Environment || cache.Origin() != "APP_CACHE_SIZE" {
+		t.Errorf("Source of -cache-size: got %v %q", cache.Source(), cache.Origin())
+	}
+}
+
+func TestParseEnvError(t *testing.T) {
+	t.Setenv("PORT", "bogus")
+	fs := flag.NewFlagSet("env", flag.ContinueOnError)
+	fs.Int("port", 80, "Port")
+	if got, err := ParseEnv(fs, ""); err == nil {
+		t.Errorf("ParseEnv with invalid value: got %+v, wanted error", got)
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	tests := []struct {
+		prefix, name, want string
+	}{
+		{"", "port", "PORT"},
+		{"APP", "db.host", "APP_DB_HOST"},
+		{"APP_", "cache-size", "APP_CACHE_SIZE"},
+		{"x", "a2b", "x_A2B"},
+	}
+	for _, test := range tests {
+		if got := EnvName(test.prefix, test.name); got != test.want {
+			t.Errorf("EnvName(%q, %q): got %q, want %q", test.prefix, test.name, got, test.want)
+		}
+	}
+}
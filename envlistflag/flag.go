@@ -0,0 +1,125 @@
+// Package envlistflag defines a repeatable flag.Value implementation that
+// collects environment variable settings of the form NAME=VALUE.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//	  "os/exec"
+//
+//	  "github.com/creachadair/goflags/envlistflag"
+//	)
+//
+//	var env = envlistflag.Value{Import: true}
+//	func init() {
+//	  flag.Var(&env, "e", env.Help("Set an environment variable"))
+//	}
+//
+//	  ...
+//	  cmd := exec.Command(prog, args...)
+//	  cmd.Env = append(os.Environ(), env.Environ()...)
+//
+// With this definition "-e DEBUG=1 -e HOME" sets DEBUG to "1" and copies the
+// value of HOME from the current environment, as "docker run -e" does. A name
+// that is not set in the current environment is omitted.
+package envlistflag
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+)
+
+// A Value represents a list of environment variable settings. A pointer to a
+// Value satisfies the flag.Value and flag.Getter interfaces. Each occurrence of
+// the flag adds one setting. If a name is set more than once, the last
+// setting wins, but its position is that of the first.
+type Value struct {
+	// If true, an argument without "=" is the name of a variable whose value
+	// is copied from the current environment. Otherwise such an argument is
+	// rejected.
+	Import bool
+
+	names  []string // in order of first occurrence
+	values map[string]string
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	if v.Import {
+		return h + " (NAME=VALUE or NAME; repeatable)"
+	}
+	return h + " (NAME=VALUE; repeatable)"
+}
+
+// Environ returns the settings as a list of "NAME=VALUE" strings, in the form
+// used by os.Environ and exec.Cmd.
+func (v *Value) Environ() []string {
+	out := make([]string, len(v.names))
+	for i, name := range v.names {
+		out[i] = name + "=" + v.values[name]
+	}
+	return out
+}
+
+// ArgStrings returns the settings as "NAME=VALUE" strings, as reported by
+// Environ, each of which is a valid argument to Set.
+func (v *Value) ArgStrings() []string { return v.Environ() }
+
+// Reset discards all the settings of v.
+func (v *Value) Reset() { v.names, v.values = nil, nil }
+
+// Map returns the settings as a map from names to values.
+func (v *Value) Map() map[string]string { return maps.Clone(v.values) }
+
+// Lookup returns the value of the named variable, and reports whether it was
+// set.
+func (v *Value) Lookup(name string) (string, bool) {
+	val, ok := v.values[name]
+	return val, ok
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return strings.Join(v.Environ(), " ") }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	name, val, ok := strings.Cut(s, "=")
+	if err := checkName(name); err != nil {
+		return err
+	}
+	if !ok {
+		if !v.Import {
+			return fmt.Errorf("envlistflag: missing value for %q, want NAME=VALUE", name)
+		}
+		val, ok = os.LookupEnv(name)
+		if !ok {
+			return nil // omit unset variables
+		}
+	}
+	if v.values == nil {
+		v.values = make(map[string]string)
+	}
+	if _, dup := v.values[name]; !dup {
+		v.names = append(v.names, name)
+	}
+	v.values[name] = val
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type []string, as returned by Environ.
+func (v *Value) Get() any { return v.Environ() }
+
+// checkName reports an error if name is not a usable variable name.
+func checkName(name string) error {
+	if name == "" {
+		return fmt.Errorf("envlistflag: empty variable name")
+	}
+	if strings.ContainsAny(name, "\x00") {
+		return fmt.Errorf("envlistflag: invalid variable name %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package envlistflag
+
+import (
+	"flag"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	t.Setenv("ENVLISTFLAG_IMPORTED", "from-env")
+
+	env := Value{Import: true}
+	fs := flag.NewFlagSet("env", flag.ContinueOnError)
+	fs.Var(&env, "e", env.Help("Set a variable"))
+
+	if err := fs.Parse([]string{
+		"-e", "A=1",
+		"-e", "B=x=y",
+		"-e", "ENVLISTFLAG_IMPORTED",
+		"-e", "ENVLISTFLAG_NOT_SET",
+		"-e", "EMPTY=",
+		"-e", "A=2",
+	}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	want := []string{"A=2", "B=x=y", "ENVLISTFLAG_IMPORTED=from-env", "EMPTY="}
+	if got := env.Get().([]string); !slices.Equal(got, want) {
+		t.Errorf("Value for -e: got %q, want %q", got, want)
+	}
+	wantMap := map[string]string{"A": "2", "B": "x=y", "ENVLISTFLAG_IMPORTED": "from-env", "EMPTY": ""}
+	if got := env.Map(); !maps.Equal(got, wantMap) {
+		t.Errorf("Map for -e: got %v, want %v", got, wantMap)
+	}
+	if val, ok := env.Lookup("EMPTY"); !ok || val != "" {
+		t.Errorf("Lookup(EMPTY): got %q, %v; want \"\", true", val, ok)
+	}
+	if _, ok := env.Lookup("ENVLISTFLAG_NOT_SET"); ok {
+		t.Error("Lookup(ENVLISTFLAG_NOT_SET): got true, want false")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, bad := range []string{"", "=value", "NAME", "BAD\x00NAME=1"} {
+		var v Value
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %q, wanted error", bad, v.Environ())
+		}
+	}
+}
+
+func TestArgStrings(t *testing.T) {
+	var v Value
+	for _, s := range []string{"B=2", "A=x y", "B=3"} {
+		if err := v.Set(s); err != nil {
+			t.Fatalf("Set(%q) failed: %v", s, err)
+		}
+	}
+	want := []string{"B=3", "A=x y"}
+	if got := v.ArgStrings(); !slices.Equal(got, want) {
+		t.Errorf("ArgStrings: got %q, want %q", got, want)
+	}
+}
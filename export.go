@@ -0,0 +1,94 @@
+package goflags
+
+import (
+	"expvar"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/creachadair/goflags/secretflag"
+)
+
+// An Exporter publishes the current values of the flags of a flag set, so
+// that operators can confirm the configuration a running process is using.
+// Values that hide secrets in their String method, such as secretflag and
+// dsnflag, are exported in their redacted forms; Redact may hide others.
+type Exporter struct {
+	// If non-nil, Redact is called for each flag, and the values of a flag
+	// for which it reports true are exported as secretflag.Redacted.
+	Redact func(f *flag.Flag) bool
+
+	// The name of the metric written by WriteMetrics. If empty, it is
+	// "flag_value".
+	Metric string
+}
+
+// Values returns a map from the name of each flag in fs to its current value,
+// as reported by ArgString, so that the values of ArgStringer types are not
+// quoted.
+func (e Exporter) Values(fs *flag.FlagSet) map[string]string {
+	out := make(map[string]string)
+	fs.VisitAll(func(f *flag.Flag) {
+		out[f.Name], _ = e.values(f)
+	})
+	return out
+}
+
+// values returns the current and default values of f, as reported by
+// ArgString and DefaultArg, redacted if required.
+func (e Exporter) values(f *flag.Flag) (cur, def string) {
+	if e.Redact != nil && e.Redact(f) {
+		return secretflag.Redacted, secretflag.Redacted
+	}
+	return ArgString(f.Value), DefaultArg(f)
+}
+
+// Publish publishes the values of the flags in fs, as reported by Values, as
+// an expvar variable with the given name. The values are computed each time
+// the variable is read. Like expvar.Publish, it panics if the name is already
+// in use.
+func (e Exporter) Publish(name string, fs *flag.FlagSet) {
+	expvar.Publish(name, expvar.Func(func() any { return e.Values(fs) }))
+}
+
+// WriteMetrics writes the values of the flags in fs to w in the Prometheus
+// text exposition format, as an "info" metric with the value 1 and labels for
+// the name, current value, default value, and source of each flag:
+//
+//	flag_value{name="addr",value=":80",default=":80",source="default"} 1
+//
+// The source is the source of a *defaultflag.Value, or else "flag" for a flag
+// that was set (see CheckGroups), or "default".
+func (e Exporter) WriteMetrics(w io.Writer, fs *flag.FlagSet) error {
+	metric := e.Metric
+	if metric == "" {
+		metric = "flag_value"
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "# HELP %s Current value of a command-line flag.\n", metric)
+	fmt.Fprintf(&buf, "# TYPE %s gauge\n", metric)
+	for _, fi := range Describe(fs) {
+		cur, def := e.values(fs.Lookup(fi.Name))
+		fmt.Fprintf(&buf, "%s{name=%s,value=%s,default=%s,source=%s} 1\n", metric,
+			labelValue(fi.Name), labelValue(cur), labelValue(def), labelValue(flagSource(fi)))
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+// flagSource returns the source of the value of the flag described by fi: the
+// source of a *defaultflag.Value, or else "flag" if it was set, or "default".
+func flagSource(fi FlagInfo) string {
+	if fi.Source != "" {
+		return fi.Source
+	} else if fi.Set {
+		return "flag"
+	}
+	return "default"
+}
+
+// labelValue returns s quoted as a label value in the Prometheus text format.
+func labelValue(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,116 @@
+package goflags
+
+import (
+	"expvar"
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/goflags/defaultflag"
+	"github.com/creachadair/goflags/enumflag"
+	"github.com/creachadair/goflags/fileflag"
+	"github.com/creachadair/goflags/secretflag"
+)
+
+func exportFlags(t *testing.T) *flag.FlagSet {
+	t.Helper()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.String("addr", ":80", "Address")
+	fs.String("password", "", "Password")
+	fs.Var(new(secretflag.Value), "token", "Token")
+	fs.Var(defaultflag.Wrap(new(listValue)), "tags", "Tags")
+	fs.Int("n", 1, "Count")
+	if err := fs.Parse([]string{"-password", "hunter2", "-token", "abc", "-n", "5"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	v, _ := findValue[*defaultflag.Value](fs.Lookup("tags").Value)
+	if err := v.SetFrom(defaultflag.Environment, "TAGS", `say "hi"`); err != nil {
+		t.Fatalf("Set tags: %v", err)
+	}
+	return fs
+}
+
+var testExporter = Exporter{
+	Redact: func(f *flag.Flag) bool { return f.Name == "password" },
+}
+
+func TestExporterValues(t *testing.T) {
+	got := testExporter.Values(exportFlags(t))
+	want := map[string]string{
+		"addr":     ":80",
+		"password": secretflag.Redacted,
+		"token":    secretflag.Redacted,
+		"tags":     `say "hi"`,
+		"n":        "5",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Values: got %q, want %q", got, want)
+	}
+}
+
+func TestExporterPublish(t *testing.T) {
+	fs := exportFlags(t)
+	testExporter.Publish("goflags_test", fs)
+	v := expvar.Get("goflags_test")
+	if v == nil {
+		t.Fatal("Variable goflags_test was not published")
+	}
+	if got, want := v.String(), `"n":"5"`; !strings.Contains(got, want) {
+		t.Errorf("Variable: got %s, want it to contain %s", got, want)
+	}
+
+	// The values are current when the variable is read.
+	fs.Set("n", "6")
+	if got, want := v.String(), `"n":"6"`; !strings.Contains(got, want) {
+		t.Errorf("Variable: got %s, want it to contain %s", got, want)
+	}
+}
+
+func TestWriteMetrics(t *testing.T) {
+	var buf strings.Builder
+	e := testExporter
+	e.Metric = "app_flag"
+	if err := e.WriteMetrics(&buf, exportFlags(t)); err != nil {
+		t.Fatalf("WriteMetrics: unexpected error: %v", err)
+	}
+	const want = `# HELP app_flag Current value of a command-line flag.
+# TYPE app_flag gauge
+app_flag{name="addr",value=":80",default=":80",source="default"} 1
+app_flag{name="n",value="5",default="1",source="flag"} 1
+app_flag{name="password",value="[redacted]",default="[redacted]",source="flag"} 1
+app_flag{name="tags",value="say \"hi\"",default="",source="env"} 1
+app_flag{name="token",value="[redacted]",default="[redacted]",source="flag"} 1
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteMetrics: got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteMetricsArgString(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(enumflag.New("dev", "prod"), "env", "Environment")
+	fs.Var(new(fileflag.Value), "config", "Config file")
+	if err := fs.Parse([]string{"-env", "prod"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// The quoted strings reported by the String methods are not quoted again.
+	var buf strings.Builder
+	if err := (Exporter{}).WriteMetrics(&buf, fs); err != nil {
+		t.Fatalf("WriteMetrics: unexpected error: %v", err)
+	}
+	const want = `# HELP flag_value Current value of a command-line flag.
+# TYPE flag_value gauge
+flag_value{name="config",value="",default="",source="default"} 1
+flag_value{name="env",value="prod",default="dev",source="flag"} 1
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteMetrics: got:\n%s\nwant:\n%s", got, want)
+	}
+	if got, want := (Exporter{}).Values(fs)["env"], "prod"; got != want {
+		t.Errorf("Values -env: got %q, want %q", got, want)
+	}
+}
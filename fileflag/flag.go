@@ -0,0 +1,338 @@
+// Package fileflag defines a flag.Value implementation that accepts a file
+// path, with optional validation of the existence and permissions of the file
+// at the time the flag is parsed.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/fileflag"
+//	)
+//
+//	var config = fileflag.Value{MustExist: true, ExpandUser: true}
+//	var outDir = fileflag.Value{MustBeDir: true, CreateIfMissing: true}
+//
+//	func init() {
+//	  flag.Var(&config, "config", "Path of configuration file")
+//	  flag.Var(&outDir, "out", "Output directory")
+//	}
+//
+// The Contents type is a flag.Value that holds the contents of a named file
+// rather than its path:
+//
+//	var token = fileflag.Contents{MaxSize: 4096}
+//
+//	func init() {
+//	  flag.Var(&token, "token", token.Help("Access token"))
+//	}
+//
+// With this definition, "-token @secrets/token" reads the contents of the file
+// secrets/token, while "-token xyzzy" uses the literal string "xyzzy".
+package fileflag
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A Value represents a file path. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces. The options are checked when the
+// flag is set; the zero value accepts any non-empty path.
+type Value struct {
+	// The cleaned absolute path parsed from the flag.
+	Path string
+
+	// If true, the path must refer to an existing file or directory.
+	MustExist bool
+
+	// If true, the path must refer to a directory. Unless CreateIfMissing is
+	// also set, the directory must exist.
+	MustBeDir bool
+
+	// If true, the path must be writable by the current process. If the path
+	// does not exist, its parent directory must be writable.
+	MustBeWritable bool
+
+	// If true and the path does not exist, create it. If MustBeDir is set, a
+	// directory is created along with any missing parents; otherwise an empty
+	// file is created.
+	CreateIfMissing bool
+
+	// If true, a leading "~" or "~/" is replaced with the home directory of
+	// the current user.
+	ExpandUser bool
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return v.Path }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	path, err := v.resolve(s)
+	if err != nil {
+		return err
+	}
+	v.Path = path
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is the string of the path.
+func (v *Value) Get() any { return v.Path }
+
+// Complete returns the paths of files beginning with prefix, for use in shell
+// completion. Directories are reported with a trailing separator, and if
+// MustBeDir is set only directories are reported. Hidden files are omitted
+// unless prefix names them explicitly.
+func (v *Value) Complete(prefix string) []string {
+	dir, base := filepath.Split(prefix)
+	read := dir
+	if read == "" {
+		read = "."
+	} else if v.ExpandUser {
+		if exp, err := expandUser(dir); err == nil {
+			read = exp
+		}
+	}
+	ents, err := os.ReadDir(read)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, e := range ents {
+		name := e.Name()
+		if !strings.HasPrefix(name, base) || (base == "" && strings.HasPrefix(name, ".")) {
+			continue
+		}
+		isDir := e.IsDir()
+		if e.Type()&fs.ModeSymlink != 0 {
+			fi, err := os.Stat(filepath.Join(read, name))
+			isDir = err == nil && fi.IsDir()
+		}
+		if isDir {
+			out = append(out, dir+name+string(filepath.Separator))
+		} else if !v.MustBeDir {
+			out = append(out, dir+name)
+		}
+	}
+	return out
+}
+
+func (v *Value) resolve(s string) (string, error) {
+	if s == "" {
+		return "", errors.New("fileflag: empty path")
+	}
+	if v.ExpandUser {
+		exp, err := expandUser(s)
+		if err != nil {
+			return "", err
+		}
+		s = exp
+	}
+	path, err := filepath.Abs(s)
+	if err != nil {
+		return "", fmt.Errorf("fileflag: %w", err)
+	}
+
+	fi, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if v.CreateIfMissing {
+			if err := create(path, v.MustBeDir); err != nil {
+				return "", err
+			}
+			return path, nil
+		}
+		if v.MustExist || v.MustBeDir {
+			return "", fmt.Errorf("fileflag: %q does not exist", path)
+		}
+		if v.MustBeWritable {
+			if err := checkWritable(filepath.Dir(path)); err != nil {
+				return "", err
+			}
+		}
+		return path, nil
+	} else if err != nil {
+		return "", fmt.Errorf("fileflag: %w", err)
+	}
+
+	if v.MustBeDir && !fi.IsDir() {
+		return "", fmt.Errorf("fileflag: %q is not a directory", path)
+	}
+	if v.MustBeWritable {
+		if err := checkWritable(path); err != nil {
+			return "", err
+		}
+	}
+	return path, nil
+}
+
+// Contents represents the contents of a file, or a literal string. A pointer
+// to a Contents satisfies the flag.Value and flag.Getter interfaces.
+//
+// If the flag argument begins with "@", the remainder of the argument names a
+// file whose contents become the value of the flag. Otherwise the argument
+// itself is the value. Use "@@" to specify a literal beginning with "@".
+type Contents struct {
+	// The contents parsed from the flag.
+	Data []byte
+
+	// If positive, the maximum number of bytes accepted, whether the value was
+	// read from a file or given literally.
+	MaxSize int64
+
+	src string // the flag argument, for String
+}
+
+// Help concatenates a human-readable string summarizing the format of c to h,
+// for use in generating a documentation string.
+func (c *Contents) Help(h string) string {
+	return fmt.Sprintf("%s (literal or @file)", h)
+}
+
+// Text returns the current contents as a string.
+func (c *Contents) Text() string { return string(c.Data) }
+
+// Literal is the placeholder reported by String and ArgString when the value
+// of a Contents was given literally rather than read from a file.
+const Literal = "[literal]"
+
+// String satisfies part of the flag.Value interface.
+// It reports the argument that was given, not the contents of the file.
+// A literal value is reported as Literal, so that it is not disclosed.
+func (c *Contents) String() string { return fmt.Sprintf("%q", c.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface. It reports the
+// "@path" argument that was given, or Literal if the value was given
+// literally. Passing Literal back to Set does not restore the value; use
+// Snapshot for that.
+func (c *Contents) ArgString() string {
+	if c.src == "" || c.fromFile() {
+		return c.src
+	}
+	return Literal
+}
+
+// Snapshot satisfies the goflags.Snapshotter interface. The function it
+// returns restores the current contents and argument of c.
+func (c *Contents) Snapshot() func() {
+	data, src := c.Data, c.src
+	return func() { c.Data, c.src = data, src }
+}
+
+// fromFile reports whether the value of c was read from a file.
+func (c *Contents) fromFile() bool {
+	return strings.HasPrefix(c.src, "@") && !strings.HasPrefix(c.src, "@@")
+}
+
+// Set satisfies part of the flag.Value interface.
+func (c *Contents) Set(s string) error {
+	var data []byte
+	if rest, ok := strings.CutPrefix(s, "@"); ok && !strings.HasPrefix(rest, "@") {
+		d, err := readFile(rest, c.MaxSize)
+		if err != nil {
+			return err
+		}
+		data = d
+	} else {
+		lit := s
+		if ok {
+			lit = rest // unescape "@@"
+		}
+		if c.MaxSize > 0 && int64(len(lit)) > c.MaxSize {
+			return fmt.Errorf("fileflag: value exceeds %d bytes", c.MaxSize)
+		}
+		data = []byte(lit)
+	}
+	c.Data = data
+	c.src = s
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type []byte.
+func (c *Contents) Get() any { return c.Data }
+
+// readFile reads the contents of path, failing if max > 0 and the file is
+// larger than max bytes.
+func readFile(path string, max int64) ([]byte, error) {
+	if path == "" {
+		return nil, errors.New("fileflag: empty path")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("fileflag: %w", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if max > 0 {
+		r = io.LimitReader(f, max+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("fileflag: %w", err)
+	}
+	if max > 0 && int64(len(data)) > max {
+		return nil, fmt.Errorf("fileflag: %q exceeds %d bytes", path, max)
+	}
+	return data, nil
+}
+
+// expandUser replaces a leading "~" in s with the home directory of the
+// current user. Other forms of "~user" are not supported.
+func expandUser(s string) (string, error) {
+	if s != "~" && !strings.HasPrefix(s, "~/") && !strings.HasPrefix(s, "~"+string(filepath.Separator)) {
+		return s, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("fileflag: %w", err)
+	}
+	return filepath.Join(home, s[1:]), nil
+}
+
+func create(path string, dir bool) error {
+	if dir {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return fmt.Errorf("fileflag: %w", err)
+		}
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("fileflag: %w", err)
+	}
+	return f.Close()
+}
+
+// checkWritable reports an error if path cannot be written by the current
+// process. For a directory, this is checked by creating a temporary file.
+func checkWritable(path string) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("fileflag: %w", err)
+	}
+	if fi.IsDir() {
+		f, err := os.CreateTemp(path, ".fileflag-*")
+		if err != nil {
+			return fmt.Errorf("fileflag: %q is not writable", path)
+		}
+		f.Close()
+		os.Remove(f.Name())
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("fileflag: %q is not writable", path)
+	}
+	return f.Close()
+}
@@ -0,0 +1,209 @@
+package fileflag
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "exists.txt")
+	if err := os.WriteFile(file, []byte("hello"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	var path, exist, isDir Value
+	exist.MustExist = true
+	isDir.MustBeDir = true
+
+	fs := flag.NewFlagSet("file", flag.ContinueOnError)
+	fs.Var(&path, "any", "Any path")
+	fs.Var(&exist, "exist", "An existing path")
+	fs.Var(&isDir, "dir", "An existing directory")
+
+	if err := fs.Parse([]string{
+		"-any", filepath.Join(dir, "a", "..", "missing"),
+		"-exist", file,
+		"-dir", dir,
+	}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	if got, want := path.Path, filepath.Join(dir, "missing"); got != want {
+		t.Errorf("Value for -any: got %q, want %q", got, want)
+	}
+	if got := exist.Get().(string); got != file {
+		t.Errorf("Value for -exist: got %q, want %q", got, file)
+	}
+	if got := isDir.Path; got != dir {
+		t.Errorf("Value for -dir: got %q, want %q", got, dir)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "exists.txt")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	missing := filepath.Join(dir, "missing")
+
+	tests := []struct {
+		v     Value
+		input string
+	}{
+		{Value{}, ""},
+		{Value{MustExist: true}, missing},
+		{Value{MustBeDir: true}, missing},
+		{Value{MustBeDir: true}, file},
+		{Value{MustBeWritable: true}, filepath.Join(missing, "child")},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.input); err == nil {
+			t.Errorf("Set(%q) with %+v: got %q, wanted error", test.input, test.v, test.v.Path)
+		} else {
+			t.Logf("Set(%q) gave expected error: %v", test.input, err)
+		}
+	}
+}
+
+func TestCreateIfMissing(t *testing.T) {
+	dir := t.TempDir()
+
+	newFile := Value{CreateIfMissing: true, MustBeWritable: true}
+	path := filepath.Join(dir, "new.txt")
+	if err := newFile.Set(path); err != nil {
+		t.Fatalf("Set(%q) failed: %v", path, err)
+	}
+	if fi, err := os.Stat(path); err != nil {
+		t.Errorf("Stat(%q): %v", path, err)
+	} else if !fi.Mode().IsRegular() {
+		t.Errorf("Created %q: got mode %v, want regular file", path, fi.Mode())
+	}
+
+	newDir := Value{CreateIfMissing: true, MustBeDir: true}
+	path = filepath.Join(dir, "a", "b", "c")
+	if err := newDir.Set(path); err != nil {
+		t.Fatalf("Set(%q) failed: %v", path, err)
+	}
+	if fi, err := os.Stat(path); err != nil {
+		t.Errorf("Stat(%q): %v", path, err)
+	} else if !fi.IsDir() {
+		t.Errorf("Created %q: got mode %v, want directory", path, fi.Mode())
+	}
+}
+
+func TestExpandUser(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	v := Value{ExpandUser: true}
+	tests := []struct {
+		input, want string
+	}{
+		{"~", home},
+		{"~/foo/bar", filepath.Join(home, "foo", "bar")},
+		{"/x/~/y", "/x/~/y"},
+	}
+	for _, test := range tests {
+		if err := v.Set(test.input); err != nil {
+			t.Errorf("Set(%q) failed: %v", test.input, err)
+		} else if v.Path != test.want {
+			t.Errorf("Set(%q): got %q, want %q", test.input, v.Path, test.want)
+		}
+	}
+}
+
+func TestContents(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token")
+	if err := os.WriteFile(path, []byte("s3kr1t\n"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	tests := []struct {
+		input, want, arg string
+	}{
+		{"literal", "literal", Literal},
+		{"", "", ""},
+		{"@" + path, "s3kr1t\n", "@" + path},
+		{"@@home", "@home", Literal},
+	}
+	for _, test := range tests {
+		var c Contents
+		if err := c.Set(test.input); err != nil {
+			t.Errorf("Set(%q) failed: %v", test.input, err)
+		} else if got := string(c.Get().([]byte)); got != test.want {
+			t.Errorf("Set(%q): got %q, want %q", test.input, got, test.want)
+		}
+		if got, want := c.String(), `"`+test.arg+`"`; got != want {
+			t.Errorf("String after Set(%q): got %s, want %s", test.input, got, want)
+		}
+	}
+
+	var c Contents
+	if err := c.Set("s3kr1t"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	restore := c.Snapshot()
+	if err := c.Set("@" + path); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	restore()
+	if got, want := c.Text(), "s3kr1t"; got != want {
+		t.Errorf("Text after restore: got %q, want %q", got, want)
+	}
+	if got := c.ArgString(); got != Literal {
+		t.Errorf("ArgString after restore: got %q, want %q", got, Literal)
+	}
+
+	limited := Contents{MaxSize: 4}
+	for _, bad := range []string{"@" + path, "abcde", "@" + filepath.Join(dir, "missing"), "@"} {
+		if err := limited.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %q, wanted error", bad, limited.Text())
+		} else {
+			t.Logf("Set(%q) gave expected error: %v", bad, err)
+		}
+	}
+	if err := limited.Set("abcd"); err != nil {
+		t.Errorf("Set(%q) failed: %v", "abcd", err)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"alpha.txt", "alps.txt", ".hidden", "beta/"} {
+		path := filepath.Join(dir, name)
+		var err error
+		if filepath.Base(name) != name {
+			err = os.Mkdir(path, 0700)
+		} else {
+			err = os.WriteFile(path, nil, 0600)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	sep := string(filepath.Separator)
+	pfx := dir + sep
+	tests := []struct {
+		v      Value
+		prefix string
+		want   []string
+	}{
+		{Value{}, pfx + "al", []string{pfx + "alpha.txt", pfx + "alps.txt"}},
+		{Value{}, pfx, []string{pfx + "alpha.txt", pfx + "alps.txt", pfx + "beta" + sep}},
+		{Value{}, pfx + ".", []string{pfx + ".hidden"}},
+		{Value{MustBeDir: true}, pfx, []string{pfx + "beta" + sep}},
+		{Value{}, pfx + "nonesuch", nil},
+		{Value{}, filepath.Join(dir, "missing") + sep, nil},
+	}
+	for _, test := range tests {
+		if got := test.v.Complete(test.prefix); !slices.Equal(got, test.want) {
+			t.Errorf("Complete(%q) with %+v: got %q, want %q", test.prefix, test.v, got, test.want)
+		}
+	}
+}
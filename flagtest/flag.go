@@ -0,0 +1,174 @@
+// Package flagtest provides helpers for testing flag values and the flag sets
+// that use them.
+//
+// Example:
+//
+//	import (
+//	  "testing"
+//
+//	  "github.com/creachadair/goflags/flagtest"
+//	  "github.com/creachadair/goflags/sizeflag"
+//	)
+//
+//	func TestFlags(t *testing.T) {
+//	  fs := flagtest.New("test")
+//	  fs.Var(sizeflag.Base2(0), "size", "Size")
+//
+//	  flagtest.Parse(t, fs, "-size 4K")
+//	  flagtest.Check(t, fs, "size", 4096)
+//	  flagtest.Golden(t, "testdata/usage.golden", flagtest.Usage(fs))
+//	}
+//
+// Golden files are rewritten with the actual output, rather than compared,
+// when the environment variable FLAGTEST_UPDATE is set to a non-empty value.
+package flagtest
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/goflags"
+	"github.com/creachadair/goflags/shellflag"
+)
+
+// New returns a new empty flag set with the given name, which reports errors
+// from Parse rather than exiting, and discards its output.
+func New(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// Parse splits args into words, using the quoting rules of shellflag.Split,
+// and parses them with fs. It returns the remaining non-flag arguments. If
+// parsing fails, Parse reports a fatal error to t.
+func Parse(t testing.TB, fs *flag.FlagSet, args string) []string {
+	t.Helper()
+	words, err := shellflag.Split(args)
+	if err != nil {
+		t.Fatalf("Splitting %q: %v", args, err)
+	}
+	if err := fs.Parse(words); err != nil {
+		t.Fatalf("Parse %q: unexpected error: %v", args, err)
+	}
+	return fs.Args()
+}
+
+// ParseError is like Parse, but expects parsing to fail, and returns the
+// error. If parsing succeeds, ParseError reports a fatal error to t.
+func ParseError(t testing.TB, fs *flag.FlagSet, args string) error {
+	t.Helper()
+	words, err := shellflag.Split(args)
+	if err != nil {
+		t.Fatalf("Splitting %q: %v", args, err)
+	}
+	err = fs.Parse(words)
+	if err == nil {
+		t.Fatalf("Parse %q: got nil, want error", args)
+	}
+	return err
+}
+
+// Get returns the value of the named flag of fs, as reported by the Get
+// method of its flag.Getter. If the flag is not defined, is not a Getter, or
+// its value does not have type T, Get reports a fatal error to t.
+func Get[T any](t testing.TB, fs *flag.FlagSet, name string) T {
+	t.Helper()
+	v, ok := getValue(t, fs, name).(T)
+	if !ok {
+		var zero T
+		t.Fatalf("Flag -%s: value has type %T, want %T", name, getValue(t, fs, name), zero)
+	}
+	return v
+}
+
+// Check reports an error to t if the value of the named flag of fs, as
+// reported by the Get method of its flag.Getter, is not equal to want as
+// determined by reflect.DeepEqual. If the flag is not defined or is not a
+// Getter, Check reports a fatal error.
+func Check(t testing.TB, fs *flag.FlagSet, name string, want any) {
+	t.Helper()
+	if got := getValue(t, fs, name); !reflect.DeepEqual(got, want) {
+		t.Errorf("Flag -%s: got %#v (%T), want %#v (%T)", name, got, got, want, want)
+	}
+}
+
+func getValue(t testing.TB, fs *flag.FlagSet, name string) any {
+	t.Helper()
+	f := fs.Lookup(name)
+	if f == nil {
+		t.Fatalf("Flag -%s is not defined", name)
+	}
+	g, ok := f.Value.(flag.Getter)
+	if !ok {
+		t.Fatalf("Flag -%s: value %T is not a flag.Getter", name, f.Value)
+	}
+	return g.Get()
+}
+
+// Usage returns the output of the Usage function of fs, or of
+// fs.PrintDefaults if it is nil. The output of fs is restored afterward.
+func Usage(fs *flag.FlagSet) string {
+	var buf strings.Builder
+	out := fs.Output()
+	fs.SetOutput(&buf)
+	defer fs.SetOutput(out)
+	if fs.Usage != nil {
+		fs.Usage()
+	} else {
+		fs.PrintDefaults()
+	}
+	return buf.String()
+}
+
+// Golden reports an error to t if got differs from the content of the file at
+// path. If the environment variable FLAGTEST_UPDATE is not empty, Golden
+// instead writes got to the file, creating its directory if necessary.
+func Golden(t testing.TB, path, got string) {
+	t.Helper()
+	if os.Getenv("FLAGTEST_UPDATE") != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Updating golden file: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("Updating golden file: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Reading golden file: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("Output differs from %s:\n got:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// Reset sets each flag of fs to its default when the test that calls it and
+// its subtests finish, by passing the default to Set as reported by
+// goflags.DefaultArg, which unquotes the quoted defaults of ArgStringer
+// values. If Set rejects that argument, the DefValue is passed instead. This
+// allows subtests to share a flag set, and values bound to package
+// variables, without affecting each other. Values that accumulate, such as
+// multiflag values, cannot be reset this way. If a flag cannot be reset,
+// Reset reports an error to t.
+func Reset(t testing.TB, fs *flag.FlagSet) {
+	t.Helper()
+	t.Cleanup(func() {
+		fs.VisitAll(func(f *flag.Flag) {
+			arg := goflags.DefaultArg(f)
+			err := f.Value.Set(arg)
+			if err != nil && arg != f.DefValue {
+				err = f.Value.Set(f.DefValue)
+			}
+			if err != nil {
+				t.Errorf("Resetting flag -%s to %s: %v", f.Name, f.DefValue, err)
+			}
+		})
+	})
+}
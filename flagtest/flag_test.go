@@ -0,0 +1,139 @@
+package flagtest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/creachadair/goflags/enumflag"
+	"github.com/creachadair/goflags/fileflag"
+	"github.com/creachadair/goflags/sizeflag"
+)
+
+// fakeT records the failures reported by a helper.
+type fakeT struct {
+	testing.TB
+	errors []string
+	fatal  bool
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(msg string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(msg, args...))
+}
+
+func (f *fakeT) Fatalf(msg string, args ...any) {
+	f.Errorf(msg, args...)
+	f.fatal = true
+	runtime.Goexit()
+}
+
+// run calls fn with a fakeT, and returns the failures it reported.
+func run(fn func(t testing.TB)) *fakeT {
+	ft := new(fakeT)
+	done := make(chan struct{})
+	go func() { defer close(done); fn(ft) }()
+	<-done
+	return ft
+}
+
+func testFlags() (fs *flag.FlagSet, size *sizeflag.Value2, color *enumflag.Value) {
+	fs = New("test")
+	size = sizeflag.Base2(0)
+	color = enumflag.New("red", "green", "blue")
+	fs.Var(size, "size", "Size")
+	fs.Var(color, "color", color.Help("Color"))
+	fs.Duration("wait", time.Second, "Wait time")
+	fs.String("name", "", "Name")
+	return
+}
+
+func TestParse(t *testing.T) {
+	fs, _, _ := testFlags()
+	rest := Parse(t, fs, `-size 4K -color blue -name 'a b' -- x "y z"`)
+	if got, want := strings.Join(rest, ","), "x,y z"; got != want {
+		t.Errorf("Parse: got args %q, want %q", got, want)
+	}
+	Check(t, fs, "size", 4096)
+	Check(t, fs, "color", "blue")
+	Check(t, fs, "name", "a b")
+	if got := Get[time.Duration](t, fs, "wait"); got != time.Second {
+		t.Errorf("Get wait: got %v, want 1s", got)
+	}
+
+	err := ParseError(t, fs, "-color purple")
+	if !strings.Contains(err.Error(), "purple") {
+		t.Errorf("ParseError: got %v, want error for purple", err)
+	}
+}
+
+func TestFailures(t *testing.T) {
+	tests := []struct {
+		desc  string
+		fn    func(t testing.TB, fs *flag.FlagSet)
+		fatal bool
+		want  string
+	}{
+		{"parse error", func(t testing.TB, fs *flag.FlagSet) { Parse(t, fs, "-bogus") }, true, "not defined: -bogus"},
+		{"split error", func(t testing.TB, fs *flag.FlagSet) { Parse(t, fs, `"unclosed`) }, true, "Splitting"},
+		{"no parse error", func(t testing.TB, fs *flag.FlagSet) { ParseError(t, fs, "-size 1") }, true, "want error"},
+		{"wrong value", func(t testing.TB, fs *flag.FlagSet) { Check(t, fs, "size", 1) }, false, "want 1"},
+		{"wrong type", func(t testing.TB, fs *flag.FlagSet) { Check(t, fs, "size", int64(0)) }, false, "(int64)"},
+		{"undefined", func(t testing.TB, fs *flag.FlagSet) { Check(t, fs, "nonesuch", 0) }, true, "not defined"},
+		{"get type", func(t testing.TB, fs *flag.FlagSet) { Get[string](t, fs, "size") }, true, "has type int, want string"},
+	}
+	for _, tc := range tests {
+		fs, _, _ := testFlags()
+		ft := run(func(t testing.TB) { tc.fn(t, fs) })
+		if len(ft.errors) != 1 || !strings.Contains(ft.errors[0], tc.want) || ft.fatal != tc.fatal {
+			t.Errorf("%s: got errors %q (fatal %v), want %q (fatal %v)", tc.desc, ft.errors, ft.fatal, tc.want, tc.fatal)
+		}
+	}
+}
+
+func TestUsageGolden(t *testing.T) {
+	fs, _, _ := testFlags()
+	usage := Usage(fs)
+	if !strings.HasPrefix(usage, "Usage of test:\n") || !strings.Contains(usage, "Color (red|green|blue)") {
+		t.Errorf("Usage: got %q", usage)
+	}
+	if got := Usage(fs); got != usage {
+		t.Errorf("Usage again: got %q, want %q", got, usage)
+	}
+
+	path := filepath.Join(t.TempDir(), "testdata", "usage.golden")
+	t.Setenv("FLAGTEST_UPDATE", "1")
+	Golden(t, path, usage)
+	t.Setenv("FLAGTEST_UPDATE", "")
+	Golden(t, path, usage)
+	if data, err := os.ReadFile(path); err != nil || string(data) != usage {
+		t.Errorf("Golden file: got %q, %v; want %q", data, err, usage)
+	}
+
+	ft := run(func(t testing.TB) { Golden(t, path, "other") })
+	if len(ft.errors) != 1 || !strings.Contains(ft.errors[0], "differs") {
+		t.Errorf("Golden mismatch: got errors %q", ft.errors)
+	}
+}
+
+func TestReset(t *testing.T) {
+	fs, size, color := testFlags()
+	config := &fileflag.Value{Path: "/etc/app.conf"} // DefValue is quoted
+	fs.Var(config, "config", "Config file")
+	for _, args := range []string{"-size 1K -color green", "-size 2K -config /tmp/x.conf"} {
+		t.Run(args, func(t *testing.T) {
+			Reset(t, fs)
+			Parse(t, fs, args)
+		})
+		if size.Get() != 0 || color.Key() != "red" || config.Path != "/etc/app.conf" {
+			t.Errorf("After %q: got -size %v -color %v -config %q, want defaults",
+				args, size.Get(), color.Key(), config.Path)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package goflags
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/creachadair/goflags/defaultflag"
+	"github.com/creachadair/goflags/secretflag"
+)
+
+// A *Flagz is an http.Handler for a debug page that reports the flags of a
+// flag set, with their current values, defaults, and sources. A Flagz must
+// not be copied after first use.
+//
+// A GET request reports the flags as a text table, or as JSON in the format
+// of WriteJSON if the request has the query parameter "format=json" or
+// accepts only "application/json".
+//
+// A POST request updates flags, if Authorize allows it. The form values of
+// the request name the flags to update, and each value is passed to fs.Set,
+// so that it is validated by the Set method of the flag; a flag with several
+// values is set once for each, in order. The flags are set in order by name,
+// and the first error is reported with status 400; in that case, the handler
+// restores the previous values of the flags it set, as by Snapshot, except
+// for values that accumulate and have no Reset method, which cannot be reset.
+// Values whose String methods hide them, such as secretflag values, are
+// restored only if they are Snapshotter values. The values of flags that may
+// be updated should be safe for concurrent use, for example by wrapping them
+// with Atomic. Requests to the same *Flagz are serialized with respect to
+// updates, but other code that sets or visits the flags of the flag set while
+// the handler is serving must not run concurrently with an update.
+type Flagz struct {
+	FlagSet *flag.FlagSet
+
+	// If non-nil, Authorize is called for each POST request, and the update
+	// is allowed if it reports nil. Otherwise the error is reported with
+	// status 403. If Authorize is nil, updates are not allowed.
+	Authorize func(*http.Request) error
+
+	// If non-nil, Redact is called for each flag, and the values of a flag
+	// for which it reports true are reported as secretflag.Redacted, as for
+	// Exporter.
+	Redact func(f *flag.Flag) bool
+
+	mu sync.RWMutex // held across updates, and for reading during reports
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (z *Flagz) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		z.serveFlags(w, r)
+	case http.MethodPost:
+		z.serveUpdate(w, r)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (z *Flagz) serveFlags(w http.ResponseWriter, r *http.Request) {
+	z.mu.RLock()
+	info := Describe(z.FlagSet)
+	for i, fi := range info {
+		if z.Redact != nil && z.Redact(z.FlagSet.Lookup(fi.Name)) {
+			info[i].Value, info[i].Default = secretflag.Redacted, secretflag.Redacted
+		}
+	}
+	z.mu.RUnlock()
+	w.Header().Set("Cache-Control", "no-store")
+	if r.URL.Query().Get("format") == "json" || r.Header.Get("Accept") == "application/json" {
+		if info == nil {
+			info = []FlagInfo{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(info)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "FLAG\tVALUE\tDEFAULT\tSOURCE")
+	for _, fi := range info {
+		fmt.Fprintf(tw, "-%s\t%s\t%s\t%s\n", fi.Name, tableCell(fi.Value), tableCell(fi.Default), flagSource(fi))
+	}
+	tw.Flush()
+}
+
+// tableCell returns s quoted if it is empty or contains spaces, tabs, or
+// newlines, which would otherwise break the table.
+func tableCell(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\n\"") {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
+
+func (z *Flagz) serveUpdate(w http.ResponseWriter, r *http.Request) {
+	if z.Authorize == nil {
+		http.Error(w, "flag updates are not allowed", http.StatusForbidden)
+		return
+	} else if err := z.Authorize(r); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	names := slices.Sorted(maps.Keys(r.PostForm))
+	if len(names) == 0 {
+		http.Error(w, "no flags to update", http.StatusBadRequest)
+		return
+	}
+	for _, name := range names {
+		if z.FlagSet.Lookup(name) == nil {
+			http.Error(w, fmt.Sprintf("flag provided but not defined: -%s", name), http.StatusBadRequest)
+			return
+		}
+	}
+
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	var buf strings.Builder
+	var restore []func()
+	for _, name := range names {
+		restore = append(restore, z.restorer(name))
+		for _, s := range r.PostForm[name] {
+			if err := z.FlagSet.Set(name, s); err != nil {
+				for i := len(restore) - 1; i >= 0; i-- {
+					restore[i]()
+				}
+				http.Error(w, fmt.Sprintf("invalid value %q for flag -%s: %v", s, name, err), http.StatusBadRequest)
+				return
+			}
+		}
+		f := z.FlagSet.Lookup(name)
+		val := f.Value.String()
+		if z.Redact != nil && z.Redact(f) {
+			val = secretflag.Redacted
+		}
+		fmt.Fprintf(&buf, "-%s=%s\n", name, val)
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, buf.String())
+}
+
+// A Snapshotter is a flag.Value whose String method does not report its
+// value, such as secretflag.Value, and that can record its value instead.
+// Snapshot returns a function that restores the recorded value.
+type Snapshotter interface {
+	flag.Value
+	Snapshot() func()
+}
+
+// restorer returns a function that restores the current value of the named
+// flag, as described for Snapshot.
+func (z *Flagz) restorer(name string) func() { return Snapshot(z.FlagSet.Lookup(name).Value) }
+
+// Snapshot returns a function that restores the current value of v. If v, or
+// a value it wraps, is a Snapshotter, the function restores its snapshot.
+// If v accumulates values, such as a Repeatable value, and has a Reset
+// method, as multiflag.Value does, the function resets it and passes each of
+// its current values back to Set, or passes them to AtomicValue.Replace if v
+// is wrapped by Atomic; if it has no Reset method, the function does nothing. Otherwise, the function passes the current value of v, as
+// reported by ArgString, back to its Set method. The source of the value of a
+// *defaultflag.Value is also restored.
+func Snapshot(v flag.Value) func() {
+	if dv, ok := findValue[*defaultflag.Value](v); ok {
+		restore, src, origin := Snapshot(dv.Unwrap()), dv.Source(), dv.Origin()
+		return func() { restore(); dv.SetSource(src, origin) }
+	}
+	if s, ok := findValue[Snapshotter](v); ok {
+		return s.Snapshot()
+	}
+	if isRepeated(v) {
+		if !canReplace(v) {
+			return func() {}
+		}
+		args := valueArgs(v)
+		return func() { replaceValues(v, args) }
+	}
+	old := ArgString(v)
+	return func() { v.Set(old) }
+}
+
+// A resetter is a value that accumulates values, and can discard them.
+type resetter interface {
+	flag.Value
+	Reset()
+}
+
+// A replacer is a value that can replace the values it has accumulated in
+// one step, as an AtomicValue does.
+type replacer interface {
+	flag.Value
+	Replace(vals []string) error
+}
+
+// replaceValues discards the values accumulated by v, and sets it from each
+// of vals in order. It uses the Replace method of v or a value it wraps, if
+// there is one, so that a value guarded by a lock is replaced while it is
+// held; otherwise it uses a Reset method, and reports an error if there is
+// none.
+func replaceValues(v flag.Value, vals []string) error {
+	if r, ok := findValue[replacer](v); ok {
+		return r.Replace(vals)
+	}
+	r, ok := findValue[resetter](v)
+	if !ok {
+		return fmt.Errorf("%T cannot be reset", v)
+	}
+	r.Reset()
+	for _, s := range vals {
+		if err := r.Set(s); err != nil {
+			return fmt.Errorf("invalid value %q: %w", s, err)
+		}
+	}
+	return nil
+}
+
+// canReplace reports whether replaceValues can replace the values of v.
+func canReplace(v flag.Value) bool {
+	_, isReplacer := findValue[replacer](v)
+	_, isResetter := findValue[resetter](v)
+	return isReplacer || isResetter
+}
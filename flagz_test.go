@@ -0,0 +1,195 @@
+package goflags
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/creachadair/goflags/secretflag"
+)
+
+func flagzServer(t *testing.T, z *Flagz) *httptest.Server {
+	t.Helper()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.String("addr", ":80", "Address")
+	fs.String("password", "", "Password")
+	fs.Int("n", 1, "Count")
+	z.FlagSet = fs
+	z.Redact = func(f *flag.Flag) bool { return f.Name == "password" }
+	s := httptest.NewServer(z)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func fetch(t *testing.T, req *http.Request) (int, string) {
+	t.Helper()
+	rsp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer rsp.Body.Close()
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		t.Fatalf("Reading response: %v", err)
+	}
+	return rsp.StatusCode, string(body)
+}
+
+func postForm(t *testing.T, url string, form url.Values) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestFlagzGet(t *testing.T) {
+	s := flagzServer(t, &Flagz{})
+	req, _ := http.NewRequest(http.MethodGet, s.URL, nil)
+	code, body := fetch(t, req)
+	const want = `FLAG       VALUE       DEFAULT     SOURCE
+-addr      :80         :80         default
+-n         1           1           default
+-password  [redacted]  [redacted]  default
+`
+	if code != http.StatusOK || body != want {
+		t.Errorf("GET: got %d %q, want 200 %q", code, body, want)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, s.URL+"?format=json", nil)
+	code, body = fetch(t, req)
+	var info []FlagInfo
+	if err := json.Unmarshal([]byte(body), &info); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", body, err)
+	}
+	if code != http.StatusOK || len(info) != 3 || info[2].Value != "[redacted]" {
+		t.Errorf("GET JSON: got %d %+v", code, info)
+	}
+
+	req, _ = http.NewRequest(http.MethodDelete, s.URL, nil)
+	if code, _ := fetch(t, req); code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE: got %d, want %d", code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestFlagzPost(t *testing.T) {
+	// Without an authorization hook, updates are not allowed.
+	s := flagzServer(t, &Flagz{})
+	if code, _ := fetch(t, postForm(t, s.URL, url.Values{"n": {"2"}})); code != http.StatusForbidden {
+		t.Errorf("POST without Authorize: got %d, want %d", code, http.StatusForbidden)
+	}
+
+	s = flagzServer(t, &Flagz{
+		Authorize: func(r *http.Request) error {
+			if r.Header.Get("Authorization") != "ok" {
+				return errors.New("unauthorized")
+			}
+			return nil
+		},
+	})
+	req := postForm(t, s.URL, url.Values{"n": {"2"}})
+	if code, _ := fetch(t, req); code != http.StatusForbidden {
+		t.Errorf("POST unauthorized: got %d, want %d", code, http.StatusForbidden)
+	}
+
+	tests := []struct {
+		form url.Values
+		code int
+		want string
+	}{
+		{url.Values{"n": {"2"}, "password": {"x"}}, http.StatusOK, "-n=2\n-password=[redacted]\n"},
+		{url.Values{"n": {"bogus"}}, http.StatusBadRequest, `invalid value "bogus" for flag -n`},
+		{url.Values{"nonesuch": {"1"}, "n": {"3"}}, http.StatusBadRequest, "not defined: -nonesuch"},
+		{url.Values{}, http.StatusBadRequest, "no flags to update"},
+	}
+	for _, tc := range tests {
+		req := postForm(t, s.URL, tc.form)
+		req.Header.Set("Authorization", "ok")
+		code, body := fetch(t, req)
+		if code != tc.code || !strings.Contains(body, tc.want) {
+			t.Errorf("POST %v: got %d %q, want %d %q", tc.form, code, body, tc.code, tc.want)
+		}
+	}
+
+	// The successful update is reported, and the failed ones did not apply.
+	req, _ = http.NewRequest(http.MethodGet, s.URL, nil)
+	if _, body := fetch(t, req); !strings.Contains(body, "-n         2           1           flag\n") {
+		t.Errorf("GET after POST: got %q", body)
+	}
+}
+
+func TestFlagzRestoreSecret(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var token secretflag.Value
+	token.Set("literal:s3cret")
+	fs.Var(&token, "token", "Token")
+	fs.Int("z", 1, "Count")
+	s := httptest.NewServer(&Flagz{FlagSet: fs, Authorize: func(*http.Request) error { return nil }})
+	t.Cleanup(s.Close)
+
+	// The update of -token is rolled back when the update of -z fails.
+	code, _ := fetch(t, postForm(t, s.URL, url.Values{"token": {"other"}, "z": {"bogus"}}))
+	if code != http.StatusBadRequest {
+		t.Errorf("POST: got %d, want %d", code, http.StatusBadRequest)
+	}
+	if got := token.Text(); got != "s3cret" {
+		t.Errorf("After failed POST: got token %q, want %q", got, "s3cret")
+	}
+}
+
+func TestFlagzConcurrent(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	n := Atomic[int](new(intValue))
+	fs.Var(n, "n", "Count")
+	fs.Var(Atomic[int](new(intValue)), "m", "Count")
+	s := httptest.NewServer(&Flagz{FlagSet: fs, Authorize: func(*http.Request) error { return nil }})
+	t.Cleanup(s.Close)
+
+	// Run with -race to check that updates do not race with each other or
+	// with reports.
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			form := url.Values{"n": {strconv.Itoa(i)}, "m": {"bogus"}}
+			if i%2 == 0 {
+				form["m"] = []string{strconv.Itoa(i)}
+			}
+			fetch(t, postForm(t, s.URL, form))
+		}()
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, s.URL+"?format=json", nil)
+			if code, _ := fetch(t, req); code != http.StatusOK {
+				t.Errorf("GET: got %d, want %d", code, http.StatusOK)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := n.Load(); got%2 != 0 {
+		t.Errorf("Value: got %d, want an even value from a successful update", got)
+	}
+}
+
+// intValue is an integer flag.Getter for testing.
+type intValue int
+
+func (v *intValue) String() string { return strconv.Itoa(int(*v)) }
+func (v *intValue) Get() any       { return int(*v) }
+func (v *intValue) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	*v = intValue(n)
+	return err
+}
@@ -0,0 +1,92 @@
+// Package funcflag defines a generic flag.Value implementation that parses
+// its argument with a caller-provided function, in the manner of flag.Func,
+// but which also satisfies flag.Getter and records the parsed value.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//	  "net/mail"
+//
+//	  "github.com/creachadair/goflags/funcflag"
+//	)
+//
+//	var owner = funcflag.New(mail.ParseAddress)
+//	func init() {
+//	  flag.Var(owner, "owner", "Owner address")
+//	}
+//
+//	  ...
+//	  if owner.WasSet() {
+//	    notify(owner.Value())
+//	  }
+package funcflag
+
+import "fmt"
+
+// A Value holds a value of type T parsed by a function. A *Value satisfies the
+// flag.Value and flag.Getter interfaces.
+type Value[T any] struct {
+	parse  func(string) (T, error)
+	format func(T) string // if nil, use src or fmt.Sprint
+	value  T
+	src    string // the argument most recently parsed
+	set    bool   // whether Set has succeeded
+}
+
+// New returns a *Value that uses parse to convert flag arguments. Its initial
+// value is the zero value of T.
+func New[T any](parse func(string) (T, error)) *Value[T] {
+	return &Value[T]{parse: parse}
+}
+
+// WithDefault sets the initial value of v to d and returns v. The default is
+// reported by String, and hence in the flag's usage message.
+func (v *Value[T]) WithDefault(d T) *Value[T] {
+	v.value = d
+	return v
+}
+
+// WithFormat sets the function used by String to format the value of v to f,
+// and returns v. If f is nil, String reports the argument most recently set,
+// or the default formatted with fmt.Sprint.
+func (v *Value[T]) WithFormat(f func(T) string) *Value[T] {
+	v.format = f
+	return v
+}
+
+// Value returns the current value: the result of the latest successful call
+// to Set, or the default if there was none.
+func (v *Value[T]) Value() T { return v.value }
+
+// WasSet reports whether the flag was successfully set.
+func (v *Value[T]) WasSet() bool { return v.set }
+
+// String satisfies part of the flag.Value interface.
+// It returns the current value formatted by the function given to WithFormat,
+// if any; otherwise the argument most recently set, or else the default value
+// formatted with fmt.Sprint.
+func (v *Value[T]) String() string {
+	if v == nil {
+		return ""
+	} else if v.format != nil {
+		return v.format(v.value)
+	} else if v.set {
+		return v.src
+	}
+	return fmt.Sprint(v.value)
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value[T]) Set(s string) error {
+	val, err := v.parse(s)
+	if err != nil {
+		return err
+	}
+	v.value, v.src, v.set = val, s, true
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type T.
+func (v *Value[T]) Get() any { return v.value }
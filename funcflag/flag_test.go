@@ -0,0 +1,67 @@
+package funcflag
+
+import (
+	"bytes"
+	"flag"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	owner := New(mail.ParseAddress)
+	level := New(strconv.Atoi).WithDefault(3)
+
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("func", flag.ContinueOnError)
+	fs.SetOutput(&buf)
+	fs.Var(owner, "owner", "Owner address")
+	fs.Var(level, "level", "Verbosity level")
+	fs.PrintDefaults()
+	if !strings.Contains(buf.String(), "(default 3)") {
+		t.Errorf("Usage does not mention default:\n%s", buf.String())
+	}
+
+	if owner.WasSet() || owner.Value() != nil {
+		t.Errorf("Initial value for -owner: got %v, set=%v", owner.Value(), owner.WasSet())
+	}
+	if err := fs.Parse([]string{"-owner", "Alice <alice@example.com>"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if !owner.WasSet() {
+		t.Error("WasSet for -owner: got false, want true")
+	}
+	if got := owner.Get().(*mail.Address); got.Address != "alice@example.com" {
+		t.Errorf("Value for -owner: got %v, want alice@example.com", got)
+	}
+	if got, want := owner.String(), "Alice <alice@example.com>"; got != want {
+		t.Errorf("String for -owner: got %q, want %q", got, want)
+	}
+	if got := level.Get().(int); got != 3 || level.WasSet() {
+		t.Errorf("Value for -level: got %d, set=%v; want 3, false", got, level.WasSet())
+	}
+
+	if err := fs.Parse([]string{"-level", "x"}); err == nil {
+		t.Error("Invalid -level was accepted")
+	} else if level.WasSet() || level.Value() != 3 {
+		t.Errorf("Failed Set changed -level: got %d, set=%v", level.Value(), level.WasSet())
+	}
+}
+
+func TestFormat(t *testing.T) {
+	hex := New(func(s string) (int64, error) {
+		return strconv.ParseInt(strings.TrimPrefix(s, "0x"), 16, 64)
+	}).WithDefault(255).WithFormat(func(v int64) string {
+		return "0x" + strconv.FormatInt(v, 16)
+	})
+	if got, want := hex.String(), "0xff"; got != want {
+		t.Errorf("String of default: got %q, want %q", got, want)
+	}
+	if err := hex.Set("1A"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if got, want := hex.String(), "0x1a"; got != want {
+		t.Errorf("String after Set: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,170 @@
+// Package geoflag defines flag.Value implementations for geographic
+// coordinates and bounding boxes.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/geoflag"
+//	)
+//
+//	var (
+//	  center geoflag.Point
+//	  bounds geoflag.Box
+//	)
+//	func init() {
+//	  flag.Var(&center, "center", center.Help("Map center"))
+//	  flag.Var(&bounds, "bounds", bounds.Help("Search area"))
+//	}
+//
+// A point is a latitude and longitude in decimal degrees separated by a comma,
+// as "37.77,-122.42". Either component may instead have a hemisphere suffix,
+// as "48.85N,2.35E". A box is two corner points separated by a colon, as
+// "37.7,-122.5:37.8,-122.3".
+package geoflag
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// A Point is a location given by latitude and longitude in decimal degrees.
+// A pointer to a Point satisfies the flag.Value and flag.Getter interfaces.
+type Point struct {
+	Lat float64 // -90 to 90, positive north of the equator
+	Lon float64 // -180 to 180, positive east of the prime meridian
+}
+
+// ParsePoint parses a string in the format "lat,lon".
+func ParsePoint(s string) (Point, error) {
+	ls, rs, ok := strings.Cut(s, ",")
+	if !ok {
+		return Point{}, fmt.Errorf("geoflag: invalid point %q, want lat,lon", s)
+	}
+	lat, err := parseCoord(ls, "NS", 90)
+	if err != nil {
+		return Point{}, fmt.Errorf("geoflag: latitude: %w", err)
+	}
+	lon, err := parseCoord(rs, "EW", 180)
+	if err != nil {
+		return Point{}, fmt.Errorf("geoflag: longitude: %w", err)
+	}
+	return Point{Lat: lat, Lon: lon}, nil
+}
+
+// Help concatenates a human-readable string summarizing the format of p to h,
+// for use in generating a documentation string.
+func (p *Point) Help(h string) string { return h + " (lat,lon)" }
+
+// String satisfies part of the flag.Value interface.
+func (p *Point) String() string {
+	return strconv.FormatFloat(p.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(p.Lon, 'f', -1, 64)
+}
+
+// Set satisfies part of the flag.Value interface.
+func (p *Point) Set(s string) error {
+	pt, err := ParsePoint(s)
+	if err != nil {
+		return err
+	}
+	*p = pt
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Point.
+func (p *Point) Get() any { return *p }
+
+// A Box is a region bounded by lines of latitude and longitude. A pointer to
+// a Box satisfies the flag.Value and flag.Getter interfaces.
+//
+// The corners may be given in either order, and are normalized so that Min
+// is the southwest corner and Max the northeast. Boxes that cross the
+// antimeridian are not supported.
+type Box struct {
+	Min, Max Point
+}
+
+// ParseBox parses a string in the format "lat1,lon1:lat2,lon2".
+func ParseBox(s string) (Box, error) {
+	as, bs, ok := strings.Cut(s, ":")
+	if !ok {
+		return Box{}, fmt.Errorf("geoflag: invalid box %q, want lat1,lon1:lat2,lon2", s)
+	}
+	a, err := ParsePoint(as)
+	if err != nil {
+		return Box{}, err
+	}
+	b, err := ParsePoint(bs)
+	if err != nil {
+		return Box{}, err
+	}
+	return Box{
+		Min: Point{Lat: min(a.Lat, b.Lat), Lon: min(a.Lon, b.Lon)},
+		Max: Point{Lat: max(a.Lat, b.Lat), Lon: max(a.Lon, b.Lon)},
+	}, nil
+}
+
+// Help concatenates a human-readable string summarizing the format of b to h,
+// for use in generating a documentation string.
+func (b *Box) Help(h string) string { return h + " (lat1,lon1:lat2,lon2)" }
+
+// Contains reports whether p lies within b, including its edges.
+func (b *Box) Contains(p Point) bool {
+	return b.Min.Lat <= p.Lat && p.Lat <= b.Max.Lat && b.Min.Lon <= p.Lon && p.Lon <= b.Max.Lon
+}
+
+// String satisfies part of the flag.Value interface.
+func (b *Box) String() string { return b.Min.String() + ":" + b.Max.String() }
+
+// Set satisfies part of the flag.Value interface.
+func (b *Box) Set(s string) error {
+	box, err := ParseBox(s)
+	if err != nil {
+		return err
+	}
+	*b = box
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Box.
+func (b *Box) Get() any { return *b }
+
+// parseCoord parses a signed decimal number of degrees, or an unsigned one
+// followed by one of the hemisphere letters in hemi (positive first), and
+// checks that its magnitude does not exceed limit.
+func parseCoord(s, hemi string, limit float64) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.New("empty value")
+	}
+	sign := 0.0
+	switch c := strings.ToUpper(s[len(s)-1:]); c {
+	case hemi[:1]:
+		sign = 1
+	case hemi[1:]:
+		sign = -1
+	}
+	if sign != 0 {
+		s = strings.TrimSpace(s[:len(s)-1])
+		if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+			return 0, fmt.Errorf("signed value %q with hemisphere suffix", s)
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if sign != 0 {
+		f *= sign
+	}
+	if math.Abs(f) > limit {
+		return 0, fmt.Errorf("value %v out of range [%v, %v]", f, -limit, limit)
+	}
+	return f, nil
+}
@@ -0,0 +1,69 @@
+package geoflag
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var center Point
+	var bounds Box
+	fs := flag.NewFlagSet("geo", flag.ContinueOnError)
+	fs.Var(&center, "center", center.Help("Map center"))
+	fs.Var(&bounds, "bounds", bounds.Help("Search area"))
+
+	if err := fs.Parse([]string{"-center", "48.85N, 2.35E", "-bounds", "37.8,-122.3:37.7,-122.5"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := center.Get().(Point), (Point{48.85, 2.35}); got != want {
+		t.Errorf("Value for -center: got %v, want %v", got, want)
+	}
+	want := Box{Min: Point{37.7, -122.5}, Max: Point{37.8, -122.3}}
+	if got := bounds.Get().(Box); got != want {
+		t.Errorf("Value for -bounds: got %v, want %v", got, want)
+	}
+	if got, want := bounds.String(), "37.7,-122.5:37.8,-122.3"; got != want {
+		t.Errorf("String for -bounds: got %q, want %q", got, want)
+	}
+	if !bounds.Contains(Point{37.77, -122.42}) {
+		t.Error("Contains(37.77,-122.42): got false, want true")
+	}
+	if bounds.Contains(center.Get().(Point)) {
+		t.Errorf("Contains(%v): got true, want false", center.String())
+	}
+}
+
+func TestParsePoint(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Point
+	}{
+		{"0,0", Point{0, 0}},
+		{"37.77,-122.42", Point{37.77, -122.42}},
+		{"33.87S,151.21E", Point{-33.87, 151.21}},
+		{"40.7n,74w", Point{40.7, -74}},
+		{"-90,180", Point{-90, 180}},
+		{"90N,180W", Point{90, -180}},
+	}
+	for _, test := range tests {
+		got, err := ParsePoint(test.input)
+		if err != nil {
+			t.Errorf("ParsePoint(%q): unexpected error: %v", test.input, err)
+		} else if got != test.want {
+			t.Errorf("ParsePoint(%q): got %v, want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, bad := range []string{"", "37.77", "91,0", "0,180.5", "1E,2N", "-5N,0", "x,y", "NaN,0", "1,2,3"} {
+		if p, err := ParsePoint(bad); err == nil {
+			t.Errorf("ParsePoint(%q): got %v, wanted error", bad, p)
+		}
+	}
+	for _, bad := range []string{"", "1,2", "1,2:3", "1,2:91,0"} {
+		if b, err := ParseBox(bad); err == nil {
+			t.Errorf("ParseBox(%q): got %v, wanted error", bad, b)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+// Package gpuflag defines a flag.Value implementation for selecting compute
+// devices, such as GPUs, by index.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/gpuflag"
+//	)
+//
+//	var gpus = gpuflag.Value{Available: 8, Max: 4}
+//	func init() {
+//	  flag.Var(&gpus, "gpus", gpus.Help("Devices to use"))
+//	}
+//
+// With this definition "-gpus 0,2-3" selects devices 0, 2, and 3, "-gpus all"
+// selects every available device, and "-gpus none" selects no devices.
+package gpuflag
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A Value represents a set of device indices. A pointer to a Value satisfies
+// the flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The selected device indices, in increasing order without duplicates.
+	// If All is true and Available is not positive, Devices is empty.
+	Devices []int
+
+	// Whether "all" was selected.
+	All bool
+
+	// If positive, the number of devices present. Indices must be less than
+	// Available, and "all" selects indices 0 through Available-1.
+	Available int
+
+	// If positive, the most devices that may be selected.
+	Max int
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	if v.Available > 0 {
+		return fmt.Sprintf("%s (all, none, or indices in 0-%d, e.g., 0,2-3)", h, v.Available-1)
+	}
+	return h + " (all, none, or indices, e.g., 0,2-3)"
+}
+
+// Resolve returns the selected indices given that n devices are present. If
+// All is set, this is 0 through n-1; otherwise it is Devices. It reports an
+// error if a selected index is not less than n, or if more than Max devices
+// would be selected.
+func (v *Value) Resolve(n int) ([]int, error) {
+	if v.All {
+		if v.Max > 0 && n > v.Max {
+			return nil, fmt.Errorf("gpuflag: %d devices selected, at most %d allowed", n, v.Max)
+		}
+		out := make([]int, n)
+		for i := range out {
+			out[i] = i
+		}
+		return out, nil
+	}
+	if len(v.Devices) != 0 && v.Devices[len(v.Devices)-1] >= n {
+		return nil, fmt.Errorf("gpuflag: device %d not present (have %d)", v.Devices[len(v.Devices)-1], n)
+	}
+	return v.Devices, nil
+}
+
+// Env returns the selection as a comma-separated list of indices, in the
+// format used by environment variables such as CUDA_VISIBLE_DEVICES, and
+// reports whether the variable should be set. If All is set and Available is
+// not positive, the indices are not known, and Env reports "", false: the
+// variable should be left unset, so that every device is visible, as an empty
+// value hides them all. If no devices are selected, Env reports "", true.
+func (v *Value) Env() (string, bool) {
+	if v.All && len(v.Devices) == 0 {
+		return "", false
+	}
+	parts := make([]string, len(v.Devices))
+	for i, d := range v.Devices {
+		parts[i] = strconv.Itoa(d)
+	}
+	return strings.Join(parts, ","), true
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string {
+	switch {
+	case v.All:
+		return "all"
+	case len(v.Devices) == 0:
+		return "none"
+	}
+	return compact(v.Devices)
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "all":
+		if v.Max > 0 && v.Available > v.Max {
+			return fmt.Errorf("gpuflag: %d devices selected, at most %d allowed", v.Available, v.Max)
+		}
+		v.Devices, v.All = nil, true
+		if v.Available > 0 {
+			v.Devices, _ = v.Resolve(v.Available)
+		}
+		return nil
+	case "none", "":
+		v.Devices, v.All = nil, false
+		return nil
+	}
+	var devs []int
+	for _, part := range strings.Split(s, ",") {
+		lo, hi, err := parseRange(strings.TrimSpace(part))
+		if err != nil {
+			return err
+		}
+		if v.Available > 0 && hi >= v.Available {
+			return fmt.Errorf("gpuflag: device %d not present (have %d)", hi, v.Available)
+		}
+		for i := lo; i <= hi; i++ {
+			devs = append(devs, i)
+		}
+	}
+	slices.Sort(devs)
+	devs = slices.Compact(devs)
+	if v.Max > 0 && len(devs) > v.Max {
+		return fmt.Errorf("gpuflag: %d devices selected, at most %d allowed", len(devs), v.Max)
+	}
+	v.Devices, v.All = devs, false
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type []int.
+func (v *Value) Get() any { return v.Devices }
+
+// maxIndex bounds device indices to reject typos such as "0-1000000".
+const maxIndex = 4096
+
+func parseRange(s string) (lo, hi int, err error) {
+	if s == "" {
+		return 0, 0, errors.New("gpuflag: empty device index")
+	}
+	a, b, isRange := strings.Cut(s, "-")
+	if lo, err = parseIndex(a); err != nil {
+		return 0, 0, err
+	}
+	hi = lo
+	if isRange {
+		if hi, err = parseIndex(b); err != nil {
+			return 0, 0, err
+		}
+		if hi < lo {
+			return 0, 0, fmt.Errorf("gpuflag: invalid range %q", s)
+		}
+	}
+	return lo, hi, nil
+}
+
+func parseIndex(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n >= maxIndex {
+		return 0, fmt.Errorf("gpuflag: invalid device index %q", s)
+	}
+	return n, nil
+}
+
+// compact renders sorted indices with runs collapsed into ranges.
+func compact(devs []int) string {
+	var parts []string
+	for i := 0; i < len(devs); {
+		j := i
+		for j+1 < len(devs) && devs[j+1] == devs[j]+1 {
+			j++
+		}
+		if j > i {
+			parts = append(parts, fmt.Sprintf("%d-%d", devs[i], devs[j]))
+		} else {
+			parts = append(parts, strconv.Itoa(devs[i]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
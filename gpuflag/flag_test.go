@@ -0,0 +1,95 @@
+package gpuflag
+
+import (
+	"flag"
+	"slices"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	gpus := Value{Available: 8}
+	fs := flag.NewFlagSet("gpu", flag.ContinueOnError)
+	fs.Var(&gpus, "gpus", gpus.Help("Devices"))
+
+	if got, want := gpus.String(), "none"; got != want {
+		t.Errorf("Initial -gpus: got %q, want %q", got, want)
+	}
+	if err := fs.Parse([]string{"-gpus", "3,0-1,2,7"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := gpus.Get().([]int), []int{0, 1, 2, 3, 7}; !slices.Equal(got, want) {
+		t.Errorf("Value for -gpus: got %v, want %v", got, want)
+	}
+	if got, want := gpus.String(), "0-3,7"; got != want {
+		t.Errorf("String for -gpus: got %q, want %q", got, want)
+	}
+	if got, ok := gpus.Env(); got != "0,1,2,3,7" || !ok {
+		t.Errorf("Env for -gpus: got %q, %v, want %q, true", got, ok, "0,1,2,3,7")
+	}
+
+	if err := fs.Parse([]string{"-gpus", "all"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := gpus.Devices, []int{0, 1, 2, 3, 4, 5, 6, 7}; !gpus.All || !slices.Equal(got, want) {
+		t.Errorf("Value for -gpus all: got %v (all=%v), want %v", got, gpus.All, want)
+	}
+	if err := fs.Parse([]string{"-gpus", "none"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if gpus.All || len(gpus.Devices) != 0 {
+		t.Errorf("Value for -gpus none: got %v (all=%v), want empty", gpus.Devices, gpus.All)
+	}
+	if got, ok := gpus.Env(); got != "" || !ok {
+		t.Errorf("Env for -gpus none: got %q, %v, want \"\", true", got, ok)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	var v Value
+	if err := v.Set("all"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if got, err := v.Resolve(3); err != nil || !slices.Equal(got, []int{0, 1, 2}) {
+		t.Errorf("Resolve(3): got %v, %v; want [0 1 2]", got, err)
+	}
+	if err := v.Set("1,4"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if got, err := v.Resolve(2); err == nil {
+		t.Errorf("Resolve(2): got %v, wanted error", got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		v     Value
+		input string
+	}{
+		{Value{}, "x"},
+		{Value{}, "-1"},
+		{Value{}, "3-1"},
+		{Value{}, "0,,1"},
+		{Value{}, "0-100000"},
+		{Value{Available: 4}, "4"},
+		{Value{Available: 4}, "2-5"},
+		{Value{Max: 2}, "0-2"},
+		{Value{Available: 4, Max: 2}, "all"},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.input); err == nil {
+			t.Errorf("Set(%q) with %+v: got %v, wanted error", test.input, test.v, test.v.Devices)
+		}
+	}
+}
+
+func TestEnvAllUnknown(t *testing.T) {
+	// With no count of devices, "all" cannot be listed, and the variable
+	// should be left unset rather than set empty, which would hide them all.
+	var gpus Value
+	if err := gpus.Set("all"); err != nil {
+		t.Fatalf("Set all: unexpected error: %v", err)
+	}
+	if got, ok := gpus.Env(); got != "" || ok {
+		t.Errorf("Env for all: got %q, %v, want \"\", false", got, ok)
+	}
+}
@@ -0,0 +1,168 @@
+package goflags
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/creachadair/goflags/aliasflag"
+	"github.com/creachadair/goflags/defaultflag"
+)
+
+// groups records the constraints declared for each flag set.
+var groups struct {
+	sync.Mutex
+	m map[*flag.FlagSet][]group
+}
+
+// A group is a constraint on the flags with the given names.
+type group struct {
+	kind  groupKind
+	names []string
+}
+
+type groupKind int
+
+const (
+	exclusive groupKind = iota
+	requiresAll
+	atLeastOne
+)
+
+// Exclusive declares that at most one of the named flags of fs may be set.
+func Exclusive(fs *flag.FlagSet, names ...string) { addGroup(fs, exclusive, names) }
+
+// RequiresAll declares that if any of the named flags of fs is set, all of
+// them must be set.
+func RequiresAll(fs *flag.FlagSet, names ...string) { addGroup(fs, requiresAll, names) }
+
+// AtLeastOne declares that at least one of the named flags of fs must be set.
+func AtLeastOne(fs *flag.FlagSet, names ...string) { addGroup(fs, atLeastOne, names) }
+
+func addGroup(fs *flag.FlagSet, kind groupKind, names []string) {
+	groups.Lock()
+	defer groups.Unlock()
+	if groups.m == nil {
+		groups.m = make(map[*flag.FlagSet][]group)
+	}
+	groups.m[fs] = append(groups.m[fs], group{kind: kind, names: append([]string(nil), names...)})
+}
+
+// CheckGroups reports an error for each constraint declared for fs by
+// Exclusive, RequiresAll, or AtLeastOne that is not satisfied. It should be
+// called after fs.Parse, and after ParseEnv if it is used. The errors are
+// joined with errors.Join, in the order the constraints were declared.
+//
+// A flag is considered set if it was set on the command line or by fs.Set,
+// as reported by fs.Visit, including by a deprecated name or alias, or if its
+// value is a *defaultflag.Value that does not hold its default, so that
+// values loaded by LoadConfig are counted.
+func CheckGroups(fs *flag.FlagSet) error { return CheckGroupsWith(fs) }
+
+// CheckGroupsWith is like CheckGroups, but also considers the named flags of
+// fs to be set. This allows flags that share their values with another flag
+// set, and were set there, to be counted, as the command package does for
+// the flags that subcommands inherit.
+func CheckGroupsWith(fs *flag.FlagSet, names ...string) error {
+	groups.Lock()
+	gs := groups.m[fs]
+	groups.Unlock()
+	if len(gs) == 0 {
+		return nil
+	}
+
+	isSet := setFlags(fs)
+	for _, name := range names {
+		if f := fs.Lookup(name); f != nil {
+			markSet(fs, isSet, f)
+		}
+	}
+	var errs []error
+	for _, g := range gs {
+		var set, unset []string
+		for _, name := range g.names {
+			if fs.Lookup(name) == nil {
+				errs = append(errs, fmt.Errorf("goflags: flag group refers to undefined flag -%s", name))
+			} else if isSet[name] {
+				set = append(set, name)
+			} else {
+				unset = append(unset, name)
+			}
+		}
+		switch {
+		case g.kind == exclusive && len(set) > 1:
+			errs = append(errs, fmt.Errorf("goflags: flags %s cannot be used together", joinNames(set, "and")))
+		case g.kind == requiresAll && len(set) > 0 && len(unset) > 0:
+			verb := "requires"
+			if len(set) > 1 {
+				verb = "require"
+			}
+			errs = append(errs, fmt.Errorf("goflags: %s %s %s", flagNames(set), verb, joinNames(unset, "and")))
+		case g.kind == atLeastOne && len(set) == 0 && len(unset) > 0:
+			errs = append(errs, fmt.Errorf("goflags: at least one of %s is required", joinNames(unset, "or")))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Forget discards the constraints and usage sections declared for fs, so
+// that fs may be garbage collected. They are otherwise kept for the life of
+// the program; a program that creates many short-lived flag sets, as the
+// command package does, should call Forget when it is done with each.
+func Forget(fs *flag.FlagSet) {
+	groups.Lock()
+	delete(groups.m, fs)
+	groups.Unlock()
+	forgetSections(fs)
+}
+
+// setFlags returns the names of the flags of fs that are set, as described
+// by CheckGroups. A flag set by a deprecated name or an alias also counts as
+// setting the flag it refers to.
+func setFlags(fs *flag.FlagSet) map[string]bool {
+	isSet := make(map[string]bool)
+	fs.VisitAll(func(f *flag.Flag) {
+		if dv, ok := findValue[*defaultflag.Value](f.Value); ok && !dv.IsDefault() {
+			isSet[f.Name] = true
+		}
+	})
+	fs.Visit(func(f *flag.Flag) { markSet(fs, isSet, f) })
+	return isSet
+}
+
+// markSet records in isSet that f is set, and so is the flag it refers to if
+// it is deprecated or an alias.
+func markSet(fs *flag.FlagSet, isSet map[string]bool, f *flag.Flag) {
+	isSet[f.Name] = true
+	if d, ok := Deprecated(f); ok && d.New != "" {
+		isSet[d.New] = true
+	} else if name, _ := aliasflag.Canonical(fs, f.Name); name != "" {
+		isSet[name] = true
+	}
+}
+
+// flagNames returns "flag -a" or "flags -a and -b" for the given names.
+func flagNames(names []string) string {
+	if len(names) == 1 {
+		return "flag -" + names[0]
+	}
+	return "flags " + joinNames(names, "and")
+}
+
+// joinNames returns the given flag names joined in a list with a conjunction,
+// for example "-a, -b, and -c" with "and".
+func joinNames(names []string, conj string) string {
+	dashed := make([]string, len(names))
+	for i, name := range names {
+		dashed[i] = "-" + name
+	}
+	switch len(dashed) {
+	case 1:
+		return dashed[0]
+	case 2:
+		return dashed[0] + " " + conj + " " + dashed[1]
+	}
+	return strings.Join(dashed[:len(dashed)-1], ", ") + ", " + conj + " " + dashed[len(dashed)-1]
+}
@@ -0,0 +1,128 @@
+package goflags
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/goflags/defaultflag"
+)
+
+func newGroupFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("group", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Bool("json", false, "JSON output")
+	fs.Bool("yaml", false, "YAML output")
+	fs.Bool("text", false, "Text output")
+	fs.String("tls-cert", "", "Certificate file")
+	fs.String("tls-key", "", "Key file")
+	fs.String("tls-ca", "", "CA file")
+	fs.Var(defaultflag.Wrap(new(listValue)), "user", "User")
+	fs.String("token", "", "Token")
+
+	Exclusive(fs, "json", "yaml", "text")
+	RequiresAll(fs, "tls-cert", "tls-key", "tls-ca")
+	AtLeastOne(fs, "user", "token")
+	return fs
+}
+
+func TestCheckGroups(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string // error messages, or nil for success
+	}{
+		{[]string{"-token", "x"}, nil},
+		{[]string{"-token", "x", "-json"}, nil},
+		{[]string{"-token", "x", "-tls-cert", "c", "-tls-key", "k", "-tls-ca", "a"}, nil},
+		{[]string{}, []string{"at least one of -user or -token is required"}},
+		{[]string{"-user", "x", "-json", "-text"}, []string{"flags -json and -text cannot be used together"}},
+		{[]string{"-user", "x", "-json", "-text", "-yaml"}, []string{"flags -json, -yaml, and -text cannot be used together"}},
+		{[]string{"-token", "x", "-tls-key", "k"}, []string{"flag -tls-key requires -tls-cert and -tls-ca"}},
+		{[]string{"-token", "x", "-tls-key", "k", "-tls-ca", "a"}, []string{"flags -tls-key and -tls-ca require -tls-cert"}},
+		{[]string{"-json", "-yaml", "-tls-cert", "c"}, []string{
+			"flags -json and -yaml cannot be used together",
+			"flag -tls-cert requires -tls-key and -tls-ca",
+			"at least one of -user or -token is required",
+		}},
+	}
+	for _, test := range tests {
+		fs := newGroupFlagSet()
+		if err := fs.Parse(test.args); err != nil {
+			t.Fatalf("Parse(%q) failed: %v", test.args, err)
+		}
+		err := CheckGroups(fs)
+		if test.want == nil {
+			if err != nil {
+				t.Errorf("CheckGroups(%q): unexpected error: %v", test.args, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("CheckGroups(%q): got nil, wanted error", test.args)
+			continue
+		}
+		got := strings.Split(err.Error(), "\n")
+		for i, msg := range got {
+			got[i] = strings.TrimPrefix(msg, "goflags: ")
+		}
+		if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+			t.Errorf("CheckGroups(%q):\ngot  %q\nwant %q", test.args, got, test.want)
+		}
+	}
+}
+
+func TestCheckGroupsSources(t *testing.T) {
+	fs := newGroupFlagSet()
+	if err := fs.Parse(nil); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if _, err := ApplyConfig(fs, Config{"user": {"alice"}}, "test.json"); err != nil {
+		t.Fatalf("ApplyConfig failed: %v", err)
+	}
+	if err := CheckGroups(fs); err != nil {
+		t.Errorf("CheckGroups with -user from config: unexpected error: %v", err)
+	}
+
+	other := flag.NewFlagSet("other", flag.ContinueOnError)
+	if err := CheckGroups(other); err != nil {
+		t.Errorf("CheckGroups without groups: unexpected error: %v", err)
+	}
+	Exclusive(other, "a", "b")
+	if err := CheckGroups(other); err == nil {
+		t.Error("CheckGroups with undefined flags: got nil, wanted error")
+	}
+}
+
+func TestCheckGroupsWith(t *testing.T) {
+	fs := newGroupFlagSet()
+	if err := fs.Parse([]string{"-token", "x", "-json"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if err := CheckGroupsWith(fs, "nonesuch"); err != nil {
+		t.Errorf("CheckGroupsWith undefined name: unexpected error: %v", err)
+	}
+	err := CheckGroupsWith(fs, "yaml")
+	if err == nil || !strings.Contains(err.Error(), "flags -json and -yaml cannot be used together") {
+		t.Errorf("CheckGroupsWith -yaml: got %v, want group error", err)
+	}
+}
+
+func TestForget(t *testing.T) {
+	fs := newGroupFlagSet()
+	if err := fs.Parse([]string{"-json", "-yaml"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if err := CheckGroups(fs); err == nil {
+		t.Fatal("CheckGroups: got nil, want error")
+	}
+	Forget(fs)
+	if err := CheckGroups(fs); err != nil {
+		t.Errorf("CheckGroups after Forget: unexpected error: %v", err)
+	}
+	groups.Lock()
+	defer groups.Unlock()
+	if _, ok := groups.m[fs]; ok {
+		t.Error("Forget did not release the flag set")
+	}
+}
@@ -0,0 +1,164 @@
+// Package hashflag defines a flag.Value implementation that selects a
+// cryptographic hash function by name.
+//
+// Example:
+//
+//	import (
+//	  "crypto"
+//	  _ "crypto/sha256"
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/hashflag"
+//	)
+//
+//	var digest = hashflag.Value{Hash: crypto.SHA256}
+//	func init() {
+//	  flag.Var(&digest, "hash", digest.Help("Hash algorithm"))
+//	}
+//
+//	  ...
+//	  h := digest.New()
+//
+// The names are those of the crypto.Hash constants, such as "sha256",
+// "sha3_256", and "blake2b_512", compared without regard to case, "-", or "_".
+// This package does not link any hash implementations itself: an algorithm
+// is accepted only if its implementation is linked into the binary, as by
+// importing crypto/sha256 or golang.org/x/crypto/blake2b.
+package hashflag
+
+import (
+	"crypto"
+	"fmt"
+	"hash"
+	"slices"
+	"strings"
+)
+
+// names maps canonical algorithm names to hash functions.
+var names = map[string]crypto.Hash{
+	"md4":         crypto.MD4,
+	"md5":         crypto.MD5,
+	"sha1":        crypto.SHA1,
+	"sha224":      crypto.SHA224,
+	"sha256":      crypto.SHA256,
+	"sha384":      crypto.SHA384,
+	"sha512":      crypto.SHA512,
+	"sha512_224":  crypto.SHA512_224,
+	"sha512_256":  crypto.SHA512_256,
+	"sha3_224":    crypto.SHA3_224,
+	"sha3_256":    crypto.SHA3_256,
+	"sha3_384":    crypto.SHA3_384,
+	"sha3_512":    crypto.SHA3_512,
+	"ripemd160":   crypto.RIPEMD160,
+	"blake2s_256": crypto.BLAKE2s_256,
+	"blake2b_256": crypto.BLAKE2b_256,
+	"blake2b_384": crypto.BLAKE2b_384,
+	"blake2b_512": crypto.BLAKE2b_512,
+}
+
+// aliases maps additional normalized names to hash functions.
+var aliases = map[string]crypto.Hash{
+	"blake2s": crypto.BLAKE2s_256,
+	"blake2b": crypto.BLAKE2b_512,
+}
+
+// Lookup returns the hash function with the given name, and reports whether
+// the name is known. It does not check whether the function is available.
+func Lookup(name string) (crypto.Hash, bool) {
+	key := normalize(name)
+	for n, h := range names {
+		if normalize(n) == key {
+			return h, true
+		}
+	}
+	h, ok := aliases[key]
+	return h, ok
+}
+
+// Name returns the canonical name of h, or "" if h is not known.
+func Name(h crypto.Hash) string {
+	for n, v := range names {
+		if v == h {
+			return n
+		}
+	}
+	return ""
+}
+
+// Available returns the canonical names of the hash functions that are
+// linked into the binary, in sorted order.
+func Available() []string {
+	var out []string
+	for n, h := range names {
+		if h.Available() {
+			out = append(out, n)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+func normalize(name string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(name))
+}
+
+// A Value represents a choice of hash function. A pointer to a Value
+// satisfies the flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The hash function selected by the flag. The zero value means none.
+	Hash crypto.Hash
+
+	// If non-empty, only these algorithms are accepted. Otherwise any
+	// available algorithm is accepted.
+	Allowed []string
+}
+
+// Help concatenates a human-readable string summarizing the accepted
+// algorithms of v to h, for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	names := v.Allowed
+	if len(names) == 0 {
+		names = Available()
+	}
+	return fmt.Sprintf("%s (%s)", h, strings.Join(names, "|"))
+}
+
+// New returns a new hash.Hash for the selected function. It panics if no
+// function has been selected.
+func (v *Value) New() hash.Hash { return v.Hash.New() }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return Name(v.Hash) }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	h, ok := Lookup(s)
+	if !ok {
+		return fmt.Errorf("hashflag: unknown algorithm %q", s)
+	}
+	if len(v.Allowed) != 0 && !slices.ContainsFunc(v.Allowed, func(a string) bool {
+		ah, ok := Lookup(a)
+		return ok && ah == h
+	}) {
+		return fmt.Errorf("hashflag: algorithm %q not allowed, expected one of (%s)",
+			s, strings.Join(v.Allowed, "|"))
+	}
+	if !h.Available() {
+		return fmt.Errorf("hashflag: algorithm %q is not linked into this binary", s)
+	}
+	v.Hash = h
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type func() hash.Hash, or is nil if no function has
+// been selected.
+func (v *Value) Get() any {
+	if v.Hash == 0 {
+		return nil
+	}
+	return v.Hash.New
+}
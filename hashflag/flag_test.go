@@ -0,0 +1,80 @@
+package hashflag
+
+import (
+	"crypto"
+	_ "crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"hash"
+	"slices"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var digest Value
+	fs := flag.NewFlagSet("hash", flag.ContinueOnError)
+	fs.Var(&digest, "hash", digest.Help("Hash algorithm"))
+
+	if got := digest.Get(); got != nil {
+		t.Errorf("Initial value for -hash: got %v, want nil", got)
+	}
+	if err := fs.Parse([]string{"-hash", "SHA-256"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if digest.Hash != crypto.SHA256 {
+		t.Errorf("Value for -hash: got %v, want SHA-256", digest.Hash)
+	}
+	if got, want := digest.String(), `"sha256"`; got != want {
+		t.Errorf("String for -hash: got %s, want %s", got, want)
+	}
+
+	h := digest.Get().(func() hash.Hash)()
+	h.Write([]byte("abc"))
+	const want = "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		t.Errorf("Digest: got %s, want %s", got, want)
+	}
+
+	if !slices.Contains(Available(), "sha256") || slices.Contains(Available(), "blake2b_512") {
+		t.Errorf("Available: got %q", Available())
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		v     Value
+		input string
+	}{
+		{Value{}, ""},
+		{Value{}, "crc32"},
+		{Value{}, "blake2b"}, // not linked
+		{Value{}, "md4"},     // not linked
+		{Value{Allowed: []string{"sha512"}}, "sha256"},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.input); err == nil {
+			t.Errorf("Set(%q) with %+v: got %v, wanted error", test.input, test.v, test.v.Hash)
+		} else {
+			t.Logf("Set(%q) gave expected error: %v", test.input, err)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name string
+		want crypto.Hash
+	}{
+		{"sha512_256", crypto.SHA512_256},
+		{"SHA512/256", 0},
+		{"sha3-256", crypto.SHA3_256},
+		{"BLAKE2b", crypto.BLAKE2b_512},
+		{"blake2b-256", crypto.BLAKE2b_256},
+	}
+	for _, test := range tests {
+		got, ok := Lookup(test.name)
+		if ok != (test.want != 0) || got != test.want {
+			t.Errorf("Lookup(%q): got %v, %v; want %v", test.name, got, ok, test.want)
+		}
+	}
+}
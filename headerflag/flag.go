@@ -0,0 +1,164 @@
+// Package headerflag defines a repeatable flag.Value implementation that
+// collects HTTP header fields into an http.Header.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/headerflag"
+//	)
+//
+//	var headers headerflag.Value
+//	func init() {
+//	  flag.Var(&headers, "H", headers.Help("Add a request header"))
+//	}
+//
+//	  ...
+//	  for name, vals := range headers.Header {
+//	    req.Header[name] = append(req.Header[name], vals...)
+//	  }
+//
+// With this definition "-H 'Accept: text/html' -H x-trace=1" adds the fields
+// "Accept" and "X-Trace" to the header. Names are canonicalized as by
+// http.CanonicalHeaderKey.
+package headerflag
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// A Policy determines how a field whose name was already set is handled.
+type Policy int
+
+const (
+	Append  Policy = iota // add the value to those already present (default)
+	Replace               // replace the values already present
+	Reject                // report an error
+)
+
+// sensitive lists the fields whose values are redacted by default.
+var sensitive = []string{
+	"Authorization",
+	"Cookie",
+	"Proxy-Authorization",
+	"Set-Cookie",
+	"X-Api-Key",
+	"X-Auth-Token",
+}
+
+// A Value represents a collection of HTTP header fields. A pointer to a Value
+// satisfies the flag.Value and flag.Getter interfaces. Each occurrence of the
+// flag adds one field, given as "Name: value" or "Name=value".
+type Value struct {
+	// The fields parsed from the flag, or nil if none have been set.
+	Header http.Header
+
+	// How a repeated field name is handled.
+	Duplicates Policy
+
+	// Additional field names whose values are redacted by String. The values
+	// of Authorization, Cookie, and other credential-bearing fields are
+	// always redacted.
+	Sensitive []string
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string { return h + ` ("Name: value"; repeatable)` }
+
+// String satisfies part of the flag.Value interface.
+// The values of sensitive fields are replaced with "[redacted]".
+func (v *Value) String() string { return fmt.Sprintf("%q", strings.Join(v.ArgStrings(), "\n")) }
+
+// ArgStrings returns the fields of v as "Name: value" strings, sorted by name,
+// each of which is a valid argument to Set. Sensitive values are redacted, as
+// by String.
+func (v *Value) ArgStrings() []string {
+	names := make([]string, 0, len(v.Header))
+	for name := range v.Header {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var parts []string
+	for _, name := range names {
+		for _, val := range v.Header[name] {
+			if v.isSensitive(name) {
+				val = "[redacted]"
+			}
+			parts = append(parts, name+": "+val)
+		}
+	}
+	return parts
+}
+
+// Reset discards all the fields of v.
+func (v *Value) Reset() { v.Header = nil }
+
+// Snapshot satisfies the goflags.Snapshotter interface. It records the
+// current fields, whose sensitive values String does not report, and returns
+// a function that restores them.
+func (v *Value) Snapshot() func() {
+	old := v.Header.Clone()
+	return func() { v.Header = old }
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	i := strings.IndexAny(s, ":=")
+	if i < 0 {
+		return fmt.Errorf("headerflag: invalid header %q, want \"Name: value\"", s)
+	}
+	name, val := strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:])
+	if !validName(name) {
+		return fmt.Errorf("headerflag: invalid field name %q", name)
+	}
+	if strings.ContainsAny(val, "\r\n\x00") {
+		return fmt.Errorf("headerflag: invalid value for %q", name)
+	}
+	key := http.CanonicalHeaderKey(name)
+	if v.Header == nil {
+		v.Header = make(http.Header)
+	}
+	if _, ok := v.Header[key]; ok {
+		switch v.Duplicates {
+		case Replace:
+			v.Header.Set(key, val)
+			return nil
+		case Reject:
+			return fmt.Errorf("headerflag: duplicate field %q", key)
+		}
+	}
+	v.Header.Add(key, val)
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type http.Header.
+func (v *Value) Get() any { return v.Header }
+
+func (v *Value) isSensitive(name string) bool {
+	match := func(s string) bool { return strings.EqualFold(s, name) }
+	return slices.ContainsFunc(sensitive, match) || slices.ContainsFunc(v.Sensitive, match)
+}
+
+// validName reports whether name is a valid field name token (RFC 9110).
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+package headerflag
+
+import (
+	"flag"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var hdr Value
+	fs := flag.NewFlagSet("header", flag.ContinueOnError)
+	fs.Var(&hdr, "H", hdr.Help("Request header"))
+
+	if err := fs.Parse([]string{
+		"-H", "Accept: text/html",
+		"-H", "x-trace=1",
+		"-H", "accept:application/json",
+		"-H", "Authorization: Bearer s3kr1t",
+		"-H", "X-Empty:",
+	}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	h := hdr.Get().(http.Header)
+	if got, want := h.Values("Accept"), []string{"text/html", "application/json"}; !slices.Equal(got, want) {
+		t.Errorf("Accept: got %q, want %q", got, want)
+	}
+	if got, want := h.Get("X-Trace"), "1"; got != want {
+		t.Errorf("X-Trace: got %q, want %q", got, want)
+	}
+	if vals, ok := h["X-Empty"]; !ok || len(vals) != 1 || vals[0] != "" {
+		t.Errorf("X-Empty: got %q, %v; want one empty value", vals, ok)
+	}
+
+	s := hdr.String()
+	if strings.Contains(s, "s3kr1t") {
+		t.Errorf("String leaks credential: %s", s)
+	}
+	if !strings.Contains(s, "Authorization: [redacted]") || !strings.Contains(s, "X-Trace: 1") {
+		t.Errorf("String: got %s, missing expected fields", s)
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	rep := Value{Duplicates: Replace}
+	for _, s := range []string{"A: 1", "a: 2"} {
+		if err := rep.Set(s); err != nil {
+			t.Fatalf("Set(%q): unexpected error: %v", s, err)
+		}
+	}
+	if got := rep.Header.Values("A"); !slices.Equal(got, []string{"2"}) {
+		t.Errorf("Replace: got %q, want [2]", got)
+	}
+
+	rej := Value{Duplicates: Reject}
+	if err := rej.Set("A: 1"); err != nil {
+		t.Fatalf("Set: unexpected error: %v", err)
+	}
+	if err := rej.Set("a=2"); err == nil {
+		t.Error("Reject: duplicate field was accepted")
+	}
+}
+
+func TestSensitive(t *testing.T) {
+	v := Value{Sensitive: []string{"x-secret"}}
+	v.Set("X-Secret: hunter2")
+	if s := v.String(); strings.Contains(s, "hunter2") {
+		t.Errorf("String leaks X-Secret: %s", s)
+	}
+	if args := v.ArgStrings(); slices.ContainsFunc(args, func(s string) bool { return strings.Contains(s, "hunter2") }) {
+		t.Errorf("ArgStrings leaks X-Secret: %q", args)
+	}
+}
+
+func TestArgStrings(t *testing.T) {
+	var v Value
+	for _, s := range []string{"x-b: 2", "X-A: 1", "X-B: 3"} {
+		if err := v.Set(s); err != nil {
+			t.Fatalf("Set(%q) failed: %v", s, err)
+		}
+	}
+	want := []string{"X-A: 1", "X-B: 2", "X-B: 3"}
+	if got := v.ArgStrings(); !slices.Equal(got, want) {
+		t.Fatalf("ArgStrings: got %q, want %q", got, want)
+	}
+	var cp Value
+	for _, s := range want {
+		if err := cp.Set(s); err != nil {
+			t.Errorf("Set(%q) failed: %v", s, err)
+		}
+	}
+	if got, want := cp.String(), v.String(); got != want {
+		t.Errorf("After round trip: got %s, want %s", got, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, bad := range []string{"", "NoSeparator", ": value", "Bad Name: x", "A: b\r\nInjected: c"} {
+		var v Value
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %v, wanted error", bad, v.Header)
+		}
+	}
+}
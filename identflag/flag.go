@@ -0,0 +1,143 @@
+// Package identflag defines a flag.Value implementation for identifiers that
+// must satisfy naming rules imposed by some downstream system.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/identflag"
+//	)
+//
+//	var name = identflag.Value{Rule: identflag.DNSLabel}
+//	func init() {
+//	  flag.Var(&name, "name", name.Help("Name of the new service"))
+//	}
+//
+// The constraints are checked when the flag is set, so that an invalid name
+// is reported before any work is done. Besides the predefined rules, callers
+// may define a Rule with their own regular expression.
+package identflag
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+// A Rule is a naming rule.
+type Rule struct {
+	Name    string         // a short description, e.g., "DNS label"
+	Pattern *regexp.Regexp // the pattern an identifier must match in full
+	MaxLen  int            // if positive, the maximum length in bytes
+}
+
+// Predefined rules.
+var (
+	// DNSLabel is an RFC 1123 DNS label: lower-case letters, digits, and "-",
+	// beginning and ending with a letter or digit, at most 63 bytes.
+	DNSLabel = Rule{
+		Name:    "DNS label",
+		Pattern: regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`),
+		MaxLen:  63,
+	}
+
+	// DNSSubdomain is an RFC 1123 subdomain: one or more DNS labels separated
+	// by ".", at most 253 bytes.
+	DNSSubdomain = Rule{
+		Name:    "DNS subdomain",
+		Pattern: regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`),
+		MaxLen:  253,
+	}
+
+	// CIdent is an identifier in the C language: an ASCII letter or "_",
+	// followed by letters, digits, and "_".
+	CIdent = Rule{
+		Name:    "C identifier",
+		Pattern: regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`),
+	}
+)
+
+// NewRule returns a Rule with the given name, that requires identifiers to
+// match the regular expression expr in full. It panics if expr is invalid.
+func NewRule(name, expr string, maxLen int) Rule {
+	return Rule{
+		Name:    name,
+		Pattern: regexp.MustCompile(`^(?:` + expr + `)$`),
+		MaxLen:  maxLen,
+	}
+}
+
+// Check reports an error if s does not satisfy r.
+func (r Rule) Check(s string) error {
+	if r.MaxLen > 0 && len(s) > r.MaxLen {
+		return fmt.Errorf("identflag: %q is longer than %d bytes", s, r.MaxLen)
+	}
+	if r.Pattern != nil && !r.Pattern.MatchString(s) {
+		return fmt.Errorf("identflag: %q is not a valid %s", s, r.Name)
+	}
+	return nil
+}
+
+// A Value represents an identifier. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces. The zero value accepts any non-empty
+// string.
+type Value struct {
+	// The identifier parsed from the flag.
+	Ident string
+
+	// The rule the identifier must satisfy.
+	Rule Rule
+
+	// If positive, bounds on the length of the identifier in characters,
+	// in addition to those of the rule.
+	MinLen, MaxLen int
+}
+
+// Help concatenates a human-readable string summarizing the constraints of v
+// to h, for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	desc := v.Rule.Name
+	if desc == "" {
+		desc = "identifier"
+	}
+	switch {
+	case v.MinLen > 0 && v.MaxLen > 0:
+		desc += fmt.Sprintf(", %d to %d characters", v.MinLen, v.MaxLen)
+	case v.MinLen > 0:
+		desc += fmt.Sprintf(", at least %d characters", v.MinLen)
+	case v.MaxLen > 0:
+		desc += fmt.Sprintf(", at most %d characters", v.MaxLen)
+	}
+	return fmt.Sprintf("%s (%s)", h, desc)
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return v.Ident }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	if s == "" {
+		return errors.New("identflag: empty identifier")
+	}
+	n := utf8.RuneCountInString(s)
+	if v.MinLen > 0 && n < v.MinLen {
+		return fmt.Errorf("identflag: %q is shorter than %d characters", s, v.MinLen)
+	}
+	if v.MaxLen > 0 && n > v.MaxLen {
+		return fmt.Errorf("identflag: %q is longer than %d characters", s, v.MaxLen)
+	}
+	if err := v.Rule.Check(s); err != nil {
+		return err
+	}
+	v.Ident = s
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is the string of the identifier.
+func (v *Value) Get() any { return v.Ident }
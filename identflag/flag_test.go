@@ -0,0 +1,80 @@
+package identflag
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	name := Value{Rule: DNSLabel}
+	sym := Value{Rule: CIdent, MaxLen: 8}
+
+	fs := flag.NewFlagSet("ident", flag.ContinueOnError)
+	fs.Var(&name, "name", name.Help("Service name"))
+	fs.Var(&sym, "sym", sym.Help("Symbol"))
+
+	if got, want := sym.Help("S"), "S (C identifier, at most 8 characters)"; got != want {
+		t.Errorf("Help: got %q, want %q", got, want)
+	}
+	if err := fs.Parse([]string{"-name", "web-01", "-sym", "_init"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got := name.Get().(string); got != "web-01" {
+		t.Errorf("Value for -name: got %q, want web-01", got)
+	}
+	if got, want := sym.String(), `"_init"`; got != want {
+		t.Errorf("Value for -sym: got %s, want %s", got, want)
+	}
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		rule  Rule
+		input string
+		ok    bool
+	}{
+		{DNSLabel, "a", true},
+		{DNSLabel, "my-app-2", true},
+		{DNSLabel, "My-App", false},
+		{DNSLabel, "-app", false},
+		{DNSLabel, "app-", false},
+		{DNSLabel, "a.b", false},
+		{DNSLabel, strings.Repeat("a", 63), true},
+		{DNSLabel, strings.Repeat("a", 64), false},
+		{DNSSubdomain, "api.example.com", true},
+		{DNSSubdomain, "api..example", false},
+		{DNSSubdomain, "api.example.", false},
+		{CIdent, "x1", true},
+		{CIdent, "1x", false},
+		{CIdent, "a-b", false},
+		{NewRule("ticket", `[A-Z]+-[0-9]+`, 0), "PROJ-123", true},
+		{NewRule("ticket", `[A-Z]+-[0-9]+`, 0), "PROJ-123x", false},
+		{NewRule("ticket", `[A-Z]+-[0-9]+`, 6), "PROJ-123", false},
+	}
+	for _, test := range tests {
+		err := test.rule.Check(test.input)
+		if test.ok && err != nil {
+			t.Errorf("%s.Check(%q): unexpected error: %v", test.rule.Name, test.input, err)
+		} else if !test.ok && err == nil {
+			t.Errorf("%s.Check(%q): got nil, wanted error", test.rule.Name, test.input)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		v     Value
+		input string
+	}{
+		{Value{}, ""},
+		{Value{MinLen: 3}, "ab"},
+		{Value{MaxLen: 2}, "abc"},
+		{Value{Rule: CIdent}, "not valid"},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.input); err == nil {
+			t.Errorf("Set(%q) with %+v: got %q, wanted error", test.input, test.v, test.v.Ident)
+		}
+	}
+}
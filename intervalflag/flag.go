@@ -0,0 +1,183 @@
+// Package intervalflag defines a flag.Value implementation for numeric
+// intervals written in mathematical interval notation.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/intervalflag"
+//	)
+//
+//	var window = intervalflag.Value{Interval: intervalflag.MustParse("[0,100)")}
+//	func init() {
+//	  flag.Var(&window, "accept", window.Help("Accepted range of values"))
+//	}
+//
+//	  ...
+//	  if !window.Interval.Contains(x) { ... }
+//
+// A square bracket denotes a closed bound, which includes its endpoint, and a
+// parenthesis an open bound, which does not. An endpoint may be "-inf" or
+// "inf" (also "+inf"), which must be open: "(-inf, 5]".
+package intervalflag
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// An Interval is a range of real numbers. The zero value is the closed
+// interval [0, 0].
+type Interval struct {
+	Lo, Hi         float64 // the endpoints; Lo may be -Inf and Hi may be +Inf
+	LoOpen, HiOpen bool    // whether the corresponding endpoint is excluded
+}
+
+// Parse parses an interval in the notation described by the package comment.
+// The interval must not be empty.
+func Parse(s string) (Interval, error) {
+	t := strings.TrimSpace(s)
+	if len(t) < 2 {
+		return Interval{}, fmt.Errorf("intervalflag: invalid interval %q", s)
+	}
+	var iv Interval
+	switch t[0] {
+	case '[':
+	case '(':
+		iv.LoOpen = true
+	default:
+		return Interval{}, fmt.Errorf("intervalflag: interval %q must begin with [ or (", s)
+	}
+	switch t[len(t)-1] {
+	case ']':
+	case ')':
+		iv.HiOpen = true
+	default:
+		return Interval{}, fmt.Errorf("intervalflag: interval %q must end with ] or )", s)
+	}
+	los, his, ok := strings.Cut(t[1:len(t)-1], ",")
+	if !ok {
+		return Interval{}, fmt.Errorf("intervalflag: missing comma in %q", s)
+	}
+	var err error
+	if iv.Lo, err = parseBound(los); err != nil {
+		return Interval{}, err
+	}
+	if iv.Hi, err = parseBound(his); err != nil {
+		return Interval{}, err
+	}
+	switch {
+	case math.IsInf(iv.Lo, 1) || math.IsInf(iv.Hi, -1):
+		return Interval{}, fmt.Errorf("intervalflag: infinite bound in wrong position in %q", s)
+	case math.IsInf(iv.Lo, -1) && !iv.LoOpen, math.IsInf(iv.Hi, 1) && !iv.HiOpen:
+		return Interval{}, fmt.Errorf("intervalflag: infinite bound must be open in %q", s)
+	case iv.Lo > iv.Hi:
+		return Interval{}, fmt.Errorf("intervalflag: lower bound exceeds upper bound in %q", s)
+	case iv.Lo == iv.Hi && (iv.LoOpen || iv.HiOpen):
+		return Interval{}, fmt.Errorf("intervalflag: interval %q is empty", s)
+	}
+	return iv, nil
+}
+
+// MustParse parses an interval, and panics if it is invalid. It is intended
+// for use in defining default values.
+func MustParse(s string) Interval {
+	iv, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return iv
+}
+
+func parseBound(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "-inf", "-∞":
+		return math.Inf(-1), nil
+	case "inf", "+inf", "∞", "+∞":
+		return math.Inf(1), nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("intervalflag: invalid bound %q", s)
+	}
+	return f, nil
+}
+
+// Contains reports whether x lies within iv.
+func (iv Interval) Contains(x float64) bool {
+	if x < iv.Lo || (iv.LoOpen && x == iv.Lo) {
+		return false
+	}
+	if x > iv.Hi || (iv.HiOpen && x == iv.Hi) {
+		return false
+	}
+	return !math.IsNaN(x)
+}
+
+// Check reports an error if x does not lie within iv.
+func (iv Interval) Check(x float64) error {
+	if !iv.Contains(x) {
+		return fmt.Errorf("value %v is not in %s", x, iv)
+	}
+	return nil
+}
+
+// IsBounded reports whether both endpoints of iv are finite.
+func (iv Interval) IsBounded() bool { return !math.IsInf(iv.Lo, 0) && !math.IsInf(iv.Hi, 0) }
+
+// String returns iv in the notation accepted by Parse.
+func (iv Interval) String() string {
+	open, close := "[", "]"
+	if iv.LoOpen {
+		open = "("
+	}
+	if iv.HiOpen {
+		close = ")"
+	}
+	return open + formatBound(iv.Lo) + "," + formatBound(iv.Hi) + close
+}
+
+func formatBound(f float64) string {
+	switch {
+	case math.IsInf(f, -1):
+		return "-inf"
+	case math.IsInf(f, 1):
+		return "inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// A Value represents an interval. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The interval parsed from the flag.
+	Interval Interval
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string { return h + " (interval, e.g., [0,100) or (-inf,5])" }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return v.Interval.String() }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	iv, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	v.Interval = iv
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Interval.
+func (v *Value) Get() any { return v.Interval }
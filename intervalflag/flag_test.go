@@ -0,0 +1,68 @@
+package intervalflag
+
+import (
+	"flag"
+	"math"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	window := Value{Interval: MustParse("[0,100)")}
+	fs := flag.NewFlagSet("interval", flag.ContinueOnError)
+	fs.Var(&window, "accept", window.Help("Accepted range"))
+
+	if got, want := window.String(), `"[0,100)"`; got != want {
+		t.Errorf("Initial -accept: got %s, want %s", got, want)
+	}
+	if err := fs.Parse([]string{"-accept", "(-inf, 5]"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	want := Interval{Lo: math.Inf(-1), Hi: 5, LoOpen: true}
+	if got := window.Get().(Interval); got != want {
+		t.Errorf("Value for -accept: got %+v, want %+v", got, want)
+	}
+	if window.Interval.IsBounded() {
+		t.Error("IsBounded: got true, want false")
+	}
+	if err := window.Interval.Check(6); err == nil {
+		t.Error("Check(6): got nil, want error")
+	}
+}
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		iv   string
+		x    float64
+		want bool
+	}{
+		{"[0,100)", 0, true},
+		{"[0,100)", 99.9, true},
+		{"[0,100)", 100, false},
+		{"(0,100]", 0, false},
+		{"(0,100]", 100, true},
+		{"[5,5]", 5, true},
+		{"(-inf,inf)", -1e300, true},
+		{"(-inf,inf)", math.NaN(), false},
+		{"[1e3, 1e4]", 1500, true},
+	}
+	for _, test := range tests {
+		iv := MustParse(test.iv)
+		if got := iv.Contains(test.x); got != test.want {
+			t.Errorf("%s.Contains(%v): got %v, want %v", test.iv, test.x, got, test.want)
+		}
+		if rt := MustParse(iv.String()); rt != iv {
+			t.Errorf("Parse(%q) did not round-trip: got %v", iv.String(), rt)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, bad := range []string{
+		"", "[]", "0,1", "[0,1", "0,1]", "[0;1]", "[x,1]", "[1,0]", "(1,1]", "[1,1)",
+		"[-inf,0]", "[0,inf]", "(inf,0)", "(0,-inf)", "[NaN,1]", "[0,1,2]",
+	} {
+		if iv, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q): got %v, wanted error", bad, iv)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+// Package jsonpathflag defines a flag.Value implementation for simple path
+// expressions that extract values from JSON documents.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/jsonpathflag"
+//	)
+//
+//	var sel jsonpathflag.Value
+//	func init() {
+//	  flag.Var(&sel, "select", sel.Help("Values to print"))
+//	}
+//
+//	  ...
+//	  var doc any
+//	  json.Unmarshal(data, &doc)
+//	  for _, v := range sel.Path.Eval(doc) {
+//	    fmt.Println(v)
+//	  }
+//
+// The syntax is a common subset of JSONPath and jq. A path may begin with "$"
+// or "." and is followed by steps:
+//
+//	.name      the field "name" of an object
+//	["name"]   the same, for names that are not identifiers ('name' also works)
+//	[2]        the element at offset 2 of an array (negative counts from the end)
+//	[] or [*]  all elements of an array (or all fields of an object)
+//	.*         the same as [*]
+//
+// For example, "$.items[0].name" and ".items[].name" are both valid. Syntax
+// errors are reported when the flag is parsed.
+package jsonpathflag
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A Step is a single step of a Path.
+type Step struct {
+	Field string // if non-empty, select this object field
+	Index int    // otherwise, if All is false, select this array offset
+	All   bool   // if true, select all elements or fields
+}
+
+// String renders s in the syntax accepted by Parse.
+func (s Step) String() string {
+	switch {
+	case s.All:
+		return "[*]"
+	case s.Field != "":
+		if isIdent(s.Field) {
+			return "." + s.Field
+		}
+		return "[" + strconv.Quote(s.Field) + "]"
+	}
+	return "[" + strconv.Itoa(s.Index) + "]"
+}
+
+// A Path is a parsed path expression. The zero value selects the whole
+// document.
+type Path struct {
+	Steps []Step
+}
+
+// Parse parses a path expression.
+func Parse(s string) (Path, error) {
+	var p Path
+	if s == "" {
+		return p, errors.New("jsonpathflag: empty path")
+	}
+	if s == "." {
+		return p, nil // jq identity
+	}
+	rest := strings.TrimPrefix(s, "$")
+	for rest != "" {
+		step, next, err := parseStep(rest)
+		if err != nil {
+			return Path{}, fmt.Errorf("jsonpathflag: invalid path %q: %w", s, err)
+		}
+		p.Steps = append(p.Steps, step)
+		rest = next
+	}
+	return p, nil
+}
+
+// MustParse parses a path, and panics if it is invalid. It is intended for
+// use in defining default values.
+func MustParse(s string) Path {
+	p, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func parseStep(s string) (Step, string, error) {
+	switch s[0] {
+	case '.':
+		s = s[1:]
+		if strings.HasPrefix(s, "[") {
+			return parseStep(s) // jq allows ".[0]"
+		} else if rest, ok := strings.CutPrefix(s, "*"); ok {
+			return Step{All: true}, rest, nil
+		}
+		i := 0
+		for i < len(s) && isIdentByte(s[i], i) {
+			i++
+		}
+		if i == 0 {
+			return Step{}, "", errors.New("missing field name after '.'")
+		}
+		return Step{Field: s[:i]}, s[i:], nil
+
+	case '[':
+		end := strings.IndexByte(s, ']')
+		if end < 0 {
+			return Step{}, "", errors.New("unclosed '['")
+		}
+		inner := s[1:end]
+		switch {
+		case inner == "" || inner == "*":
+			return Step{All: true}, s[end+1:], nil
+		case inner[0] == '"' || inner[0] == '\'':
+			return parseQuoted(s)
+		}
+		n, err := strconv.Atoi(inner)
+		if err != nil {
+			return Step{}, "", fmt.Errorf("invalid index %q", inner)
+		}
+		return Step{Index: n}, s[end+1:], nil
+	}
+	return Step{}, "", fmt.Errorf("unexpected %q", s[0])
+}
+
+// parseQuoted parses a quoted field name in brackets at the start of s.
+func parseQuoted(s string) (Step, string, error) {
+	q := s[1]
+	for i := 2; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case q:
+			lit := s[1 : i+1]
+			if q == '\'' {
+				lit = strconv.Quote(strings.ReplaceAll(lit[1:len(lit)-1], `\'`, `'`))
+			}
+			name, err := strconv.Unquote(lit)
+			if err != nil {
+				return Step{}, "", fmt.Errorf("invalid quoted name %s", s[1:i+1])
+			}
+			if name == "" {
+				return Step{}, "", errors.New("empty field name")
+			}
+			rest, ok := strings.CutPrefix(s[i+1:], "]")
+			if !ok {
+				return Step{}, "", errors.New("missing ']' after quoted name")
+			}
+			return Step{Field: name}, rest, nil
+		}
+	}
+	return Step{}, "", errors.New("unterminated quoted name")
+}
+
+func isIdentByte(c byte, i int) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || i > 0 && '0' <= c && c <= '9'
+}
+
+func isIdent(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isIdentByte(s[i], i) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// String renders p in the syntax accepted by Parse, beginning with "$".
+func (p Path) String() string {
+	var sb strings.Builder
+	sb.WriteString("$")
+	for _, s := range p.Steps {
+		sb.WriteString(s.String())
+	}
+	return sb.String()
+}
+
+// Eval returns the values selected by p in doc, which should have the form
+// produced by decoding JSON into an any: a map[string]any, []any, or scalar.
+// Steps that do not apply, such as a missing field or an out-of-range index,
+// select nothing. Fields of an object selected by a wildcard are visited in
+// order of their names.
+func (p Path) Eval(doc any) []any {
+	cur := []any{doc}
+	for _, s := range p.Steps {
+		var next []any
+		for _, v := range cur {
+			next = s.apply(v, next)
+		}
+		cur = next
+	}
+	return cur
+}
+
+// First returns the first value selected by p in doc, and reports whether
+// there was one.
+func (p Path) First(doc any) (any, bool) {
+	vs := p.Eval(doc)
+	if len(vs) == 0 {
+		return nil, false
+	}
+	return vs[0], true
+}
+
+func (s Step) apply(v any, out []any) []any {
+	switch t := v.(type) {
+	case map[string]any:
+		if s.All {
+			for _, k := range slices.Sorted(maps.Keys(t)) {
+				out = append(out, t[k])
+			}
+		} else if s.Field != "" {
+			if e, ok := t[s.Field]; ok {
+				out = append(out, e)
+			}
+		}
+	case []any:
+		if s.All {
+			out = append(out, t...)
+		} else if s.Field == "" {
+			i := s.Index
+			if i < 0 {
+				i += len(t)
+			}
+			if i >= 0 && i < len(t) {
+				out = append(out, t[i])
+			}
+		}
+	}
+	return out
+}
+
+// A Value represents a path expression. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces.
+type Value struct {
+	Path Path
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string { return h + " (path, e.g., $.items[0].name)" }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return v.Path.String() }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	p, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	v.Path = p
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Path.
+func (v *Value) Get() any { return v.Path }
@@ -0,0 +1,88 @@
+package jsonpathflag
+
+import (
+	"encoding/json"
+	"flag"
+	"reflect"
+	"testing"
+)
+
+const testDoc = `{
+  "items": [
+    {"name": "alpha", "tags": ["x", "y"]},
+    {"name": "beta", "tags": []},
+    {"name": "gamma", "tags": ["z"]}
+  ],
+  "meta": {"total": 3, "odd key": true}
+}`
+
+func TestFlagBits(t *testing.T) {
+	var sel Value
+	fs := flag.NewFlagSet("jsonpath", flag.ContinueOnError)
+	fs.Var(&sel, "select", sel.Help("Selection"))
+
+	if err := fs.Parse([]string{"-select", ".items[].name"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := sel.String(), `"$.items[*].name"`; got != want {
+		t.Errorf("Value for -select: got %s, want %s", got, want)
+	}
+	if got := len(sel.Get().(Path).Steps); got != 3 {
+		t.Errorf("Steps for -select: got %d, want 3", got)
+	}
+}
+
+func TestEval(t *testing.T) {
+	var doc any
+	if err := json.Unmarshal([]byte(testDoc), &doc); err != nil {
+		t.Fatalf("Decoding test document: %v", err)
+	}
+	tests := []struct {
+		path string
+		want []any
+	}{
+		{"$.items[0].name", []any{"alpha"}},
+		{".items[].name", []any{"alpha", "beta", "gamma"}},
+		{"$.items[-1].name", []any{"gamma"}},
+		{"$.items[*].tags[*]", []any{"x", "y", "z"}},
+		{".items[5].name", nil},
+		{".meta.total", []any{3.0}},
+		{`$.meta["odd key"]`, []any{true}},
+		{`.meta['odd key']`, []any{true}},
+		{".meta.*", []any{true, 3.0}},
+		{".missing.field", nil},
+		{".items.name", nil},
+		{".[0]", nil},
+		{"$", []any{doc}},
+		{".", []any{doc}},
+	}
+	for _, test := range tests {
+		p, err := Parse(test.path)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", test.path, err)
+			continue
+		}
+		if got := p.Eval(doc); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Eval(%q): got %v, want %v", test.path, got, test.want)
+		}
+		// The canonical form must parse to the same path.
+		if q, err := Parse(p.String()); err != nil || !reflect.DeepEqual(q, p) {
+			t.Errorf("Parse(%q): got %+v, %v; want %+v", p.String(), q, err, p)
+		}
+	}
+
+	if v, ok := MustParse(".items[1].name").First(doc); !ok || v != "beta" {
+		t.Errorf("First: got %v, %v; want beta, true", v, ok)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, input := range []string{
+		"", "items", "$.", "..a", ".items[", ".items[x]", ".a[]]",
+		`.a["b]`, `.a["b"`, `.a[""]`, ".1abc", "$$",
+	} {
+		if p, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q): got %v, wanted error", input, p)
+		}
+	}
+}
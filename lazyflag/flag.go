@@ -0,0 +1,185 @@
+// Package lazyflag defines a flag.Value wrapper that defers parsing and
+// validation of a flag until its value is needed.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/fileflag"
+//	  "github.com/creachadair/goflags/lazyflag"
+//	)
+//
+//	var token = lazyflag.Wrap(&fileflag.Contents{MaxSize: 4096})
+//	func init() {
+//	  flag.Var(token, "token", "Access token (literal or @file)")
+//	}
+//
+//	  ...
+//	  if needAuth {
+//	    if err := token.Resolve(); err != nil {
+//	      log.Fatalf("Invalid -token: %v", err)
+//	    }
+//	  }
+//
+// With this definition, the argument of "-token @path" is stored as given
+// when the flags are parsed, and the file is read only when the program calls
+// Resolve or Get. This is useful for validations that are expensive, such as
+// network lookups or file reads, for flags a program may not use.
+package lazyflag
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/creachadair/goflags"
+)
+
+// A Value wraps a flag.Value and defers calls to its Set method. A *Value
+// satisfies the flag.Value and flag.Getter interfaces.
+type Value struct {
+	mu       sync.Mutex
+	v        flag.Value
+	raw      string   // the argument most recently given to Set
+	pending  []string // arguments not yet passed to v.Set, in order
+	resolved bool     // v.Set has been called with the pending arguments
+	err      error
+}
+
+// Wrap returns a *Value that wraps v. Until a flag is set, the current value
+// of v is its default.
+func Wrap(v flag.Value) *Value { return &Value{v: v} }
+
+// Raw returns the unparsed argument most recently given to Set, and reports
+// whether Set has been called.
+func (v *Value) Raw() (string, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.raw, len(v.pending) != 0 || v.resolved
+}
+
+// Resolve parses the pending arguments, if any, with the wrapped value, in the
+// order they were given to Set, and reports the first error. This preserves
+// every occurrence of a repeated flag whose wrapped value accumulates them,
+// such as a *multiflag.Value. The arguments are parsed only once; later calls
+// report the same result.
+func (v *Value) Resolve() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.pending) != 0 {
+		args := v.pending
+		v.pending, v.resolved = nil, true
+		for _, arg := range args {
+			if err := v.v.Set(arg); err != nil {
+				v.err = fmt.Errorf("lazyflag: invalid value %q: %w", arg, err)
+				break
+			}
+		}
+	}
+	return v.err
+}
+
+// Resolved resolves any pending argument and returns the wrapped value, with
+// the error reported by Resolve.
+func (v *Value) Resolved() (flag.Value, error) {
+	err := v.Resolve()
+	return v.v, err
+}
+
+// Unwrap returns the wrapped value, without resolving a pending argument.
+func (v *Value) Unwrap() flag.Value { return v.v }
+
+// String satisfies part of the flag.Value interface. If arguments are
+// pending, String reports the most recent without resolving it, quoted if the
+// wrapped value is a goflags.ArgStringer.
+func (v *Value) String() string {
+	if v == nil || v.v == nil {
+		return "" // the zero value, as used by flag.PrintDefaults
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.pending) != 0 {
+		if _, ok := v.v.(goflags.ArgStringer); ok {
+			return strconv.Quote(v.raw)
+		}
+		return v.raw
+	}
+	return v.v.String()
+}
+
+// ArgString satisfies the goflags.ArgStringer interface. If arguments are
+// pending, ArgString reports the most recent without resolving it.
+func (v *Value) ArgString() string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.pending) != 0 {
+		return v.raw
+	}
+	return goflags.ArgString(v.v)
+}
+
+// Set satisfies part of the flag.Value interface. It records s to be parsed
+// by Resolve, after any other pending arguments, and never reports an error.
+func (v *Value) Set(s string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.raw, v.pending, v.err = s, append(v.pending, s), nil
+	return nil
+}
+
+// Get satisfies the flag.Getter interface. It resolves any pending argument,
+// and returns the result of the Get method of the wrapped value, or the
+// wrapped value itself if it is not a flag.Getter. If the argument is invalid,
+// Get returns the error instead.
+func (v *Value) Get() any {
+	if err := v.Resolve(); err != nil {
+		return err
+	}
+	if g, ok := v.v.(flag.Getter); ok {
+		return g.Get()
+	}
+	return v.v
+}
+
+// IsBoolFlag reports whether the wrapped value is a boolean flag.
+func (v *Value) IsBoolFlag() bool {
+	b, ok := v.v.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// ResolveAll resolves every flag in fs whose value is, or wraps, a *Value
+// with a pending argument, and returns the first error reported, if any. This
+// includes flags set other than by fs.Parse, such as from a configuration
+// file, which are not marked as set in fs. This is useful for validating all
+// deferred flags at a point of the program's choosing.
+func ResolveAll(fs *flag.FlagSet) error {
+	var first error
+	fs.VisitAll(func(f *flag.Flag) {
+		v, ok := findValue(f.Value)
+		if !ok {
+			return
+		}
+		if err := v.Resolve(); err != nil && first == nil {
+			first = fmt.Errorf("flag -%s: %w", f.Name, err)
+		}
+	})
+	return first
+}
+
+// findValue returns the *Value that is fv or is wrapped by it, as reported by
+// the Unwrap methods of fv and the values it wraps.
+func findValue(fv flag.Value) (*Value, bool) {
+	for fv != nil {
+		if v, ok := fv.(*Value); ok {
+			return v, true
+		}
+		u, ok := fv.(interface{ Unwrap() flag.Value })
+		if !ok {
+			break
+		}
+		fv = u.Unwrap()
+	}
+	return nil, false
+}
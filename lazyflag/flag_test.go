@@ -0,0 +1,147 @@
+package lazyflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"testing"
+
+	"github.com/creachadair/goflags"
+	"github.com/creachadair/goflags/multiflag"
+)
+
+// countValue is a flag.Getter that counts calls to Set.
+type countValue struct {
+	s    string
+	sets int
+}
+
+func (c *countValue) String() string { return c.s }
+func (c *countValue) Get() any       { return c.s }
+func (c *countValue) Set(s string) error {
+	c.sets++
+	if s == "bad" {
+		return errors.New("bad value")
+	}
+	c.s = s
+	return nil
+}
+
+func TestFlagBits(t *testing.T) {
+	inner := &countValue{s: "default"}
+	lazy := Wrap(inner)
+	fs := flag.NewFlagSet("lazy", flag.ContinueOnError)
+	fs.Var(lazy, "opt", "Option")
+
+	if got, want := lazy.String(), "default"; got != want {
+		t.Errorf("Initial -opt: got %q, want %q", got, want)
+	}
+	if err := fs.Parse([]string{"-opt", "first", "-opt", "second"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if inner.sets != 0 {
+		t.Errorf("Set called %d times during parsing, want 0", inner.sets)
+	}
+	if raw, ok := lazy.Raw(); !ok || raw != "second" {
+		t.Errorf("Raw: got %q, %v; want second, true", raw, ok)
+	}
+	if got, want := lazy.String(), "second"; got != want {
+		t.Errorf("String before Resolve: got %q, want %q", got, want)
+	}
+	if got, want := lazy.Get(), any("second"); got != want {
+		t.Errorf("Get: got %v, want %v", got, want)
+	}
+	lazy.Get() // a second Get does not parse again
+	if err := ResolveAll(fs); err != nil {
+		t.Errorf("ResolveAll: unexpected error: %v", err)
+	}
+	if inner.sets != 2 {
+		t.Errorf("Set called %d times, want 2", inner.sets)
+	}
+}
+
+func TestRepeated(t *testing.T) {
+	inner := multiflag.Wrap(func() flag.Getter { return new(countValue) })
+	lazy := Wrap(inner)
+	fs := flag.NewFlagSet("lazy", flag.ContinueOnError)
+	fs.Var(lazy, "tag", "Tag")
+
+	if err := fs.Parse([]string{"-tag", "a", "-tag", "b", "-tag", "c"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if inner.Len() != 0 {
+		t.Errorf("Got %d values before Resolve, want 0", inner.Len())
+	}
+	if err := lazy.Resolve(); err != nil {
+		t.Fatalf("Resolve: unexpected error: %v", err)
+	}
+	if got, want := fmt.Sprint(inner.Results()), "[a b c]"; got != want {
+		t.Errorf("Values after Resolve: got %s, want %s", got, want)
+	}
+
+	// Later arguments are added to the resolved values.
+	if err := lazy.Set("d"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := lazy.Resolve(); err != nil {
+		t.Fatalf("Resolve: unexpected error: %v", err)
+	}
+	if got, want := inner.Len(), 4; got != want {
+		t.Errorf("Got %d values, want %d", got, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	inner := &countValue{s: "default"}
+	lazy := Wrap(inner)
+	fs := flag.NewFlagSet("lazy", flag.ContinueOnError)
+	fs.Var(lazy, "opt", "Option")
+
+	if err := fs.Parse([]string{"-opt", "bad"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if err := ResolveAll(fs); err == nil {
+		t.Error("ResolveAll: got nil, wanted error")
+	}
+	if err, ok := lazy.Get().(error); !ok {
+		t.Errorf("Get: got %v, wanted error", err)
+	}
+	if _, err := lazy.Resolved(); err == nil {
+		t.Error("Resolved: got nil, wanted error")
+	}
+	if inner.sets != 1 {
+		t.Errorf("Set called %d times, want 1", inner.sets)
+	}
+
+	// A new argument clears the error.
+	if err := lazy.Set("good"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := lazy.Resolve(); err != nil {
+		t.Errorf("Resolve: unexpected error: %v", err)
+	}
+}
+
+func TestResolveAllUnset(t *testing.T) {
+	inner := &countValue{s: "default"}
+	lazy := Wrap(inner)
+	fs := flag.NewFlagSet("lazy", flag.ContinueOnError)
+	fs.Var(lazy, "opt", "Option")
+
+	// A value set other than by fs.Parse, as from a config file, is resolved.
+	if err := lazy.Set("bad"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if got := goflags.ArgString(lazy); got != "bad" {
+		t.Errorf("ArgString before Resolve: got %q, want bad", got)
+	}
+	if lazy.Unwrap() != inner || inner.sets != 0 {
+		t.Errorf("Unwrap: got %v with %d sets, want the wrapped value unresolved", lazy.Unwrap(), inner.sets)
+	}
+	if err := ResolveAll(fs); err == nil {
+		t.Error("ResolveAll: got nil, wanted error")
+	}
+	if inner.sets != 1 {
+		t.Errorf("Set called %d times, want 1", inner.sets)
+	}
+}
@@ -0,0 +1,73 @@
+// Package levelflag defines a flag.Value implementation for log levels as
+// defined by the log/slog package.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//	  "log/slog"
+//
+//	  "github.com/creachadair/goflags/levelflag"
+//	)
+//
+//	var level levelflag.Value // default is Info
+//	func init() {
+//	  flag.Var(&level, "log-level", level.Help("Minimum level to log"))
+//	}
+//
+//	  ...
+//	  h := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: &level})
+//
+// Since a *Value satisfies the slog.Leveler interface, it can be passed
+// directly as the level of a handler. Pass a pointer, as shown, so that the
+// handler sees later changes to the flag; a Value passed by value is a copy
+// of the level at that time.
+package levelflag
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// A Value represents a log level. A *Value satisfies the flag.Getter and
+// slog.Leveler interfaces. The zero value is slog.LevelInfo.
+//
+// The flag accepts the names "debug", "info", "warn", and "error" without
+// regard to case, optionally followed by a signed offset, e.g., "debug-4" or
+// "warn+1". A plain integer is also accepted.
+type Value slog.Level
+
+// Help concatenates a human-readable string summarizing the legal values of v
+// to h, for use in generating a documentation string.
+func (v Value) Help(h string) string { return h + " (debug|info|warn|error)" }
+
+// Level returns the current value as a slog.Level. This satisfies the
+// slog.Leveler interface.
+func (v Value) Level() slog.Level { return slog.Level(v) }
+
+// AtLeast reports whether a message at level lvl should be logged with v as
+// the minimum threshold, that is, whether lvl >= v.
+func (v Value) AtLeast(lvl slog.Level) bool { return lvl >= slog.Level(v) }
+
+// String satisfies part of the flag.Value interface.
+func (v Value) String() string { return slog.Level(v).String() }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	if n, err := strconv.Atoi(s); err == nil {
+		*v = Value(n)
+		return nil
+	}
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return fmt.Errorf("levelflag: invalid level %q", s)
+	}
+	*v = Value(lvl)
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type slog.Level.
+func (v Value) Get() any { return slog.Level(v) }
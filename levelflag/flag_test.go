@@ -0,0 +1,81 @@
+package levelflag
+
+import (
+	"bytes"
+	"flag"
+	"log/slog"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var level Value
+
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("level", flag.ContinueOnError)
+	fs.Var(&level, "log-level", level.Help("Minimum level to log"))
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+	t.Logf("Level flag set:\n%s", buf.String())
+
+	if got := level.Level(); got != slog.LevelInfo {
+		t.Errorf("Initial value for -log-level: got %v, want %v", got, slog.LevelInfo)
+	}
+	if err := fs.Parse([]string{"-log-level", "WARN"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got := level.Get().(slog.Level); got != slog.LevelWarn {
+		t.Errorf("Value for -log-level: got %v, want %v", got, slog.LevelWarn)
+	}
+	if level.AtLeast(slog.LevelInfo) {
+		t.Error("AtLeast(INFO): got true, want false")
+	}
+	if !level.AtLeast(slog.LevelError) {
+		t.Error("AtLeast(ERROR): got false, want true")
+	}
+
+	// A *Value can be used directly as a handler level.
+	var out bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: &level}))
+	log.Info("hidden")
+	log.Error("shown")
+	if got := out.String(); bytes.Contains(out.Bytes(), []byte("hidden")) {
+		t.Errorf("Log output included a message below the threshold:\n%s", got)
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		in   string
+		want slog.Level
+	}{
+		{"debug", slog.LevelDebug},
+		{"Info", slog.LevelInfo},
+		{"warn", slog.LevelWarn},
+		{"ERROR", slog.LevelError},
+		{"debug-4", slog.LevelDebug - 4},
+		{"error+2", slog.LevelError + 2},
+		{"-8", -8},
+		{"12", 12},
+	}
+	for _, test := range tests {
+		var v Value
+		if err := v.Set(test.in); err != nil {
+			t.Errorf("Set(%q) failed: %v", test.in, err)
+		} else if v.Level() != test.want {
+			t.Errorf("Set(%q): got %v, want %v", test.in, v.Level(), test.want)
+		}
+
+		// The string form round-trips.
+		var w Value
+		if err := w.Set(v.String()); err != nil || w != v {
+			t.Errorf("Round trip of %q via %q: got %v, %v", test.in, v.String(), w, err)
+		}
+	}
+
+	var v Value
+	for _, bad := range []string{"", "verbose", "info+", "debug--1"} {
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %v, wanted error", bad, v)
+		}
+	}
+}
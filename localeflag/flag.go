@@ -0,0 +1,251 @@
+// Package localeflag defines a flag.Value implementation for BCP 47 language
+// tags, such as "en-US" or "pt-BR".
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/localeflag"
+//	)
+//
+//	var lang = localeflag.Value{Supported: []string{"en-US", "fr-FR", "de"}}
+//	func init() {
+//	  flag.Var(&lang, "lang", lang.Help("User interface language"))
+//	}
+//
+// Tags are checked for conformance with the syntax of RFC 5646 and converted
+// to canonical case, e.g., "EN-us" becomes "en-US" and "zh-hant-tw" becomes
+// "zh-Hant-TW". The contents of the subtags are not checked against the IANA
+// registry, so that this package does not depend on a large data table.
+// Programs that need full matching or canonicalization should use the
+// golang.org/x/text/language package, for which this is a lightweight
+// alternative.
+package localeflag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Tag is a syntactically valid BCP 47 language tag in canonical case.
+// The zero value is the empty tag.
+type Tag string
+
+// Parse parses s as a BCP 47 language tag. Either "-" or "_" may be used to
+// separate subtags.
+func Parse(s string) (Tag, error) {
+	parts := strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 || len(parts) != strings.Count(s, "-")+strings.Count(s, "_")+1 {
+		return "", fmt.Errorf("localeflag: invalid language tag %q", s)
+	}
+	for _, p := range parts {
+		if len(p) > 8 || !isAlnum(p) {
+			return "", fmt.Errorf("localeflag: invalid language tag %q", s)
+		}
+	}
+	out, ok := canonical(parts)
+	if !ok {
+		return "", fmt.Errorf("localeflag: invalid language tag %q", s)
+	}
+	return Tag(strings.Join(out, "-")), nil
+}
+
+// MustParse parses s as a language tag, and panics if this fails.
+func MustParse(s string) Tag {
+	t, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Language returns the primary language subtag of t, e.g., "en" for "en-US".
+func (t Tag) Language() string {
+	lang, _, _ := strings.Cut(string(t), "-")
+	return lang
+}
+
+// Script returns the script subtag of t, e.g., "Hant" for "zh-Hant-TW",
+// or "" if t has none.
+func (t Tag) Script() string {
+	for _, p := range t.subtags() {
+		if len(p) == 4 && isAlpha(p) {
+			return p
+		}
+	}
+	return ""
+}
+
+// Region returns the region subtag of t, e.g., "US" for "en-US", or "" if t
+// has none.
+func (t Tag) Region() string {
+	for _, p := range t.subtags() {
+		if (len(p) == 2 && isAlpha(p)) || (len(p) == 3 && isDigits(p)) {
+			return p
+		}
+	}
+	return ""
+}
+
+// String returns t as a string.
+func (t Tag) String() string { return string(t) }
+
+// subtags returns the subtags of t following the primary language, up to the
+// first extension or private-use singleton.
+func (t Tag) subtags() []string {
+	parts := strings.Split(string(t), "-")
+	if len(parts) < 2 || len(parts[0]) == 1 {
+		return nil
+	}
+	for i, p := range parts[1:] {
+		if len(p) == 1 {
+			return parts[1 : i+1]
+		}
+	}
+	return parts[1:]
+}
+
+// A Value represents a language tag. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The tag parsed from the flag.
+	Tag Tag
+
+	// If non-empty, the tag must be one of these, compared without regard to
+	// case. The original spelling of the matching entry is not preserved; the
+	// resulting tag is always in canonical case.
+	Supported []string
+}
+
+// Help concatenates a human-readable string summarizing the legal values of v
+// to h, for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	if len(v.Supported) == 0 {
+		return h + " (BCP 47 language tag)"
+	}
+	return fmt.Sprintf("%s (%s)", h, strings.Join(v.Supported, "|"))
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return string(v.Tag) }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	t, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	if len(v.Supported) != 0 {
+		ok := false
+		for _, sup := range v.Supported {
+			if st, err := Parse(sup); err == nil && st == t {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return fmt.Errorf("expected one of (%s)", strings.Join(v.Supported, "|"))
+		}
+	}
+	v.Tag = t
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Tag.
+func (v *Value) Get() any { return v.Tag }
+
+// canonical checks the structure of the subtags in parts, and returns them in
+// canonical case. The grammar follows RFC 5646 section 2.1:
+//
+//	langtag   = language ["-" script] ["-" region] *("-" variant)
+//	            *("-" extension) ["-" privateuse]
+//	language  = 2*3ALPHA ["-" extlang] / 4ALPHA / 5*8ALPHA
+//	extlang   = 3ALPHA *2("-" 3ALPHA)
+//	script    = 4ALPHA
+//	region    = 2ALPHA / 3DIGIT
+//	variant   = 5*8alphanum / (DIGIT 3alphanum)
+//	extension = singleton 1*("-" (2*8alphanum))
+//	privateuse = "x" 1*("-" (1*8alphanum))
+func canonical(parts []string) ([]string, bool) {
+	for i := range parts {
+		parts[i] = strings.ToLower(parts[i])
+	}
+	if parts[0] == "x" {
+		return parts, privateUse(parts[1:])
+	}
+
+	i := 0
+	lang := parts[i]
+	if len(lang) < 2 || !isAlpha(lang) {
+		return nil, false
+	}
+	i++
+	if len(lang) <= 3 {
+		for n := 0; n < 3 && i < len(parts) && len(parts[i]) == 3 && isAlpha(parts[i]); n++ {
+			i++ // extlang
+		}
+	}
+	if i < len(parts) && len(parts[i]) == 4 && isAlpha(parts[i]) {
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		i++ // script
+	}
+	if i < len(parts) && ((len(parts[i]) == 2 && isAlpha(parts[i])) || (len(parts[i]) == 3 && isDigits(parts[i]))) {
+		parts[i] = strings.ToUpper(parts[i])
+		i++ // region
+	}
+	for i < len(parts) && isVariant(parts[i]) {
+		i++
+	}
+	for i < len(parts) && len(parts[i]) == 1 && parts[i] != "x" {
+		i++ // extension singleton
+		start := i
+		for i < len(parts) && len(parts[i]) >= 2 {
+			i++
+		}
+		if i == start {
+			return nil, false
+		}
+	}
+	if i < len(parts) && parts[i] == "x" {
+		return parts, privateUse(parts[i+1:])
+	}
+	return parts, i == len(parts)
+}
+
+func privateUse(parts []string) bool { return len(parts) > 0 }
+
+func isVariant(s string) bool {
+	return len(s) >= 5 || (len(s) == 4 && s[0] >= '0' && s[0] <= '9')
+}
+
+func isAlnum(s string) bool {
+	for _, c := range s {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isAlpha(s string) bool {
+	for _, c := range s {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
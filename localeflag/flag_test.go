@@ -0,0 +1,82 @@
+package localeflag
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var locale Value
+	lang := Value{Supported: []string{"en-US", "fr-FR", "de"}}
+
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("locale", flag.ContinueOnError)
+	fs.Var(&locale, "locale", locale.Help("Locale"))
+	fs.Var(&lang, "lang", lang.Help("User interface language"))
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+	t.Logf("Locale flag set:\n%s", buf.String())
+
+	if err := fs.Parse([]string{"-locale", "zh_hant_tw", "-lang", "EN-us"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := locale.Get().(Tag), Tag("zh-Hant-TW"); got != want {
+		t.Errorf("Value for -locale: got %q, want %q", got, want)
+	}
+	if got := locale.Tag; got.Language() != "zh" || got.Script() != "Hant" || got.Region() != "TW" {
+		t.Errorf("Subtags of %q: got %q, %q, %q", got, got.Language(), got.Script(), got.Region())
+	}
+	if got, want := lang.Tag, Tag("en-US"); got != want {
+		t.Errorf("Value for -lang: got %q, want %q", got, want)
+	}
+
+	if err := fs.Parse([]string{"-lang", "pt-BR"}); err == nil {
+		t.Error("Expected error from unsupported language, but got none")
+	} else {
+		t.Logf("Got expected error from unsupported -lang: %v", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in, want             string
+		lang, script, region string
+	}{
+		{"en", "en", "en", "", ""},
+		{"EN", "en", "en", "", ""},
+		{"pt-br", "pt-BR", "pt", "", "BR"},
+		{"es-419", "es-419", "es", "", "419"},
+		{"sr-latn-rs", "sr-Latn-RS", "sr", "Latn", "RS"},
+		{"zh-yue-HK", "zh-yue-HK", "zh", "", "HK"},
+		{"de-CH-1901", "de-CH-1901", "de", "", "CH"},
+		{"sl-rozaj-biske", "sl-rozaj-biske", "sl", "", ""},
+		{"en-US-u-ca-gregory", "en-US-u-ca-gregory", "en", "", "US"},
+		{"en-a-bbb-x-a-ccc", "en-a-bbb-x-a-ccc", "en", "", ""},
+		{"x-whatever", "x-whatever", "x", "", ""},
+		{"de-Qaaa", "de-Qaaa", "de", "Qaaa", ""},
+	}
+	for _, test := range tests {
+		got, err := Parse(test.in)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", test.in, err)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("Parse(%q): got %q, want %q", test.in, got, test.want)
+		}
+		if got.Language() != test.lang || got.Script() != test.script || got.Region() != test.region {
+			t.Errorf("Subtags of %q: got (%q, %q, %q), want (%q, %q, %q)", got,
+				got.Language(), got.Script(), got.Region(), test.lang, test.script, test.region)
+		}
+	}
+
+	for _, bad := range []string{
+		"", "-", "e", "en-", "-en", "en--US", "1en", "en-US-", "toolonglang",
+		"en-u", "en-x", "x", "en-US-u-ca-x", "en_US.UTF-8", "en-abcdefghi", "en-12",
+	} {
+		if got, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q): got %q, wanted error", bad, got)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Package matrixflag defines a repeatable flag.Value implementation that
+// collects the dimensions of a parameter matrix and expands them into all
+// their combinations, as in a CI build matrix.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/matrixflag"
+//	)
+//
+//	var matrix matrixflag.Value
+//	func init() {
+//	  flag.Var(&matrix, "matrix", matrix.Help("Test matrix dimension"))
+//	}
+//
+//	  ...
+//	  for combo := range matrix.All() {
+//	    run(combo["os"], combo["go"])
+//	  }
+//
+// With this definition "-matrix os=linux,darwin -matrix go=1.22,1.23" yields
+// four combinations. Repeating a key adds values to its dimension.
+package matrixflag
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+)
+
+// A Value represents a set of named dimensions, each with a list of values.
+// A pointer to a Value satisfies the flag.Value and flag.Getter interfaces.
+type Value struct {
+	keys []string            // in order of first occurrence
+	vals map[string][]string // distinct values of each key, in order
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string { return h + " (key=value,...; repeatable)" }
+
+// Keys returns the names of the dimensions, in order of first occurrence.
+func (v *Value) Keys() []string { return slices.Clone(v.keys) }
+
+// Values returns the values of the named dimension.
+func (v *Value) Values(key string) []string { return slices.Clone(v.vals[key]) }
+
+// Len returns the number of combinations. A matrix with no dimensions has one
+// combination, the empty one.
+func (v *Value) Len() int {
+	n := 1
+	for _, key := range v.keys {
+		n *= len(v.vals[key])
+	}
+	return n
+}
+
+// All returns an iterator over the combinations of the matrix, each mapping
+// every key to one of its values. The last key varies fastest. Each map is
+// freshly allocated and may be retained by the caller.
+func (v *Value) All() iter.Seq[map[string]string] {
+	return func(yield func(map[string]string) bool) {
+		idx := make([]int, len(v.keys))
+		for {
+			combo := make(map[string]string, len(v.keys))
+			for i, key := range v.keys {
+				combo[key] = v.vals[key][idx[i]]
+			}
+			if !yield(combo) {
+				return
+			}
+
+			// Advance the index like an odometer.
+			i := len(idx) - 1
+			for ; i >= 0; i-- {
+				idx[i]++
+				if idx[i] < len(v.vals[v.keys[i]]) {
+					break
+				}
+				idx[i] = 0
+			}
+			if i < 0 {
+				return
+			}
+		}
+	}
+}
+
+// Combinations returns all the combinations of the matrix, in the order
+// produced by All.
+func (v *Value) Combinations() []map[string]string { return slices.Collect(v.All()) }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", strings.Join(v.ArgStrings(), " ")) }
+
+// ArgStrings returns the dimensions of v as "key=value,..." strings, in order
+// of first occurrence, each of which is a valid argument to Set.
+func (v *Value) ArgStrings() []string {
+	parts := make([]string, len(v.keys))
+	for i, key := range v.keys {
+		parts[i] = key + "=" + strings.Join(v.vals[key], ",")
+	}
+	return parts
+}
+
+// Reset discards all the dimensions of v.
+func (v *Value) Reset() { v.keys, v.vals = nil, nil }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	key, list, ok := strings.Cut(s, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return fmt.Errorf("matrixflag: invalid dimension %q, want key=value,...", s)
+	}
+	var add []string
+	for _, val := range strings.Split(list, ",") {
+		val = strings.TrimSpace(val)
+		if val == "" {
+			return fmt.Errorf("matrixflag: empty value for %q", key)
+		}
+		add = append(add, val)
+	}
+	if v.vals == nil {
+		v.vals = make(map[string][]string)
+	}
+	cur, exists := v.vals[key]
+	if !exists {
+		v.keys = append(v.keys, key)
+	}
+	for _, val := range add {
+		if !slices.Contains(cur, val) {
+			cur = append(cur, val)
+		}
+	}
+	v.vals[key] = cur
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type []map[string]string, as returned by
+// Combinations.
+func (v *Value) Get() any { return v.Combinations() }
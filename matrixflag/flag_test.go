@@ -0,0 +1,79 @@
+package matrixflag
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var matrix Value
+	fs := flag.NewFlagSet("matrix", flag.ContinueOnError)
+	fs.Var(&matrix, "matrix", matrix.Help("Dimension"))
+
+	if got := matrix.Len(); got != 1 {
+		t.Errorf("Initial Len: got %d, want 1", got)
+	}
+	if err := fs.Parse([]string{
+		"-matrix", "os=linux,darwin",
+		"-matrix", "go=1.22",
+		"-matrix", "go=1.23, 1.22",
+	}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	if got, want := matrix.Keys(), []string{"os", "go"}; !slices.Equal(got, want) {
+		t.Errorf("Keys: got %q, want %q", got, want)
+	}
+	if got, want := matrix.Values("go"), []string{"1.22", "1.23"}; !slices.Equal(got, want) {
+		t.Errorf("Values(go): got %q, want %q", got, want)
+	}
+	if got := matrix.Len(); got != 4 {
+		t.Errorf("Len: got %d, want 4", got)
+	}
+
+	var got []string
+	for _, c := range matrix.Get().([]map[string]string) {
+		got = append(got, fmt.Sprintf("%s/%s", c["os"], c["go"]))
+	}
+	want := []string{"linux/1.22", "linux/1.23", "darwin/1.22", "darwin/1.23"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Combinations: got %q, want %q", got, want)
+	}
+	if got, want := matrix.String(), `"os=linux,darwin go=1.22,1.23"`; got != want {
+		t.Errorf("String: got %s, want %s", got, want)
+	}
+
+	// Stopping early is respected.
+	n := 0
+	for range matrix.All() {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("All with break: got %d iterations, want 1", n)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, bad := range []string{"", "novalue", "=a", "k=", "k=a,,b"} {
+		var v Value
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %v, wanted error", bad, v.String())
+		}
+	}
+}
+
+func TestArgStrings(t *testing.T) {
+	var v Value
+	for _, s := range []string{"os=linux,mac", "go=1.22", "os=windows"} {
+		if err := v.Set(s); err != nil {
+			t.Fatalf("Set(%q) failed: %v", s, err)
+		}
+	}
+	want := []string{"os=linux,mac,windows", "go=1.22"}
+	if got := v.ArgStrings(); !slices.Equal(got, want) {
+		t.Errorf("ArgStrings: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,107 @@
+// Package mimeflag defines a flag.Value implementation for MIME media types
+// with optional parameters.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/mimeflag"
+//	)
+//
+//	var contentType = mimeflag.Value{Allowed: []string{"application/json", "text/*"}}
+//	func init() {
+//	  flag.Var(&contentType, "content-type", contentType.Help("Upload content type"))
+//	}
+//
+// With this definition "-content-type 'text/plain; charset=utf-8'" sets the
+// type to "text/plain" with the parameter charset=utf-8. Media types are
+// parsed with mime.ParseMediaType, so type names and parameter names are
+// converted to lower case.
+package mimeflag
+
+import (
+	"fmt"
+	"maps"
+	"mime"
+	"slices"
+	"strings"
+)
+
+// A Value represents a media type. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The media type parsed from the flag, in lower case, e.g., "text/plain".
+	Type string
+
+	// The parameters parsed from the flag, or nil if there were none.
+	Params map[string]string
+
+	// If non-empty, the media type must match one of these. An entry may be
+	// a full type, such as "image/png", or a pattern such as "image/*".
+	Allowed []string
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	if len(v.Allowed) == 0 {
+		return h + " (type/subtype[; param=value])"
+	}
+	return fmt.Sprintf("%s (%s)", h, strings.Join(v.Allowed, "|"))
+}
+
+// MediaType returns the media type and parameters formatted as in a
+// Content-Type header, or "" if no type has been set.
+func (v *Value) MediaType() string {
+	if v.Type == "" {
+		return ""
+	}
+	return mime.FormatMediaType(v.Type, v.Params)
+}
+
+// Param returns the value of the named parameter, or "".
+func (v *Value) Param(name string) string { return v.Params[strings.ToLower(name)] }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return v.MediaType() }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	mt, params, err := mime.ParseMediaType(s)
+	if err != nil {
+		return fmt.Errorf("mimeflag: invalid media type %q: %w", s, err)
+	}
+	if typ, sub, ok := strings.Cut(mt, "/"); !ok || typ == "" || sub == "" || typ == "*" || sub == "*" {
+		return fmt.Errorf("mimeflag: invalid media type %q, want type/subtype", mt)
+	}
+	if len(v.Allowed) != 0 && !slices.ContainsFunc(v.Allowed, func(p string) bool { return matches(p, mt) }) {
+		return fmt.Errorf("mimeflag: media type %q not allowed, expected one of (%s)",
+			mt, strings.Join(v.Allowed, "|"))
+	}
+	if len(params) == 0 {
+		params = nil
+	}
+	v.Type, v.Params = mt, maps.Clone(params)
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is the string returned by MediaType.
+func (v *Value) Get() any { return v.MediaType() }
+
+// matches reports whether the media type mt matches pattern, which is either
+// a media type or "type/*" or "*/*".
+func matches(pattern, mt string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "*/*" || pattern == mt {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+		return strings.HasPrefix(mt, prefix+"/")
+	}
+	return false
+}
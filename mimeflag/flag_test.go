@@ -0,0 +1,69 @@
+package mimeflag
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	ct := Value{Allowed: []string{"application/json", "text/*"}}
+	fs := flag.NewFlagSet("mime", flag.ContinueOnError)
+	fs.Var(&ct, "content-type", ct.Help("Content type"))
+
+	if got, want := ct.String(), `""`; got != want {
+		t.Errorf("Initial -content-type: got %s, want %s", got, want)
+	}
+	if err := fs.Parse([]string{"-content-type", "Text/Plain; Charset=utf-8"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if ct.Type != "text/plain" || ct.Param("charset") != "utf-8" {
+		t.Errorf("Value for -content-type: got %q %v", ct.Type, ct.Params)
+	}
+	if got, want := ct.Get().(string), "text/plain; charset=utf-8"; got != want {
+		t.Errorf("MediaType: got %q, want %q", got, want)
+	}
+	if err := fs.Parse([]string{"-content-type", "application/json"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	} else if ct.Params != nil {
+		t.Errorf("Params for application/json: got %v, want nil", ct.Params)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		v     Value
+		input string
+	}{
+		{Value{}, ""},
+		{Value{}, "text"},
+		{Value{}, "text/"},
+		{Value{}, "*/*"},
+		{Value{}, "text/plain; charset"},
+		{Value{}, "bad type/x"},
+		{Value{Allowed: []string{"text/*"}}, "image/png"},
+		{Value{Allowed: []string{"image/png"}}, "image/jpeg"},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.input); err == nil {
+			t.Errorf("Set(%q) with %+v: got %q, wanted error", test.input, test.v, test.v.Type)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		pattern, mt string
+		want        bool
+	}{
+		{"*/*", "image/png", true},
+		{"image/*", "image/png", true},
+		{"Image/PNG", "image/png", true},
+		{"image/*", "imagex/png", false},
+		{"text/plain", "text/html", false},
+	}
+	for _, test := range tests {
+		if got := matches(test.pattern, test.mt); got != test.want {
+			t.Errorf("matches(%q, %q): got %v, want %v", test.pattern, test.mt, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+// Package modeflag defines a flag.Value implementation for file permission
+// modes, written in octal ("0644") or symbolic ("u=rw,go=r") notation.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/modeflag"
+//	)
+//
+//	var mode = modeflag.Value{Mode: 0644, NoSetuid: true}
+//	func init() {
+//	  flag.Var(&mode, "mode", mode.Help("Permissions for created files"))
+//	}
+//
+// Symbolic modes follow chmod(1): a comma-separated list of clauses, each of
+// the form [ugoa...][+-=][rwxXst...]. A clause with "+" or "-" adjusts the
+// current value of the flag, so "-mode g+w" applied to a default of 0644
+// yields 0664. "X" grants execute permission only if some execute bit is
+// already set. An empty list of who-letters is treated as "a".
+package modeflag
+
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// A Value represents a file permission mode. A pointer to a Value satisfies
+// the flag.Value and flag.Getter interfaces. Only the permission bits and the
+// setuid, setgid, and sticky bits of the mode are used.
+type Value struct {
+	// The mode parsed from the flag.
+	Mode fs.FileMode
+
+	// If true, modes with the setuid or setgid bits are rejected.
+	NoSetuid bool
+
+	// If true, modes with any bits other than the permission bits (0777) are
+	// rejected, that is, setuid, setgid, and sticky.
+	PermOnly bool
+}
+
+const specialBits = fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	return h + " (octal, e.g., 0644, or symbolic, e.g., u=rw,go=r)"
+}
+
+// String satisfies part of the flag.Value interface.
+// The mode is rendered in octal with a leading zero.
+func (v *Value) String() string { return fmt.Sprintf("%#o", toOctal(v.Mode)) }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	var mode fs.FileMode
+	if s != "" && s[0] >= '0' && s[0] <= '7' {
+		n, err := strconv.ParseUint(s, 8, 32)
+		if err != nil || n > 07777 {
+			return fmt.Errorf("modeflag: invalid octal mode %q", s)
+		}
+		mode = fromOctal(uint32(n))
+	} else {
+		m, err := applySymbolic(v.Mode&(fs.ModePerm|specialBits), s)
+		if err != nil {
+			return err
+		}
+		mode = m
+	}
+	if v.PermOnly && mode&specialBits != 0 {
+		return fmt.Errorf("modeflag: mode %#o has special bits", toOctal(mode))
+	}
+	if v.NoSetuid && mode&(fs.ModeSetuid|fs.ModeSetgid) != 0 {
+		return fmt.Errorf("modeflag: mode %#o has setuid or setgid bits", toOctal(mode))
+	}
+	v.Mode = mode
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type fs.FileMode.
+func (v *Value) Get() any { return v.Mode }
+
+// toOctal converts m to the traditional Unix octal representation.
+func toOctal(m fs.FileMode) uint32 {
+	n := uint32(m.Perm())
+	if m&fs.ModeSetuid != 0 {
+		n |= 04000
+	}
+	if m&fs.ModeSetgid != 0 {
+		n |= 02000
+	}
+	if m&fs.ModeSticky != 0 {
+		n |= 01000
+	}
+	return n
+}
+
+// fromOctal converts a traditional Unix octal mode to an fs.FileMode.
+func fromOctal(n uint32) fs.FileMode {
+	m := fs.FileMode(n & 0777)
+	if n&04000 != 0 {
+		m |= fs.ModeSetuid
+	}
+	if n&02000 != 0 {
+		m |= fs.ModeSetgid
+	}
+	if n&01000 != 0 {
+		m |= fs.ModeSticky
+	}
+	return m
+}
+
+// applySymbolic applies the symbolic mode clauses of s to base.
+func applySymbolic(base fs.FileMode, s string) (fs.FileMode, error) {
+	if s == "" {
+		return 0, fmt.Errorf("modeflag: empty mode")
+	}
+	n := toOctal(base)
+	for _, clause := range strings.Split(s, ",") {
+		i := strings.IndexAny(clause, "+-=")
+		if i < 0 {
+			return 0, fmt.Errorf("modeflag: invalid clause %q", clause)
+		}
+		var who uint32
+		for _, c := range clause[:i] {
+			switch c {
+			case 'u':
+				who |= 04700
+			case 'g':
+				who |= 02070
+			case 'o':
+				who |= 01007
+			case 'a':
+				who |= 07777
+			default:
+				return 0, fmt.Errorf("modeflag: invalid clause %q", clause)
+			}
+		}
+		if who == 0 {
+			who = 07777
+		}
+
+		// A clause may contain several actions, e.g., "u+r-w".
+		rest := clause[i:]
+		for rest != "" {
+			op := rest[0]
+			j := strings.IndexAny(rest[1:], "+-=")
+			if j < 0 {
+				j = len(rest) - 1
+			}
+			perms, err := parsePerms(rest[1:j+1], n)
+			if err != nil {
+				return 0, fmt.Errorf("modeflag: invalid clause %q", clause)
+			}
+			bits := perms & who
+			switch op {
+			case '+':
+				n |= bits
+			case '-':
+				n &^= bits
+			case '=':
+				// Clear all the bits of the named classes, including their
+				// special bits, then set the requested ones.
+				n = n&^who | bits
+			}
+			rest = rest[j+1:]
+		}
+	}
+	return fromOctal(n), nil
+}
+
+// parsePerms returns the bits named by the permission letters of s, for all
+// classes. The caller masks the result to the classes of interest.
+func parsePerms(s string, cur uint32) (uint32, error) {
+	var bits uint32
+	for _, c := range s {
+		switch c {
+		case 'r':
+			bits |= 0444
+		case 'w':
+			bits |= 0222
+		case 'x':
+			bits |= 0111
+		case 'X':
+			if cur&0111 != 0 {
+				bits |= 0111
+			}
+		case 's':
+			bits |= 06000
+		case 't':
+			bits |= 01000
+		default:
+			return 0, fmt.Errorf("invalid permission %q", c)
+		}
+	}
+	return bits, nil
+}
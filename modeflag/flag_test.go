@@ -0,0 +1,102 @@
+package modeflag
+
+import (
+	"bytes"
+	"flag"
+	"io/fs"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	mode := Value{Mode: 0644}
+
+	var buf bytes.Buffer
+	fset := flag.NewFlagSet("mode", flag.ContinueOnError)
+	fset.Var(&mode, "mode", mode.Help("File mode"))
+	fset.SetOutput(&buf)
+	fset.PrintDefaults()
+	t.Logf("Mode flag set:\n%s", buf.String())
+
+	if got, want := mode.String(), "0644"; got != want {
+		t.Errorf("Initial value for -mode: got %q, want %q", got, want)
+	}
+	if err := fset.Parse([]string{"-mode", "g+w"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := mode.String(), "0664"; got != want {
+		t.Errorf("Value for -mode: got %q, want %q", got, want)
+	}
+	if err := fset.Parse([]string{"-mode", "0700"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got := mode.Get().(fs.FileMode); got != 0700 {
+		t.Errorf("Value for -mode: got %v, want %v", got, fs.FileMode(0700))
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		base uint32 // in Unix octal notation
+		in   string
+		want string
+	}{
+		{0, "0644", "0644"},
+		{0, "755", "0755"},
+		{0, "4755", "04755"},
+		{0, "1777", "01777"},
+		{0, "u=rw,go=r", "0644"},
+		{0, "a=rwx", "0777"},
+		{0, "=rx", "0555"},
+		{0777, "o-rwx", "0770"},
+		{0644, "+x", "0755"},
+		{0644, "u+x,g-r", "0704"},
+		{0644, "u+rwx-w", "0544"},
+		{0600, "go=u", "0600"}, // "u" is not a permission letter
+		{0644, "a+X", "0644"},
+		{0744, "a+X", "0755"},
+		{0755, "u+s", "04755"},
+		{0755, "g+s", "02755"},
+		{0777, "+t", "01777"},
+		{04755, "u=rwx", "0755"},
+		{04755, "g=rx", "04755"},
+	}
+	for _, test := range tests {
+		v := Value{Mode: fromOctal(test.base)}
+		err := v.Set(test.in)
+		if test.in == "go=u" {
+			if err == nil {
+				t.Errorf("Set(%q): got %v, wanted error", test.in, v.String())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Set(%q) on %#o failed: %v", test.in, test.base, err)
+		} else if got := v.String(); got != test.want {
+			t.Errorf("Set(%q) on %#o: got %s, want %s", test.in, test.base, got, test.want)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		v  Value
+		in string
+	}{
+		{Value{}, ""},
+		{Value{}, "0999"},
+		{Value{}, "17777"},
+		{Value{}, "rw"},
+		{Value{}, "q=rw"},
+		{Value{}, "u=rwz"},
+		{Value{}, "u=r,"},
+		{Value{NoSetuid: true}, "4755"},
+		{Value{NoSetuid: true}, "g+s"},
+		{Value{PermOnly: true}, "1777"},
+		{Value{PermOnly: true}, "+t"},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.in); err == nil {
+			t.Errorf("Set(%q) with %+v: got %v, wanted error", test.in, test.v, test.v.String())
+		}
+	}
+}
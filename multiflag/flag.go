@@ -0,0 +1,92 @@
+// Package multiflag defines a flag.Value implementation that makes any other
+// flag.Getter repeatable.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/multiflag"
+//	  "github.com/creachadair/goflags/regexpflag"
+//	)
+//
+//	var excludes = multiflag.Wrap(func() flag.Getter { return new(regexpflag.Value) })
+//	func init() {
+//	  flag.Var(excludes, "exclude", "Exclude paths matching this pattern (repeatable)")
+//	}
+//
+// With this definition "-exclude '^vendor/' -exclude '_test\.go$'" yields two
+// parsed patterns, reported by the Values and Results methods in the order
+// they were given.
+package multiflag
+
+import (
+	"flag"
+	"strings"
+)
+
+// A Value collects each occurrence of a flag into a separate value. A *Value
+// satisfies the flag.Value and flag.Getter interfaces.
+type Value struct {
+	newValue func() flag.Getter
+	vals     []flag.Getter
+}
+
+// Wrap returns a *Value that calls newValue to obtain a fresh value for each
+// occurrence of the flag, and parses the argument of that occurrence with it.
+// The values returned by newValue may be preconfigured, e.g., with bounds; a
+// value that reports an error is discarded.
+func Wrap(newValue func() flag.Getter) *Value { return &Value{newValue: newValue} }
+
+// Len reports the number of values collected.
+func (v *Value) Len() int { return len(v.vals) }
+
+// Values returns the collected values, in the order they were set.
+func (v *Value) Values() []flag.Getter { return v.vals }
+
+// Results returns the result of calling Get on each of the collected values,
+// in the order they were set.
+func (v *Value) Results() []any {
+	out := make([]any, len(v.vals))
+	for i, g := range v.vals {
+		out[i] = g.Get()
+	}
+	return out
+}
+
+// Reset discards all collected values.
+func (v *Value) Reset() { v.vals = nil }
+
+// String satisfies part of the flag.Value interface. It joins the string
+// representations of the collected values with commas.
+func (v *Value) String() string {
+	if v == nil {
+		return "" // the zero value, as used by flag.PrintDefaults
+	}
+	parts := make([]string, len(v.vals))
+	for i, g := range v.vals {
+		parts[i] = g.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	g := v.newValue()
+	if err := g.Set(s); err != nil {
+		return err
+	}
+	v.vals = append(v.vals, g)
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type []any, as returned by Results.
+func (v *Value) Get() any { return v.Results() }
+
+// IsBoolFlag reports whether the wrapped values are boolean flags, so that a
+// repeatable boolean flag may be given without an argument.
+func (v *Value) IsBoolFlag() bool {
+	b, ok := v.newValue().(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
@@ -0,0 +1,69 @@
+package multiflag
+
+import (
+	"flag"
+	"io"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/creachadair/goflags/regexpflag"
+	"github.com/creachadair/goflags/sizeflag"
+)
+
+type boolValue bool
+
+func (b *boolValue) String() string   { return strconv.FormatBool(bool(*b)) }
+func (b *boolValue) Get() any         { return bool(*b) }
+func (b *boolValue) IsBoolFlag() bool { return true }
+func (b *boolValue) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	*b = boolValue(v)
+	return err
+}
+
+func TestFlagBits(t *testing.T) {
+	sizes := Wrap(func() flag.Getter { return sizeflag.Base2(nil) })
+	excludes := Wrap(func() flag.Getter { return new(regexpflag.Value) })
+	marks := Wrap(func() flag.Getter { return new(boolValue) })
+
+	fs := flag.NewFlagSet("multi", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(sizes, "size", "Sizes")
+	fs.Var(excludes, "exclude", "Patterns")
+	fs.Var(marks, "mark", "Marks")
+
+	if got := sizes.String(); got != "" {
+		t.Errorf("Initial -size: got %q, want empty", got)
+	}
+	if err := fs.Parse([]string{
+		"-size", "4k", "-exclude", "^a", "-size", "1m", "-mark", "-exclude", `\.go$`, "-mark=false",
+	}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	got := sizes.Get().([]any)
+	if len(got) != 2 || got[0] != 4096 || got[1] != 1<<20 {
+		t.Errorf("Value for -size: got %v, want [4096 1048576]", got)
+	}
+	if n := excludes.Len(); n != 2 {
+		t.Fatalf("Len for -exclude: got %d, want 2", n)
+	}
+	if re := excludes.Results()[1].(*regexp.Regexp); !re.MatchString("x.go") {
+		t.Errorf("Value for -exclude: %v does not match x.go", re)
+	}
+	if got := marks.Results(); len(got) != 2 || got[0] != true || got[1] != false {
+		t.Errorf("Value for -mark: got %v, want [true false]", got)
+	}
+
+	if err := fs.Parse([]string{"-size", "bogus"}); err == nil {
+		t.Error("Parse -size bogus: got nil, wanted error")
+	}
+	if n := sizes.Len(); n != 2 {
+		t.Errorf("Len after error: got %d, want 2", n)
+	}
+	sizes.Reset()
+	if n := sizes.Len(); n != 0 {
+		t.Errorf("Len after Reset: got %d, want 0", n)
+	}
+}
@@ -0,0 +1,107 @@
+package goflags
+
+import (
+	"flag"
+	"strings"
+)
+
+// Namespace defines the flags of a component in fs with names prefixed by
+// "prefix.", so that several components can define flags in the same flag
+// set without collisions. The register function defines the flags of the
+// component, with unprefixed names, in a new flag set, as a library might in
+// a function such as
+//
+//	func RegisterFlags(fs *flag.FlagSet)
+//
+// and Namespace then copies them into fs as described by Embed. It returns
+// the flag set passed to register, whose flags share their values with the
+// prefixed flags of fs.
+func Namespace(fs *flag.FlagSet, prefix string, register func(*flag.FlagSet)) *flag.FlagSet {
+	src := flag.NewFlagSet(prefix, flag.ContinueOnError)
+	register(src)
+	Embed(fs, prefix, src)
+	return src
+}
+
+// Embed defines a flag in fs for each flag of src, with the name prefixed by
+// "prefix.", so that for prefix "db" the flag "addr" becomes "db.addr". This
+// is the same separator used by Bind for nested structs. The flags of fs
+// share the values of the flags of src, and have the same defaults.
+//
+// References to the flags of src in their usage strings, such as "-addr", are
+// rewritten to the prefixed names. The flag groups (see Exclusive) and usage
+// sections (see Section) declared for src are also declared for fs, with the
+// prefixed names. Like fs.Var, Embed panics if a flag with a prefixed name is
+// already defined in fs.
+func Embed(fs *flag.FlagSet, prefix string, src *flag.FlagSet) {
+	rename := func(name string) string {
+		if src.Lookup(name) == nil {
+			return ""
+		}
+		return prefix + "." + name
+	}
+	src.VisitAll(func(f *flag.Flag) {
+		name := rename(f.Name)
+		fs.Var(f.Value, name, renameFlagRefs(f.Usage, rename))
+		fs.Lookup(name).DefValue = f.DefValue
+	})
+
+	groups.Lock()
+	for _, g := range groups.m[src] {
+		groups.m[fs] = append(groups.m[fs], group{kind: g.kind, names: prefixNames(prefix, g.names)})
+	}
+	groups.Unlock()
+
+	sections.Lock()
+	ss := sections.m[src]
+	sections.Unlock()
+	for _, s := range ss {
+		Section(fs, s.title, prefixNames(prefix, s.names)...)
+	}
+}
+
+func prefixNames(prefix string, names []string) []string {
+	out := make([]string, len(names))
+	for i, name := range names {
+		out[i] = prefix + "." + name
+	}
+	return out
+}
+
+// renameFlagRefs returns a copy of usage in which each reference to a flag
+// "-name" or "--name", at the start of a word, is replaced by the name
+// reported by rename, if that is not empty.
+func renameFlagRefs(usage string, rename func(string) string) string {
+	var buf strings.Builder
+	for i := 0; i < len(usage); {
+		if usage[i] != '-' || (i > 0 && isNameByte(usage[i-1])) {
+			buf.WriteByte(usage[i])
+			i++
+			continue
+		}
+		dashes := 1
+		if i+1 < len(usage) && usage[i+1] == '-' {
+			dashes = 2
+		}
+		end := i + dashes
+		for end < len(usage) && isNameByte(usage[end]) {
+			end++
+		}
+		name := strings.TrimRight(usage[i+dashes:end], ".-")
+		if newName := rename(name); name != "" && newName != "" {
+			buf.WriteString(usage[i:i+dashes] + newName)
+			i += dashes + len(name)
+		} else {
+			buf.WriteString(usage[i:end])
+			i = max(end, i+dashes)
+		}
+	}
+	return buf.String()
+}
+
+// isNameByte reports whether c may occur in the name of a flag, as referred
+// to in a usage string.
+func isNameByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '_' || c == '-' || c == '.'
+}
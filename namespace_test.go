@@ -0,0 +1,105 @@
+package goflags
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+// registerDB defines flags as a library might.
+func registerDB(fs *flag.FlagSet) {
+	fs.String("addr", "localhost:5432", "Database address")
+	fs.Int("pool", 4, "Connection pool size, if -addr is remote")
+	fs.Bool("read-only", false, "Open read-only; ignores -pool")
+	Exclusive(fs, "pool", "read-only")
+	Section(fs, "Connection", "addr", "pool")
+}
+
+func TestNamespace(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	db := Namespace(fs, "db", registerDB)
+	cache := Namespace(fs, "cache", registerDB)
+
+	if err := fs.Parse([]string{"-db.addr", "db:1", "-cache.pool", "8"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	checkFlag := func(fs *flag.FlagSet, name, want string) {
+		t.Helper()
+		if got := fs.Lookup(name).Value.String(); got != want {
+			t.Errorf("Flag %s of %s: got %q, want %q", name, fs.Name(), got, want)
+		}
+	}
+	checkFlag(db, "addr", "db:1")
+	checkFlag(db, "pool", "4")
+	checkFlag(cache, "addr", "localhost:5432")
+	checkFlag(cache, "pool", "8")
+
+	if got, want := fs.Lookup("cache.pool").DefValue, "4"; got != want {
+		t.Errorf("Default of -cache.pool: got %q, want %q", got, want)
+	}
+	if got, want := fs.Lookup("db.pool").Usage, "Connection pool size, if -db.addr is remote"; got != want {
+		t.Errorf("Usage of -db.pool: got %q, want %q", got, want)
+	}
+	if got, want := fs.Lookup("db.read-only").Usage, "Open read-only; ignores -db.pool"; got != want {
+		t.Errorf("Usage of -db.read-only: got %q, want %q", got, want)
+	}
+
+	// The flag groups of the components apply to the prefixed flags.
+	if err := CheckGroups(fs); err != nil {
+		t.Errorf("CheckGroups: unexpected error: %v", err)
+	}
+	if err := fs.Parse([]string{"-db.pool=2", "-db.read-only"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	err := CheckGroups(fs)
+	if err == nil || !strings.Contains(err.Error(), "-db.pool and -db.read-only") {
+		t.Errorf("CheckGroups: got %v, want error for -db.pool and -db.read-only", err)
+	}
+
+	// The usage sections of the components are merged.
+	var buf strings.Builder
+	Usage{}.Write(&buf, fs)
+	if got, want := buf.String(), "\nConnection:\n  -db.addr string"; !strings.Contains(got, want) {
+		t.Errorf("Usage: got %q, want it to contain %q", got, want)
+	}
+}
+
+func TestEmbedCollision(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.String("db.addr", "", "Address")
+	defer func() {
+		if recover() == nil {
+			t.Error("Namespace did not panic for a duplicate flag")
+		}
+	}()
+	Namespace(fs, "db", registerDB)
+}
+
+func TestRenameFlagRefs(t *testing.T) {
+	rename := func(name string) string {
+		if name == "addr" || name == "pool" {
+			return "db." + name
+		}
+		return ""
+	}
+	tests := []struct {
+		input, want string
+	}{
+		{"", ""},
+		{"no references", "no references"},
+		{"see -addr", "see -db.addr"},
+		{"see --addr.", "see --db.addr."},
+		{"(-addr, -pool)", "(-db.addr, -db.pool)"},
+		{"-pool-size and -addrs", "-pool-size and -addrs"},
+		{"read-only or -other", "read-only or -other"},
+		{"a - b -- c -", "a - b -- c -"},
+	}
+	for _, tc := range tests {
+		if got := renameFlagRefs(tc.input, rename); got != tc.want {
+			t.Errorf("renameFlagRefs(%q): got %q, want %q", tc.input, got, tc.want)
+		}
+	}
+}
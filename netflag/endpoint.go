@@ -0,0 +1,90 @@
+package netflag
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// An Endpoint represents a network endpoint in "host:port" form. A pointer to
+// an Endpoint satisfies the flag.Value and flag.Getter interfaces.
+//
+// IPv6 addresses must be enclosed in brackets, e.g., "[::1]:8080". The host
+// may be empty, as in ":8080", unless RequireHost is set. If DefaultPort is
+// set, the port may be omitted, as in "example.com" or "[::1]".
+type Endpoint struct {
+	// If non-zero, the port to use when the flag argument has no port.
+	DefaultPort int
+
+	// If true, the host must be non-empty.
+	RequireHost bool
+
+	host string
+	port int
+	set  bool
+}
+
+// Host returns the host portion of the endpoint, without brackets.
+func (e *Endpoint) Host() string { return e.host }
+
+// Port returns the port of the endpoint.
+func (e *Endpoint) Port() int { return e.port }
+
+// Addr returns the address of the endpoint in "host:port" form, suitable for
+// use with net.Dial or net.Listen.
+func (e *Endpoint) Addr() string {
+	if !e.set {
+		return ""
+	}
+	return net.JoinHostPort(e.host, strconv.Itoa(e.port))
+}
+
+// String satisfies part of the flag.Value interface.
+func (e *Endpoint) String() string { return fmt.Sprintf("%q", e.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (e *Endpoint) ArgString() string { return e.Addr() }
+
+// Set satisfies part of the flag.Value interface.
+func (e *Endpoint) Set(s string) error {
+	host, port, err := e.split(s)
+	if err != nil {
+		return err
+	}
+	if e.RequireHost && host == "" {
+		return fmt.Errorf("netflag: missing host in %q", s)
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil || p < 0 || p > 65535 {
+		return fmt.Errorf("netflag: invalid port %q", port)
+	}
+	e.host, e.port, e.set = host, p, true
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is the string returned by Addr.
+func (e *Endpoint) Get() any { return e.Addr() }
+
+// split separates s into host and port, applying the default port if s does
+// not include one.
+func (e *Endpoint) split(s string) (host, port string, _ error) {
+	host, port, err := net.SplitHostPort(s)
+	if err == nil {
+		return host, port, nil
+	}
+	if e.DefaultPort == 0 || s == "" {
+		return "", "", fmt.Errorf("netflag: invalid endpoint %q", s)
+	}
+
+	// Without a port, the input is either a bracketed IPv6 address or a host
+	// name or IPv4 address without colons.
+	defPort := strconv.Itoa(e.DefaultPort)
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		return s[1 : len(s)-1], defPort, nil
+	} else if !strings.ContainsAny(s, ":[]") {
+		return s, defPort, nil
+	}
+	return "", "", fmt.Errorf("netflag: invalid endpoint %q", s)
+}
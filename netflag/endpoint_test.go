@@ -0,0 +1,73 @@
+package netflag
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestEndpoint(t *testing.T) {
+	var listen Endpoint
+	upstream := Endpoint{DefaultPort: 443, RequireHost: true}
+
+	fs := flag.NewFlagSet("endpoint", flag.PanicOnError)
+	fs.Var(&listen, "listen", "Listen address")
+	fs.Var(&upstream, "upstream", "Upstream server")
+
+	if got, want := listen.String(), `""`; got != want {
+		t.Errorf("Initial value for -listen: got %s, want %s", got, want)
+	}
+
+	if err := fs.Parse([]string{"-listen", ":8080", "-upstream", "[2001:db8::1]"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	if got, want := listen.Get().(string), ":8080"; got != want {
+		t.Errorf("Value for -listen: got %q, want %q", got, want)
+	}
+	if host, port := upstream.Host(), upstream.Port(); host != "2001:db8::1" || port != 443 {
+		t.Errorf("Value for -upstream: got host %q port %d, want 2001:db8::1 443", host, port)
+	}
+	if got, want := upstream.Addr(), "[2001:db8::1]:443"; got != want {
+		t.Errorf("Addr for -upstream: got %q, want %q", got, want)
+	}
+}
+
+func TestEndpointParse(t *testing.T) {
+	tests := []struct {
+		e    Endpoint
+		in   string
+		host string
+		port int
+		ok   bool
+	}{
+		{Endpoint{}, "localhost:80", "localhost", 80, true},
+		{Endpoint{}, "[::1]:0", "::1", 0, true},
+		{Endpoint{}, "1.2.3.4:65535", "1.2.3.4", 65535, true},
+		{Endpoint{DefaultPort: 53}, "ns1.example.com", "ns1.example.com", 53, true},
+		{Endpoint{DefaultPort: 53}, "10.0.0.1", "10.0.0.1", 53, true},
+		{Endpoint{DefaultPort: 53}, "10.0.0.1:5353", "10.0.0.1", 5353, true},
+
+		{Endpoint{}, "", "", 0, false},
+		{Endpoint{}, "localhost", "", 0, false},
+		{Endpoint{}, "localhost:http", "", 0, false},
+		{Endpoint{}, "localhost:65536", "", 0, false},
+		{Endpoint{}, "::1:80", "", 0, false},
+		{Endpoint{DefaultPort: 80}, "::1", "", 0, false},
+		{Endpoint{RequireHost: true}, ":80", "", 0, false},
+	}
+	for _, test := range tests {
+		err := test.e.Set(test.in)
+		if !test.ok {
+			if err == nil {
+				t.Errorf("Set(%q): got %q, wanted error", test.in, test.e.Addr())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Set(%q) failed: %v", test.in, err)
+		} else if test.e.Host() != test.host || test.e.Port() != test.port {
+			t.Errorf("Set(%q): got (%q, %d), want (%q, %d)",
+				test.in, test.e.Host(), test.e.Port(), test.host, test.port)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Package netflag defines flag.Value implementations for network addresses.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/netflag"
+//	)
+//
+//	var bindIP = netflag.IP{V4Only: true, NoUnspecified: true}
+//	func init() {
+//	  flag.Var(&bindIP, "bind-ip", bindIP.Help("Address to bind"))
+//	}
+package netflag
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+)
+
+// An IP represents a single IP address. A pointer to an IP satisfies the
+// flag.Value and flag.Getter interfaces. The constraints are checked when the
+// flag is set; the zero value accepts any valid IPv4 or IPv6 address.
+type IP struct {
+	// The address parsed from the flag. The zero value is invalid.
+	Addr netip.Addr
+
+	// If true, only IPv4 addresses are accepted. An IPv4-mapped IPv6 address
+	// such as "::ffff:1.2.3.4" is accepted and converted to IPv4.
+	V4Only bool
+
+	// If true, only IPv6 addresses are accepted. An IPv4-mapped IPv6 address
+	// is rejected, as it refers to an IPv4 address.
+	V6Only bool
+
+	// If true, the unspecified address ("0.0.0.0" or "::") is rejected.
+	NoUnspecified bool
+
+	// If true, loopback addresses ("127.0.0.0/8" or "::1") are rejected.
+	NoLoopback bool
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *IP) Help(h string) string {
+	switch {
+	case v.V4Only:
+		return h + " (IPv4 address)"
+	case v.V6Only:
+		return h + " (IPv6 address)"
+	}
+	return h + " (IP address)"
+}
+
+// IP returns the current address as a net.IP, or nil if no valid address has
+// been set.
+func (v *IP) IP() net.IP {
+	if !v.Addr.IsValid() {
+		return nil
+	}
+	return net.IP(v.Addr.AsSlice())
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *IP) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *IP) ArgString() string {
+	if !v.Addr.IsValid() {
+		return ""
+	}
+	return v.Addr.String()
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *IP) Set(s string) error {
+	a, err := netip.ParseAddr(s)
+	if err != nil {
+		return fmt.Errorf("netflag: invalid IP address %q", s)
+	}
+	if err := v.check(a); err != nil {
+		return err
+	}
+	if v.V4Only {
+		a = a.Unmap()
+	}
+	v.Addr = a
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type netip.Addr.
+func (v *IP) Get() any { return v.Addr }
+
+// check reports whether a satisfies the constraints of v. An IPv4-mapped IPv6
+// address, such as "::ffff:0.0.0.0", is checked as the IPv4 address it maps.
+func (v *IP) check(a netip.Addr) error {
+	u := a.Unmap()
+	switch {
+	case v.V4Only && !u.Is4():
+		return fmt.Errorf("netflag: %v is not an IPv4 address", a)
+	case v.V6Only && !u.Is6():
+		return fmt.Errorf("netflag: %v is not an IPv6 address", a)
+	case v.NoUnspecified && u.IsUnspecified():
+		return errors.New("netflag: unspecified address not allowed")
+	case v.NoLoopback && u.IsLoopback():
+		return errors.New("netflag: loopback address not allowed")
+	}
+	return nil
+}
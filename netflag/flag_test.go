@@ -0,0 +1,66 @@
+package netflag
+
+import (
+	"bytes"
+	"flag"
+	"net/netip"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var addr IP
+	bind := IP{V4Only: true, NoUnspecified: true}
+
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("ip", flag.PanicOnError)
+	fs.Var(&addr, "ip", addr.Help("Any address"))
+	fs.Var(&bind, "bind-ip", bind.Help("Address to bind"))
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+	t.Logf("IP flag set:\n%s", buf.String())
+
+	if ip := addr.IP(); ip != nil {
+		t.Errorf("Initial value for -ip: got %v, want nil", ip)
+	}
+
+	if err := fs.Parse([]string{"-ip", "2001:db8::1", "-bind-ip", "::ffff:10.0.0.1"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	if got, want := addr.Get().(netip.Addr), netip.MustParseAddr("2001:db8::1"); got != want {
+		t.Errorf("Value for -ip: got %v, want %v", got, want)
+	}
+	if got, want := bind.String(), `"10.0.0.1"`; got != want {
+		t.Errorf("Value for -bind-ip: got %s, want %s", got, want)
+	}
+	if got, want := bind.IP().String(), "10.0.0.1"; got != want {
+		t.Errorf("IP for -bind-ip: got %q, want %q", got, want)
+	}
+}
+
+func TestIPErrors(t *testing.T) {
+	tests := []struct {
+		v     IP
+		input string
+	}{
+		{IP{}, ""},
+		{IP{}, "1.2.3"},
+		{IP{}, "example.com"},
+		{IP{V4Only: true}, "::1"},
+		{IP{V6Only: true}, "1.2.3.4"},
+		{IP{NoUnspecified: true}, "0.0.0.0"},
+		{IP{NoUnspecified: true}, "::"},
+		{IP{NoUnspecified: true}, "::ffff:0.0.0.0"},
+		{IP{V4Only: true, NoUnspecified: true}, "::ffff:0.0.0.0"},
+		{IP{V6Only: true}, "::ffff:1.2.3.4"},
+		{IP{NoLoopback: true}, "127.0.0.53"},
+		{IP{NoLoopback: true}, "::1"},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.input); err == nil {
+			t.Errorf("Set(%q) with %+v: got %v, wanted error", test.input, test.v, test.v.Addr)
+		} else {
+			t.Logf("Set(%q) gave expected error: %v", test.input, err)
+		}
+	}
+}
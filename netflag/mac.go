@@ -0,0 +1,42 @@
+package netflag
+
+import (
+	"fmt"
+	"net"
+)
+
+// A MAC represents a hardware address. A pointer to a MAC satisfies the
+// flag.Value and flag.Getter interfaces.
+//
+// The flag argument may use any notation accepted by net.ParseMAC, e.g.,
+//
+//	00:00:5e:00:53:01
+//	00-00-5E-00-53-01
+//	0000.5e00.5301
+//
+// including the 8- and 20-octet EUI-64 and InfiniBand forms.
+type MAC struct {
+	// The address parsed from the flag, or nil if none has been set.
+	Addr net.HardwareAddr
+}
+
+// String satisfies part of the flag.Value interface.
+// The address is rendered in colon-separated lower-case hexadecimal.
+func (m *MAC) String() string { return fmt.Sprintf("%q", m.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (m *MAC) ArgString() string { return m.Addr.String() }
+
+// Set satisfies part of the flag.Value interface.
+func (m *MAC) Set(s string) error {
+	hw, err := net.ParseMAC(s)
+	if err != nil {
+		return fmt.Errorf("netflag: invalid hardware address %q", s)
+	}
+	m.Addr = hw
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type net.HardwareAddr.
+func (m *MAC) Get() any { return m.Addr }
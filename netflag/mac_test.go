@@ -0,0 +1,34 @@
+package netflag
+
+import (
+	"flag"
+	"net"
+	"testing"
+)
+
+func TestMAC(t *testing.T) {
+	var mac MAC
+
+	fs := flag.NewFlagSet("mac", flag.PanicOnError)
+	fs.Var(&mac, "mac", "Hardware address")
+
+	if got, want := mac.String(), `""`; got != want {
+		t.Errorf("Initial value for -mac: got %s, want %s", got, want)
+	}
+
+	const want = "00:00:5e:00:53:01"
+	for _, in := range []string{"00:00:5e:00:53:01", "00-00-5E-00-53-01", "0000.5e00.5301"} {
+		if err := fs.Parse([]string{"-mac", in}); err != nil {
+			t.Fatalf("Argument parsing failed: %v", err)
+		}
+		if got := mac.Get().(net.HardwareAddr).String(); got != want {
+			t.Errorf("Value for -mac %q: got %q, want %q", in, got, want)
+		}
+	}
+
+	for _, bad := range []string{"", "00:00:5e:00:53", "00:00:5e:00:53:zz"} {
+		if err := mac.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %v, wanted error", bad, mac.Addr)
+		}
+	}
+}
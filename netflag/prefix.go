@@ -0,0 +1,95 @@
+package netflag
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// Prefixes represents a list of IP network prefixes in CIDR notation, such as
+// "10.0.0.0/8". A pointer to a Prefixes satisfies the flag.Value and
+// flag.Getter interfaces.
+//
+// The flag argument is a comma-separated list of prefixes. A bare address is
+// accepted as a prefix containing only that address. Each occurrence of the
+// flag appends to the list, so that
+//
+//	-allow 10.0.0.0/8,192.168.0.0/16 -allow ::1
+//
+// yields three prefixes. Prefixes present before the first occurrence, such
+// as a default, are replaced rather than appended to.
+type Prefixes struct {
+	// The prefixes parsed from the flag, in order of occurrence.
+	Prefixes []netip.Prefix
+
+	set bool // Set has been called since the value was created or reset
+}
+
+// Contains reports whether addr is contained in any of the prefixes of p.
+func (p *Prefixes) Contains(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, pfx := range p.Prefixes {
+		if pfx.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// String satisfies part of the flag.Value interface.
+func (p *Prefixes) String() string { return fmt.Sprintf("%q", p.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (p *Prefixes) ArgString() string { return strings.Join(p.ArgStrings(), ",") }
+
+// ArgStrings satisfies the goflags.Repeatable interface. It reports each
+// prefix as a separate argument.
+func (p *Prefixes) ArgStrings() []string {
+	out := make([]string, len(p.Prefixes))
+	for i, pfx := range p.Prefixes {
+		out[i] = pfx.String()
+	}
+	return out
+}
+
+// Reset discards all the prefixes of p, including any default.
+func (p *Prefixes) Reset() { p.Prefixes, p.set = nil, true }
+
+// Set satisfies part of the flag.Value interface.
+func (p *Prefixes) Set(s string) error {
+	var out []netip.Prefix
+	for _, elt := range strings.Split(s, ",") {
+		pfx, err := parsePrefix(strings.TrimSpace(elt))
+		if err != nil {
+			return err
+		}
+		out = append(out, pfx)
+	}
+	if !p.set {
+		p.Prefixes, p.set = nil, true // discard the default
+	}
+	p.Prefixes = append(p.Prefixes, out...)
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type []netip.Prefix.
+func (p *Prefixes) Get() any { return p.Prefixes }
+
+// parsePrefix parses s as a CIDR prefix or a single address. The result is
+// masked, so that "10.1.2.3/8" is reported as "10.0.0.0/8".
+func parsePrefix(s string) (netip.Prefix, error) {
+	if !strings.Contains(s, "/") {
+		a, err := netip.ParseAddr(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("netflag: invalid prefix %q", s)
+		}
+		a = a.Unmap()
+		return netip.PrefixFrom(a, a.BitLen()), nil
+	}
+	pfx, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("netflag: invalid prefix %q", s)
+	}
+	return pfx.Masked(), nil
+}
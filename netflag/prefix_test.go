@@ -0,0 +1,88 @@
+package netflag
+
+import (
+	"flag"
+	"net/netip"
+	"testing"
+)
+
+func TestPrefixes(t *testing.T) {
+	var allow Prefixes
+
+	fs := flag.NewFlagSet("prefix", flag.PanicOnError)
+	fs.Var(&allow, "allow", "Allowed networks")
+
+	if got, want := allow.String(), `""`; got != want {
+		t.Errorf("Initial value for -allow: got %s, want %s", got, want)
+	}
+
+	if err := fs.Parse([]string{"-allow", "10.1.2.3/8, 192.168.0.0/16", "-allow", "::1"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	if got, want := allow.String(), `"10.0.0.0/8,192.168.0.0/16,::1/128"`; got != want {
+		t.Errorf("Value for -allow: got %s, want %s", got, want)
+	}
+	if got := len(allow.Get().([]netip.Prefix)); got != 3 {
+		t.Errorf("Prefix count for -allow: got %d, want 3", got)
+	}
+
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"10.99.0.1", true},
+		{"192.168.4.4", true},
+		{"::ffff:10.0.0.1", true},
+		{"::1", true},
+		{"11.0.0.1", false},
+		{"::2", false},
+	}
+	for _, test := range tests {
+		if got := allow.Contains(netip.MustParseAddr(test.addr)); got != test.want {
+			t.Errorf("Contains(%s): got %v, want %v", test.addr, got, test.want)
+		}
+	}
+}
+
+func TestPrefixErrors(t *testing.T) {
+	for _, bad := range []string{"", "10.0.0.0/33", "10.0.0/8", "1.2.3.4,", "host/24"} {
+		var p Prefixes
+		if err := p.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %v, wanted error", bad, p.Prefixes)
+		} else {
+			t.Logf("Set(%q) gave expected error: %v", bad, err)
+		}
+	}
+}
+
+func TestPrefixesDefault(t *testing.T) {
+	allow := Prefixes{Prefixes: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}}
+
+	fs := flag.NewFlagSet("prefix", flag.PanicOnError)
+	fs.Var(&allow, "allow", "Allowed networks")
+
+	if got, want := allow.String(), `"127.0.0.0/8"`; got != want {
+		t.Errorf("Default for -allow: got %s, want %s", got, want)
+	}
+	if err := fs.Parse([]string{"-allow", "10.0.0.0/8", "-allow", "::1"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := allow.String(), `"10.0.0.0/8,::1/128"`; got != want {
+		t.Errorf("Value for -allow: got %s, want %s", got, want)
+	}
+	if allow.Contains(netip.MustParseAddr("127.0.0.1")) {
+		t.Error("Contains(127.0.0.1): default prefix was not replaced")
+	}
+
+	allow.Reset()
+	if err := allow.Set("192.168.0.0/16"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := allow.Set("::1"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if got, want := allow.String(), `"192.168.0.0/16,::1/128"`; got != want {
+		t.Errorf("Value after Reset: got %s, want %s", got, want)
+	}
+}
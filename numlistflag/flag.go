@@ -0,0 +1,135 @@
+// Package numlistflag defines generic flag.Value implementations for lists of
+// numbers given as comma-separated strings.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/numlistflag"
+//	)
+//
+//	var buckets = numlistflag.Float64s{Min: 0, Max: 60, Increasing: true}
+//	var ports numlistflag.Ints
+//	func init() {
+//	  flag.Var(&buckets, "buckets", buckets.Help("Histogram bucket bounds (seconds)"))
+//	  flag.Var(&ports, "ports", ports.Help("Ports to probe"))
+//	}
+//
+// With this definition, "-buckets 0.1,0.5,1,5" sets buckets.Values to the
+// four given bounds. Each occurrence of the flag replaces the list.
+package numlistflag
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Number is the set of element types supported by Value.
+type Number interface{ int | int64 | float64 }
+
+// A Value represents a list of numbers of type T. A pointer to a Value
+// satisfies the flag.Value and flag.Getter interfaces.
+type Value[T Number] struct {
+	// The numbers parsed from the flag.
+	Values []T
+
+	// If Max > Min, each element must lie in the closed interval [Min, Max].
+	// Otherwise any element is accepted.
+	Min, Max T
+
+	// If true, the elements must be strictly increasing.
+	Increasing bool
+}
+
+// Ints is a list of int values.
+type Ints = Value[int]
+
+// Int64s is a list of int64 values.
+type Int64s = Value[int64]
+
+// Float64s is a list of float64 values.
+type Float64s = Value[float64]
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value[T]) Help(h string) string {
+	var extra string
+	if v.Max > v.Min {
+		extra = fmt.Sprintf(", each in [%v, %v]", v.Min, v.Max)
+	}
+	if v.Increasing {
+		extra += ", increasing"
+	}
+	return fmt.Sprintf("%s (comma-separated numbers%s)", h, extra)
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value[T]) String() string {
+	parts := make([]string, len(v.Values))
+	for i, n := range v.Values {
+		parts[i] = format(n)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value[T]) Set(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return errors.New("numlistflag: empty list")
+	}
+	var vals []T
+	for i, elt := range strings.Split(s, ",") {
+		n, err := parse[T](strings.TrimSpace(elt))
+		if err != nil {
+			return err
+		}
+		if v.Max > v.Min && (n < v.Min || n > v.Max) {
+			return fmt.Errorf("numlistflag: element %v out of range [%v, %v]", format(n), v.Min, v.Max)
+		}
+		if v.Increasing && i > 0 && n <= vals[i-1] {
+			return fmt.Errorf("numlistflag: element %v does not exceed %v", format(n), format(vals[i-1]))
+		}
+		vals = append(vals, n)
+	}
+	v.Values = vals
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type []T.
+func (v *Value[T]) Get() any { return v.Values }
+
+func parse[T Number](s string) (T, error) {
+	var zero T
+	switch any(zero).(type) {
+	case int:
+		n, err := strconv.ParseInt(s, 0, strconv.IntSize)
+		if err != nil {
+			return zero, fmt.Errorf("numlistflag: invalid integer %q", s)
+		}
+		return T(n), nil
+	case int64:
+		n, err := strconv.ParseInt(s, 0, 64)
+		if err != nil {
+			return zero, fmt.Errorf("numlistflag: invalid integer %q", s)
+		}
+		return T(n), nil
+	default:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(f) {
+			return zero, fmt.Errorf("numlistflag: invalid number %q", s)
+		}
+		return T(f), nil
+	}
+}
+
+func format[T Number](n T) string {
+	if f, ok := any(n).(float64); ok {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return strconv.FormatInt(int64(n), 10)
+}
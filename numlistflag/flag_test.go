@@ -0,0 +1,66 @@
+package numlistflag
+
+import (
+	"flag"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	buckets := Float64s{Min: 0, Max: 60, Increasing: true}
+	var ports Ints
+	var ids Int64s
+
+	fs := flag.NewFlagSet("numlist", flag.ContinueOnError)
+	fs.Var(&buckets, "buckets", buckets.Help("Bucket bounds"))
+	fs.Var(&ports, "ports", ports.Help("Ports"))
+	fs.Var(&ids, "ids", ids.Help("IDs"))
+
+	if err := fs.Parse([]string{
+		"-buckets", "0.1, 0.5,1,5e1",
+		"-ports", "1,2",
+		"-ports", "80,0x1bb,80",
+		"-ids", "-9223372036854775808,9223372036854775807",
+	}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	if got, want := buckets.Get().([]float64), []float64{0.1, 0.5, 1, 50}; !slices.Equal(got, want) {
+		t.Errorf("Value for -buckets: got %v, want %v", got, want)
+	}
+	if got, want := ports.Values, []int{80, 443, 80}; !slices.Equal(got, want) {
+		t.Errorf("Value for -ports: got %v, want %v", got, want)
+	}
+	if got, want := ids.Values, []int64{math.MinInt64, math.MaxInt64}; !slices.Equal(got, want) {
+		t.Errorf("Value for -ids: got %v, want %v", got, want)
+	}
+	if got, want := buckets.String(), "0.1,0.5,1,50"; got != want {
+		t.Errorf("String for -buckets: got %q, want %q", got, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, bad := range []string{"", "1,,2", "1.5", "x", "99999999999999999999"} {
+		var v Ints
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Ints.Set(%q): got %v, wanted error", bad, v.Values)
+		}
+	}
+	tests := []struct {
+		v     Float64s
+		input string
+	}{
+		{Float64s{}, "NaN"},
+		{Float64s{}, "1,x"},
+		{Float64s{Min: 0, Max: 1}, "0.5,1.5"},
+		{Float64s{Min: 0, Max: 1}, "-0.1"},
+		{Float64s{Increasing: true}, "1,2,2"},
+		{Float64s{Increasing: true}, "3,1"},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.input); err == nil {
+			t.Errorf("Set(%q) with %+v: got %v, wanted error", test.input, test.v, test.v.Values)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Package optionflag defines a generic flag.Value implementation that
+// distinguishes a flag that was not given from one set to the zero value.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/optionflag"
+//	)
+//
+//	var (
+//	  workers = optionflag.Int()
+//	  verbose = optionflag.Bool()
+//	)
+//	func init() {
+//	  flag.Var(workers, "workers", "Number of workers (overrides config)")
+//	  flag.Var(verbose, "v", "Verbose logging (overrides config)")
+//	}
+//
+//	  ...
+//	  if n, ok := workers.Get(); ok {
+//	    cfg.Workers = n // only if -workers was given, even if "-workers 0"
+//	  }
+//	  cfg.Verbose = verbose.OrElse(cfg.Verbose)
+package optionflag
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// An Optional records a value of type T and whether it was set. A pointer to
+// an Optional satisfies the flag.Value interface.
+//
+// Note that Get returns (T, bool), so an Optional does not satisfy the
+// flag.Getter interface; use Value to obtain the value as an any.
+type Optional[T any] struct {
+	parse  func(string) (T, error)
+	isBool bool
+	value  T
+	set    bool
+}
+
+// New returns an unset *Optional that uses parse to convert flag arguments.
+func New[T any](parse func(string) (T, error)) *Optional[T] {
+	return &Optional[T]{parse: parse}
+}
+
+// Bool returns an unset *Optional for a boolean flag. As with flag.Bool, the
+// flag may be given without an argument to set it true.
+func Bool() *Optional[bool] {
+	return &Optional[bool]{parse: strconv.ParseBool, isBool: true}
+}
+
+// Int returns an unset *Optional for an integer flag.
+func Int() *Optional[int] { return New(strconv.Atoi) }
+
+// Float64 returns an unset *Optional for a floating-point flag.
+func Float64() *Optional[float64] {
+	return New(func(s string) (float64, error) { return strconv.ParseFloat(s, 64) })
+}
+
+// String returns an unset *Optional for a string flag.
+func String() *Optional[string] {
+	return New(func(s string) (string, error) { return s, nil })
+}
+
+// Duration returns an unset *Optional for a time.Duration flag.
+func Duration() *Optional[time.Duration] { return New(time.ParseDuration) }
+
+// Get returns the value of o and reports whether it was set. If o was not
+// set, Get returns the zero value of T and false.
+func (o *Optional[T]) Get() (T, bool) { return o.value, o.set }
+
+// OrElse returns the value of o if it was set, otherwise it returns d.
+func (o *Optional[T]) OrElse(d T) T {
+	if o.set {
+		return o.value
+	}
+	return d
+}
+
+// IsSet reports whether o was set.
+func (o *Optional[T]) IsSet() bool { return o.set }
+
+// Value returns the value of o if it was set, otherwise nil.
+func (o *Optional[T]) Value() any {
+	if o.set {
+		return o.value
+	}
+	return nil
+}
+
+// Reset returns o to the unset state.
+func (o *Optional[T]) Reset() {
+	var zero T
+	o.value, o.set = zero, false
+}
+
+// IsBoolFlag reports whether o was constructed by Bool, so that the flag
+// package allows it to be given without an argument.
+func (o *Optional[T]) IsBoolFlag() bool { return o.isBool }
+
+// String satisfies part of the flag.Value interface.
+// It returns "" if o is not set.
+func (o *Optional[T]) String() string {
+	if o == nil || !o.set {
+		return ""
+	}
+	return fmt.Sprint(o.value)
+}
+
+// Set satisfies part of the flag.Value interface.
+func (o *Optional[T]) Set(s string) error {
+	v, err := o.parse(s)
+	if err != nil {
+		return err
+	}
+	o.value, o.set = v, true
+	return nil
+}
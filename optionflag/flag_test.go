@@ -0,0 +1,58 @@
+package optionflag
+
+import (
+	"flag"
+	"testing"
+	"time"
+)
+
+func TestFlagBits(t *testing.T) {
+	workers := Int()
+	verbose := Bool()
+	name := String()
+	timeout := Duration()
+	ratio := Float64()
+
+	fs := flag.NewFlagSet("option", flag.ContinueOnError)
+	fs.Var(workers, "workers", "Workers")
+	fs.Var(verbose, "v", "Verbose")
+	fs.Var(name, "name", "Name")
+	fs.Var(timeout, "timeout", "Timeout")
+	fs.Var(ratio, "ratio", "Ratio")
+
+	if err := fs.Parse([]string{"-workers", "0", "-v", "-name", ""}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	if n, ok := workers.Get(); n != 0 || !ok {
+		t.Errorf("Value for -workers: got %d, %v; want 0, true", n, ok)
+	}
+	if got := workers.OrElse(8); got != 0 {
+		t.Errorf("OrElse for -workers: got %d, want 0", got)
+	}
+	if got := verbose.OrElse(false); !got {
+		t.Error("Value for -v: got false, want true")
+	}
+	if s, ok := name.Get(); s != "" || !ok {
+		t.Errorf("Value for -name: got %q, %v; want empty, true", s, ok)
+	}
+	if d, ok := timeout.Get(); d != 0 || ok {
+		t.Errorf("Value for -timeout: got %v, %v; want 0, false", d, ok)
+	}
+	if got := timeout.OrElse(time.Second); got != time.Second {
+		t.Errorf("OrElse for -timeout: got %v, want 1s", got)
+	}
+	if ratio.IsSet() || ratio.Value() != nil || ratio.String() != "" {
+		t.Errorf("Unset -ratio: got %v", ratio.Value())
+	}
+
+	workers.Reset()
+	if _, ok := workers.Get(); ok {
+		t.Error("After Reset, -workers is still set")
+	}
+	if err := fs.Parse([]string{"-workers", "x"}); err == nil {
+		t.Error("Invalid -workers was accepted")
+	} else if workers.IsSet() {
+		t.Error("Failed Set marked -workers as set")
+	}
+}
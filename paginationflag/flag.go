@@ -0,0 +1,219 @@
+// Package paginationflag defines flag.Value implementations for selecting a
+// page of results, either by page number and size or by limit and cursor.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/paginationflag"
+//	)
+//
+//	var pager = paginationflag.Value{DefaultLimit: 20, MaxLimit: 500}
+//	func init() { pager.Define(flag.CommandLine) }
+//
+// This defines the flags -page, -limit, -offset, and -cursor, which share the
+// state of pager. With this definition "-page 3/50" selects 50 results
+// starting at offset 100, "-page 3" uses the default page size, and
+// "-limit 50 -cursor abc" selects 50 results following the cursor "abc".
+// After parsing, call Check to validate the combination of flags and obtain
+// the result:
+//
+//	flag.Parse()
+//	page, err := pager.Check()
+package paginationflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// A Page describes a page of results. Exactly one of Offset and Cursor is
+// meaningful for a given request; the other has its zero value.
+type Page struct {
+	Limit  int    // the maximum number of results
+	Offset int    // the number of results to skip
+	Cursor string // an opaque continuation token
+}
+
+// Number returns the 1-based page number selected by p, based on its Offset
+// and Limit. It returns 0 if p.Limit is not positive.
+func (p Page) Number() int {
+	if p.Limit <= 0 {
+		return 0
+	}
+	return p.Offset/p.Limit + 1
+}
+
+// String renders p in a human-readable format.
+func (p Page) String() string {
+	if p.Cursor != "" {
+		return fmt.Sprintf("limit=%d cursor=%q", p.Limit, p.Cursor)
+	}
+	return fmt.Sprintf("limit=%d offset=%d", p.Limit, p.Offset)
+}
+
+// A Value represents a page selection. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces for the "N[/SIZE]" page syntax. The
+// Limit, Offset, and Cursor methods return flag.Value implementations that
+// share the state of v, for the other styles.
+type Value struct {
+	// The page selected by the flags.
+	Page Page
+
+	// If positive, the limit used when none is given.
+	DefaultLimit int
+
+	// If positive, the maximum limit or page size allowed.
+	MaxLimit int
+
+	hasPage, hasLimit, hasOffset, hasCursor bool
+}
+
+// Define registers flags named "page", "limit", "offset", and "cursor" in fs,
+// all sharing the state of v.
+func (v *Value) Define(fs *flag.FlagSet) {
+	fs.Var(v, "page", v.Help("Page number"))
+	fs.Var(v.Limit(), "limit", "Maximum number of results")
+	fs.Var(v.Offset(), "offset", "Number of results to skip")
+	fs.Var(v.Cursor(), "cursor", "Continuation token from a previous page")
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	if v.MaxLimit > 0 {
+		return fmt.Sprintf("%s (N[/SIZE], SIZE at most %d)", h, v.MaxLimit)
+	}
+	return h + " (N[/SIZE])"
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string {
+	if !v.hasPage {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", v.Page.Number(), v.Page.Limit)
+}
+
+// Set satisfies part of the flag.Value interface. It accepts a 1-based page
+// number, optionally followed by "/" and a page size.
+func (v *Value) Set(s string) error {
+	num, size, hasSize := strings.Cut(s, "/")
+	n, err := strconv.Atoi(num)
+	if err != nil || n < 1 {
+		return fmt.Errorf("paginationflag: invalid page number %q", num)
+	}
+	limit := v.Page.Limit
+	if hasSize {
+		if limit, err = v.parseLimit(size); err != nil {
+			return err
+		}
+	} else if limit <= 0 {
+		if limit = v.DefaultLimit; limit <= 0 {
+			return fmt.Errorf("paginationflag: page %q needs a size", s)
+		}
+	}
+	if n-1 > math.MaxInt/limit {
+		return fmt.Errorf("paginationflag: page %d is out of range", n)
+	}
+	v.Page.Limit, v.Page.Offset = limit, (n-1)*limit
+	v.hasPage = true
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Page.
+func (v *Value) Get() any { return v.Page }
+
+// Limit returns a flag.Value that sets the limit of v.
+func (v *Value) Limit() flag.Value {
+	return field{v, func() string { return strconv.Itoa(v.Page.Limit) }, func(s string) error {
+		n, err := v.parseLimit(s)
+		if err != nil {
+			return err
+		}
+		if v.hasPage && n != v.Page.Limit {
+			// Keep the page number stable if -page was given first.
+			v.Page.Offset = (v.Page.Number() - 1) * n
+		}
+		v.Page.Limit, v.hasLimit = n, true
+		return nil
+	}}
+}
+
+// Offset returns a flag.Value that sets the offset of v.
+func (v *Value) Offset() flag.Value {
+	return field{v, func() string { return strconv.Itoa(v.Page.Offset) }, func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return fmt.Errorf("paginationflag: invalid offset %q", s)
+		}
+		v.Page.Offset, v.hasOffset = n, true
+		return nil
+	}}
+}
+
+// Cursor returns a flag.Value that sets the cursor of v.
+func (v *Value) Cursor() flag.Value {
+	return field{v, func() string { return fmt.Sprintf("%q", v.Page.Cursor) }, func(s string) error {
+		if s == "" {
+			return errors.New("paginationflag: empty cursor")
+		}
+		v.Page.Cursor, v.hasCursor = s, true
+		return nil
+	}}
+}
+
+// Check reports whether the flags given for v are consistent, and returns the
+// selected page. A cursor may not be combined with a page number or offset,
+// and a page number may not be combined with an offset. If no limit was
+// given, DefaultLimit is used.
+func (v *Value) Check() (Page, error) {
+	switch {
+	case v.hasCursor && (v.hasPage || v.hasOffset):
+		return Page{}, errors.New("paginationflag: cursor cannot be combined with page or offset")
+	case v.hasPage && v.hasOffset:
+		return Page{}, errors.New("paginationflag: page cannot be combined with offset")
+	}
+	p := v.Page
+	if p.Limit <= 0 {
+		p.Limit = v.DefaultLimit
+	}
+	if p.Limit <= 0 {
+		return Page{}, errors.New("paginationflag: no limit specified")
+	}
+	return p, nil
+}
+
+func (v *Value) parseLimit(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("paginationflag: invalid limit %q", s)
+	}
+	if v.MaxLimit > 0 && n > v.MaxLimit {
+		return 0, fmt.Errorf("paginationflag: limit %d exceeds maximum %d", n, v.MaxLimit)
+	}
+	return n, nil
+}
+
+// field is a flag.Value that updates part of a Value.
+type field struct {
+	v   *Value
+	str func() string
+	set func(string) error
+}
+
+func (f field) String() string {
+	if f.v == nil {
+		return "" // the zero value, as used by flag.PrintDefaults
+	}
+	return f.str()
+}
+
+func (f field) Set(s string) error { return f.set(s) }
+func (f field) Get() any           { return f.v.Page }
@@ -0,0 +1,81 @@
+package paginationflag
+
+import (
+	"flag"
+	"io"
+	"testing"
+)
+
+func parse(t *testing.T, v *Value, args ...string) (Page, error) {
+	t.Helper()
+	fs := flag.NewFlagSet("page", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	v.Define(fs)
+	if err := fs.Parse(args); err != nil {
+		return Page{}, err
+	}
+	return v.Check()
+}
+
+func TestFlagBits(t *testing.T) {
+	tests := []struct {
+		args []string
+		want Page
+	}{
+		{nil, Page{Limit: 20}},
+		{[]string{"-page", "3/50"}, Page{Limit: 50, Offset: 100}},
+		{[]string{"-page", "3"}, Page{Limit: 20, Offset: 40}},
+		{[]string{"-page", "2", "-limit", "10"}, Page{Limit: 10, Offset: 10}},
+		{[]string{"-limit", "10", "-page", "2"}, Page{Limit: 10, Offset: 10}},
+		{[]string{"-limit", "50", "-cursor", "abc"}, Page{Limit: 50, Cursor: "abc"}},
+		{[]string{"-offset", "7"}, Page{Limit: 20, Offset: 7}},
+	}
+	for _, test := range tests {
+		v := Value{DefaultLimit: 20, MaxLimit: 100}
+		got, err := parse(t, &v, test.args...)
+		if err != nil {
+			t.Errorf("Parse %q: unexpected error: %v", test.args, err)
+		} else if got != test.want {
+			t.Errorf("Parse %q: got %+v, want %+v", test.args, got, test.want)
+		}
+	}
+
+	v := Value{DefaultLimit: 20}
+	if _, err := parse(t, &v, "-page", "4/25"); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got, want := v.String(), "4/25"; got != want {
+		t.Errorf("String: got %q, want %q", got, want)
+	}
+	if got, want := v.Get().(Page).Number(), 4; got != want {
+		t.Errorf("Number: got %d, want %d", got, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := [][]string{
+		{"-page", "0"},
+		{"-page", "x/10"},
+		{"-page", "2/0"},
+		{"-page", "2/1000"},
+		{"-limit", "-1"},
+		{"-limit", "101"},
+		{"-offset", "-5"},
+		{"-cursor", ""},
+		{"-page", "2", "-cursor", "abc"},
+		{"-offset", "2", "-cursor", "abc"},
+		{"-page", "2", "-offset", "5"},
+		{"-page", "9223372036854775807/100"},
+	}
+	for _, args := range tests {
+		v := Value{DefaultLimit: 20, MaxLimit: 100}
+		if got, err := parse(t, &v, args...); err == nil {
+			t.Errorf("Parse %q: got %+v, wanted error", args, got)
+		}
+	}
+
+	var v Value
+	if got, err := parse(t, &v, "-cursor", "abc"); err == nil {
+		t.Errorf("Parse without limit: got %+v, wanted error", got)
+	}
+}
@@ -0,0 +1,81 @@
+// Package pathflag defines a flag.Value implementation for lists of paths
+// separated by the OS path list separator, in the style of $PATH.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/pathflag"
+//	)
+//
+//	var pluginPath = pathflag.Value{MustExist: true, Dedup: true}
+//	func init() {
+//	  flag.Var(&pluginPath, "plugin-path", "Directories to search for plugins")
+//	}
+//
+// On Unix-like systems "-plugin-path /usr/lib/x:/opt/x" yields the two elements
+// "/usr/lib/x" and "/opt/x". On Windows the separator is ";".
+package pathflag
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// A Value represents a list of paths. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces. Empty elements of the list are
+// discarded. Setting the flag replaces any previous contents.
+type Value struct {
+	// The paths parsed from the flag, in order.
+	Paths []string
+
+	// If true, each element of the list must name an existing file or
+	// directory.
+	MustExist bool
+
+	// If true, duplicate elements are removed, keeping the first occurrence.
+	// Paths are compared after cleaning, so "a/b" and "a//b/" are duplicates.
+	Dedup bool
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string {
+	return strings.Join(v.Paths, string(filepath.ListSeparator))
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, elt := range filepath.SplitList(s) {
+		if elt == "" {
+			continue
+		}
+		if v.MustExist {
+			if _, err := os.Stat(elt); err != nil {
+				return fmt.Errorf("pathflag: %q does not exist", elt)
+			}
+		}
+		if v.Dedup {
+			key := filepath.Clean(elt)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		paths = append(paths, elt)
+	}
+	v.Paths = paths
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type []string.
+func (v *Value) Get() any { return slices.Clone(v.Paths) }
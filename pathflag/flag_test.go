@@ -0,0 +1,56 @@
+package pathflag
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func join(elts ...string) string { return strings.Join(elts, string(filepath.ListSeparator)) }
+
+func TestFlagBits(t *testing.T) {
+	var search Value
+	dedup := Value{Dedup: true}
+
+	fs := flag.NewFlagSet("path", flag.ContinueOnError)
+	fs.Var(&search, "search", "Search path")
+	fs.Var(&dedup, "dedup", "Search path without duplicates")
+
+	if err := fs.Parse([]string{
+		"-search", join("a", "", "b", "a"),
+		"-dedup", join("a", "b", "a/", "./b", "c"),
+	}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	if got, want := search.Get().([]string), []string{"a", "b", "a"}; !slices.Equal(got, want) {
+		t.Errorf("Value for -search: got %q, want %q", got, want)
+	}
+	if got, want := dedup.Paths, []string{"a", "b", "c"}; !slices.Equal(got, want) {
+		t.Errorf("Value for -dedup: got %q, want %q", got, want)
+	}
+	if got, want := dedup.String(), `"`+join("a", "b", "c")+`"`; got != want {
+		t.Errorf("String for -dedup: got %s, want %s", got, want)
+	}
+}
+
+func TestMustExist(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatalf("Mkdir: %v", err)
+	}
+
+	v := Value{MustExist: true}
+	if err := v.Set(join(dir, sub)); err != nil {
+		t.Errorf("Set existing paths failed: %v", err)
+	}
+	if err := v.Set(join(dir, filepath.Join(dir, "missing"))); err == nil {
+		t.Errorf("Set missing path: got %q, wanted error", v.Paths)
+	} else {
+		t.Logf("Set missing path gave expected error: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+// Package percentflag defines a flag.Value implementation for percentages,
+// normalized to a fraction in the interval [0, 1].
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/percentflag"
+//	)
+//
+//	var sampleRate = percentflag.Value{Fraction: 0.1}
+//	func init() {
+//	  flag.Var(&sampleRate, "sample-rate", sampleRate.Help("Fraction of requests to trace"))
+//	}
+//
+// The flag accepts a number with a "%" suffix, e.g., "75%", or a bare number.
+// By default a bare number is a fraction, so that "0.75" is equivalent to
+// "75%". If BareIsPercent is true, a bare number is a percentage instead, so
+// that "75" is equivalent to "75%".
+package percentflag
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// A Value represents a percentage. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The value parsed from the flag, as a fraction in [0, 1].
+	Fraction float64
+
+	// If true, a number without a "%" suffix is interpreted as a percentage
+	// rather than as a fraction.
+	BareIsPercent bool
+
+	// If Max > Min, the fraction must lie in the closed interval [Min, Max],
+	// which should be a subset of [0, 1]. Otherwise any fraction in [0, 1] is
+	// accepted.
+	Min, Max float64
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	lo, hi := v.bounds()
+	return fmt.Sprintf("%s (%s-%s)", h, formatPercent(lo), formatPercent(hi))
+}
+
+// Percent returns the value as a percentage in [0, 100].
+func (v *Value) Percent() float64 { return v.Fraction * 100 }
+
+// String satisfies part of the flag.Value interface.
+// The value is rendered as a percentage with a "%" suffix.
+func (v *Value) String() string { return formatPercent(v.Fraction) }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	t := strings.TrimSpace(s)
+	num, isPct := strings.CutSuffix(t, "%")
+	f, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("percentflag: invalid percentage %q", s)
+	}
+	if isPct || v.BareIsPercent {
+		f /= 100
+	}
+	if lo, hi := v.bounds(); f < lo || f > hi {
+		return fmt.Errorf("percentflag: %q is outside the range %s-%s",
+			s, formatPercent(lo), formatPercent(hi))
+	}
+	v.Fraction = f
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is the float64 fraction.
+func (v *Value) Get() any { return v.Fraction }
+
+func (v *Value) bounds() (lo, hi float64) {
+	if v.Max > v.Min {
+		return v.Min, v.Max
+	}
+	return 0, 1
+}
+
+// formatPercent renders the fraction f as a percentage. The percentage is
+// rounded to 12 significant digits, to remove the error of scaling a binary
+// fraction, so that 0.07 is rendered as "7%" rather than "7.000000000000001%".
+func formatPercent(f float64) string {
+	p, _ := strconv.ParseFloat(strconv.FormatFloat(f*100, 'g', 12, 64), 64)
+	return strconv.FormatFloat(p, 'f', -1, 64) + "%"
+}
@@ -0,0 +1,85 @@
+package percentflag
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	rate := Value{Fraction: 0.1}
+	target := Value{BareIsPercent: true, Min: 0.5, Max: 0.9}
+
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("percent", flag.ContinueOnError)
+	fs.Var(&rate, "rate", rate.Help("Sampling rate"))
+	fs.Var(&target, "target", target.Help("Utilization target"))
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+	t.Logf("Percent flag set:\n%s", buf.String())
+
+	if got, want := rate.String(), "10%"; got != want {
+		t.Errorf("Initial value for -rate: got %q, want %q", got, want)
+	}
+	if err := fs.Parse([]string{"-rate", "0.25", "-target", "75"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got := rate.Get().(float64); got != 0.25 {
+		t.Errorf("Value for -rate: got %v, want 0.25", got)
+	}
+	if got := target.Percent(); got != 75 {
+		t.Errorf("Value for -target: got %v%%, want 75%%", got)
+	}
+	if err := fs.Parse([]string{"-target", "95%"}); err == nil {
+		t.Errorf("Parse out-of-range -target: got %v, wanted error", target.String())
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		v    Value
+		in   string
+		want float64
+	}{
+		{Value{}, "0", 0},
+		{Value{}, "1", 1},
+		{Value{}, "0.75", 0.75},
+		{Value{}, "75%", 0.75},
+		{Value{}, " 12.5 % ", 0.125},
+		{Value{}, "100%", 1},
+		{Value{BareIsPercent: true}, "75", 0.75},
+		{Value{BareIsPercent: true}, "0.5%", 0.005},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.in); err != nil {
+			t.Errorf("Set(%q) failed: %v", test.in, err)
+		} else if test.v.Fraction != test.want {
+			t.Errorf("Set(%q): got %v, want %v", test.in, test.v.Fraction, test.want)
+		}
+	}
+
+	for _, bad := range []string{"", "%", "abc", "1.5", "-1%", "101%", "NaN", "Inf%"} {
+		var v Value
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %v, wanted error", bad, v.Fraction)
+		} else {
+			t.Logf("Set(%q) gave expected error: %v", bad, err)
+		}
+	}
+}
+
+func TestStringRoundTrip(t *testing.T) {
+	for _, in := range []string{"7%", "29%", "0.1%", "12.5%", "57%", "99.99%", "100%"} {
+		var v Value
+		if err := v.Set(in); err != nil {
+			t.Fatalf("Set(%q) failed: %v", in, err)
+		}
+		if got := v.String(); got != in {
+			t.Errorf("String after Set(%q): got %q", in, got)
+		}
+	}
+	v := Value{Min: 0.01, Max: 0.07}
+	if got, want := v.Help("Rate"), "Rate (1%-7%)"; got != want {
+		t.Errorf("Help: got %q, want %q", got, want)
+	}
+}
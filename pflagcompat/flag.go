@@ -0,0 +1,221 @@
+// Package pflagcompat adapts the flag values of this module to the interfaces
+// of the github.com/spf13/pflag package, as used by cobra, so that the same
+// value definitions work with both the standard flag package and pflag.
+//
+// The adapters satisfy the pflag interfaces structurally, so this package
+// does not depend on pflag.
+//
+// Example:
+//
+//	import (
+//	  "github.com/creachadair/goflags/pflagcompat"
+//	  "github.com/creachadair/goflags/sizeflag"
+//	  "github.com/spf13/cobra"
+//	)
+//
+//	var cmd = &cobra.Command{Use: "serve"}
+//	var cache = sizeflag.Base2(64 << 20)
+//	func init() {
+//	  cmd.Flags().Var(pflagcompat.Wrap(cache), "cache-size", "Cache size")
+//	}
+//
+// Register copies all the flags of a flag.FlagSet, so that a set of flags
+// defined for the standard flag package can be added to a cobra command:
+//
+//	pflagcompat.Register(fs, func(name, usage string, v pflagcompat.Typed) {
+//	  cmd.Flags().Var(v, name, usage)
+//	})
+//
+// A repeatable value, such as headerflag.Value or a multiflag.Value, is
+// wrapped as a *SliceValue, which satisfies the pflag.SliceValue interface.
+package pflagcompat
+
+import (
+	"flag"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/creachadair/goflags"
+	"github.com/creachadair/goflags/multiflag"
+)
+
+// A Typed is a flag.Value with a Type method, which satisfies the pflag.Value
+// interface.
+type Typed interface {
+	flag.Value
+	Type() string
+}
+
+// A Value wraps a flag.Value to satisfy the pflag.Value interface. A *Value
+// satisfies the flag.Value, flag.Getter, and pflag.Value interfaces.
+type Value struct {
+	flag.Value
+	typeName string
+}
+
+// Wrap returns a *Value wrapping v, whose Type method reports a name derived
+// from the type of v: for a value defined by a "...flag" package of this
+// module, the name of the package without the suffix, such as "size" for a
+// sizeflag.Value2; otherwise the pflag name of the type of its Get method,
+// such as "duration", or else "value".
+func Wrap(v flag.Value) *Value { return &Value{Value: v, typeName: typeName(v)} }
+
+// WithType returns a *Value wrapping v, whose Type method reports name.
+func WithType(v flag.Value, name string) *Value { return &Value{Value: v, typeName: name} }
+
+// Type satisfies part of the pflag.Value interface. It returns the name of
+// the type of the value, as shown in the help text of pflag.
+func (v *Value) Type() string { return v.typeName }
+
+// Unwrap returns the wrapped value.
+func (v *Value) Unwrap() flag.Value { return v.Value }
+
+// String satisfies part of the flag.Value interface. It reports the value as
+// goflags.ArgString does, so the quoted strings reported by ArgStringer values
+// are unquoted, as pflag expects.
+func (v *Value) String() string {
+	if v == nil || v.Value == nil {
+		return "" // the zero value, as used by flag.PrintDefaults
+	}
+	return goflags.ArgString(v.Value)
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is that of the wrapped value, or nil if it is not a
+// flag.Getter.
+func (v *Value) Get() any {
+	if g, ok := v.Value.(flag.Getter); ok {
+		return g.Get()
+	}
+	return nil
+}
+
+// IsBoolFlag reports whether the wrapped value is a boolean flag.
+func (v *Value) IsBoolFlag() bool {
+	b, ok := v.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// A SliceValue wraps a repeatable flag.Value to satisfy the pflag.Value and
+// pflag.SliceValue interfaces. A repeatable value is a goflags.Repeatable or
+// a *multiflag.Value.
+type SliceValue struct {
+	*Value
+}
+
+// Slice returns a *SliceValue wrapping v, whose Type method reports the name
+// reported by Wrap with the suffix "Slice". It reports false if v is not a
+// repeatable value.
+func Slice(v flag.Value) (*SliceValue, bool) {
+	if !isRepeatable(v) {
+		return nil, false
+	}
+	return &SliceValue{Value: WithType(v, typeName(v)+"Slice")}, true
+}
+
+// Append satisfies part of the pflag.SliceValue interface. It sets the value
+// from s, as for a repetition of the flag.
+func (v *SliceValue) Append(s string) error { return v.Value.Value.Set(s) }
+
+// Replace satisfies part of the pflag.SliceValue interface. It discards the
+// current values, then sets the value from each of vals in order. It
+// reports an error if the wrapped value does not have a Reset method, as
+// multiflag.Value does.
+func (v *SliceValue) Replace(vals []string) error {
+	r, ok := v.Value.Value.(interface{ Reset() })
+	if !ok {
+		return fmt.Errorf("pflagcompat: %T does not support Replace", v.Value.Value)
+	}
+	r.Reset()
+	for _, s := range vals {
+		if err := v.Append(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetSlice satisfies part of the pflag.SliceValue interface. It returns the
+// arguments that reproduce the current value when passed to Append in order.
+func (v *SliceValue) GetSlice() []string {
+	switch t := v.Value.Value.(type) {
+	case goflags.Repeatable:
+		return t.ArgStrings()
+	case *multiflag.Value:
+		var out []string
+		for _, g := range t.Values() {
+			out = append(out, goflags.ArgString(g))
+		}
+		return out
+	}
+	return nil
+}
+
+// Register calls define with the name, usage, and wrapped value of each flag
+// in fs, in lexicographical order by name, for example to add them to a
+// pflag.FlagSet. Repeatable values are wrapped by Slice, and other values by
+// Wrap. To allow a boolean flag to be given without an argument, set the
+// NoOptDefVal of its pflag.Flag to "true", as pflag.FlagSet.AddGoFlag does.
+func Register(fs *flag.FlagSet, define func(name, usage string, v Typed)) {
+	fs.VisitAll(func(f *flag.Flag) {
+		if sv, ok := Slice(f.Value); ok {
+			define(f.Name, f.Usage, sv)
+		} else {
+			define(f.Name, f.Usage, Wrap(f.Value))
+		}
+	})
+}
+
+func isRepeatable(v flag.Value) bool {
+	switch v.(type) {
+	case goflags.Repeatable, *multiflag.Value:
+		return true
+	}
+	return false
+}
+
+const modulePath = "github.com/creachadair/goflags"
+
+// typeName returns the pflag type name for v.
+func typeName(v flag.Value) string {
+	if t := valueType(v); t != nil && strings.HasPrefix(t.PkgPath(), modulePath+"/") {
+		if name, ok := strings.CutSuffix(path.Base(t.PkgPath()), "flag"); ok && name != "" {
+			return name
+		}
+	}
+	if g, ok := v.(flag.Getter); ok {
+		switch g.Get().(type) {
+		case bool:
+			return "bool"
+		case string:
+			return "string"
+		case int:
+			return "int"
+		case int64:
+			return "int64"
+		case uint:
+			return "uint"
+		case uint64:
+			return "uint64"
+		case float64:
+			return "float64"
+		case time.Duration:
+			return "duration"
+		case []string:
+			return "stringSlice"
+		}
+	}
+	return "value"
+}
+
+// valueType returns the type of v, or the type it points to.
+func valueType(v flag.Value) reflect.Type {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,155 @@
+package pflagcompat
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/creachadair/goflags/enumflag"
+	"github.com/creachadair/goflags/headerflag"
+	"github.com/creachadair/goflags/multiflag"
+	"github.com/creachadair/goflags/sizeflag"
+	"github.com/creachadair/goflags/urlflag"
+)
+
+// pflagValue and sliceValue are the interfaces of the same names in the pflag
+// package.
+type pflagValue interface {
+	String() string
+	Set(string) error
+	Type() string
+}
+
+type sliceValue interface {
+	Append(string) error
+	Replace([]string) error
+	GetSlice() []string
+}
+
+var (
+	_ pflagValue = (*Value)(nil)
+	_ pflagValue = (*SliceValue)(nil)
+	_ sliceValue = (*SliceValue)(nil)
+)
+
+func TestWrap(t *testing.T) {
+	color := Wrap(enumflag.New("red", "green", "blue"))
+	if got, want := color.String(), "red"; got != want {
+		t.Errorf("String: got %q, want %q", got, want)
+	}
+	if err := color.Set("blue"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if got := color.Get(); got != "blue" {
+		t.Errorf("Get: got %v, want blue", got)
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Duration("d", time.Second, "")
+	fs.Int("n", 0, "")
+	tests := []struct {
+		v    flag.Value
+		want string
+	}{
+		{enumflag.New("a"), "enum"},
+		{sizeflag.Base2(0), "size"},
+		{new(urlflag.Value), "url"},
+		{fs.Lookup("d").Value, "duration"},
+		{fs.Lookup("n").Value, "int"},
+		{stringValue("x"), "value"},
+	}
+	for _, tc := range tests {
+		if got := Wrap(tc.v).Type(); got != tc.want {
+			t.Errorf("Type of %T: got %q, want %q", tc.v, got, tc.want)
+		}
+	}
+	if got := WithType(sizeflag.Base2(0), "bytes").Type(); got != "bytes" {
+		t.Errorf("WithType: got %q, want bytes", got)
+	}
+
+	var zero *Value
+	if got := zero.String(); got != "" {
+		t.Errorf("String of nil: got %q, want empty", got)
+	}
+}
+
+// stringValue is a flag.Value that is not a flag.Getter.
+type stringValue string
+
+func (s stringValue) String() string     { return string(s) }
+func (s stringValue) Set(v string) error { return nil }
+
+func TestSlice(t *testing.T) {
+	if _, ok := Slice(sizeflag.Base2(0)); ok {
+		t.Error("Slice of a scalar value: got true, want false")
+	}
+
+	hdr, ok := Slice(new(headerflag.Value))
+	if !ok {
+		t.Fatal("Slice of headerflag.Value: got false, want true")
+	}
+	if got, want := hdr.Type(), "headerSlice"; got != want {
+		t.Errorf("Type: got %q, want %q", got, want)
+	}
+	for _, s := range []string{"Accept: text/plain", "X-Trace: 1"} {
+		if err := hdr.Append(s); err != nil {
+			t.Fatalf("Append %q: %v", s, err)
+		}
+	}
+	if got, want := hdr.GetSlice(), []string{"Accept: text/plain", "X-Trace: 1"}; !slices.Equal(got, want) {
+		t.Errorf("GetSlice: got %q, want %q", got, want)
+	}
+	if err := hdr.Replace([]string{"A: b"}); err != nil {
+		t.Errorf("Replace: unexpected error: %v", err)
+	}
+	if got, want := hdr.GetSlice(), []string{"A: b"}; !slices.Equal(got, want) {
+		t.Errorf("GetSlice after Replace: got %q, want %q", got, want)
+	}
+
+	multi, ok := Slice(multiflag.Wrap(func() flag.Getter { return enumflag.New("x", "y") }))
+	if !ok {
+		t.Fatal("Slice of multiflag.Value: got false, want true")
+	}
+	for _, s := range []string{"y", "x"} {
+		if err := multi.Append(s); err != nil {
+			t.Fatalf("Append %q: %v", s, err)
+		}
+	}
+	if got, want := multi.GetSlice(), []string{"y", "x"}; !slices.Equal(got, want) {
+		t.Errorf("GetSlice: got %q, want %q", got, want)
+	}
+	if err := multi.Replace([]string{"x"}); err != nil {
+		t.Errorf("Replace: unexpected error: %v", err)
+	}
+	if got, want := multi.GetSlice(), []string{"x"}; !slices.Equal(got, want) {
+		t.Errorf("GetSlice after Replace: got %q, want %q", got, want)
+	}
+	if err := multi.Replace([]string{"z"}); err == nil {
+		t.Error("Replace with an invalid value: got nil, want error")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(sizeflag.Base2(0), "size", "Size")
+	fs.Var(new(headerflag.Value), "header", "Header")
+	fs.Bool("v", false, "Verbose")
+
+	type def struct{ Name, Usage, Type string }
+	var got []def
+	Register(fs, func(name, usage string, v Typed) {
+		got = append(got, def{name, usage, v.Type()})
+	})
+	want := []def{
+		{"header", "Header", "headerSlice"},
+		{"size", "Size", "size"},
+		{"v", "Verbose", "bool"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Register: got %+v, want %+v", got, want)
+	}
+}
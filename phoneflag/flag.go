@@ -0,0 +1,187 @@
+// Package phoneflag defines a flag.Value implementation for telephone numbers
+// in E.164 format.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/phoneflag"
+//	)
+//
+//	var notify = phoneflag.Value{DefaultRegion: "US"}
+//	func init() {
+//	  flag.Var(&notify, "notify", notify.Help("Number to text on failure"))
+//	}
+//
+// With this definition "-notify '+44 20 7946 0958'" and
+// "-notify '(415) 555-2671'" are both accepted, and normalized to
+// "+442079460958" and "+14155552671" respectively.
+//
+// Validation is structural only: a number must have a country calling code
+// and between 7 and 15 digits in total. This package does not check numbering
+// plans or whether a number is assigned.
+package phoneflag
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// A Region describes the national dialing conventions of a region.
+type Region struct {
+	Code  string // the country calling code, e.g., "44"
+	Trunk string // the national trunk prefix, e.g., "0", or ""
+}
+
+// regions maps ISO 3166 region codes to their dialing conventions.
+var regions = map[string]Region{
+	"AR": {"54", "0"}, "AT": {"43", "0"}, "AU": {"61", "0"}, "BE": {"32", "0"},
+	"BR": {"55", "0"}, "CA": {"1", "1"}, "CH": {"41", "0"}, "CL": {"56", ""},
+	"CN": {"86", "0"}, "CO": {"57", ""}, "CZ": {"420", ""}, "DE": {"49", "0"},
+	"DK": {"45", ""}, "EG": {"20", "0"}, "ES": {"34", ""}, "FI": {"358", "0"},
+	"FR": {"33", "0"}, "GB": {"44", "0"}, "GR": {"30", ""}, "HK": {"852", ""},
+	"HU": {"36", "06"}, "ID": {"62", "0"}, "IE": {"353", "0"}, "IL": {"972", "0"},
+	"IN": {"91", "0"}, "IT": {"39", ""}, "JP": {"81", "0"}, "KE": {"254", "0"},
+	"KR": {"82", "0"}, "MX": {"52", ""}, "MY": {"60", "0"}, "NG": {"234", "0"},
+	"NL": {"31", "0"}, "NO": {"47", ""}, "NZ": {"64", "0"}, "PE": {"51", "0"},
+	"PH": {"63", "0"}, "PK": {"92", "0"}, "PL": {"48", ""}, "PT": {"351", ""},
+	"RO": {"40", "0"}, "RU": {"7", "8"}, "SA": {"966", "0"}, "SE": {"46", "0"},
+	"SG": {"65", ""}, "TH": {"66", "0"}, "TR": {"90", "0"}, "TW": {"886", "0"},
+	"UA": {"380", "0"}, "US": {"1", "1"}, "VN": {"84", "0"}, "ZA": {"27", "0"},
+}
+
+// LookupRegion returns the dialing conventions for the named ISO 3166 region,
+// and reports whether the region is known.
+func LookupRegion(name string) (Region, bool) {
+	r, ok := regions[strings.ToUpper(name)]
+	return r, ok
+}
+
+const (
+	minDigits = 7
+	maxDigits = 15
+)
+
+// Normalize converts s to E.164 format, "+" followed by digits. Spaces,
+// hyphens, dots, and parentheses are ignored, as is a parenthesized trunk
+// prefix as in "+44 (0)20 7946 0958". A number that begins with "+" or
+// the international prefix "00" is taken to include its country calling code.
+// Otherwise it is a national number in the region named by region, whose
+// trunk prefix is removed; if region is empty, such numbers are rejected.
+func Normalize(s, region string) (string, error) {
+	digits, intl, err := clean(s)
+	if err != nil {
+		return "", err
+	}
+	if !intl {
+		if rest, ok := strings.CutPrefix(digits, "00"); ok {
+			digits, intl = rest, true
+		}
+	}
+	if !intl {
+		if region == "" {
+			return "", fmt.Errorf("phoneflag: %q has no country code", s)
+		}
+		r, ok := LookupRegion(region)
+		if !ok {
+			return "", fmt.Errorf("phoneflag: unknown region %q", region)
+		}
+		if r.Trunk != "" {
+			if rest, ok := strings.CutPrefix(digits, r.Trunk); ok && len(rest) >= minDigits-len(r.Code) {
+				digits = rest
+			}
+		}
+		digits = r.Code + digits
+	}
+	if digits == "" || digits[0] == '0' {
+		return "", fmt.Errorf("phoneflag: invalid country code in %q", s)
+	}
+	if n := len(digits); n < minDigits || n > maxDigits {
+		return "", fmt.Errorf("phoneflag: %q must have %d to %d digits", s, minDigits, maxDigits)
+	}
+	return "+" + digits, nil
+}
+
+// clean removes punctuation from s and reports whether it had a leading "+".
+func clean(s string) (string, bool, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", false, errors.New("phoneflag: empty phone number")
+	}
+	intl := strings.HasPrefix(s, "+")
+	if intl {
+		// Drop the "(0)" trunk prefix sometimes written after a country code.
+		s = strings.Replace(s[1:], "(0)", "", 1)
+	}
+	var buf strings.Builder
+	for _, c := range s {
+		switch {
+		case '0' <= c && c <= '9':
+			buf.WriteRune(c)
+		case c == ' ' || c == '-' || c == '.' || c == '(' || c == ')':
+			// ignore punctuation
+		default:
+			return "", false, fmt.Errorf("phoneflag: invalid character %q in phone number", c)
+		}
+	}
+	return buf.String(), intl, nil
+}
+
+// A Value represents a telephone number in E.164 format. A pointer to a Value
+// satisfies the flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The normalized number parsed from the flag, e.g., "+14155552671".
+	Number string
+
+	// If non-empty, the ISO 3166 region code (e.g., "US") assumed for a number
+	// without a country calling code. If empty, a country code is required.
+	DefaultRegion string
+
+	// If non-empty, only numbers with one of these country calling codes
+	// (without "+") are accepted.
+	CountryCodes []string
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	if v.DefaultRegion != "" {
+		return fmt.Sprintf("%s (phone number, default region %s)", h, v.DefaultRegion)
+	}
+	return h + " (phone number with +country code)"
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return v.Number }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	num, err := Normalize(s, v.DefaultRegion)
+	if err != nil {
+		return err
+	}
+	if len(v.CountryCodes) != 0 && !hasCode(num[1:], v.CountryCodes) {
+		return fmt.Errorf("phoneflag: country code of %q not allowed, expected one of (+%s)",
+			num, strings.Join(v.CountryCodes, "|+"))
+	}
+	v.Number = num
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is the string of the normalized number.
+func (v *Value) Get() any { return v.Number }
+
+func hasCode(digits string, codes []string) bool {
+	for _, c := range codes {
+		if strings.HasPrefix(digits, strings.TrimPrefix(c, "+")) {
+			return true
+		}
+	}
+	return false
+}
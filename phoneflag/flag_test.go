@@ -0,0 +1,67 @@
+package phoneflag
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	notify := Value{DefaultRegion: "US"}
+	fs := flag.NewFlagSet("phone", flag.ContinueOnError)
+	fs.Var(&notify, "notify", notify.Help("Number to notify"))
+
+	if got, want := notify.String(), `""`; got != want {
+		t.Errorf("Initial -notify: got %s, want %s", got, want)
+	}
+	if err := fs.Parse([]string{"-notify", "(415) 555-2671"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := notify.Get().(string), "+14155552671"; got != want {
+		t.Errorf("Value for -notify: got %q, want %q", got, want)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input, region, want string
+	}{
+		{"+1 415 555 2671", "", "+14155552671"},
+		{"+44 (0)20 7946 0958", "", "+442079460958"},
+		{"0044 20 7946 0958", "", "+442079460958"},
+		{"020 7946 0958", "GB", "+442079460958"},
+		{"1-415-555-2671", "us", "+14155552671"},
+		{"415.555.2671", "US", "+14155552671"},
+		{"030 123456", "DE", "+4930123456"},
+		{"06 1 234 5678", "HU", "+3612345678"},
+		{"+683 4002", "", "+6834002"},
+	}
+	for _, test := range tests {
+		got, err := Normalize(test.input, test.region)
+		if err != nil {
+			t.Errorf("Normalize(%q, %q): unexpected error: %v", test.input, test.region, err)
+		} else if got != test.want {
+			t.Errorf("Normalize(%q, %q): got %q, want %q", test.input, test.region, got, test.want)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		v     Value
+		input string
+	}{
+		{Value{}, ""},
+		{Value{}, "415 555 2671"},
+		{Value{}, "+0 123 456 789"},
+		{Value{}, "+1 415 555 2671 ext 5"},
+		{Value{}, "+1 555"},
+		{Value{}, "+1234567890123456"},
+		{Value{DefaultRegion: "XX"}, "555 2671"},
+		{Value{CountryCodes: []string{"44"}}, "+1 415 555 2671"},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.input); err == nil {
+			t.Errorf("Set(%q) with %+v: got %q, wanted error", test.input, test.v, test.v.Number)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Package portflag defines flag.Value implementations for TCP and UDP port
+// numbers, and ranges of ports.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/portflag"
+//	)
+//
+//	var port = portflag.Value{Port: 8080, NoPrivileged: true}
+//	var pool = portflag.Range{Lo: 9000, Hi: 9100}
+//
+//	func init() {
+//	  flag.Var(&port, "port", port.Help("Port to listen on"))
+//	  flag.Var(&pool, "pool", "Ports available to workers")
+//	}
+//
+// Port 0 conventionally asks the operating system to assign a port. A Value
+// accepts "0" or "auto" for this purpose, unless NoZero is set.
+package portflag
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"strconv"
+	"strings"
+)
+
+// MaxPort is the largest valid port number.
+const MaxPort = 65535
+
+// MaxPrivileged is the largest privileged port number. On many systems,
+// listening on a port at or below this number requires special privileges.
+const MaxPrivileged = 1023
+
+// A Value represents a port number. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The port parsed from the flag.
+	Port int
+
+	// If true, ports 1 to MaxPrivileged are rejected.
+	NoPrivileged bool
+
+	// If true, port 0 is rejected.
+	NoZero bool
+}
+
+// Help concatenates a human-readable string summarizing the legal values of v
+// to h, for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	lo := 1
+	if v.NoPrivileged {
+		lo = MaxPrivileged + 1
+	}
+	if v.NoZero {
+		return fmt.Sprintf("%s (%d-%d)", h, lo, MaxPort)
+	}
+	return fmt.Sprintf("%s (%d-%d, or 0 to auto-assign)", h, lo, MaxPort)
+}
+
+// IsAuto reports whether the port is 0, meaning it should be assigned by the
+// operating system.
+func (v *Value) IsAuto() bool { return v.Port == 0 }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return strconv.Itoa(v.Port) }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	var p int
+	if s != "auto" {
+		n, err := parsePort(s)
+		if err != nil {
+			return err
+		}
+		p = n
+	}
+	if err := check(p, v.NoPrivileged, v.NoZero); err != nil {
+		return err
+	}
+	v.Port = p
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type int.
+func (v *Value) Get() any { return v.Port }
+
+// A Range represents a contiguous range of port numbers, written "lo-hi", or
+// a single port. A pointer to a Range satisfies the flag.Value and
+// flag.Getter interfaces. Port 0 is never part of a range.
+type Range struct {
+	// The bounds of the range, inclusive.
+	Lo, Hi int
+
+	// If true, ranges that include privileged ports are rejected.
+	NoPrivileged bool
+}
+
+// Len reports the number of ports in r.
+func (r *Range) Len() int {
+	if r.Lo == 0 {
+		return 0
+	}
+	return r.Hi - r.Lo + 1
+}
+
+// Contains reports whether port p is in r.
+func (r *Range) Contains(p int) bool { return r.Lo != 0 && r.Lo <= p && p <= r.Hi }
+
+// All returns an iterator over the ports in r, in increasing order.
+func (r *Range) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for p := r.Lo; p != 0 && p <= r.Hi; p++ {
+			if !yield(p) {
+				return
+			}
+		}
+	}
+}
+
+// String satisfies part of the flag.Value interface.
+func (r *Range) String() string {
+	switch {
+	case r.Lo == 0:
+		return ""
+	case r.Lo == r.Hi:
+		return strconv.Itoa(r.Lo)
+	}
+	return fmt.Sprintf("%d-%d", r.Lo, r.Hi)
+}
+
+// Set satisfies part of the flag.Value interface.
+func (r *Range) Set(s string) error {
+	los, his, ok := strings.Cut(s, "-")
+	if !ok {
+		his = los
+	}
+	lo, err := parsePort(strings.TrimSpace(los))
+	if err != nil {
+		return err
+	}
+	hi, err := parsePort(strings.TrimSpace(his))
+	if err != nil {
+		return err
+	}
+	if lo > hi {
+		return fmt.Errorf("portflag: range %q is reversed", s)
+	}
+	if err := check(lo, r.NoPrivileged, true); err != nil {
+		return err
+	}
+	r.Lo, r.Hi = lo, hi
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is a [2]int of the bounds.
+func (r *Range) Get() any { return [2]int{r.Lo, r.Hi} }
+
+func parsePort(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n > MaxPort {
+		return 0, fmt.Errorf("portflag: invalid port %q", s)
+	}
+	return n, nil
+}
+
+func check(p int, noPrivileged, noZero bool) error {
+	if p == 0 && noZero {
+		return errors.New("portflag: port 0 is not allowed")
+	}
+	if p != 0 && p <= MaxPrivileged && noPrivileged {
+		return fmt.Errorf("portflag: privileged port %d is not allowed", p)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package portflag
+
+import (
+	"bytes"
+	"flag"
+	"slices"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	port := Value{Port: 8080, NoPrivileged: true}
+	debug := Value{}
+	pool := Range{Lo: 9000, Hi: 9002}
+
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("port", flag.ContinueOnError)
+	fs.Var(&port, "port", port.Help("Port to listen on"))
+	fs.Var(&debug, "debug-port", debug.Help("Debug port"))
+	fs.Var(&pool, "pool", "Worker ports")
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+	t.Logf("Port flag set:\n%s", buf.String())
+
+	if got := slices.Collect(pool.All()); !slices.Equal(got, []int{9000, 9001, 9002}) {
+		t.Errorf("Initial value for -pool: got %v", got)
+	}
+	if err := fs.Parse([]string{"-port", "8443", "-debug-port", "auto", "-pool", "10000-10099"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got := port.Get().(int); got != 8443 {
+		t.Errorf("Value for -port: got %d, want 8443", got)
+	}
+	if !debug.IsAuto() {
+		t.Errorf("Value for -debug-port: got %d, want auto", debug.Port)
+	}
+	if got := pool.Get().([2]int); got != [2]int{10000, 10099} {
+		t.Errorf("Value for -pool: got %v, want [10000 10099]", got)
+	}
+	if pool.Len() != 100 || !pool.Contains(10050) || pool.Contains(10100) {
+		t.Errorf("Range %v: got Len %d, Contains(10050)=%v", pool.String(), pool.Len(), pool.Contains(10050))
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		v  Value
+		in string
+	}{
+		{Value{}, ""},
+		{Value{}, "http"},
+		{Value{}, "-1"},
+		{Value{}, "65536"},
+		{Value{NoZero: true}, "0"},
+		{Value{NoZero: true}, "auto"},
+		{Value{NoPrivileged: true}, "80"},
+		{Value{NoPrivileged: true}, "1023"},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.in); err == nil {
+			t.Errorf("Set(%q) with %+v: got %d, wanted error", test.in, test.v, test.v.Port)
+		}
+	}
+
+	for _, bad := range []string{"", "0-10", "10-5", "1-65536", "a-b", "5-", "80-90"} {
+		r := Range{NoPrivileged: bad == "80-90"}
+		if err := r.Set(bad); err == nil {
+			t.Errorf("Range.Set(%q): got %v, wanted error", bad, r.String())
+		}
+	}
+
+	var r Range
+	if err := r.Set("443"); err != nil {
+		t.Errorf("Range.Set(443) failed: %v", err)
+	} else if r.Len() != 1 || r.String() != "443" {
+		t.Errorf("Range.Set(443): got %q (len %d)", r.String(), r.Len())
+	}
+}
@@ -0,0 +1,110 @@
+// Package queryflag defines a repeatable flag.Value implementation that
+// collects URL query parameters into a url.Values.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/queryflag"
+//	)
+//
+//	var params = queryflag.Value{AllowQuery: true}
+//	func init() {
+//	  flag.Var(&params, "q", params.Help("Add a query parameter"))
+//	}
+//
+//	  ...
+//	  u.RawQuery = params.Encode()
+//
+// Each "k=v" argument adds one parameter. The key and value are taken
+// literally, so "-q 'name=a&b'" sets name to "a&b", which is escaped when the
+// query is encoded. If AllowQuery is set, an argument beginning with "?" is
+// parsed as a complete, already-encoded query string: "-q '?a=1&b=x%20y'".
+package queryflag
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// A Value represents a collection of URL query parameters. A pointer to a
+// Value satisfies the flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The parameters parsed from the flag, or nil if none have been set.
+	Values url.Values
+
+	// If true, an argument beginning with "?" is decoded as a full query
+	// string and all its parameters are added.
+	AllowQuery bool
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	if v.AllowQuery {
+		return h + " (key=value or ?query; repeatable)"
+	}
+	return h + " (key=value; repeatable)"
+}
+
+// Encode returns the parameters in URL-encoded form, sorted by key.
+func (v *Value) Encode() string { return v.Values.Encode() }
+
+// ArgStrings returns the parameters as "key=value" strings, sorted by key,
+// each of which is a valid argument to Set.
+func (v *Value) ArgStrings() []string {
+	var out []string
+	for _, key := range slices.Sorted(maps.Keys(v.Values)) {
+		for _, val := range v.Values[key] {
+			out = append(out, key+"="+val)
+		}
+	}
+	return out
+}
+
+// Reset discards all the parameters of v.
+func (v *Value) Reset() { v.Values = nil }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.Encode()) }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	if q, ok := strings.CutPrefix(s, "?"); ok && v.AllowQuery {
+		vals, err := url.ParseQuery(q)
+		if err != nil {
+			return fmt.Errorf("queryflag: invalid query: %w", err)
+		}
+		for key, vs := range vals {
+			for _, val := range vs {
+				v.add(key, val)
+			}
+		}
+		return nil
+	}
+	key, val, ok := strings.Cut(s, "=")
+	switch {
+	case !ok:
+		return fmt.Errorf("queryflag: invalid parameter %q, want key=value", s)
+	case key == "":
+		return errors.New("queryflag: empty parameter name")
+	}
+	v.add(key, val)
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type url.Values.
+func (v *Value) Get() any { return v.Values }
+
+func (v *Value) add(key, val string) {
+	if v.Values == nil {
+		v.Values = make(url.Values)
+	}
+	v.Values.Add(key, val)
+}
@@ -0,0 +1,77 @@
+package queryflag
+
+import (
+	"flag"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	q := Value{AllowQuery: true}
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	fs.Var(&q, "q", q.Help("Query parameter"))
+
+	if err := fs.Parse([]string{
+		"-q", "name=a&b",
+		"-q", "?page=2&sort=x%20y&tag=1",
+		"-q", "tag=2",
+		"-q", "empty=",
+	}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	vals := q.Get().(url.Values)
+	if got, want := vals.Get("name"), "a&b"; got != want {
+		t.Errorf("name: got %q, want %q", got, want)
+	}
+	if got, want := vals.Get("sort"), "x y"; got != want {
+		t.Errorf("sort: got %q, want %q", got, want)
+	}
+	if got, want := vals["tag"], []string{"1", "2"}; !slices.Equal(got, want) {
+		t.Errorf("tag: got %q, want %q", got, want)
+	}
+	if got, want := q.Encode(), "empty=&name=a%26b&page=2&sort=x+y&tag=1&tag=2"; got != want {
+		t.Errorf("Encode: got %q, want %q", got, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		v     Value
+		input string
+	}{
+		{Value{}, ""},
+		{Value{}, "novalue"},
+		{Value{}, "=value"},
+		{Value{AllowQuery: true}, "?a=%zz"},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.input); err == nil {
+			t.Errorf("Set(%q) with %+v: got %v, wanted error", test.input, test.v, test.v.Values)
+		}
+	}
+}
+
+func TestArgStrings(t *testing.T) {
+	v := Value{AllowQuery: true}
+	for _, s := range []string{"b=x&y", "?a=1&b=2", "a=3"} {
+		if err := v.Set(s); err != nil {
+			t.Fatalf("Set(%q) failed: %v", s, err)
+		}
+	}
+	want := []string{"a=1", "a=3", "b=x&y", "b=2"}
+	got := v.ArgStrings()
+	if !slices.Equal(got, want) {
+		t.Fatalf("ArgStrings: got %q, want %q", got, want)
+	}
+	var cp Value
+	for _, s := range got {
+		if err := cp.Set(s); err != nil {
+			t.Errorf("Set(%q) failed: %v", s, err)
+		}
+	}
+	if got, want := cp.Encode(), v.Encode(); got != want {
+		t.Errorf("After round trip: got %q, want %q", got, want)
+	}
+}
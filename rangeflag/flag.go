@@ -0,0 +1,175 @@
+// Package rangeflag defines a flag.Value implementation for sets of integers
+// written in range notation, such as "1-5,8,10-12".
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/rangeflag"
+//	)
+//
+//	var pages = rangeflag.Value{Min: 1, Max: 500}
+//	func init() {
+//	  flag.Var(&pages, "pages", pages.Help("Pages to print"))
+//	}
+//
+// The grammar of a range set is:
+//
+//	set   = elt {',' elt}
+//	elt   = int ['-' int]
+//	int   = ['-'] digits
+//
+// The elements of a range "a-b" are the integers from a to b inclusive, and
+// must satisfy a ≤ b. Overlapping and repeated elements are merged, so the
+// resulting set is ordered and free of duplicates. Whitespace around elements
+// is ignored.
+package rangeflag
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A span is a closed interval [lo, hi] of integers.
+type span struct{ lo, hi int }
+
+// A Value represents an ordered set of integers. A pointer to a Value
+// satisfies the flag.Value and flag.Getter interfaces. Setting the flag
+// replaces any previous contents.
+type Value struct {
+	// If Max > Min, every element of the set must lie in the closed interval
+	// [Min, Max].
+	Min, Max int
+
+	spans []span // ordered, non-overlapping, non-adjacent
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	if v.Max > v.Min {
+		return fmt.Sprintf("%s (e.g., 1-5,8; range %d-%d)", h, v.Min, v.Max)
+	}
+	return fmt.Sprintf("%s (e.g., 1-5,8)", h)
+}
+
+// Contains reports whether n is an element of the set.
+func (v *Value) Contains(n int) bool {
+	_, ok := slices.BinarySearchFunc(v.spans, n, func(s span, n int) int {
+		if s.hi < n {
+			return -1
+		} else if s.lo > n {
+			return 1
+		}
+		return 0
+	})
+	return ok
+}
+
+// Len reports the number of elements in the set.
+func (v *Value) Len() int {
+	var n int
+	for _, s := range v.spans {
+		n += s.hi - s.lo + 1
+	}
+	return n
+}
+
+// All returns an iterator over the elements of the set in increasing order.
+func (v *Value) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for _, s := range v.spans {
+			for i := s.lo; i <= s.hi; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Ints returns the elements of the set in increasing order.
+func (v *Value) Ints() []int { return slices.Collect(v.All()) }
+
+// String satisfies part of the flag.Value interface.
+// The set is rendered in its most compact range notation.
+func (v *Value) String() string {
+	parts := make([]string, len(v.spans))
+	for i, s := range v.spans {
+		if s.lo == s.hi {
+			parts[i] = strconv.Itoa(s.lo)
+		} else {
+			parts[i] = strconv.Itoa(s.lo) + "-" + strconv.Itoa(s.hi)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	var spans []span
+	for _, elt := range strings.Split(s, ",") {
+		sp, err := parseSpan(strings.TrimSpace(elt))
+		if err != nil {
+			return err
+		}
+		if v.Max > v.Min && (sp.lo < v.Min || sp.hi > v.Max) {
+			return fmt.Errorf("rangeflag: %q is outside the range %d-%d", elt, v.Min, v.Max)
+		}
+		spans = append(spans, sp)
+	}
+	v.spans = merge(spans)
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Value, a copy of v, so that a large range is
+// not expanded; use its Ints or All method to enumerate the elements.
+func (v *Value) Get() any { return Value{Min: v.Min, Max: v.Max, spans: slices.Clone(v.spans)} }
+
+// parseSpan parses a single element, either "n" or "a-b". A leading "-" is a
+// sign, so "-3--1" is the range from -3 to -1.
+func parseSpan(s string) (span, error) {
+	cut := strings.Index(strings.TrimPrefix(s, "-"), "-")
+	if cut < 0 {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return span{}, fmt.Errorf("rangeflag: invalid element %q", s)
+		}
+		return span{n, n}, nil
+	}
+	if strings.HasPrefix(s, "-") {
+		cut++
+	}
+	lo, err := strconv.Atoi(strings.TrimSpace(s[:cut]))
+	if err != nil {
+		return span{}, fmt.Errorf("rangeflag: invalid range %q", s)
+	}
+	hi, err := strconv.Atoi(strings.TrimSpace(s[cut+1:]))
+	if err != nil {
+		return span{}, fmt.Errorf("rangeflag: invalid range %q", s)
+	}
+	if lo > hi {
+		return span{}, fmt.Errorf("rangeflag: range %q is reversed", s)
+	}
+	return span{lo, hi}, nil
+}
+
+// merge sorts spans and combines those that overlap or are adjacent.
+func merge(spans []span) []span {
+	slices.SortFunc(spans, func(a, b span) int { return cmp.Compare(a.lo, b.lo) })
+	var out []span
+	for _, s := range spans {
+		if n := len(out) - 1; n >= 0 && s.lo <= out[n].hi+1 {
+			out[n].hi = max(out[n].hi, s.hi)
+		} else {
+			out = append(out, s)
+		}
+	}
+	return out
+}
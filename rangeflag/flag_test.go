@@ -0,0 +1,99 @@
+package rangeflag
+
+import (
+	"bytes"
+	"flag"
+	"slices"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	pages := Value{Min: 1, Max: 20}
+
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("range", flag.ContinueOnError)
+	fs.Var(&pages, "pages", pages.Help("Pages to print"))
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+	t.Logf("Range flag set:\n%s", buf.String())
+
+	if err := fs.Parse([]string{"-pages", "10-12, 1-5,8,4"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	want := []int{1, 2, 3, 4, 5, 8, 10, 11, 12}
+	if got := pages.Get().(Value); !slices.Equal(got.Ints(), want) || got.String() != pages.String() {
+		t.Errorf("Value for -pages: got %v, want %v", got, want)
+	}
+	if got := pages.Len(); got != len(want) {
+		t.Errorf("Len for -pages: got %d, want %d", got, len(want))
+	}
+	for i := -1; i < 15; i++ {
+		if got, want := pages.Contains(i), slices.Contains(want, i); got != want {
+			t.Errorf("Contains(%d): got %v, want %v", i, got, want)
+		}
+	}
+
+	if err := fs.Parse([]string{"-pages", "0-3"}); err == nil {
+		t.Error("Parse out-of-bounds range: got nil, wanted error")
+	}
+	if err := fs.Parse([]string{"-pages", "19-21"}); err == nil {
+		t.Error("Parse out-of-bounds range: got nil, wanted error")
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"0", "0"},
+		{"3,2,1", "1-3"},
+		{"1-5,8,10-12", "1-5,8,10-12"},
+		{"5-8,1-6", "1-8"},
+		{"1-2,3-4", "1-4"},
+		{"7,7,7", "7"},
+		{"-3--1,0", "-3-0"},
+		{"-5,5", "-5,5"},
+		{" 1 - 2 , 9 ", "1-2,9"},
+	}
+	for _, test := range tests {
+		var v Value
+		if err := v.Set(test.in); err != nil {
+			t.Errorf("Set(%q) failed: %v", test.in, err)
+			continue
+		}
+		got := v.String()
+		if got != test.want {
+			t.Errorf("Set(%q): got %q, want %q", test.in, got, test.want)
+		}
+
+		// The string form round-trips.
+		var w Value
+		if err := w.Set(got); err != nil {
+			t.Errorf("Set(%q) failed: %v", got, err)
+		} else if !slices.Equal(w.Ints(), v.Ints()) {
+			t.Errorf("Round trip of %q: got %v, want %v", got, w.Ints(), v.Ints())
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, bad := range []string{"", ",", "1,", "a", "1-", "-", "1-b", "5-3", "1--", "1-2-3"} {
+		var v Value
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %q, wanted error", bad, v.String())
+		} else {
+			t.Logf("Set(%q) gave expected error: %v", bad, err)
+		}
+	}
+}
+
+func TestGetLarge(t *testing.T) {
+	var ids Value
+	if err := ids.Set("0-9223372036854775806"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	got := ids.Get().(Value)
+	if !got.Contains(1<<62) || got.String() != "0-9223372036854775806" {
+		t.Errorf("Get: got %v, want 0-9223372036854775806", got.String())
+	}
+}
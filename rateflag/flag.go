@@ -0,0 +1,228 @@
+// Package rateflag defines a flag.Value implementation for rates, written as
+// a count of events per interval of time, such as "100/s" or "10 per hour".
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/rateflag"
+//	)
+//
+//	var limit = rateflag.MustParse("50/s")
+//	func init() {
+//	  flag.Var(&limit, "rate-limit", limit.Help("Maximum request rate"))
+//	}
+//
+//	  ...
+//	  bucket := limit.Bucket(10)
+//	  if !bucket.Allow() { ... }
+//
+// The grammar of a rate is:
+//
+//	rate     = count sep interval
+//	sep      = '/' | "per"
+//	count    = number
+//	interval = [number] unit | duration
+//	unit     = "ms" | "s" | "sec" | "second" | "m" | "min" | "minute"
+//	         | "h" | "hr" | "hour" | "d" | "day"
+//
+// Units may also be plural, e.g., "seconds". A duration is anything accepted
+// by time.ParseDuration, e.g., "1m30s". Whitespace around terms is ignored.
+package rateflag
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// units maps unit names to their durations.
+var units = map[string]time.Duration{
+	"ms": time.Millisecond, "msec": time.Millisecond, "millisecond": time.Millisecond,
+	"s": time.Second, "sec": time.Second, "second": time.Second,
+	"m": time.Minute, "min": time.Minute, "minute": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hour": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour,
+}
+
+// A Value represents a rate of events. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The number of events per interval.
+	Count float64
+
+	// The interval over which Count events occur.
+	Interval time.Duration
+
+	src string
+}
+
+// MustParse returns a Value for the rate described by s, and panics if s is
+// not a valid rate. It is intended for use in setting default values.
+func MustParse(s string) Value {
+	var v Value
+	if err := v.Set(s); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string { return h + " (e.g., 100/s, 5000/min)" }
+
+// PerSecond returns the rate as a number of events per second. It returns 0
+// if the value is not set.
+func (v *Value) PerSecond() float64 {
+	if v.Interval <= 0 {
+		return 0
+	}
+	return v.Count / v.Interval.Seconds()
+}
+
+// Every returns the average time between events, or 0 if the rate is zero or
+// not set.
+func (v *Value) Every() time.Duration {
+	if v.Count <= 0 {
+		return 0
+	}
+	return time.Duration(float64(v.Interval) / v.Count)
+}
+
+// Bucket returns a new token bucket that admits events at the rate of v,
+// with bursts of up to burst events. The bucket is initially full. If burst
+// is less than 1, it is treated as 1.
+func (v *Value) Bucket(burst int) *TokenBucket {
+	b := float64(max(burst, 1))
+	return &TokenBucket{rate: v.PerSecond(), burst: b, tokens: b, last: time.Now()}
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string {
+	if v.src != "" {
+		return v.src
+	}
+	if v.Interval == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v.Count, 'g', -1, 64) + "/" + formatInterval(v.Interval)
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	cs, is, ok := strings.Cut(s, "/")
+	if !ok {
+		cs, is, ok = strings.Cut(s, " per ")
+	}
+	if !ok {
+		return fmt.Errorf("rateflag: invalid rate %q, want count/interval", s)
+	}
+	count, err := strconv.ParseFloat(strings.TrimSpace(cs), 64)
+	if err != nil || count < 0 || math.IsInf(count, 0) || math.IsNaN(count) {
+		return fmt.Errorf("rateflag: invalid count %q", strings.TrimSpace(cs))
+	}
+	interval, err := parseInterval(strings.TrimSpace(is))
+	if err != nil {
+		return err
+	}
+	v.Count, v.Interval, v.src = count, interval, strings.TrimSpace(s)
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is the float64 rate in events per second.
+func (v *Value) Get() any { return v.PerSecond() }
+
+func parseInterval(s string) (time.Duration, error) {
+	// Split a leading number from the unit, if present.
+	i := strings.IndexFunc(s, func(r rune) bool { return !(r >= '0' && r <= '9' || r == '.') })
+	if i < 0 {
+		return 0, fmt.Errorf("rateflag: missing unit in %q", s)
+	}
+	num, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	if d, ok := units[unit]; ok || (strings.HasSuffix(unit, "s") && units[unit[:len(unit)-1]] != 0) {
+		if !ok {
+			d = units[unit[:len(unit)-1]]
+		}
+		n := 1.0
+		if num != "" {
+			f, err := strconv.ParseFloat(num, 64)
+			if err != nil || f <= 0 {
+				return 0, fmt.Errorf("rateflag: invalid interval %q", s)
+			}
+			n = f
+		}
+		return time.Duration(n * float64(d)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("rateflag: invalid interval %q", s)
+	}
+	return d, nil
+}
+
+func formatInterval(d time.Duration) string {
+	switch d {
+	case time.Millisecond:
+		return "ms"
+	case time.Second:
+		return "s"
+	case time.Minute:
+		return "min"
+	case time.Hour:
+		return "h"
+	case 24 * time.Hour:
+		return "day"
+	}
+	return d.String()
+}
+
+// A TokenBucket admits events at a fixed average rate, permitting bursts up to
+// a fixed size. It is safe for concurrent use.
+type TokenBucket struct {
+	μ      sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// Allow reports whether an event may occur now, and if so consumes a token.
+func (b *TokenBucket) Allow() bool { return b.allowAt(time.Now()) }
+
+// Delay returns how long the caller must wait before a token is available,
+// or 0 if one is available now. Unlike Allow, Delay does not consume a token.
+// If the rate is zero and the bucket is empty, Delay returns math.MaxInt64.
+func (b *TokenBucket) Delay() time.Duration {
+	b.μ.Lock()
+	defer b.μ.Unlock()
+	b.refill(time.Now())
+	if b.tokens >= 1 {
+		return 0
+	} else if b.rate <= 0 {
+		return math.MaxInt64
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+func (b *TokenBucket) allowAt(now time.Time) bool {
+	b.μ.Lock()
+	defer b.μ.Unlock()
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true
+	}
+	return false
+}
+
+func (b *TokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
@@ -0,0 +1,105 @@
+package rateflag
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+	"time"
+)
+
+func TestFlagBits(t *testing.T) {
+	limit := MustParse("50/s")
+
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("rate", flag.ContinueOnError)
+	fs.Var(&limit, "rate-limit", limit.Help("Maximum request rate"))
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+	t.Logf("Rate flag set:\n%s", buf.String())
+
+	if got := limit.PerSecond(); got != 50 {
+		t.Errorf("Initial value for -rate-limit: got %v, want 50", got)
+	}
+	if err := fs.Parse([]string{"-rate-limit", "5000 / min"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if limit.Count != 5000 || limit.Interval != time.Minute {
+		t.Errorf("Value for -rate-limit: got (%v, %v), want (5000, 1m)", limit.Count, limit.Interval)
+	}
+	if got, want := limit.Get().(float64), 5000.0/60; got != want {
+		t.Errorf("Rate for -rate-limit: got %v, want %v", got, want)
+	}
+	if got, want := limit.String(), "5000 / min"; got != want {
+		t.Errorf("String for -rate-limit: got %q, want %q", got, want)
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		in       string
+		count    float64
+		interval time.Duration
+	}{
+		{"100/s", 100, time.Second},
+		{"1/ms", 1, time.Millisecond},
+		{"10 per hour", 10, time.Hour},
+		{"3 per 2 days", 3, 48 * time.Hour},
+		{"2.5/sec", 2.5, time.Second},
+		{"60/10s", 60, 10 * time.Second},
+		{"7/minutes", 7, time.Minute},
+		{"1/1m30s", 1, 90 * time.Second},
+		{"0/s", 0, time.Second},
+	}
+	for _, test := range tests {
+		var v Value
+		if err := v.Set(test.in); err != nil {
+			t.Errorf("Set(%q) failed: %v", test.in, err)
+		} else if v.Count != test.count || v.Interval != test.interval {
+			t.Errorf("Set(%q): got (%v, %v), want (%v, %v)",
+				test.in, v.Count, v.Interval, test.count, test.interval)
+		}
+	}
+
+	for _, bad := range []string{"", "100", "100/", "/s", "x/s", "-1/s", "1/0s", "1/-5s", "1/fortnight", "1 per"} {
+		var v Value
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %v/%v, wanted error", bad, v.Count, v.Interval)
+		}
+	}
+
+	// A rate constructed directly renders canonically.
+	v := Value{Count: 3, Interval: time.Hour}
+	if got, want := v.String(), "3/h"; got != want {
+		t.Errorf("String: got %q, want %q", got, want)
+	}
+	if got, want := v.Every(), 20*time.Minute; got != want {
+		t.Errorf("Every: got %v, want %v", got, want)
+	}
+}
+
+func TestBucket(t *testing.T) {
+	v := MustParse("10/s")
+	b := v.Bucket(3)
+
+	now := b.last
+	for i := 0; i < 3; i++ {
+		if !b.allowAt(now) {
+			t.Fatalf("Allow %d: got false, want true", i+1)
+		}
+	}
+	if b.allowAt(now) {
+		t.Error("Allow after burst: got true, want false")
+	}
+
+	// After 100ms, one more token is available at 10/s.
+	now = now.Add(100 * time.Millisecond)
+	if !b.allowAt(now) {
+		t.Error("Allow after refill: got false, want true")
+	}
+	if b.allowAt(now) {
+		t.Error("Allow after refill: got true, want false")
+	}
+	if d := b.Delay(); d <= 0 || d > 100*time.Millisecond {
+		t.Errorf("Delay: got %v, want (0, 100ms]", d)
+	}
+}
@@ -0,0 +1,321 @@
+package goflags
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/creachadair/goflags/defaultflag"
+)
+
+// A Reloader re-applies a configuration file to the reloadable flags of a
+// flag set while a program runs, so that a long-running service can adjust
+// settings such as log levels and rate limits without restarting. Only flags
+// marked by Reloadable or OnChange are updated; other flags keep the values
+// they had at startup. The values of reloadable flags may be read while they
+// are reloaded, so they should be wrapped with Atomic or otherwise safe for
+// concurrent use.
+type Reloader struct {
+	// If non-nil, OnError is called with the errors reported by reloads in
+	// Watch. If nil, the errors are logged with log.Printf.
+	OnError func(error)
+
+	fs   *flag.FlagSet
+	path string
+
+	mu       sync.Mutex
+	names    map[string][]func(old, new string) // reloadable flags and callbacks
+	loaded   map[string]bool                    // flags set by the last reload
+	modified time.Time                          // of the file at the last reload
+	size     int64                              // of the file at the last reload
+}
+
+// NewReloader returns a Reloader that applies the configuration file at path,
+// in the format indicated by its extension as for LoadConfig, to fs.
+func NewReloader(fs *flag.FlagSet, path string) *Reloader {
+	return &Reloader{fs: fs, path: path, names: make(map[string][]func(old, new string))}
+}
+
+// Reloadable marks the named flags of fs as reloadable. It panics if a flag
+// is not defined in fs.
+func (r *Reloader) Reloadable(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range names {
+		r.lookup(name)
+		if _, ok := r.names[name]; !ok {
+			r.names[name] = nil
+		}
+	}
+}
+
+// OnChange marks the named flag of fs as reloadable, and arranges for fn to
+// be called with the old and new string values of the flag after a reload
+// changes its value. Multiple callbacks for the same flag are called in the
+// order they were added. It panics if the flag is not defined in fs.
+func (r *Reloader) OnChange(name string, fn func(old, new string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookup(name)
+	r.names[name] = append(r.names[name], fn)
+}
+
+func (r *Reloader) lookup(name string) *flag.Flag {
+	f := r.fs.Lookup(name)
+	if f == nil {
+		panic(fmt.Sprintf("goflags: reloadable flag -%s is not defined", name))
+	}
+	return f
+}
+
+// Reload reads the configuration file and sets each reloadable flag that it
+// names, as ApplyConfig does, then calls the callbacks for the flags whose
+// values changed. It returns the names of the changed flags, in order.
+//
+// A repeatable value, such as a multiflag.Value, is reset before the values
+// in the file are applied, so that they replace rather than extend the values
+// of the previous reload; Reload reports an error for a repeatable value that
+// has no Reset method. A repeatable value wrapped by Atomic is replaced while
+// holding its lock, as by AtomicValue.Replace. A reloadable flag set by an
+// earlier reload that is not named by the file is reset to its default, as
+// reported by DefaultArg, or to no values if it is repeatable. Flags set on
+// the command line are not changed, and flags of fs that are not reloadable
+// are ignored.
+//
+// Reload reports an error if the file names a flag that is not defined in fs,
+// or if a value is invalid; in that case the flags are restored to their
+// previous values, as by Snapshot, and no callbacks are called. Callbacks are
+// called after the reload is complete, so they may use r.
+func (r *Reloader) Reload() ([]string, error) {
+	r.mu.Lock()
+	changed, calls, err := r.reloadLocked()
+	r.mu.Unlock()
+	for _, call := range calls {
+		call()
+	}
+	return changed, err
+}
+
+// reloadLocked implements Reload, and returns the callbacks to call for the
+// changed flags. The caller must hold r.mu.
+func (r *Reloader) reloadLocked() ([]string, []func(), error) {
+	cfg, err := readConfig(r.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fi, err := os.Stat(r.path); err == nil {
+		r.modified, r.size = fi.ModTime(), fi.Size()
+	}
+
+	set := make(map[string]bool)
+	r.fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	for name := range cfg {
+		if r.fs.Lookup(name) == nil {
+			return nil, nil, fmt.Errorf("goflags: config %s sets unknown flag %q", r.path, name)
+		}
+	}
+
+	old := make(map[string]string)
+	loaded := make(map[string]bool)
+	var restore []func()
+	for _, name := range slices.Sorted(maps.Keys(r.names)) {
+		if set[name] {
+			continue // the command line takes precedence
+		}
+		f := r.fs.Lookup(name)
+		old[name] = f.Value.String()
+		restore = append(restore, Snapshot(f.Value))
+		vals, inFile := cfg[name]
+		if err := r.apply(f, vals, inFile); err != nil {
+			for i := len(restore) - 1; i >= 0; i-- {
+				restore[i]()
+			}
+			return nil, nil, err
+		}
+		if inFile {
+			loaded[name] = true
+		}
+	}
+	r.loaded = loaded
+
+	var changed []string
+	var calls []func()
+	for _, name := range slices.Sorted(maps.Keys(old)) {
+		prev, cur := old[name], r.fs.Lookup(name).Value.String()
+		if cur == prev {
+			continue
+		}
+		changed = append(changed, name)
+		for _, fn := range r.names[name] {
+			calls = append(calls, func() { fn(prev, cur) })
+		}
+	}
+	return changed, calls, nil
+}
+
+// apply sets f from vals, if the configuration file names it, or else resets
+// it to its default if the last reload set it.
+func (r *Reloader) apply(f *flag.Flag, vals []string, inFile bool) error {
+	if !inFile && !r.loaded[f.Name] {
+		return nil
+	}
+	dv, _ := findValue[*defaultflag.Value](f.Value)
+	if isRepeated(f.Value) {
+		if !inFile {
+			vals = nil
+		}
+		if err := replaceValues(f.Value, vals); err != nil {
+			return fmt.Errorf("goflags: reloading flag -%s from %s: %w", f.Name, r.path, err)
+		}
+		if dv != nil && inFile {
+			dv.SetSource(defaultflag.ConfigFile, r.path)
+		} else if dv != nil {
+			dv.SetSource(defaultflag.Default, "")
+		}
+		return nil
+	}
+	if inFile {
+		return setConfigValues(f, vals, r.path)
+	}
+	if err := f.Value.Set(DefaultArg(f)); err != nil {
+		return fmt.Errorf("goflags: resetting flag -%s: %w", f.Name, err)
+	}
+	if dv != nil {
+		dv.SetSource(defaultflag.Default, "")
+	}
+	return nil
+}
+
+// Watch calls Reload when the process receives SIGHUP, and, if interval is
+// positive, when the modification time or size of the configuration file
+// changes, checked at that interval. It blocks until ctx ends, and then
+// returns ctx.Err(). Errors from Reload are reported to r.OnError.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) error {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		tick = t.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-hup:
+		case <-tick:
+			if !r.fileChanged() {
+				continue
+			}
+		}
+		if _, err := r.Reload(); err != nil {
+			if r.OnError != nil {
+				r.OnError(err)
+			} else {
+				log.Printf("goflags: reload: %v", err)
+			}
+		}
+	}
+}
+
+// fileChanged reports whether the configuration file has changed since the
+// last reload.
+func (r *Reloader) fileChanged() bool {
+	fi, err := os.Stat(r.path)
+	if err != nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !fi.ModTime().Equal(r.modified) || fi.Size() != r.size
+}
+
+// An AtomicValue is a flag.Value that guards another value with a lock, so
+// that it may be read while it is set concurrently, as by a Reloader. An
+// *AtomicValue satisfies the flag.Value and flag.Getter interfaces.
+type AtomicValue[T any] struct {
+	mu sync.RWMutex
+	v  flag.Getter
+}
+
+// Atomic returns an AtomicValue wrapping v, whose Get method reports values
+// of type T.
+func Atomic[T any](v flag.Getter) *AtomicValue[T] { return &AtomicValue[T]{v: v} }
+
+// Load returns the current value, as reported by the Get method of the
+// wrapped value, or the zero value of T if that is not of type T.
+func (a *AtomicValue[T]) Load() T {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	t, _ := a.v.Get().(T)
+	return t
+}
+
+// Unwrap returns the wrapped value.
+func (a *AtomicValue[T]) Unwrap() flag.Value { return a.v }
+
+// String satisfies part of the flag.Value interface.
+func (a *AtomicValue[T]) String() string {
+	if a == nil || a.v == nil {
+		return "" // the zero value, as used by flag.PrintDefaults
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.v.String()
+}
+
+// Set satisfies part of the flag.Value interface.
+func (a *AtomicValue[T]) Set(s string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.v.Set(s)
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is that of the wrapped value.
+func (a *AtomicValue[T]) Get() any {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.v.Get()
+}
+
+// IsBoolFlag reports whether the wrapped value is a boolean flag.
+func (a *AtomicValue[T]) IsBoolFlag() bool { return isBoolFlag(a.v) }
+
+// Replace discards the values of a wrapped value that accumulates values,
+// such as a multiflag.Value, and sets it from each of vals in order, all
+// while holding the lock, so that readers see either the previous values or
+// all of the new ones. If a value is invalid, the previous values are
+// restored. Replace reports an error if the wrapped value has no Reset
+// method.
+func (a *AtomicValue[T]) Replace(vals []string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	r, ok := findValue[resetter](a.v)
+	if !ok {
+		return fmt.Errorf("%T cannot be reset", a.v)
+	}
+	old := valueArgs(a.v)
+	r.Reset()
+	for _, s := range vals {
+		if err := a.v.Set(s); err != nil {
+			r.Reset()
+			for _, o := range old {
+				a.v.Set(o)
+			}
+			return fmt.Errorf("invalid value %q: %w", s, err)
+		}
+	}
+	return nil
+}
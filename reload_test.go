@@ -0,0 +1,312 @@
+package goflags
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/creachadair/goflags/fileflag"
+	"github.com/creachadair/goflags/levelflag"
+	"github.com/creachadair/goflags/multiflag"
+	"github.com/creachadair/goflags/regexpflag"
+)
+
+func TestReloader(t *testing.T) {
+	path := writeFile(t, "config.json", `{"level": "debug", "filter": "^a", "port": 80}`)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	level := Atomic[slog.Level](new(levelflag.Value))
+	filter := Atomic[*regexp.Regexp](new(regexpflag.Value))
+	fs.Var(level, "level", "Log level")
+	fs.Var(filter, "filter", "Filter")
+	port := fs.Int("port", 0, "Port")
+	name := fs.String("name", "x", "Name")
+	if err := fs.Parse([]string{"-name", "y"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	r := NewReloader(fs, path)
+	var changes []string
+	r.OnChange("level", func(old, new string) {
+		changes = append(changes, "level:"+old+"->"+new)
+	})
+	r.Reloadable("filter", "name")
+
+	mustReload := func(want ...string) {
+		t.Helper()
+		got, err := r.Reload()
+		if err != nil {
+			t.Fatalf("Reload: unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Reload: got changes %q, want %q", got, want)
+		}
+	}
+
+	mustReload("filter", "level")
+	if got, want := level.Load(), slog.LevelDebug; got != want {
+		t.Errorf("Level: got %v, want %v", got, want)
+	}
+	if got := filter.Load(); got == nil || got.String() != "^a" {
+		t.Errorf("Filter: got %v, want ^a", got)
+	}
+	if *port != 0 {
+		t.Errorf("Port: got %d, want 0 (not reloadable)", *port)
+	}
+
+	// Reloading an unchanged file changes nothing.
+	mustReload()
+
+	// A flag removed from the file is reset to its default, and a flag set on
+	// the command line is not changed.
+	rewriteFile(t, path, `{"filter": "^b", "name": "z"}`)
+	mustReload("filter", "level")
+	if got, want := level.Load(), slog.LevelInfo; got != want {
+		t.Errorf("Level: got %v, want %v", got, want)
+	}
+	if got := filter.Load(); got == nil || got.String() != "^b" {
+		t.Errorf("Filter: got %v, want ^b", got)
+	}
+	if *name != "y" {
+		t.Errorf("Name: got %q, want y (from the command line)", *name)
+	}
+	if want := []string{"level:INFO->DEBUG", "level:DEBUG->INFO"}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("Changes: got %q, want %q", changes, want)
+	}
+
+	// Errors are reported, and callbacks are not called.
+	changes = nil
+	for _, bad := range []string{`{"level": "bogus"}`, `{"nonesuch": 1}`, `{`} {
+		rewriteFile(t, path, bad)
+		if _, err := r.Reload(); err == nil {
+			t.Errorf("Reload %s: got nil, want error", bad)
+		}
+	}
+	if len(changes) != 0 {
+		t.Errorf("Changes after errors: got %q, want none", changes)
+	}
+}
+
+func TestReloaderReset(t *testing.T) {
+	path := writeFile(t, "config.json", `{"tag": ["a", "b"], "config": "/tmp/x.conf", "port": 80}`)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	tags := multiflag.Wrap(func() flag.Getter { return new(boolValue) })
+	fs.Var(tags, "tag", "Tags")
+	config := &fileflag.Value{Path: "/etc/app.conf"} // DefValue is quoted
+	fs.Var(config, "config", "Config file")
+	port := fs.Int("port", 0, "Port")
+
+	r := NewReloader(fs, path)
+	r.Reloadable("tag", "config", "port")
+
+	// Reloading a repeatable value replaces its values.
+	for range 2 {
+		if _, err := r.Reload(); err != nil {
+			t.Fatalf("Reload: unexpected error: %v", err)
+		}
+		if got := tags.Len(); got != 2 {
+			t.Errorf("Tags: got %d values, want 2", got)
+		}
+	}
+
+	// An invalid value restores the flags set before it.
+	rewriteFile(t, path, `{"tag": ["c"], "config": "/tmp/y.conf", "port": "bogus"}`)
+	if _, err := r.Reload(); err == nil {
+		t.Error("Reload: got nil, want error")
+	}
+	if tags.Len() != 2 || config.Path != "/tmp/x.conf" || *port != 80 {
+		t.Errorf("After error: got %d tags, -config %q, -port %d; want 2, /tmp/x.conf, 80",
+			tags.Len(), config.Path, *port)
+	}
+
+	// Flags removed from the file are reset to their defaults.
+	rewriteFile(t, path, `{}`)
+	if _, err := r.Reload(); err != nil {
+		t.Fatalf("Reload: unexpected error: %v", err)
+	}
+	if tags.Len() != 0 || config.Path != "/etc/app.conf" || *port != 0 {
+		t.Errorf("After reset: got %d tags, -config %q, -port %d; want defaults",
+			tags.Len(), config.Path, *port)
+	}
+}
+
+func TestReloaderAtomicRepeatable(t *testing.T) {
+	path := writeFile(t, "config.json", `{"tag": ["a", "b", "c"]}`)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	tags := Atomic[[]any](multiflag.Wrap(func() flag.Getter { return new(boolValue) }))
+	fs.Var(tags, "tag", "Tags")
+	r := NewReloader(fs, path)
+	r.Reloadable("tag")
+	if _, err := r.Reload(); err != nil {
+		t.Fatalf("Reload: unexpected error: %v", err)
+	}
+
+	// Readers see only complete values while the flag is reloaded.
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if n := len(tags.Load()); n != 3 {
+				t.Errorf("Load during reload: got %d values, want 3", n)
+				return
+			}
+		}
+	}()
+	for range 100 {
+		if _, err := r.Reload(); err != nil {
+			t.Fatalf("Reload: unexpected error: %v", err)
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	if err := tags.Replace([]string{"true", "false"}); err != nil {
+		t.Errorf("Replace: unexpected error: %v", err)
+	} else if n := len(tags.Load()); n != 2 {
+		t.Errorf("After Replace: got %d values, want 2", n)
+	}
+}
+
+func TestReloaderCallback(t *testing.T) {
+	path := writeFile(t, "config.json", `{"port": 80}`)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("port", 0, "Port")
+	fs.String("name", "", "Name")
+
+	// A callback may use the Reloader without deadlock.
+	r := NewReloader(fs, path)
+	var calls int
+	r.OnChange("port", func(_, _ string) {
+		calls++
+		r.Reloadable("name")
+		if _, err := r.Reload(); err != nil {
+			t.Errorf("Reload in callback: unexpected error: %v", err)
+		}
+	})
+	if _, err := r.Reload(); err != nil {
+		t.Fatalf("Reload: unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Callbacks: got %d calls, want 1", calls)
+	}
+}
+
+func TestReloaderUndefined(t *testing.T) {
+	r := NewReloader(flag.NewFlagSet("test", flag.ContinueOnError), "config.json")
+	defer func() {
+		if recover() == nil {
+			t.Error("Reloadable did not panic for an undefined flag")
+		}
+	}()
+	r.Reloadable("nonesuch")
+}
+
+func TestWatch(t *testing.T) {
+	path := writeFile(t, "config.yaml", "level: warn\n")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	level := Atomic[slog.Level](new(levelflag.Value))
+	fs.Var(level, "level", "Log level")
+
+	r := NewReloader(fs, path)
+	changed := make(chan string, 10)
+	r.OnChange("level", func(_, new string) { changed <- new })
+	var mu sync.Mutex
+	var errs []error
+	r.OnError = func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Watch(ctx, time.Millisecond) }()
+
+	wait := func(want string) {
+		t.Helper()
+		select {
+		case got := <-changed:
+			if got != want {
+				t.Errorf("Changed: got %s, want %s", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for %s", want)
+		}
+	}
+	wait("WARN")
+	rewriteFile(t, path, "level: error # with a longer line\n")
+	wait("ERROR")
+	for range 100 {
+		_ = level.Load() // concurrent reads are safe
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Watch: got %v, want %v", err, context.Canceled)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(errs) != 0 {
+		t.Errorf("Watch errors: %v", errs)
+	}
+}
+
+func TestAtomic(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	v := Atomic[bool](new(boolValue))
+	fs.Var(v, "b", "Bool")
+	if err := fs.Parse([]string{"-b"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !v.Load() || v.String() != "true" || v.Get() != true {
+		t.Errorf("Value: got %v, want true", v.String())
+	}
+	if got := Atomic[string](new(boolValue)).Load(); got != "" {
+		t.Errorf("Load with the wrong type: got %q, want empty", got)
+	}
+	var zero *AtomicValue[int]
+	if got := zero.String(); got != "" {
+		t.Errorf("String of nil: got %q, want empty", got)
+	}
+	if strings.Contains(v.String(), "\"") {
+		t.Errorf("String: got %q, want unquoted", v.String())
+	}
+}
+
+// rewriteFile replaces the content of the file at path.
+func rewriteFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Writing %s: %v", path, err)
+	}
+}
+
+// boolValue is a boolean flag.Getter for testing.
+type boolValue bool
+
+func (b *boolValue) String() string     { return map[bool]string{true: "true", false: "false"}[bool(*b)] }
+func (b *boolValue) Set(s string) error { *b = boolValue(s == "true"); return nil }
+func (b *boolValue) Get() any           { return bool(*b) }
+func (b *boolValue) IsBoolFlag() bool   { return true }
@@ -0,0 +1,207 @@
+// Package resourceflag defines a flag.Value implementation for CPU and memory
+// resource requests, in the notation used by Kubernetes.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/resourceflag"
+//	)
+//
+//	var limits resourceflag.Value
+//	func init() {
+//	  flag.Var(&limits, "limits", limits.Help("Resource limits for each task"))
+//	}
+//
+// With this definition "-limits cpu=500m,mem=1.5Gi" requests half of a CPU
+// and 1.5 GiB of memory. CPU is given as a number of cores, either decimal
+// ("1.5") or in thousandths with an "m" suffix ("1500m"). Memory is given as a
+// number of bytes with an optional suffix: "Ki", "Mi", "Gi", and so on are
+// powers of 2, while "k", "M", "G", and so on are powers of 10; the "m"
+// suffix, which Kubernetes reads as thousandths, is not accepted. Each Set
+// updates only the resources it names, so the flag may be repeated.
+package resourceflag
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/creachadair/goflags/sizeflag"
+)
+
+// Resources is a quantity of compute resources.
+type Resources struct {
+	MilliCPU int64 // CPU in thousandths of a core
+	Memory   int64 // memory in bytes
+}
+
+// CPU returns the CPU quantity of r as a number of cores.
+func (r Resources) CPU() float64 { return float64(r.MilliCPU) / 1000 }
+
+// String renders r in the syntax accepted by Parse.
+func (r Resources) String() string {
+	var parts []string
+	if r.MilliCPU != 0 {
+		parts = append(parts, "cpu="+FormatCPU(r.MilliCPU))
+	}
+	if r.Memory != 0 {
+		parts = append(parts, "mem="+FormatMemory(r.Memory))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Parse parses a comma-separated list of "cpu=..." and "mem=..." settings.
+// The key "memory" is accepted as a synonym for "mem".
+func Parse(s string) (Resources, error) {
+	var r Resources
+	return r, r.update(s)
+}
+
+func (r *Resources) update(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return errors.New("resourceflag: empty resource list")
+	}
+	for _, elt := range strings.Split(s, ",") {
+		key, val, ok := strings.Cut(strings.TrimSpace(elt), "=")
+		if !ok {
+			return fmt.Errorf("resourceflag: invalid resource %q, expected name=quantity", elt)
+		}
+		var err error
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "cpu":
+			r.MilliCPU, err = ParseCPU(val)
+		case "mem", "memory":
+			r.Memory, err = ParseMemory(val)
+		default:
+			return fmt.Errorf("resourceflag: unknown resource %q, expected one of (cpu|mem)", key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseCPU parses a CPU quantity and returns it in thousandths of a core.
+// It accepts a decimal number of cores with at most three fractional digits,
+// such as "2" or "0.25", or an integer with an "m" suffix, such as "250m".
+func ParseCPU(s string) (int64, error) {
+	t := strings.TrimSpace(s)
+	if num, ok := strings.CutSuffix(t, "m"); ok {
+		n, err := strconv.ParseInt(num, 10, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("resourceflag: invalid CPU quantity %q", s)
+		}
+		return n, nil
+	}
+	whole, frac, _ := strings.Cut(t, ".")
+	if whole == "" && frac == "" || len(frac) > 3 || !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("resourceflag: invalid CPU quantity %q", s)
+	}
+	frac += strings.Repeat("0", 3-len(frac))
+	n, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("resourceflag: CPU quantity %q out of range", s)
+	}
+	return n, nil
+}
+
+// FormatCPU renders a CPU quantity in thousandths of a core as a whole number
+// of cores if possible, or otherwise with an "m" suffix.
+func FormatCPU(milli int64) string {
+	if milli%1000 == 0 {
+		return strconv.FormatInt(milli/1000, 10)
+	}
+	return strconv.FormatInt(milli, 10) + "m"
+}
+
+// ParseMemory parses a memory quantity and returns it in bytes. A suffix
+// ending in "i", such as "Gi", denotes a power of 2; other suffixes denote
+// powers of 10. The quantity is parsed by the sizeflag package, except that
+// a lowercase "m" suffix is rejected, since Kubernetes reads it as thousandths
+// of a byte rather than millions.
+func ParseMemory(s string) (int64, error) {
+	t := strings.TrimSpace(s)
+	parse := sizeflag.Parse10
+	if num, ok := strings.CutSuffix(t, "i"); ok {
+		if num == "" || !strings.ContainsRune("kKmMgGtTpPeE", rune(num[len(num)-1])) {
+			return 0, fmt.Errorf("resourceflag: invalid memory quantity %q", s)
+		}
+		t, parse = num, sizeflag.Parse2
+	}
+	if strings.HasSuffix(t, "m") {
+		return 0, fmt.Errorf("resourceflag: invalid memory quantity %q (use M for megabytes)", s)
+	}
+	n, err := parse(t)
+	if err != nil || t == "" || n < 0 {
+		return 0, fmt.Errorf("resourceflag: invalid memory quantity %q", s)
+	}
+	return n, nil
+}
+
+var (
+	binSuffix = []string{"", "Ki", "Mi", "Gi", "Ti", "Pi", "Ei"}
+	decSuffix = []string{"", "k", "M", "G", "T", "P", "E"}
+)
+
+// FormatMemory renders a memory quantity in bytes in the shortest form that
+// represents it exactly, preferring a binary suffix in case of a tie.
+func FormatMemory(n int64) string {
+	bin := format(n, binSuffix, 1024)
+	if dec := format(n, decSuffix, 1000); len(dec) < len(bin) {
+		return dec
+	}
+	return bin
+}
+
+// format renders n with the largest suffix whose scale divides n exactly.
+func format(n int64, suffix []string, base int64) string {
+	i := 0
+	for n != 0 && n%base == 0 && i+1 < len(suffix) {
+		n /= base
+		i++
+	}
+	return strconv.FormatInt(n, 10) + suffix[i]
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// A Value represents a quantity of resources. A pointer to a Value satisfies
+// the flag.Value and flag.Getter interfaces.
+type Value struct {
+	Resources Resources
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string { return h + " (cpu=N[m],mem=SIZE, e.g., cpu=500m,mem=1.5Gi)" }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return v.Resources.String() }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	r := v.Resources
+	if err := r.update(s); err != nil {
+		return err
+	}
+	v.Resources = r
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Resources.
+func (v *Value) Get() any { return v.Resources }
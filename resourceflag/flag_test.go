@@ -0,0 +1,69 @@
+package resourceflag
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var limits Value
+	fs := flag.NewFlagSet("resource", flag.ContinueOnError)
+	fs.Var(&limits, "limits", limits.Help("Limits"))
+
+	if got, want := limits.String(), `""`; got != want {
+		t.Errorf("Initial -limits: got %s, want %s", got, want)
+	}
+	if err := fs.Parse([]string{"-limits", "cpu=500m,mem=1.5Gi", "-limits", "cpu=2"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	want := Resources{MilliCPU: 2000, Memory: 3 << 29}
+	if got := limits.Get().(Resources); got != want {
+		t.Errorf("Value for -limits: got %+v, want %+v", got, want)
+	}
+	if got, want := limits.String(), `"cpu=2,mem=1536Mi"`; got != want {
+		t.Errorf("String for -limits: got %s, want %s", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Resources
+		str   string
+	}{
+		{"cpu=250m", Resources{MilliCPU: 250}, "cpu=250m"},
+		{"cpu=0.25", Resources{MilliCPU: 250}, "cpu=250m"},
+		{"cpu=.5", Resources{MilliCPU: 500}, "cpu=500m"},
+		{"CPU=4", Resources{MilliCPU: 4000}, "cpu=4"},
+		{"mem=512Mi", Resources{Memory: 512 << 20}, "mem=512Mi"},
+		{"memory=2G", Resources{Memory: 2e9}, "mem=2G"},
+		{"mem=128974848", Resources{Memory: 128974848}, "mem=123Mi"},
+		{"mem=1500", Resources{Memory: 1500}, "mem=1500"},
+		{"mem=1500k", Resources{Memory: 1500000}, "mem=1500k"},
+		{"cpu=1.5, mem=1Ki", Resources{MilliCPU: 1500, Memory: 1024}, "cpu=1500m,mem=1Ki"},
+	}
+	for _, test := range tests {
+		got, err := Parse(test.input)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", test.input, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("Parse(%q): got %+v, want %+v", test.input, got, test.want)
+		}
+		if s := got.String(); s != test.str {
+			t.Errorf("String(%+v): got %q, want %q", got, s, test.str)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, input := range []string{
+		"", "cpu", "gpu=1", "cpu=x", "cpu=-1", "cpu=1.2345", "cpu=1.5m",
+		"mem=", "mem=5i", "mem=1Xi", "mem=-1Gi", "mem=lots", "mem=512m", "mem=1mi",
+	} {
+		if got, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q): got %+v, wanted error", input, got)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Package secretflag defines a flag.Value implementation for secret values,
+// such as passwords and access tokens, that are never rendered as text.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/secretflag"
+//	)
+//
+//	var apiKey secretflag.Value
+//	func init() {
+//	  flag.Var(&apiKey, "api-key", apiKey.Help("Service API key"))
+//	}
+//
+// The flag argument may have one of the following forms:
+//
+//	env:NAME     -- the value of environment variable NAME
+//	file:PATH    -- the contents of the file at PATH
+//	literal:TEXT -- the literal string TEXT
+//	TEXT         -- the literal string TEXT, if no other form applies
+//
+// A single trailing newline is removed from the contents of a file.
+//
+// The String method of a Value always returns "[redacted]" so that secrets do
+// not leak via usage messages, logs, or error messages.
+package secretflag
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Redacted is the string rendering of every Value.
+const Redacted = "[redacted]"
+
+// A Value represents a secret. A pointer to a Value satisfies the flag.Value
+// and flag.Getter interfaces.
+type Value struct {
+	data []byte
+	set  bool
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	return h + " (env:NAME, file:PATH, or literal)"
+}
+
+// Bytes returns the secret value. The caller must not modify the result.
+func (v *Value) Bytes() []byte { return v.data }
+
+// Text returns the secret value as a string.
+func (v *Value) Text() string { return string(v.data) }
+
+// IsSet reports whether the value has been set.
+func (v *Value) IsSet() bool { return v.set }
+
+// String satisfies part of the flag.Value interface.
+// It always returns Redacted.
+func (v Value) String() string { return Redacted }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	data, err := resolve(s)
+	if err != nil {
+		return err
+	}
+	v.data, v.set = data, true
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type []byte.
+func (v *Value) Get() any { return v.data }
+
+// Snapshot satisfies the goflags.Snapshotter interface. It records the
+// current secret, which String does not report, and returns a function that
+// restores it.
+func (v *Value) Snapshot() func() {
+	data, set := v.data, v.set
+	return func() { v.data, v.set = data, set }
+}
+
+// GoString returns Redacted, so that the secret does not leak through the
+// %#v formatting verb.
+func (v Value) GoString() string { return Redacted }
+
+// resolve returns the secret described by s. Error messages do not include
+// literal values, only the names of variables and paths.
+func resolve(s string) ([]byte, error) {
+	if name, ok := strings.CutPrefix(s, "env:"); ok {
+		if name == "" {
+			return nil, errors.New("secretflag: empty variable name")
+		}
+		val, ok := os.LookupEnv(name)
+		if !ok {
+			return nil, fmt.Errorf("secretflag: environment variable %q is not set", name)
+		}
+		return []byte(val), nil
+	}
+	if path, ok := strings.CutPrefix(s, "file:"); ok {
+		if path == "" {
+			return nil, errors.New("secretflag: empty path")
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("secretflag: reading secret from %q failed", path)
+		}
+		if t, ok := bytes.CutSuffix(data, []byte("\n")); ok {
+			data, _ = bytes.CutSuffix(t, []byte("\r"))
+		}
+		return data, nil
+	}
+	return []byte(strings.TrimPrefix(s, "literal:")), nil
+}
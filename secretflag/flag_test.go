@@ -0,0 +1,83 @@
+package secretflag
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	const secret = "hunter2"
+	t.Setenv("SECRETFLAG_TEST_KEY", secret)
+
+	var key Value
+
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("secret", flag.ContinueOnError)
+	fs.Var(&key, "key", key.Help("The key"))
+	fs.SetOutput(&buf)
+
+	if key.IsSet() {
+		t.Error("Initial value for -key is set")
+	}
+	if err := fs.Parse([]string{"-key", "env:SECRETFLAG_TEST_KEY"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got := string(key.Get().([]byte)); got != secret {
+		t.Errorf("Value for -key: got %q, want %q", got, secret)
+	}
+
+	// The secret does not appear in any of the usual renderings.
+	fs.PrintDefaults()
+	for _, s := range []string{
+		buf.String(),
+		key.String(),
+		fmt.Sprint(&key),
+		fmt.Sprintf("%v %+v %#v %s %x", key, key, key, key, key),
+		fs.Lookup("key").Value.String(),
+	} {
+		if strings.Contains(s, secret) {
+			t.Errorf("Secret leaked in %q", s)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token")
+	if err := os.WriteFile(path, []byte("from-file\r\n"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	t.Setenv("SECRETFLAG_EMPTY", "")
+
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"", ""},
+		{"literal:env:X", "env:X"},
+		{"file:" + path, "from-file"},
+		{"env:SECRETFLAG_EMPTY", ""},
+	}
+	for _, test := range tests {
+		var v Value
+		if err := v.Set(test.in); err != nil {
+			t.Errorf("Set(%q) failed: %v", test.in, err)
+		} else if got := v.Text(); got != test.want {
+			t.Errorf("Set(%q): got %q, want %q", test.in, got, test.want)
+		}
+	}
+
+	for _, bad := range []string{"env:", "env:SECRETFLAG_NOT_DEFINED", "file:", "file:" + filepath.Join(dir, "nope")} {
+		var v Value
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %q, wanted error", bad, v.Text())
+		} else {
+			t.Logf("Set(%q) gave expected error: %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package seedflag
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var seed, rnd Value
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	fs.Var(&seed, "seed", seed.Help("Seed"))
+	fs.Var(&rnd, "rnd", rnd.Help("Random seed"))
+
+	if got := seed.Get().(uint64); got != 0 {
+		t.Errorf("Initial value for -seed: got %d, want 0", got)
+	}
+	if err := fs.Parse([]string{"-seed", "0x2a", "-rnd", "random"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got := seed.Uint64(); got != 42 || seed.WasRandom() {
+		t.Errorf("Value for -seed: got %d, random=%v; want 42, false", got, seed.WasRandom())
+	}
+	if !rnd.WasRandom() {
+		t.Error("WasRandom for -rnd: got false, want true")
+	}
+
+	// The recorded seed of a random value reproduces it.
+	var again Value
+	if err := again.Set(rnd.String()); err != nil {
+		t.Fatalf("Set(%q): unexpected error: %v", rnd.String(), err)
+	}
+	if again.Uint64() != rnd.Uint64() || again.WasRandom() {
+		t.Errorf("Replayed seed: got %d, want %d", again.Uint64(), rnd.Uint64())
+	}
+}
+
+func TestValues(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int64
+	}{
+		{"0", 0},
+		{"12345", 12345},
+		{"-1", -1},
+		{"-0x10", -16},
+		{"0xffffffffffffffff", -1},
+		{"-9223372036854775808", -1 << 63},
+		{"18446744073709551615", -1},
+	}
+	for _, test := range tests {
+		var v Value
+		if err := v.Set(test.input); err != nil {
+			t.Errorf("Set(%q): unexpected error: %v", test.input, err)
+		} else if got := v.Int64(); got != test.want {
+			t.Errorf("Set(%q): got %d, want %d", test.input, got, test.want)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, bad := range []string{"", "rand", "1.5", "0x", "18446744073709551616", "-9223372036854775809"} {
+		var v Value
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %v, wanted error", bad, v.Uint64())
+		}
+	}
+}
@@ -0,0 +1,318 @@
+// Package selectorflag defines a flag.Value implementation that parses label
+// selector expressions in the style of Kubernetes.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/selectorflag"
+//	)
+//
+//	var sel selectorflag.Value
+//	func init() {
+//	  flag.Var(&sel, "l", sel.Help("Select objects by label"))
+//	}
+//
+//	  ...
+//	  if sel.Selector.Matches(obj.Labels) { ... }
+//
+// A selector is a comma-separated list of requirements, all of which must be
+// satisfied:
+//
+//	key            label key is present
+//	!key           label key is absent
+//	key=value      label key has the given value (also "==")
+//	key!=value     label key is absent or has a different value
+//	key in (a,b)   label key has one of the listed values
+//	key notin (a,b)  label key is absent or has none of the listed values
+package selectorflag
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// An Op is the operator of a requirement.
+type Op int
+
+const (
+	Exists       Op = iota // key
+	DoesNotExist           // !key
+	Equals                 // key=value
+	NotEquals              // key!=value
+	In                     // key in (values)
+	NotIn                  // key notin (values)
+)
+
+// A Requirement is a single condition on a label.
+type Requirement struct {
+	Key    string
+	Op     Op
+	Values []string // one value for Equals and NotEquals; empty for Exists and DoesNotExist
+}
+
+// Matches reports whether labels satisfy r.
+func (r Requirement) Matches(labels map[string]string) bool {
+	val, ok := labels[r.Key]
+	switch r.Op {
+	case Exists:
+		return ok
+	case DoesNotExist:
+		return !ok
+	case Equals, In:
+		return ok && slices.Contains(r.Values, val)
+	case NotEquals, NotIn:
+		return !ok || !slices.Contains(r.Values, val)
+	}
+	panic(fmt.Sprintf("selectorflag: invalid operator %d", r.Op))
+}
+
+// String returns the requirement in the syntax accepted by Parse.
+func (r Requirement) String() string {
+	switch r.Op {
+	case Exists:
+		return r.Key
+	case DoesNotExist:
+		return "!" + r.Key
+	case Equals:
+		return r.Key + "=" + r.Values[0]
+	case NotEquals:
+		return r.Key + "!=" + r.Values[0]
+	case In:
+		return r.Key + " in (" + strings.Join(r.Values, ",") + ")"
+	case NotIn:
+		return r.Key + " notin (" + strings.Join(r.Values, ",") + ")"
+	}
+	return fmt.Sprintf("%s <invalid op %d>", r.Key, r.Op)
+}
+
+// A Selector is a conjunction of requirements. The empty selector matches all
+// label sets.
+type Selector []Requirement
+
+// Matches reports whether labels satisfy every requirement of s.
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the selector in the syntax accepted by Parse.
+func (s Selector) String() string {
+	parts := make([]string, len(s))
+	for i, r := range s {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// Parse parses a selector expression.
+func Parse(s string) (Selector, error) {
+	var sel Selector
+	rest := strings.TrimSpace(s)
+	for rest != "" {
+		// Find the end of this requirement: the first comma not inside parens.
+		depth, end := 0, len(rest)
+	scan:
+		for i := 0; i < len(rest); i++ {
+			switch rest[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			case ',':
+				if depth == 0 {
+					end = i
+					break scan
+				}
+			}
+		}
+		r, err := parseRequirement(strings.TrimSpace(rest[:end]))
+		if err != nil {
+			return nil, err
+		}
+		sel = append(sel, r)
+		if end == len(rest) {
+			break
+		}
+		rest = strings.TrimSpace(rest[end+1:])
+		if rest == "" {
+			return nil, errors.New("selectorflag: trailing comma")
+		}
+	}
+	return sel, nil
+}
+
+// MustParse parses a selector expression, and panics if it is invalid.
+// It is intended for use in defining default values.
+func MustParse(s string) Selector {
+	sel, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return sel
+}
+
+func parseRequirement(s string) (Requirement, error) {
+	if s == "" {
+		return Requirement{}, errors.New("selectorflag: empty requirement")
+	}
+	if key, ok := strings.CutPrefix(s, "!"); ok {
+		key = strings.TrimSpace(key)
+		if err := checkKey(key); err != nil {
+			return Requirement{}, err
+		}
+		return Requirement{Key: key, Op: DoesNotExist}, nil
+	}
+	if i := strings.IndexAny(s, "!="); i >= 0 {
+		key := strings.TrimSpace(s[:i])
+		op, val := Equals, s[i+1:]
+		if s[i] == '!' {
+			var ok bool
+			if val, ok = strings.CutPrefix(val, "="); !ok {
+				return Requirement{}, fmt.Errorf("selectorflag: invalid requirement %q", s)
+			}
+			op = NotEquals
+		} else {
+			val = strings.TrimPrefix(val, "=") // "=="
+		}
+		val = strings.TrimSpace(val)
+		if err := checkKey(key); err != nil {
+			return Requirement{}, err
+		}
+		if err := checkValue(val); err != nil {
+			return Requirement{}, err
+		}
+		return Requirement{Key: key, Op: op, Values: []string{val}}, nil
+	}
+	if key, list, ok := strings.Cut(s, "("); ok {
+		key = strings.TrimSpace(key)
+		op := In
+		if k, ok := cutWord(key, "notin"); ok {
+			key, op = k, NotIn
+		} else if k, ok := cutWord(key, "in"); ok {
+			key = k
+		} else {
+			return Requirement{}, fmt.Errorf("selectorflag: invalid requirement %q, want in or notin", s)
+		}
+		list, ok := strings.CutSuffix(strings.TrimSpace(list), ")")
+		if !ok {
+			return Requirement{}, fmt.Errorf("selectorflag: missing ) in %q", s)
+		}
+		if err := checkKey(key); err != nil {
+			return Requirement{}, err
+		}
+		var vals []string
+		for _, val := range strings.Split(list, ",") {
+			val = strings.TrimSpace(val)
+			if err := checkValue(val); err != nil {
+				return Requirement{}, err
+			}
+			vals = append(vals, val)
+		}
+		if len(vals) == 1 && vals[0] == "" {
+			return Requirement{}, fmt.Errorf("selectorflag: empty value list in %q", s)
+		}
+		return Requirement{Key: key, Op: op, Values: vals}, nil
+	}
+	if err := checkKey(s); err != nil {
+		return Requirement{}, err
+	}
+	return Requirement{Key: s, Op: Exists}, nil
+}
+
+// cutWord reports whether s ends with a blank followed by word, and if so
+// returns the rest of s without trailing blanks.
+func cutWord(s, word string) (string, bool) {
+	rest, ok := strings.CutSuffix(s, word)
+	if !ok || rest == "" || (rest[len(rest)-1] != ' ' && rest[len(rest)-1] != '\t') {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
+
+// checkKey reports an error if key is not a valid label key: an optional DNS
+// subdomain prefix and "/", followed by a name of at most 63 characters.
+func checkKey(key string) error {
+	name := key
+	if i := strings.LastIndexByte(key, '/'); i >= 0 {
+		prefix := key[:i]
+		name = key[i+1:]
+		if prefix == "" || len(prefix) > 253 || !isName(strings.ReplaceAll(prefix, ".", "-"), false) {
+			return fmt.Errorf("selectorflag: invalid key prefix %q", prefix)
+		}
+	}
+	if name == "" || len(name) > 63 || !isName(name, true) {
+		return fmt.Errorf("selectorflag: invalid key %q", key)
+	}
+	return nil
+}
+
+// checkValue reports an error if val is not a valid label value: empty, or a
+// name of at most 63 characters.
+func checkValue(val string) error {
+	if val != "" && (len(val) > 63 || !isName(val, true)) {
+		return fmt.Errorf("selectorflag: invalid value %q", val)
+	}
+	return nil
+}
+
+// isName reports whether s consists of letters, digits, and "-" (and also
+// "_" and "." if punct is true), beginning and ending with a letter or digit.
+func isName(s string, punct bool) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '-' || punct && (c == '_' || c == '.'):
+			if i == 0 || i == len(s)-1 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
+
+// A Value represents a label selector. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The selector parsed from the flag. The zero value matches everything.
+	Selector Selector
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	return h + " (e.g., env=prod,tier!=db,release in (a,b))"
+}
+
+// Matches reports whether labels satisfy the selector.
+func (v *Value) Matches(labels map[string]string) bool { return v.Selector.Matches(labels) }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return v.Selector.String() }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	sel, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	v.Selector = sel
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Selector.
+func (v *Value) Get() any { return v.Selector }
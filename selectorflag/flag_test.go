@@ -0,0 +1,76 @@
+package selectorflag
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var sel Value
+	fs := flag.NewFlagSet("selector", flag.ContinueOnError)
+	fs.Var(&sel, "l", sel.Help("Label selector"))
+
+	if !sel.Matches(map[string]string{"any": "thing"}) {
+		t.Error("Empty selector does not match")
+	}
+	if err := fs.Parse([]string{"-l", "env=prod, tier!=db,release in (a, b),!legacy"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := sel.String(), `"env=prod,tier!=db,release in (a,b),!legacy"`; got != want {
+		t.Errorf("String: got %s, want %s", got, want)
+	}
+	if got := sel.Get().(Selector); len(got) != 4 {
+		t.Errorf("Get: got %d requirements, want 4", len(got))
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		sel    string
+		labels map[string]string
+		want   bool
+	}{
+		{"", nil, true},
+		{"a", map[string]string{"a": ""}, true},
+		{"a", map[string]string{"b": "1"}, false},
+		{"!a", map[string]string{"b": "1"}, true},
+		{"!a", map[string]string{"a": "1"}, false},
+		{"a=1", map[string]string{"a": "1"}, true},
+		{"a==1", map[string]string{"a": "2"}, false},
+		{"a=1", nil, false},
+		{"a!=1", nil, true},
+		{"a!=1", map[string]string{"a": "1"}, false},
+		{"a in (x,y)", map[string]string{"a": "y"}, true},
+		{"a in (x,y)", map[string]string{"a": "z"}, false},
+		{"a notin (x,y)", map[string]string{"a": "z"}, true},
+		{"a notin (x,y)", nil, true},
+		{"a notin (x,y)", map[string]string{"a": "x"}, false},
+		{"example.com/app=web,a", map[string]string{"example.com/app": "web", "a": ""}, true},
+		{"example.com/app=web,a", map[string]string{"example.com/app": "web"}, false},
+		{"a=", map[string]string{"a": ""}, true},
+	}
+	for _, test := range tests {
+		sel, err := Parse(test.sel)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", test.sel, err)
+			continue
+		}
+		if got := sel.Matches(test.labels); got != test.want {
+			t.Errorf("Parse(%q).Matches(%v): got %v, want %v", test.sel, test.labels, got, test.want)
+		}
+		if rt, err := Parse(sel.String()); err != nil || rt.String() != sel.String() {
+			t.Errorf("Parse(%q) did not round-trip: got %q, %v", sel.String(), rt, err)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, bad := range []string{
+		"a=1,", ",a", "a,,b", "!", "a!1", "-a=1", "a=-x", "a in x", "a in (x", "a within (x)",
+		"a in ()", "bad key=1", "/a", "a/=1", "a=" + string(make([]byte, 64)),
+	} {
+		if sel, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q): got %v, wanted error", bad, sel)
+		}
+	}
+}
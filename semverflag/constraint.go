@@ -0,0 +1,172 @@
+package semverflag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Constraint represents a set of conditions on a semantic version. A pointer
+// to a Constraint satisfies the flag.Value and flag.Getter interfaces.
+//
+// A constraint is a list of alternatives separated by "||", each of which is a
+// list of comparisons separated by spaces or commas, all of which must hold.
+// Each comparison is an operator followed by a version:
+//
+//	=1.2.3   -- exactly 1.2.3 (also "1.2.3" with no operator)
+//	!=1.2.3  -- any version except 1.2.3
+//	>1.2.3, >=1.2.3, <1.2.3, <=1.2.3
+//	~1.2.3   -- at least 1.2.3, but below 1.3.0
+//	^1.2.3   -- at least 1.2.3, but below 2.0.0 (below 0.3.0 for ^0.2.3)
+//
+// The version in a comparison may omit the minor and patch numbers, which are
+// then zero, except that "~1" means ">=1.0.0 <2.0.0". For example:
+//
+//	>=1.2.0 <2.0.0 || >=3
+//
+// Comparisons use semantic version precedence, so ">=1.2.0 <2.0.0" accepts the
+// pre-release "2.0.0-rc.1". The upper bounds implied by "~" and "^" exclude
+// the pre-releases of the bound, so "^1.2.3" rejects "2.0.0-rc.1".
+//
+// An empty constraint accepts any version.
+type Constraint struct {
+	alts [][]comparison
+	src  string
+}
+
+type comparison struct {
+	op  string // one of "=", "!=", ">", ">=", "<", "<="
+	ver Version
+}
+
+func (c comparison) check(v Version) bool {
+	n := v.Compare(c.ver)
+	switch c.op {
+	case "=":
+		return n == 0
+	case "!=":
+		return n != 0
+	case ">":
+		return n > 0
+	case ">=":
+		return n >= 0
+	case "<":
+		return n < 0
+	case "<=":
+		return n <= 0
+	}
+	panic("invalid operator " + c.op)
+}
+
+// ParseConstraint parses a constraint expression.
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{src: strings.TrimSpace(s)}
+	if c.src == "" {
+		return c, nil
+	}
+	for _, alt := range strings.Split(s, "||") {
+		terms := strings.FieldsFunc(alt, func(r rune) bool { return r == ' ' || r == ',' || r == '\t' })
+		if len(terms) == 0 {
+			return Constraint{}, fmt.Errorf("semverflag: empty alternative in %q", s)
+		}
+		var cmps []comparison
+		for i := 0; i < len(terms); i++ {
+			term := terms[i]
+
+			// Permit a space between an operator and its version, as in ">= 1.2".
+			if strings.Trim(term, "=!<>~^") == "" && i+1 < len(terms) {
+				term += terms[i+1]
+				i++
+			}
+			cs, err := parseComparison(term)
+			if err != nil {
+				return Constraint{}, fmt.Errorf("semverflag: %w in %q", err, s)
+			}
+			cmps = append(cmps, cs...)
+		}
+		c.alts = append(c.alts, cmps)
+	}
+	return c, nil
+}
+
+// Check reports whether v satisfies c.
+func (c Constraint) Check(v Version) bool {
+	if len(c.alts) == 0 {
+		return true
+	}
+	for _, alt := range c.alts {
+		ok := true
+		for _, cmp := range alt {
+			if !cmp.check(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// String satisfies part of the flag.Value interface.
+func (c *Constraint) String() string { return fmt.Sprintf("%q", c.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (c *Constraint) ArgString() string { return c.src }
+
+// Set satisfies part of the flag.Value interface.
+func (c *Constraint) Set(s string) error {
+	p, err := ParseConstraint(s)
+	if err != nil {
+		return err
+	}
+	*c = p
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Constraint.
+func (c *Constraint) Get() any { return *c }
+
+// parseComparison parses a single operator and version, expanding the tilde
+// and caret operators into a pair of bounds.
+func parseComparison(s string) ([]comparison, error) {
+	op := "="
+	for _, pfx := range []string{"!=", ">=", "<=", "=", ">", "<", "~", "^"} {
+		if rest, ok := strings.CutPrefix(s, pfx); ok {
+			op, s = pfx, rest
+			break
+		}
+	}
+	v, n, err := parsePartial(strings.TrimPrefix(s, "v"))
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case "~":
+		hi := Version{Major: v.Major, Minor: v.Minor + 1}
+		if n == 1 {
+			hi = Version{Major: v.Major + 1}
+		}
+		return []comparison{{">=", v}, {"<", upper(hi)}}, nil
+	case "^":
+		var hi Version
+		switch {
+		case v.Major > 0 || n == 1:
+			hi = Version{Major: v.Major + 1}
+		case v.Minor > 0 || n == 2:
+			hi = Version{Minor: v.Minor + 1}
+		default:
+			hi = Version{Patch: v.Patch + 1}
+		}
+		return []comparison{{">=", v}, {"<", upper(hi)}}, nil
+	}
+	return []comparison{{op, v}}, nil
+}
+
+// upper returns the lowest pre-release of v, so that an exclusive upper bound
+// of 2.0.0 also excludes the pre-releases of 2.0.0.
+func upper(v Version) Version {
+	v.Pre = []string{"0"}
+	return v
+}
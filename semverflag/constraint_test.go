@@ -0,0 +1,73 @@
+package semverflag
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestConstraint(t *testing.T) {
+	var supported Constraint
+
+	fs := flag.NewFlagSet("constraint", flag.ContinueOnError)
+	fs.Var(&supported, "supported", "Supported versions")
+
+	if !supported.Check(MustParse("99.0.0")) {
+		t.Error("Empty constraint rejected a version")
+	}
+	if err := fs.Parse([]string{"-supported", ">=1.2.0 <2.0.0"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := supported.String(), `">=1.2.0 <2.0.0"`; got != want {
+		t.Errorf("Value for -supported: got %s, want %s", got, want)
+	}
+	if !supported.Get().(Constraint).Check(MustParse("1.5.0")) {
+		t.Error("Check(1.5.0): got false, want true")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		expr string
+		yes  []string
+		no   []string
+	}{
+		{"1.2.3", []string{"1.2.3", "1.2.3+b"}, []string{"1.2.4", "1.2.3-rc"}},
+		{"=1.2", []string{"1.2.0"}, []string{"1.2.1"}},
+		{"!=1.2.3", []string{"1.2.4"}, []string{"1.2.3"}},
+		{">=1.2.0 <2.0.0", []string{"1.2.0", "1.9.9", "2.0.0-rc.1"}, []string{"1.1.9", "2.0.0"}},
+		{">= 1.2, < 2", []string{"1.2.0"}, []string{"2.0.0"}},
+		{">1.0.0 <=1.1.0", []string{"1.0.1", "1.1.0"}, []string{"1.0.0", "1.1.1"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.2.2", "1.3.0", "1.3.0-a"}},
+		{"~1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{"^1.2.3", []string{"1.2.3", "1.9.0"}, []string{"1.2.2", "2.0.0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"^0.0", []string{"0.0.5"}, []string{"0.1.0"}},
+		{"<1 || >=3", []string{"0.9.0", "3.1.0"}, []string{"1.0.0", "2.9.9"}},
+		{"v1.0.0", []string{"1.0.0"}, nil},
+	}
+	for _, test := range tests {
+		c, err := ParseConstraint(test.expr)
+		if err != nil {
+			t.Errorf("ParseConstraint(%q) failed: %v", test.expr, err)
+			continue
+		}
+		for _, v := range test.yes {
+			if !c.Check(MustParse(v)) {
+				t.Errorf("%q.Check(%s): got false, want true", test.expr, v)
+			}
+		}
+		for _, v := range test.no {
+			if c.Check(MustParse(v)) {
+				t.Errorf("%q.Check(%s): got true, want false", test.expr, v)
+			}
+		}
+	}
+
+	for _, bad := range []string{">=", "1.2.3 ||", "|| 1.0", ">>1.0.0", "=>1.0", "~1.x", "1.2-rc"} {
+		if _, err := ParseConstraint(bad); err == nil {
+			t.Errorf("ParseConstraint(%q): got nil, wanted error", bad)
+		}
+	}
+}
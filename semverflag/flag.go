@@ -0,0 +1,226 @@
+// Package semverflag defines flag.Value implementations for semantic versions
+// as described by https://semver.org, and for constraints on them.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/semverflag"
+//	)
+//
+//	var target semverflag.Value
+//	var supported semverflag.Constraint
+//
+//	func init() {
+//	  flag.Var(&target, "target", "Target version")
+//	  flag.Var(&supported, "supported", "Supported versions, e.g., \">=1.2.0 <2.0.0\"")
+//	}
+//
+//	  ...
+//	  if !supported.Check(target.Version) { ... }
+//
+// A version may have an optional "v" prefix, which is not preserved.
+package semverflag
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Version is a semantic version. The zero value is version 0.0.0.
+type Version struct {
+	Major, Minor, Patch int
+
+	// The dot-separated identifiers of the pre-release suffix, if any.
+	// For example, "1.0.0-rc.1" has Pre == []string{"rc", "1"}.
+	Pre []string
+
+	// The build metadata suffix, if any, without the leading "+".
+	// Build metadata is not considered when comparing versions.
+	Build string
+}
+
+// Parse parses a semantic version, with an optional "v" prefix.
+func Parse(s string) (Version, error) {
+	v, rest, err := parsePartial(strings.TrimPrefix(s, "v"))
+	if err != nil || rest != 3 {
+		return Version{}, fmt.Errorf("semverflag: invalid version %q", s)
+	}
+	return v, nil
+}
+
+// MustParse parses a semantic version, and panics if this fails.
+func MustParse(s string) Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// String renders v in canonical form, without a "v" prefix.
+func (v Version) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Pre) != 0 {
+		sb.WriteString("-" + strings.Join(v.Pre, "."))
+	}
+	if v.Build != "" {
+		sb.WriteString("+" + v.Build)
+	}
+	return sb.String()
+}
+
+// Compare compares v and w by semantic version precedence, returning -1 if
+// v < w, 0 if they are equal, and +1 if v > w.
+func (v Version) Compare(w Version) int {
+	if c := cmp.Compare(v.Major, w.Major); c != 0 {
+		return c
+	} else if c := cmp.Compare(v.Minor, w.Minor); c != 0 {
+		return c
+	} else if c := cmp.Compare(v.Patch, w.Patch); c != 0 {
+		return c
+	}
+
+	// A version without a pre-release suffix has higher precedence.
+	switch {
+	case len(v.Pre) == 0 && len(w.Pre) == 0:
+		return 0
+	case len(v.Pre) == 0:
+		return 1
+	case len(w.Pre) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.Pre) && i < len(w.Pre); i++ {
+		if c := comparePre(v.Pre[i], w.Pre[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(v.Pre), len(w.Pre))
+}
+
+// Less reports whether v has lower precedence than w.
+func (v Version) Less(w Version) bool { return v.Compare(w) < 0 }
+
+// IsPrerelease reports whether v has a pre-release suffix.
+func (v Version) IsPrerelease() bool { return len(v.Pre) != 0 }
+
+// A Value represents a semantic version. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The version parsed from the flag.
+	Version Version
+
+	// If true, versions with a pre-release suffix are rejected.
+	NoPrerelease bool
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return v.Version.String() }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	ver, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	if v.NoPrerelease && ver.IsPrerelease() {
+		return fmt.Errorf("semverflag: pre-release version %q not allowed", s)
+	}
+	v.Version = ver
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Version.
+func (v *Value) Get() any { return v.Version }
+
+// parsePartial parses a version in which the minor and patch numbers may be
+// omitted, returning the version and the number of components present.
+// Omitted components are zero. A pre-release or build suffix is permitted
+// only if all three numbers are present.
+func parsePartial(s string) (Version, int, error) {
+	var v Version
+	core, build, hasBuild := strings.Cut(s, "+")
+	if hasBuild {
+		if !validIdents(build, false) {
+			return Version{}, 0, fmt.Errorf("invalid build metadata %q", build)
+		}
+		v.Build = build
+	}
+	core, pre, hasPre := strings.Cut(core, "-")
+	if hasPre {
+		if !validIdents(pre, true) {
+			return Version{}, 0, fmt.Errorf("invalid pre-release %q", pre)
+		}
+		v.Pre = strings.Split(pre, ".")
+	}
+
+	nums := strings.Split(core, ".")
+	if len(nums) > 3 || ((hasPre || hasBuild) && len(nums) != 3) {
+		return Version{}, 0, fmt.Errorf("invalid version %q", s)
+	}
+	dst := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, n := range nums {
+		if !isNumeric(n) || (len(n) > 1 && n[0] == '0') {
+			return Version{}, 0, fmt.Errorf("invalid version number %q", n)
+		}
+		z, err := strconv.Atoi(n)
+		if err != nil {
+			return Version{}, 0, err
+		}
+		*dst[i] = z
+	}
+	return v, len(nums), nil
+}
+
+// validIdents reports whether s is a non-empty dot-separated list of
+// identifiers of [0-9A-Za-z-]. If pre is true, numeric identifiers must not
+// have leading zeroes.
+func validIdents(s string, pre bool) bool {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return false
+		}
+		for _, c := range id {
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '-') {
+				return false
+			}
+		}
+		if pre && isNumeric(id) && len(id) > 1 && id[0] == '0' {
+			return false
+		}
+	}
+	return true
+}
+
+func isNumeric(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// comparePre compares pre-release identifiers: numeric identifiers compare
+// numerically and have lower precedence than alphanumeric identifiers, which
+// compare lexically.
+func comparePre(a, b string) int {
+	an, bn := isNumeric(a), isNumeric(b)
+	switch {
+	case an && bn:
+		if c := cmp.Compare(len(a), len(b)); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	case an:
+		return -1
+	case bn:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
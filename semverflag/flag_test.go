@@ -0,0 +1,74 @@
+package semverflag
+
+import (
+	"flag"
+	"io"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var target Value
+	stable := Value{NoPrerelease: true}
+
+	fs := flag.NewFlagSet("semver", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(&target, "target", "Target version")
+	fs.Var(&stable, "stable", "Stable version")
+
+	if got, want := target.String(), "0.0.0"; got != want {
+		t.Errorf("Initial value for -target: got %q, want %q", got, want)
+	}
+	if err := fs.Parse([]string{"-target", "v1.2.3-rc.1+build.5", "-stable", "2.0.0"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := target.String(), "1.2.3-rc.1+build.5"; got != want {
+		t.Errorf("Value for -target: got %q, want %q", got, want)
+	}
+	if got := target.Get().(Version); !got.Less(stable.Version) {
+		t.Errorf("Less(%v, %v): got false, want true", got, stable.Version)
+	}
+	if err := fs.Parse([]string{"-stable", "2.1.0-beta"}); err == nil {
+		t.Error("Parse pre-release -stable: got nil, wanted error")
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, in := range []string{
+		"0.0.0", "1.2.3", "10.20.30", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-0.3.7",
+		"1.0.0-x.7.z.92", "1.0.0-x-y-z.--", "1.0.0+20130313144700", "1.0.0-beta+exp.sha.5114f85",
+		"1.0.0+21AF26D3----117B344092BD",
+	} {
+		v, err := Parse(in)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", in, err)
+		} else if got := v.String(); got != in {
+			t.Errorf("Parse(%q): got %q", in, got)
+		}
+	}
+	for _, bad := range []string{
+		"", "1", "1.2", "1.2.3.4", "01.2.3", "1.02.3", "1.2.03", "1.2.3-", "1.2.3+",
+		"1.2.3-01", "1.2.3-a..b", "1.2.3-a_b", "a.b.c", "-1.2.3", "1.2-rc", "vv1.2.3",
+	} {
+		if v, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q): got %v, wanted error", bad, v)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// Each is strictly less than its successor, per semver.org section 11.
+	order := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0",
+		"10.0.0",
+	}
+	for i := 0; i+1 < len(order); i++ {
+		a, b := MustParse(order[i]), MustParse(order[i+1])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Errorf("Compare(%v, %v): got %d, want -1", a, b, a.Compare(b))
+		}
+	}
+	if c := MustParse("1.0.0+a").Compare(MustParse("1.0.0+b")); c != 0 {
+		t.Errorf("Compare with build metadata: got %d, want 0", c)
+	}
+}
@@ -0,0 +1,135 @@
+// Package setflag defines a flag.Value implementation that collects a set of
+// distinct strings.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/setflag"
+//	)
+//
+//	// Restrict the members to a fixed universe, as with enumflag.
+//	var features = setflag.New("auth", "cache", "metrics", "tracing")
+//	func init() {
+//	  flag.Var(features, "enable", features.Help("Features to enable"))
+//	}
+//
+//	  ...
+//	  if features.Has("cache") { ... }
+//
+// Each occurrence of the flag adds one or more comma-separated members, so
+// "-enable cache,metrics -enable CACHE" yields the set {cache, metrics}.
+// Duplicates are collapsed.
+package setflag
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// A Value represents a set of strings. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces. The zero value accepts any non-empty
+// strings as members, compared exactly.
+type Value struct {
+	allowed []string // if non-empty, the universe of members
+	members map[string]bool
+}
+
+// New returns a *Value whose members are restricted to the given keys.
+// Members are compared without regard to case, as in enumflag, and are
+// reported with the spelling given here.
+func New(allowed ...string) *Value { return &Value{allowed: allowed} }
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	if len(v.allowed) == 0 {
+		return h + " (comma-separated; repeatable)"
+	}
+	return fmt.Sprintf("%s (any of %s)", h, strings.Join(v.allowed, "|"))
+}
+
+// Choices returns the allowed members of the set, in the order given to New,
+// or nil if any strings are allowed.
+func (v *Value) Choices() []string { return slices.Clone(v.allowed) }
+
+// Has reports whether key is a member of the set. If the set has an allowed
+// universe, key is compared without regard to case.
+func (v *Value) Has(key string) bool {
+	if k, ok := v.lookup(key); ok {
+		return v.members[k]
+	}
+	return false
+}
+
+// Len returns the number of members in the set.
+func (v *Value) Len() int { return len(v.members) }
+
+// Slice returns the members of the set. If the set has an allowed universe,
+// members are in the order of the universe; otherwise they are sorted.
+func (v *Value) Slice() []string {
+	var out []string
+	if len(v.allowed) != 0 {
+		for _, key := range v.allowed {
+			if v.members[key] {
+				out = append(out, key)
+			}
+		}
+		return out
+	}
+	for key := range v.members {
+		out = append(out, key)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return strings.Join(v.Slice(), ",") }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	var add []string
+	for _, elt := range strings.Split(s, ",") {
+		elt = strings.TrimSpace(elt)
+		if elt == "" {
+			return errors.New("setflag: empty member")
+		}
+		key, ok := v.lookup(elt)
+		if !ok {
+			return fmt.Errorf("expected any of (%s)", strings.Join(v.allowed, "|"))
+		}
+		add = append(add, key)
+	}
+	if v.members == nil {
+		v.members = make(map[string]bool)
+	}
+	for _, key := range add {
+		v.members[key] = true
+	}
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type []string, as returned by Slice.
+func (v *Value) Get() any { return v.Slice() }
+
+// lookup returns the canonical spelling of key, and reports whether key is a
+// permitted member.
+func (v *Value) lookup(key string) (string, bool) {
+	if len(v.allowed) == 0 {
+		return key, true
+	}
+	for _, a := range v.allowed {
+		if strings.EqualFold(a, key) {
+			return a, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,60 @@
+package setflag
+
+import (
+	"flag"
+	"slices"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	features := New("auth", "cache", "metrics", "tracing")
+	var tags Value
+
+	fs := flag.NewFlagSet("set", flag.ContinueOnError)
+	fs.Var(features, "enable", features.Help("Features"))
+	fs.Var(&tags, "tag", tags.Help("Tags"))
+
+	if got, want := features.Choices(), []string{"auth", "cache", "metrics", "tracing"}; !slices.Equal(got, want) {
+		t.Errorf("Choices for -enable: got %q, want %q", got, want)
+	}
+	if got := tags.Choices(); got != nil {
+		t.Errorf("Choices for -tag: got %q, want nil", got)
+	}
+
+	if err := fs.Parse([]string{
+		"-enable", "metrics,cache",
+		"-enable", "CACHE",
+		"-tag", "b, a",
+		"-tag", "b",
+		"-tag", "B",
+	}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	if got, want := features.Get().([]string), []string{"cache", "metrics"}; !slices.Equal(got, want) {
+		t.Errorf("Value for -enable: got %q, want %q", got, want)
+	}
+	if !features.Has("Metrics") || features.Has("auth") || features.Has("bogus") {
+		t.Errorf("Has: wrong membership for %v", features)
+	}
+	if got, want := tags.Slice(), []string{"B", "a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("Value for -tag: got %q, want %q", got, want)
+	}
+	if got, want := tags.String(), `"B,a,b"`; got != want {
+		t.Errorf("String for -tag: got %s, want %s", got, want)
+	}
+	if got := tags.Len(); got != 3 {
+		t.Errorf("Len for -tag: got %d, want 3", got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, bad := range []string{"", "a,", "auth,bogus"} {
+		v := New("auth", "cache")
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %v, wanted error", bad, v)
+		} else if v.Len() != 0 {
+			t.Errorf("Set(%q) failed but added members: %v", bad, v)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Package shellflag defines a flag.Value implementation that splits a command
+// line into words using the quoting rules of the POSIX shell.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//	  "os/exec"
+//
+//	  "github.com/creachadair/goflags/shellflag"
+//	)
+//
+//	var command = shellflag.Value{NoMeta: true}
+//	func init() {
+//	  flag.Var(&command, "exec", command.Help("Command to run"))
+//	}
+//
+//	  ...
+//	  cmd := exec.Command(command.Args[0], command.Args[1:]...)
+//
+// With this definition, "-exec \"sh -c 'echo hi'\"" yields the three words
+// "sh", "-c", and "echo hi". No variable expansion, globbing, or command
+// substitution is performed.
+package shellflag
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// A Value represents a command split into words. A pointer to a Value
+// satisfies the flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The words parsed from the flag.
+	Args []string
+
+	// If true, unquoted shell metacharacters such as "|", ";", "$", and "*"
+	// are rejected, as are "$" and "`" inside double quotes. This guards
+	// against a command that was written expecting a real shell to interpret
+	// it.
+	NoMeta bool
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string { return h + " (shell words)" }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return Join(v.Args) }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	args, err := split(s, v.NoMeta)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New("shellflag: empty command")
+	}
+	v.Args = args
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type []string.
+func (v *Value) Get() any { return v.Args }
+
+// Split splits s into words using the quoting rules of the POSIX shell.
+// Words are separated by unquoted blanks and newlines. Single quotes preserve
+// their contents literally; within double quotes a backslash escapes only
+// "$", "`", `"`, "\", and newline; elsewhere a backslash escapes any
+// character. Metacharacters have no special meaning.
+func Split(s string) ([]string, error) { return split(s, false) }
+
+// Join combines args into a single string which, when passed to Split,
+// returns the original args. Words that contain no special characters are
+// not quoted.
+func Join(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = Quote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// Quote returns s quoted so that it is treated as a single word by the shell.
+// If s contains no special characters, it is returned unmodified.
+func Quote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if !strings.ContainsFunc(s, needsQuote) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// metachars are the characters with special meaning to the shell outside of
+// quotes, excluding blanks and quotation marks.
+const metachars = "|&;<>()$`*?[]{}~#!"
+
+func needsQuote(r rune) bool {
+	return isBlank(r) || r == '\'' || r == '"' || r == '\\' || strings.ContainsRune(metachars, r)
+}
+
+func isBlank(r rune) bool { return r == ' ' || r == '\t' || r == '\n' }
+
+func split(s string, noMeta bool) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inWord := false
+
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case isBlank(r):
+			if inWord {
+				args = append(args, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+			continue
+
+		case r == '\\':
+			i++
+			if i == len(rs) {
+				return nil, errors.New("shellflag: trailing backslash")
+			}
+			if rs[i] != '\n' { // backslash-newline is a line continuation
+				cur.WriteRune(rs[i])
+			}
+
+		case r == '\'':
+			end := indexRune(rs, i+1, '\'')
+			if end < 0 {
+				return nil, errors.New("shellflag: unterminated single quote")
+			}
+			cur.WriteString(string(rs[i+1 : end]))
+			i = end
+
+		case r == '"':
+			i++
+			for ; i < len(rs) && rs[i] != '"'; i++ {
+				switch c := rs[i]; {
+				case c == '\\' && i+1 < len(rs) && strings.ContainsRune("$`\"\\\n", rs[i+1]):
+					i++
+					if rs[i] != '\n' {
+						cur.WriteRune(rs[i])
+					}
+				case noMeta && (c == '$' || c == '`'):
+					return nil, fmt.Errorf("shellflag: metacharacter %q not allowed", c)
+				default:
+					cur.WriteRune(c)
+				}
+			}
+			if i == len(rs) {
+				return nil, errors.New("shellflag: unterminated double quote")
+			}
+
+		case noMeta && strings.ContainsRune(metachars, r):
+			return nil, fmt.Errorf("shellflag: metacharacter %q not allowed", r)
+
+		default:
+			cur.WriteRune(r)
+		}
+		inWord = true
+	}
+	if inWord {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
+
+func indexRune(rs []rune, start int, r rune) int {
+	for i := start; i < len(rs); i++ {
+		if rs[i] == r {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,86 @@
+package shellflag
+
+import (
+	"flag"
+	"slices"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var cmd Value
+	fs := flag.NewFlagSet("shell", flag.ContinueOnError)
+	fs.Var(&cmd, "exec", cmd.Help("Command to run"))
+
+	if err := fs.Parse([]string{"-exec", `sh -c 'echo hi'`}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	want := []string{"sh", "-c", "echo hi"}
+	if got := cmd.Get().([]string); !slices.Equal(got, want) {
+		t.Errorf("Value for -exec: got %q, want %q", got, want)
+	}
+	if got, want := cmd.String(), `"sh -c 'echo hi'"`; got != want {
+		t.Errorf("String: got %s, want %s", got, want)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", nil},
+		{"  \t ", nil},
+		{"a b  c", []string{"a", "b", "c"}},
+		{`a\ b c`, []string{"a b", "c"}},
+		{`'it''s'`, []string{"its"}},
+		{`'don'\''t'`, []string{"don't"}},
+		{`"a \"b\" \$c \x"`, []string{`a "b" $c \x`}},
+		{`x"y"'z'`, []string{"xyz"}},
+		{`'' ""`, []string{"", ""}},
+		{"a\\\nb", []string{"ab"}},
+		{`echo $HOME | wc`, []string{"echo", "$HOME", "|", "wc"}},
+	}
+	for _, test := range tests {
+		got, err := Split(test.input)
+		if err != nil {
+			t.Errorf("Split(%q): unexpected error: %v", test.input, err)
+			continue
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("Split(%q): got %q, want %q", test.input, got, test.want)
+		}
+		if len(got) != 0 {
+			if rt, err := Split(Join(got)); err != nil || !slices.Equal(rt, got) {
+				t.Errorf("Split(Join(%q)): got %q, %v", got, rt, err)
+			}
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		v     Value
+		input string
+	}{
+		{Value{}, ""},
+		{Value{}, "  "},
+		{Value{}, `a 'b`},
+		{Value{}, `a "b`},
+		{Value{}, `a\`},
+		{Value{NoMeta: true}, "ls | wc"},
+		{Value{NoMeta: true}, "rm *.go"},
+		{Value{NoMeta: true}, "echo $HOME"},
+		{Value{NoMeta: true}, "a; b"},
+		{Value{NoMeta: true}, `echo "$(date)"`},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.input); err == nil {
+			t.Errorf("Set(%q) with %+v: got %q, wanted error", test.input, test.v, test.v.Args)
+		}
+	}
+
+	ok := Value{NoMeta: true}
+	if err := ok.Set(`grep 'a|b' "*.go" \;`); err != nil {
+		t.Errorf("Set with quoted metacharacters: unexpected error: %v", err)
+	}
+}
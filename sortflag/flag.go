@@ -0,0 +1,180 @@
+// Package sortflag defines a flag.Value implementation for sort orders.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/sortflag"
+//	)
+//
+//	var order = sortflag.Value{Allowed: []string{"name", "size", "mtime"}}
+//	func init() {
+//	  flag.Var(&order, "sort", order.Help("Sort order"))
+//	}
+//
+// With this definition "-sort size:desc,name" sorts by descending size, and
+// then by ascending name. To sort with the resulting order, build a comparator
+// from functions that compare each key:
+//
+//	cmp, err := sortflag.Comparator(order.Order, map[string]func(a, b File) int{
+//	  "name":  func(a, b File) int { return strings.Compare(a.Name, b.Name) },
+//	  "size":  func(a, b File) int { return cmp.Compare(a.Size, b.Size) },
+//	  "mtime": func(a, b File) int { return a.ModTime.Compare(b.ModTime) },
+//	})
+//	...
+//	slices.SortFunc(files, cmp)
+package sortflag
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// A Key is a single sort key with a direction.
+type Key struct {
+	Name string
+	Desc bool // if true, sort in descending order
+}
+
+// String renders k as "name:asc" or "name:desc".
+func (k Key) String() string {
+	if k.Desc {
+		return k.Name + ":desc"
+	}
+	return k.Name + ":asc"
+}
+
+// An Order is an ordered list of sort keys, most significant first.
+type Order []Key
+
+// Parse parses a comma-separated list of keys, each of the form "name",
+// "name:asc", or "name:desc". A key may not occur more than once.
+func Parse(s string) (Order, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var o Order
+	for _, part := range strings.Split(s, ",") {
+		name, dir, _ := strings.Cut(strings.TrimSpace(part), ":")
+		if name == "" {
+			return nil, errors.New("sortflag: empty sort key")
+		}
+		k := Key{Name: name}
+		switch strings.ToLower(dir) {
+		case "", "asc":
+		case "desc":
+			k.Desc = true
+		default:
+			return nil, fmt.Errorf("sortflag: invalid direction %q for %q, expected asc or desc", dir, name)
+		}
+		if o.Has(name) {
+			return nil, fmt.Errorf("sortflag: duplicate sort key %q", name)
+		}
+		o = append(o, k)
+	}
+	return o, nil
+}
+
+// MustParse parses an order, and panics if it is invalid. It is intended for
+// use in defining default values.
+func MustParse(s string) Order {
+	o, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return o
+}
+
+// Has reports whether o contains a key with the given name.
+func (o Order) Has(name string) bool {
+	return slices.ContainsFunc(o, func(k Key) bool { return k.Name == name })
+}
+
+// String renders o in the syntax accepted by Parse.
+func (o Order) String() string {
+	parts := make([]string, len(o))
+	for i, k := range o {
+		parts[i] = k.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// Comparator returns a function that compares values of type T according to
+// o, using cmps to compare each key in ascending order. The comparison for a
+// descending key is reversed, and ties are broken by the following keys. It
+// reports an error if o contains a key with no entry in cmps.
+func Comparator[T any](o Order, cmps map[string]func(a, b T) int) (func(a, b T) int, error) {
+	type step struct {
+		cmp  func(a, b T) int
+		desc bool
+	}
+	steps := make([]step, len(o))
+	for i, k := range o {
+		c, ok := cmps[k.Name]
+		if !ok {
+			return nil, fmt.Errorf("sortflag: no comparison for key %q", k.Name)
+		}
+		steps[i] = step{cmp: c, desc: k.Desc}
+	}
+	return func(a, b T) int {
+		for _, s := range steps {
+			if c := s.cmp(a, b); c != 0 {
+				if s.desc {
+					return -c
+				}
+				return c
+			}
+		}
+		return 0
+	}, nil
+}
+
+// A Value represents a sort order. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces. Each Set replaces the order.
+type Value struct {
+	// The order parsed from the flag.
+	Order Order
+
+	// If non-empty, the names of the keys that may be used.
+	Allowed []string
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	if len(v.Allowed) != 0 {
+		return fmt.Sprintf("%s (key[:asc|:desc],... with keys %s)", h, strings.Join(v.Allowed, "|"))
+	}
+	return h + " (key[:asc|:desc],...)"
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return v.Order.String() }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	o, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	if len(v.Allowed) != 0 {
+		for _, k := range o {
+			if !slices.Contains(v.Allowed, k.Name) {
+				return fmt.Errorf("sortflag: unknown sort key %q, expected one of (%s)",
+					k.Name, strings.Join(v.Allowed, "|"))
+			}
+		}
+	}
+	v.Order = o
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Order.
+func (v *Value) Get() any { return v.Order }
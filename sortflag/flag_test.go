@@ -0,0 +1,68 @@
+package sortflag
+
+import (
+	"cmp"
+	"flag"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	order := Value{Allowed: []string{"name", "size"}}
+	fs := flag.NewFlagSet("sort", flag.ContinueOnError)
+	fs.Var(&order, "sort", order.Help("Sort order"))
+
+	if err := fs.Parse([]string{"-sort", "size:DESC, name"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := order.String(), `"size:desc,name:asc"`; got != want {
+		t.Errorf("Value for -sort: got %s, want %s", got, want)
+	}
+	if got, want := order.Get().(Order), (Order{{"size", true}, {"name", false}}); !slices.Equal(got, want) {
+		t.Errorf("Order for -sort: got %v, want %v", got, want)
+	}
+}
+
+func TestComparator(t *testing.T) {
+	type file struct {
+		name string
+		size int
+	}
+	cmps := map[string]func(a, b file) int{
+		"name": func(a, b file) int { return strings.Compare(a.name, b.name) },
+		"size": func(a, b file) int { return cmp.Compare(a.size, b.size) },
+	}
+	files := []file{{"b", 1}, {"a", 2}, {"c", 2}, {"a", 1}}
+
+	c, err := Comparator(MustParse("size:desc,name"), cmps)
+	if err != nil {
+		t.Fatalf("Comparator failed: %v", err)
+	}
+	slices.SortFunc(files, c)
+	if want := []file{{"a", 2}, {"c", 2}, {"a", 1}, {"b", 1}}; !slices.Equal(files, want) {
+		t.Errorf("Sorted: got %v, want %v", files, want)
+	}
+
+	if _, err := Comparator(MustParse("mtime"), cmps); err == nil {
+		t.Error("Comparator with unknown key: got nil, wanted error")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		v     Value
+		input string
+	}{
+		{Value{}, ","},
+		{Value{}, ":asc"},
+		{Value{}, "name:up"},
+		{Value{}, "name,size,name:desc"},
+		{Value{Allowed: []string{"name"}}, "size"},
+	}
+	for _, test := range tests {
+		if err := test.v.Set(test.input); err == nil {
+			t.Errorf("Set(%q) with %+v: got %v, wanted error", test.input, test.v, test.v.Order)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+// Package streamflag defines flag.Value implementations for input and output
+// streams named on the command line.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/streamflag"
+//	)
+//
+//	var (
+//	  in  streamflag.Input
+//	  out = streamflag.Output{Eager: true}
+//	)
+//	func init() {
+//	  flag.Var(&in, "in", in.Help("Input file"))
+//	  flag.Var(&out, "out", out.Help("Output file"))
+//	}
+//
+//	  ...
+//	  r, err := in.Open()
+//	  ...
+//	  defer r.Close()
+//
+// The argument "-" denotes standard input (for an Input) or standard output
+// (for an Output), and is the default if the flag is not set. An argument of
+// the form "fd:N" denotes the inherited file descriptor N. Any other argument
+// is the path of a file. Closing a stream for standard input or output does
+// not close the underlying file.
+package streamflag
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// An Input represents an input stream. A pointer to an Input satisfies the
+// flag.Value and flag.Getter interfaces.
+type Input struct {
+	// The argument given for the flag.
+	Name string
+
+	// If true, a file is opened when the flag is set, so that a missing or
+	// unreadable file is reported during flag parsing. Otherwise it is opened
+	// by the Open method.
+	Eager bool
+
+	f io.ReadCloser // if Eager, the opened stream
+}
+
+// Help concatenates a human-readable string summarizing the format of in to
+// h, for use in generating a documentation string.
+func (in *Input) Help(h string) string { return h + " (path, fd:N, or - for stdin)" }
+
+// IsStdin reports whether in denotes standard input.
+func (in *Input) IsStdin() bool { return in.Name == "" || in.Name == "-" }
+
+// Open opens the input stream. If the stream was opened eagerly, Open returns
+// that stream, and it is the caller's responsibility to close it.
+func (in *Input) Open() (io.ReadCloser, error) {
+	if in.f != nil {
+		f := in.f
+		in.f = nil
+		return f, nil
+	}
+	return openInput(in.Name)
+}
+
+// String satisfies part of the flag.Value interface.
+func (in *Input) String() string { return fmt.Sprintf("%q", in.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (in *Input) ArgString() string { return in.Name }
+
+// Set satisfies part of the flag.Value interface.
+func (in *Input) Set(s string) error {
+	if s == "" {
+		return errors.New("streamflag: empty input name")
+	}
+	if _, err := parseFD(s); err != nil {
+		return err
+	}
+	if in.Eager {
+		f, err := openInput(s)
+		if err != nil {
+			return err
+		}
+		if in.f != nil {
+			in.f.Close()
+		}
+		in.f = f
+	}
+	in.Name = s
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is the string of the argument.
+func (in *Input) Get() any { return in.Name }
+
+func openInput(name string) (io.ReadCloser, error) {
+	if name == "" || name == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	if fd, err := parseFD(name); err != nil {
+		return nil, err
+	} else if fd >= 0 {
+		return os.NewFile(uintptr(fd), name), nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("streamflag: %w", err)
+	}
+	return f, nil
+}
+
+// An Output represents an output stream. A pointer to an Output satisfies the
+// flag.Value and flag.Getter interfaces.
+type Output struct {
+	// The argument given for the flag.
+	Name string
+
+	// If true, a file is created when the flag is set, so that an unwritable
+	// path is reported during flag parsing. Otherwise it is created by the
+	// Create method.
+	Eager bool
+
+	// If true, output is appended to an existing file rather than replacing
+	// its contents.
+	Append bool
+
+	// If non-zero, the permissions used to create a new file. The default is
+	// 0644 (before umask).
+	Perm os.FileMode
+
+	f io.WriteCloser // if Eager, the opened stream
+}
+
+// Help concatenates a human-readable string summarizing the format of out to
+// h, for use in generating a documentation string.
+func (out *Output) Help(h string) string { return h + " (path, fd:N, or - for stdout)" }
+
+// IsStdout reports whether out denotes standard output.
+func (out *Output) IsStdout() bool { return out.Name == "" || out.Name == "-" }
+
+// Create opens the output stream. If the stream was opened eagerly, Create
+// returns that stream, and it is the caller's responsibility to close it.
+func (out *Output) Create() (io.WriteCloser, error) {
+	if out.f != nil {
+		f := out.f
+		out.f = nil
+		return f, nil
+	}
+	return out.open(out.Name)
+}
+
+// String satisfies part of the flag.Value interface.
+func (out *Output) String() string { return fmt.Sprintf("%q", out.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (out *Output) ArgString() string { return out.Name }
+
+// Set satisfies part of the flag.Value interface.
+func (out *Output) Set(s string) error {
+	if s == "" {
+		return errors.New("streamflag: empty output name")
+	}
+	if _, err := parseFD(s); err != nil {
+		return err
+	}
+	if out.Eager {
+		f, err := out.open(s)
+		if err != nil {
+			return err
+		}
+		if out.f != nil {
+			out.f.Close()
+		}
+		out.f = f
+	}
+	out.Name = s
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is the string of the argument.
+func (out *Output) Get() any { return out.Name }
+
+func (out *Output) open(name string) (io.WriteCloser, error) {
+	if name == "" || name == "-" {
+		return nopWriteCloser{os.Stdout}, nil
+	}
+	if fd, err := parseFD(name); err != nil {
+		return nil, err
+	} else if fd >= 0 {
+		return os.NewFile(uintptr(fd), name), nil
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if out.Append {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	perm := out.Perm
+	if perm == 0 {
+		perm = 0644
+	}
+	f, err := os.OpenFile(name, flags, perm)
+	if err != nil {
+		return nil, fmt.Errorf("streamflag: %w", err)
+	}
+	return f, nil
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// parseFD returns the descriptor number of a name of the form "fd:N", or -1
+// if name does not have that form.
+func parseFD(name string) (int, error) {
+	rest, ok := strings.CutPrefix(name, "fd:")
+	if !ok {
+		return -1, nil
+	}
+	fd, err := strconv.Atoi(rest)
+	if err != nil || fd < 0 {
+		return 0, fmt.Errorf("streamflag: invalid file descriptor %q", name)
+	}
+	return fd, nil
+}
@@ -0,0 +1,117 @@
+package streamflag
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	dir := t.TempDir()
+	inPath := filepath.Join(dir, "input.txt")
+	outPath := filepath.Join(dir, "output.txt")
+	if err := os.WriteFile(inPath, []byte("hello"), 0600); err != nil {
+		t.Fatalf("Writing input: %v", err)
+	}
+
+	var in Input
+	out := Output{Eager: true}
+	fs := flag.NewFlagSet("stream", flag.ContinueOnError)
+	fs.Var(&in, "in", in.Help("Input"))
+	fs.Var(&out, "out", out.Help("Output"))
+
+	if !in.IsStdin() || !out.IsStdout() {
+		t.Errorf("Initial streams: stdin=%v stdout=%v, want true, true", in.IsStdin(), out.IsStdout())
+	}
+	if err := fs.Parse([]string{"-in", inPath, "-out", outPath}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if _, err := os.Stat(outPath); err != nil {
+		t.Errorf("Eager output was not created: %v", err)
+	}
+
+	r, err := in.Open()
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	w, err := out.Create()
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		t.Errorf("Copy failed: %v", err)
+	}
+	r.Close()
+	if err := w.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	if data, err := os.ReadFile(outPath); err != nil || string(data) != "hello" {
+		t.Errorf("Output: got %q, %v; want hello", data, err)
+	}
+}
+
+func TestDescriptor(t *testing.T) {
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Pipe: %v", err)
+	}
+	defer pr.Close()
+
+	var out Output
+	if err := out.Set("fd:" + strconv.Itoa(int(pw.Fd()))); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	w, err := out.Create()
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	io.WriteString(w, "via fd")
+	w.Close()
+	pw.Close() // already closed via w; this disarms its finalizer
+
+	if data, err := io.ReadAll(pr); err != nil || string(data) != "via fd" {
+		t.Errorf("Read from pipe: got %q, %v; want %q", data, err, "via fd")
+	}
+}
+
+func TestStdio(t *testing.T) {
+	var in Input
+	r, err := in.Open()
+	if err != nil {
+		t.Fatalf("Open stdin: %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("Close stdin: %v", err)
+	}
+	if _, err := os.Stdin.Stat(); err != nil {
+		t.Errorf("Stdin was closed: %v", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	dir := t.TempDir()
+	for _, s := range []string{"", "fd:", "fd:x", "fd:-1", filepath.Join(dir, "missing")} {
+		in := Input{Eager: true}
+		if err := in.Set(s); err == nil {
+			t.Errorf("Input.Set(%q): got nil, wanted error", s)
+		}
+	}
+	for _, s := range []string{"", "fd:nope", filepath.Join(dir, "no", "such", "dir")} {
+		out := Output{Eager: true}
+		if err := out.Set(s); err == nil {
+			t.Errorf("Output.Set(%q): got nil, wanted error", s)
+		}
+	}
+
+	// Without Eager, a missing file is reported by Open.
+	var in Input
+	if err := in.Set(filepath.Join(dir, "missing")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if _, err := in.Open(); err == nil {
+		t.Error("Open missing file: got nil, wanted error")
+	}
+}
@@ -0,0 +1,175 @@
+// Package tagflag defines a flag.Value implementation for lists of key/value
+// tags, such as the tags or labels attached to cloud resources.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/tagflag"
+//	)
+//
+//	var tags = tagflag.Value{Rules: tagflag.AWS}
+//	func init() {
+//	  flag.Var(&tags, "tag", tags.Help("Resource tags"))
+//	}
+//
+// With this definition "-tag team=infra,env=prod -tag owner=bob" gives three
+// tags. A key given more than once takes the last value. The rules are
+// checked as each tag is set, so that a tag the provider would reject is
+// reported when the flag is parsed.
+package tagflag
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Rules are constraints on a set of tags.
+type Rules struct {
+	Name string // a short description, e.g., "AWS tag"
+
+	MaxKeyLen   int // if positive, the maximum length of a key in characters
+	MaxValueLen int // if positive, the maximum length of a value in characters
+	MaxCount    int // if positive, the maximum number of tags
+
+	Key   *regexp.Regexp // if non-nil, each key must match this pattern
+	Value *regexp.Regexp // if non-nil, each non-empty value must match this pattern
+
+	// Keys beginning with any of these prefixes are rejected, without regard
+	// to case.
+	ReservedPrefixes []string
+
+	// If true, tags with empty values are rejected.
+	RequireValue bool
+}
+
+// Predefined rules for common providers.
+var (
+	// AWS describes the rules for AWS resource tags.
+	AWS = Rules{
+		Name:             "AWS tag",
+		MaxKeyLen:        128,
+		MaxValueLen:      256,
+		MaxCount:         50,
+		Key:              regexp.MustCompile(`^[\pL\pZ\pN_.:/=+\-@]+$`),
+		Value:            regexp.MustCompile(`^[\pL\pZ\pN_.:/=+\-@]*$`),
+		ReservedPrefixes: []string{"aws:"},
+	}
+
+	// GCP describes the rules for Google Cloud resource labels.
+	GCP = Rules{
+		Name:        "GCP label",
+		MaxKeyLen:   63,
+		MaxValueLen: 63,
+		MaxCount:    64,
+		Key:         regexp.MustCompile(`^\p{Ll}[\p{Ll}\p{Lo}\pN_-]*$`),
+		Value:       regexp.MustCompile(`^[\p{Ll}\p{Lo}\pN_-]*$`),
+	}
+
+	// Azure describes the rules for Azure resource tags.
+	Azure = Rules{
+		Name:             "Azure tag",
+		MaxKeyLen:        512,
+		MaxValueLen:      256,
+		MaxCount:         50,
+		Key:              regexp.MustCompile(`^[^<>%&\\?/]+$`),
+		ReservedPrefixes: []string{"microsoft", "azure", "windows"},
+	}
+)
+
+// Check reports an error if the tag key=value does not satisfy r, ignoring
+// the limit on the number of tags.
+func (r Rules) Check(key, value string) error {
+	name := r.Name
+	if name == "" {
+		name = "tag"
+	}
+	switch {
+	case key == "":
+		return fmt.Errorf("tagflag: empty %s key", name)
+	case r.MaxKeyLen > 0 && utf8.RuneCountInString(key) > r.MaxKeyLen:
+		return fmt.Errorf("tagflag: %s key %q is longer than %d characters", name, key, r.MaxKeyLen)
+	case r.Key != nil && !r.Key.MatchString(key):
+		return fmt.Errorf("tagflag: invalid %s key %q", name, key)
+	case r.RequireValue && value == "":
+		return fmt.Errorf("tagflag: %s %q has an empty value", name, key)
+	case r.MaxValueLen > 0 && utf8.RuneCountInString(value) > r.MaxValueLen:
+		return fmt.Errorf("tagflag: %s value for %q is longer than %d characters", name, key, r.MaxValueLen)
+	case r.Value != nil && value != "" && !r.Value.MatchString(value):
+		return fmt.Errorf("tagflag: invalid %s value %q for %q", name, value, key)
+	}
+	for _, p := range r.ReservedPrefixes {
+		if len(key) >= len(p) && strings.EqualFold(key[:len(p)], p) {
+			return fmt.Errorf("tagflag: %s key %q uses reserved prefix %q", name, key, p)
+		}
+	}
+	return nil
+}
+
+// A Value represents a set of tags. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces. Each Set adds one or more
+// comma-separated "key=value" tags to the set.
+type Value struct {
+	// The tags parsed from the flag.
+	Tags map[string]string
+
+	// The rules each tag must satisfy. The zero value accepts any tag with a
+	// non-empty key.
+	Rules Rules
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	if v.Rules.MaxCount > 0 {
+		return fmt.Sprintf("%s (key=value,..., repeatable, at most %d)", h, v.Rules.MaxCount)
+	}
+	return h + " (key=value,..., repeatable)"
+}
+
+// String satisfies part of the flag.Value interface. Tags are listed in
+// order of key.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string {
+	parts := make([]string, 0, len(v.Tags))
+	for _, k := range slices.Sorted(maps.Keys(v.Tags)) {
+		parts = append(parts, k+"="+v.Tags[k])
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	if s == "" {
+		return errors.New("tagflag: empty tag")
+	}
+	next := maps.Clone(v.Tags)
+	if next == nil {
+		next = make(map[string]string)
+	}
+	for _, elt := range strings.Split(s, ",") {
+		key, val, _ := strings.Cut(elt, "=")
+		key = strings.TrimSpace(key)
+		if err := v.Rules.Check(key, val); err != nil {
+			return err
+		}
+		next[key] = val
+	}
+	if n := v.Rules.MaxCount; n > 0 && len(next) > n {
+		return fmt.Errorf("tagflag: %d tags given, at most %d allowed", len(next), n)
+	}
+	v.Tags = next
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type map[string]string.
+func (v *Value) Get() any { return v.Tags }
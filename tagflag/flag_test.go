@@ -0,0 +1,66 @@
+package tagflag
+
+import (
+	"flag"
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	tags := Value{Rules: AWS}
+	fs := flag.NewFlagSet("tag", flag.ContinueOnError)
+	fs.Var(&tags, "tag", tags.Help("Tags"))
+
+	if got, want := tags.String(), `""`; got != want {
+		t.Errorf("Initial -tag: got %s, want %s", got, want)
+	}
+	if err := fs.Parse([]string{"-tag", "team=infra,env=dev", "-tag", "env=prod", "-tag", "Name=web server"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	want := map[string]string{"team": "infra", "env": "prod", "Name": "web server"}
+	if got := tags.Get().(map[string]string); !maps.Equal(got, want) {
+		t.Errorf("Value for -tag: got %v, want %v", got, want)
+	}
+	if got, want := tags.String(), `"Name=web server,env=prod,team=infra"`; got != want {
+		t.Errorf("String for -tag: got %s, want %s", got, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		v     Value
+		input string
+	}{
+		{Value{}, ""},
+		{Value{}, "=x"},
+		{Value{}, "a=1,,b=2"},
+		{Value{Rules: Rules{RequireValue: true}}, "a"},
+		{Value{Rules: Rules{RequireValue: true}}, "a="},
+		{Value{Rules: AWS}, "aws:createdBy=me"},
+		{Value{Rules: AWS}, "AWS:x=y"},
+		{Value{Rules: AWS}, strings.Repeat("k", 129) + "=v"},
+		{Value{Rules: AWS}, "k=" + strings.Repeat("v", 257)},
+		{Value{Rules: AWS}, "cost#center=1"},
+		{Value{Rules: GCP}, "Team=infra"},
+		{Value{Rules: GCP}, "1team=infra"},
+		{Value{Rules: GCP}, "team=Infra"},
+		{Value{Rules: Azure}, "a/b=c"},
+		{Value{Rules: Azure}, "MicrosoftThing=1"},
+		{Value{Rules: Rules{MaxCount: 2}}, "a=1,b=2,c=3"},
+		{Value{Rules: Rules{MaxCount: 1}, Tags: map[string]string{"a": "1"}}, "b=2"},
+	}
+	for _, test := range tests {
+		before := maps.Clone(test.v.Tags)
+		if err := test.v.Set(test.input); err == nil {
+			t.Errorf("Set(%q) with %+v: got %v, wanted error", test.input, test.v.Rules.Name, test.v.Tags)
+		} else if !maps.Equal(test.v.Tags, before) {
+			t.Errorf("Set(%q) failed but modified tags: %v", test.input, test.v.Tags)
+		}
+	}
+
+	ok := Value{Rules: Rules{MaxCount: 1}, Tags: map[string]string{"a": "1"}}
+	if err := ok.Set("a=2"); err != nil {
+		t.Errorf("Set replacing a tag: unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+// Package templateflag defines a flag.Value implementation that parses a
+// text/template, reporting syntax errors when the flag is parsed.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//	  "strings"
+//	  "text/template"
+//
+//	  "github.com/creachadair/goflags/templateflag"
+//	)
+//
+//	var format = templateflag.Value{
+//	  Funcs: template.FuncMap{"upper": strings.ToUpper},
+//	}
+//	func init() {
+//	  flag.Var(&format, "format", format.Help("Output format"))
+//	}
+//
+//	  ...
+//	  if err := format.Execute(os.Stdout, item); err != nil { ... }
+//
+// If the flag argument begins with "@", the remainder of the argument names a
+// file containing the template text. Use "@@" to specify a literal template
+// beginning with "@".
+package templateflag
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// A Value represents a parsed template. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The template parsed from the flag, or nil if none has been set.
+	Template *template.Template
+
+	// Functions made available to the template. This must be set before the
+	// flag is parsed.
+	Funcs template.FuncMap
+
+	// Options passed to the template, e.g., "missingkey=error".
+	Options []string
+
+	src string // the template text, for String
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	return fmt.Sprintf("%s (Go template or @file)", h)
+}
+
+// MustParse sets v from s, and panics if this fails. It is intended for use
+// in setting default values.
+func (v *Value) MustParse(s string) *Value {
+	if err := v.Set(s); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Execute applies the template to data, writing the output to w.
+// It reports an error if no template has been set.
+func (v *Value) Execute(w io.Writer, data any) error {
+	if v.Template == nil {
+		return errors.New("templateflag: no template")
+	}
+	return v.Template.Execute(w, data)
+}
+
+// ExecuteString applies the template to data and returns the output.
+func (v *Value) ExecuteString(data any) (string, error) {
+	var buf bytes.Buffer
+	if err := v.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return v.src }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	text := s
+	if rest, ok := strings.CutPrefix(s, "@"); ok {
+		if strings.HasPrefix(rest, "@") {
+			text = rest
+		} else {
+			data, err := os.ReadFile(rest)
+			if err != nil {
+				return fmt.Errorf("templateflag: %w", err)
+			}
+			text = string(data)
+		}
+	}
+	t, err := template.New("flag").Funcs(v.Funcs).Option(v.Options...).Parse(text)
+	if err != nil {
+		return fmt.Errorf("templateflag: %w", err)
+	}
+	v.Template = t
+	v.src = s
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type *template.Template.
+func (v *Value) Get() any { return v.Template }
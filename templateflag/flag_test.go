@@ -0,0 +1,82 @@
+package templateflag
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+type item struct {
+	Name string
+	Size int
+}
+
+func TestFlagBits(t *testing.T) {
+	format := Value{Funcs: template.FuncMap{"upper": strings.ToUpper}}
+
+	fs := flag.NewFlagSet("template", flag.ContinueOnError)
+	fs.Var(&format, "format", format.Help("Output format"))
+
+	if _, err := format.ExecuteString(nil); err == nil {
+		t.Error("Execute with no template: got nil, wanted error")
+	}
+	if err := fs.Parse([]string{"-format", "{{.Name|upper}}\t{{.Size}}"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	got, err := format.ExecuteString(item{"foo", 25})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if want := "FOO\t25"; got != want {
+		t.Errorf("Execute: got %q, want %q", got, want)
+	}
+	if format.Get().(*template.Template) == nil {
+		t.Error("Get: got nil template")
+	}
+
+	for _, bad := range []string{"{{.Name", "{{nosuchfunc .}}", "@" + filepath.Join(t.TempDir(), "none")} {
+		if err := fs.Parse([]string{"-format", bad}); err == nil {
+			t.Errorf("Parse %q: got nil, wanted error", bad)
+		} else {
+			t.Logf("Parse %q gave expected error: %v", bad, err)
+		}
+	}
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tmpl")
+	if err := os.WriteFile(path, []byte("name={{.Name}}"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	tests := []struct {
+		in, want string
+	}{
+		{"@" + path, "name=x"},
+		{"@@{{.Name}}", "@x"},
+	}
+	for _, test := range tests {
+		var v Value
+		if err := v.Set(test.in); err != nil {
+			t.Errorf("Set(%q) failed: %v", test.in, err)
+			continue
+		}
+		if got, err := v.ExecuteString(item{Name: "x"}); err != nil {
+			t.Errorf("Execute %q failed: %v", test.in, err)
+		} else if got != test.want {
+			t.Errorf("Execute %q: got %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestOptions(t *testing.T) {
+	v := Value{Options: []string{"missingkey=error"}}
+	v.MustParse("{{.missing}}")
+	if got, err := v.ExecuteString(map[string]int{}); err == nil {
+		t.Errorf("Execute: got %q, wanted error", got)
+	}
+}
@@ -0,0 +1,66 @@
+// Package textflag defines a flag.Value implementation that adapts any type
+// implementing encoding.TextUnmarshaler.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//	  "net/netip"
+//
+//	  "github.com/creachadair/goflags/textflag"
+//	)
+//
+//	var gateway = netip.MustParseAddr("192.168.1.1")
+//	func init() {
+//	  flag.Var(textflag.New(&gateway), "gateway", "Gateway address")
+//	}
+//
+// Many types in the standard library and elsewhere, including netip.Addr,
+// netip.Prefix, time.Time, slog.Level, and big.Int, implement the interface.
+// If the type also implements encoding.TextMarshaler, it is used to format
+// the current value.
+package textflag
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+// A Value adapts an encoding.TextUnmarshaler to a flag. A *Value satisfies the
+// flag.Value and flag.Getter interfaces.
+type Value struct {
+	u encoding.TextUnmarshaler
+}
+
+// New returns a *Value that sets u by calling its UnmarshalText method.
+// Typically u is a pointer to a variable holding the default value.
+func New(u encoding.TextUnmarshaler) *Value { return &Value{u: u} }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string {
+	if v == nil || v.u == nil {
+		return ""
+	}
+	if m, ok := v.u.(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(text)
+	}
+	return fmt.Sprint(v.u)
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error { return v.u.UnmarshalText([]byte(s)) }
+
+// Get satisfies the flag.Getter interface.
+// If the value passed to New is a pointer, the concrete value is the value it
+// points to; otherwise it is the value passed to New.
+func (v *Value) Get() any {
+	if rv := reflect.ValueOf(v.u); rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		return rv.Elem().Interface()
+	}
+	return v.u
+}
@@ -0,0 +1,63 @@
+package textflag
+
+import (
+	"bytes"
+	"flag"
+	"log/slog"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+// upper implements only encoding.TextUnmarshaler.
+type upper struct{ s string }
+
+func (u *upper) UnmarshalText(text []byte) error {
+	u.s = strings.ToUpper(string(text))
+	return nil
+}
+
+func TestFlagBits(t *testing.T) {
+	gateway := netip.MustParseAddr("192.168.1.1")
+	var when time.Time
+	var level slog.Level
+	var name upper
+
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("text", flag.ContinueOnError)
+	fs.SetOutput(&buf)
+	fs.Var(New(&gateway), "gateway", "Gateway address")
+	fs.Var(New(&when), "when", "Timestamp")
+	fs.Var(New(&level), "level", "Log level")
+	fs.Var(New(&name), "name", "Name")
+	fs.PrintDefaults()
+	if !strings.Contains(buf.String(), "(default 192.168.1.1)") {
+		t.Errorf("Usage does not mention default:\n%s", buf.String())
+	}
+
+	if err := fs.Parse([]string{
+		"-gateway", "10.0.0.1",
+		"-when", "2024-01-02T03:04:05Z",
+		"-level", "warn",
+		"-name", "bob",
+	}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := fs.Lookup("gateway").Value.(flag.Getter).Get(), netip.MustParseAddr("10.0.0.1"); got != want {
+		t.Errorf("Value for -gateway: got %v, want %v", got, want)
+	}
+	if got := when.Format(time.RFC3339); got != "2024-01-02T03:04:05Z" {
+		t.Errorf("Value for -when: got %s", got)
+	}
+	if got, want := fs.Lookup("level").Value.String(), "WARN"; got != want {
+		t.Errorf("String for -level: got %q, want %q", got, want)
+	}
+	if got := fs.Lookup("name").Value.(flag.Getter).Get().(upper); got.s != "BOB" {
+		t.Errorf("Value for -name: got %q, want BOB", got.s)
+	}
+
+	if err := fs.Parse([]string{"-gateway", "bogus"}); err == nil {
+		t.Error("Invalid -gateway was accepted")
+	}
+}
@@ -20,6 +20,7 @@ package timeflag
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -34,7 +35,10 @@ type Value struct {
 }
 
 // String satisfies part of the flag.Value interface.
-func (v *Value) String() string { return format(v.Time, v.Layout) }
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return format(v.Time, v.Layout) }
 
 // Help concatenates a human-readable string summarizing the format of t to h,
 // for use in generating a documentation string.
@@ -45,6 +49,24 @@ func (v *Value) Help(h string) string {
 	return fmt.Sprintf("%s (e.g., %q)", h, v.Layout)
 }
 
+// Complete returns an example of the layout of v that begins with prefix, for
+// use in shell completion. The example is the current value of v if it is set,
+// or otherwise the current time.
+func (v *Value) Complete(prefix string) []string {
+	t := v.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	layout := v.Layout
+	if layout == "" {
+		layout = time.Kitchen
+	}
+	if s := t.Format(layout); strings.HasPrefix(s, prefix) {
+		return []string{s}
+	}
+	return nil
+}
+
 // Set satisfies part of the flag.Value interface.
 func (v *Value) Set(s string) error {
 	var err error
@@ -65,7 +87,7 @@ func parse(s string, format string) (time.Time, error) {
 
 func format(t time.Time, format string) string {
 	if format == "" {
-		return fmt.Sprintf("%q", t.Format(time.Kitchen))
+		return t.Format(time.Kitchen)
 	}
-	return fmt.Sprintf("%q", t.Format(format))
+	return t.Format(format)
 }
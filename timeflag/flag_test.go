@@ -35,3 +35,23 @@ func TestFlagBits(t *testing.T) {
 		t.Errorf("Value for -ptime: got %q want %q", got, want)
 	}
 }
+
+func TestComplete(t *testing.T) {
+	v := Value{
+		Layout: "2006-01-02",
+		Time:   time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if got := v.Complete("2024-"); len(got) != 1 || got[0] != "2024-03-01" {
+		t.Errorf("Complete: got %q, want [2024-03-01]", got)
+	}
+	if got := v.Complete("1999"); got != nil {
+		t.Errorf("Complete: got %q, want none", got)
+	}
+
+	var k Value // default layout, current time
+	if got := k.Complete(""); len(got) != 1 {
+		t.Errorf("Complete: got %q, want one example", got)
+	} else if _, err := time.Parse(time.Kitchen, got[0]); err != nil {
+		t.Errorf("Complete: example %q does not parse: %v", got[0], err)
+	}
+}
@@ -0,0 +1,147 @@
+// Package tlsflag defines flag.Value implementations for configuring TLS:
+// certificate and key pairs, CA certificate bundles, and protocol versions.
+//
+// Example:
+//
+//	import (
+//	  "crypto/tls"
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/tlsflag"
+//	)
+//
+//	var (
+//	  keyPair    tlsflag.KeyPair
+//	  clientCAs  tlsflag.CertPool
+//	  minVersion = tlsflag.Version(tls.VersionTLS12)
+//	)
+//
+//	func init() {
+//	  flag.Var(keyPair.CertFile(), "tls-cert", "Server certificate (PEM)")
+//	  flag.Var(keyPair.KeyFile(), "tls-key", "Server private key (PEM)")
+//	  flag.Var(&clientCAs, "client-ca", "CA bundle for client certificates (PEM)")
+//	  flag.Var(&minVersion, "tls-min-version", minVersion.Help("Minimum TLS version"))
+//	}
+//
+//	  ...
+//	  cert, err := keyPair.Certificate()
+//	  ...
+//	  cfg := &tls.Config{
+//	    Certificates: []tls.Certificate{cert},
+//	    ClientCAs:    clientCAs.Pool,
+//	    MinVersion:   minVersion.Uint16(),
+//	  }
+//
+// The certificate and key of a KeyPair are set by separate flags, in either
+// order, and the pair is loaded when the flags are parsed, once both files
+// are known, so that an invalid pair is reported as a flag error. A pair may
+// be replaced by giving both flags again. If only one of the files of a
+// loaded pair is replaced, an error is reported by Load or Certificate rather
+// than by the flag, since the other file may follow; call Load after parsing
+// flags to check the final pair.
+package tlsflag
+
+import (
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+)
+
+// A KeyPair represents a certificate and private key loaded from a pair of
+// PEM files. The paths of the files are set via the flag.Getter values
+// returned by the CertFile and KeyFile methods.
+type KeyPair struct {
+	certFile, keyFile string
+
+	// The certificate most recently loaded, and the files it was loaded from.
+	cert                  *tls.Certificate
+	loadedCert, loadedKey string
+
+	certSet, keySet bool // set since the pair was last loaded or reported invalid
+}
+
+// CertFile returns a flag value that sets the path of the certificate file.
+// The concrete value of its Get method is the path.
+func (kp *KeyPair) CertFile() flag.Getter { return pairFile{kp, &kp.certFile} }
+
+// KeyFile returns a flag value that sets the path of the private key file.
+// The concrete value of its Get method is the path.
+func (kp *KeyPair) KeyFile() flag.Getter { return pairFile{kp, &kp.keyFile} }
+
+// IsSet reports whether either file of the pair has been set.
+func (kp *KeyPair) IsSet() bool { return kp.certFile != "" || kp.keyFile != "" }
+
+// Certificate returns the certificate loaded from the current files, loading
+// it if necessary, as Load does.
+func (kp *KeyPair) Certificate() (tls.Certificate, error) {
+	if err := kp.Load(); err != nil {
+		return tls.Certificate{}, err
+	}
+	return *kp.cert, nil
+}
+
+// Load loads the certificate from the current files, if it has not already
+// been loaded from them. It reports an error if only one of the certificate
+// and key files has been set, if neither has, or if the pair is invalid. If
+// loading fails, the certificate loaded previously, if any, is kept, and is
+// reported by Certificate if the files are set back to it.
+func (kp *KeyPair) Load() error {
+	switch {
+	case kp.certFile == "" && kp.keyFile == "":
+		return errors.New("tlsflag: no certificate specified")
+	case kp.certFile == "":
+		return errors.New("tlsflag: missing certificate file")
+	case kp.keyFile == "":
+		return errors.New("tlsflag: missing key file")
+	case kp.cert != nil && kp.certFile == kp.loadedCert && kp.keyFile == kp.loadedKey:
+		kp.certSet, kp.keySet = false, false
+		return nil // already loaded
+	}
+	cert, err := tls.LoadX509KeyPair(kp.certFile, kp.keyFile)
+	if err != nil {
+		return fmt.Errorf("tlsflag: %w", err)
+	}
+	kp.cert, kp.loadedCert, kp.loadedKey = &cert, kp.certFile, kp.keyFile
+	kp.certSet, kp.keySet = false, false
+	return nil
+}
+
+// pairFile is a flag.Getter for one of the paths of a KeyPair.
+type pairFile struct {
+	kp   *KeyPair
+	path *string
+}
+
+func (p pairFile) String() string { return fmt.Sprintf("%q", p.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (p pairFile) ArgString() string {
+	if p.path == nil {
+		return ""
+	}
+	return *p.path
+}
+
+// Set sets the path, and loads the pair if both files have been set since
+// the pair was last loaded or reported invalid. Otherwise, if both files are
+// known, it tries to load the pair, but does not report an error, since the
+// other file may follow.
+func (p pairFile) Set(s string) error {
+	*p.path = s
+	if p.path == &p.kp.certFile {
+		p.kp.certSet = true
+	} else {
+		p.kp.keySet = true
+	}
+	if p.kp.certSet && p.kp.keySet {
+		err := p.kp.Load()
+		p.kp.certSet, p.kp.keySet = false, false // the error, if any, is reported
+		return err
+	} else if p.kp.certFile != "" && p.kp.keyFile != "" {
+		p.kp.Load()
+	}
+	return nil
+}
+
+func (p pairFile) Get() any { return *p.path }
@@ -0,0 +1,136 @@
+package tlsflag
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"flag"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCert generates a self-signed CA certificate and key, and writes them as
+// PEM files in dir, returning their paths.
+func writeCert(t *testing.T, dir, name string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey: %v", err)
+	}
+
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(certFile, certPEM, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return certFile, keyFile
+}
+
+func TestKeyPair(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCert(t, dir, "server")
+	otherCert, _ := writeCert(t, dir, "other")
+
+	var kp KeyPair
+	fs := flag.NewFlagSet("tls", flag.ContinueOnError)
+	fs.Var(kp.CertFile(), "tls-cert", "Certificate")
+	fs.Var(kp.KeyFile(), "tls-key", "Private key")
+
+	if _, err := kp.Certificate(); err == nil {
+		t.Error("Certificate with no files: got nil, wanted error")
+	}
+
+	// A mismatched pair is reported when the flags are parsed.
+	if err := fs.Parse([]string{"-tls-key", keyFile, "-tls-cert", otherCert}); err == nil {
+		t.Error("Parse mismatched pair: got nil, wanted error")
+	} else {
+		t.Logf("Parse mismatched pair gave expected error: %v", err)
+	}
+	if err := kp.Load(); err == nil {
+		t.Error("Load mismatched pair: got nil, wanted error")
+	}
+
+	if err := fs.Parse([]string{"-tls-key", keyFile, "-tls-cert", certFile}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	cert, err := kp.Certificate()
+	if err != nil {
+		t.Fatalf("Certificate failed: %v", err)
+	}
+	if len(cert.Certificate) != 1 {
+		t.Errorf("Certificate: got %d certs, want 1", len(cert.Certificate))
+	}
+	if got := fs.Lookup("tls-cert").Value.(flag.Getter).Get().(string); got != certFile {
+		t.Errorf("Value for -tls-cert: got %q, want %q", got, certFile)
+	}
+
+	// A loaded pair may be replaced by another, one flag at a time.
+	otherDir := t.TempDir()
+	newCert, newKey := writeCert(t, otherDir, "server")
+	if err := fs.Parse([]string{"-tls-cert", newCert, "-tls-key", newKey}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, err := kp.Certificate(); err != nil {
+		t.Errorf("Certificate after override failed: %v", err)
+	} else if string(got.Certificate[0]) == string(cert.Certificate[0]) {
+		t.Error("Certificate after override: got the previous certificate")
+	}
+
+	// Replacing one file of a loaded pair is checked by Load, and after a
+	// failed load, the previous certificate is kept.
+	if err := fs.Parse([]string{"-tls-cert", otherCert}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if _, err := kp.Certificate(); err == nil {
+		t.Error("Certificate mismatched pair: got nil, wanted error")
+	}
+	if err := fs.Parse([]string{"-tls-cert", newCert}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if _, err := kp.Certificate(); err != nil {
+		t.Errorf("Certificate after restoring the pair failed: %v", err)
+	}
+}
+
+func TestKeyPairIncomplete(t *testing.T) {
+	certFile, _ := writeCert(t, t.TempDir(), "server")
+
+	var kp KeyPair
+	if err := kp.CertFile().Set(certFile); err != nil {
+		t.Fatalf("Set cert failed: %v", err)
+	}
+	if !kp.IsSet() {
+		t.Error("IsSet: got false, want true")
+	}
+	if _, err := kp.Certificate(); err == nil {
+		t.Error("Certificate without key: got nil, wanted error")
+	}
+}
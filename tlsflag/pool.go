@@ -0,0 +1,67 @@
+package tlsflag
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// A CertPool represents a set of CA certificates loaded from PEM files. A
+// pointer to a CertPool satisfies the flag.Value and flag.Getter interfaces.
+//
+// Each occurrence of the flag adds the certificates from the named file to the
+// pool. The special argument "system" adds the system root certificates.
+type CertPool struct {
+	// The pool of certificates, or nil if no certificates have been loaded.
+	Pool *x509.CertPool
+
+	files []string
+}
+
+// String satisfies part of the flag.Value interface.
+func (c *CertPool) String() string { return fmt.Sprintf("%q", strings.Join(c.files, ",")) }
+
+// ArgStrings satisfies the goflags.Repeatable interface. It returns the names
+// of the files loaded into the pool, and "system" if it includes the system
+// roots, in the order they were given.
+func (c *CertPool) ArgStrings() []string { return slices.Clone(c.files) }
+
+// Reset discards the certificates of the pool.
+func (c *CertPool) Reset() { c.Pool, c.files = nil, nil }
+
+// Set satisfies part of the flag.Value interface.
+func (c *CertPool) Set(s string) error {
+	if s == "system" {
+		sys, err := x509.SystemCertPool()
+		if err != nil {
+			return fmt.Errorf("tlsflag: loading system roots: %w", err)
+		}
+		if c.Pool != nil {
+			return fmt.Errorf("tlsflag: system roots must be listed first")
+		}
+		c.Pool = sys
+		c.files = append(c.files, s)
+		return nil
+	}
+
+	data, err := os.ReadFile(s)
+	if err != nil {
+		return fmt.Errorf("tlsflag: %w", err)
+	}
+	pool := c.Pool
+	if pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return fmt.Errorf("tlsflag: no certificates found in %q", s)
+	}
+	c.Pool = pool
+	c.files = append(c.files, s)
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type *x509.CertPool.
+func (c *CertPool) Get() any { return c.Pool }
@@ -0,0 +1,69 @@
+package tlsflag
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/creachadair/goflags"
+)
+
+func TestCertPool(t *testing.T) {
+	dir := t.TempDir()
+	ca1, key1 := writeCert(t, dir, "ca1")
+	ca2, _ := writeCert(t, dir, "ca2")
+
+	var pool CertPool
+	fs := flag.NewFlagSet("pool", flag.ContinueOnError)
+	fs.Var(&pool, "ca", "CA bundle")
+
+	if pool.Pool != nil {
+		t.Errorf("Initial value for -ca: got %v, want nil", pool.Pool)
+	}
+	if err := fs.Parse([]string{"-ca", ca1, "-ca", ca2}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if pool.Get() == nil {
+		t.Fatal("Value for -ca: got nil pool")
+	}
+
+	// The flag is reproduced by one argument for each file.
+	args := goflags.Args(fs)
+	if want := []string{"-ca=" + ca1, "-ca=" + ca2}; !slices.Equal(args, want) {
+		t.Errorf("Args: got %q, want %q", args, want)
+	}
+	var cp CertPool
+	fs2 := flag.NewFlagSet("pool", flag.ContinueOnError)
+	fs2.Var(&cp, "ca", "CA bundle")
+	if err := fs2.Parse(args); err != nil {
+		t.Errorf("Parse(%q): unexpected error: %v", args, err)
+	} else if cp.String() != pool.String() {
+		t.Errorf("After round trip: got %s, want %s", cp.String(), pool.String())
+	}
+	restore := goflags.Snapshot(&pool)
+	if err := pool.Set(ca1); err != nil {
+		t.Fatalf("Set(%q) failed: %v", ca1, err)
+	}
+	restore()
+	if got := pool.ArgStrings(); !slices.Equal(got, []string{ca1, ca2}) {
+		t.Errorf("After restore: got %q, want %q", got, []string{ca1, ca2})
+	}
+
+	empty := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(empty, []byte("nothing here"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, bad := range []string{empty, key1, filepath.Join(dir, "missing.pem"), "system"} {
+		if err := pool.Set(bad); err == nil {
+			t.Errorf("Set(%q): got nil, wanted error", bad)
+		} else {
+			t.Logf("Set(%q) gave expected error: %v", bad, err)
+		}
+	}
+	pool.Reset()
+	if pool.Pool != nil || len(pool.ArgStrings()) != 0 {
+		t.Errorf("After Reset: got %v, %q; want empty", pool.Pool, pool.ArgStrings())
+	}
+}
@@ -0,0 +1,66 @@
+package tlsflag
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
+
+// A Version represents a TLS protocol version, such as tls.VersionTLS12. A
+// *Version satisfies the flag.Getter interface. The zero value means no
+// version is specified, which crypto/tls interprets as its default.
+//
+// The flag accepts "tls1.2" and "tls1.3", or equivalently "1.2" and "1.3",
+// without regard to case. The obsolete versions "tls1.0" and "tls1.1" are
+// also accepted.
+type Version uint16
+
+var versions = []struct {
+	name string
+	v    uint16
+}{
+	{"tls1.0", tls.VersionTLS10},
+	{"tls1.1", tls.VersionTLS11},
+	{"tls1.2", tls.VersionTLS12},
+	{"tls1.3", tls.VersionTLS13},
+}
+
+// Help concatenates a human-readable string summarizing the legal values of v
+// to h, for use in generating a documentation string. The obsolete versions
+// are listed, as they are accepted.
+func (v Version) Help(h string) string { return h + " (tls1.0|tls1.1|tls1.2|tls1.3)" }
+
+// Uint16 returns v as a version number suitable for a tls.Config.
+func (v Version) Uint16() uint16 { return uint16(v) }
+
+// String satisfies part of the flag.Value interface.
+func (v Version) String() string {
+	for _, e := range versions {
+		if e.v == uint16(v) {
+			return e.name
+		}
+	}
+	if v == 0 {
+		return ""
+	}
+	return fmt.Sprintf("0x%04x", uint16(v))
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Version) Set(s string) error {
+	t := strings.ToLower(s)
+	if !strings.HasPrefix(t, "tls") {
+		t = "tls" + t
+	}
+	for _, e := range versions {
+		if e.name == t {
+			*v = Version(e.v)
+			return nil
+		}
+	}
+	return fmt.Errorf("tlsflag: unknown TLS version %q, expected tls1.0|tls1.1|tls1.2|tls1.3", s)
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type uint16.
+func (v Version) Get() any { return uint16(v) }
@@ -0,0 +1,55 @@
+package tlsflag
+
+import (
+	"crypto/tls"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	minVer := Version(tls.VersionTLS12)
+
+	fs := flag.NewFlagSet("version", flag.ContinueOnError)
+	fs.Var(&minVer, "min-version", minVer.Help("Minimum version"))
+
+	if got, want := minVer.String(), "tls1.2"; got != want {
+		t.Errorf("Initial value for -min-version: got %q, want %q", got, want)
+	}
+	if err := fs.Parse([]string{"-min-version", "TLS1.3"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got := minVer.Get().(uint16); got != tls.VersionTLS13 {
+		t.Errorf("Value for -min-version: got %x, want %x", got, tls.VersionTLS13)
+	}
+
+	tests := []struct {
+		in   string
+		want uint16
+	}{
+		{"1.0", tls.VersionTLS10}, {"1.1", tls.VersionTLS11},
+		{"tls1.2", tls.VersionTLS12}, {"1.3", tls.VersionTLS13},
+	}
+	for _, test := range tests {
+		var v Version
+		if err := v.Set(test.in); err != nil {
+			t.Errorf("Set(%q) failed: %v", test.in, err)
+		} else if v.Uint16() != test.want {
+			t.Errorf("Set(%q): got %x, want %x", test.in, v.Uint16(), test.want)
+		}
+	}
+	for _, bad := range []string{"", "tls", "1.4", "ssl3", "tls12"} {
+		var v Version
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %v, wanted error", bad, v)
+		}
+	}
+
+	// Every accepted version is listed in the help text.
+	help := Version(0).Help("Version")
+	for _, name := range []string{"tls1.0", "tls1.1", "tls1.2", "tls1.3"} {
+		if !strings.Contains(help, name) {
+			t.Errorf("Help: got %q, want it to list %q", help, name)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Package triflag defines a flag.Value implementation for a three-state
+// boolean that may be true, false, or "auto".
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/triflag"
+//	)
+//
+//	var color triflag.Value // default is triflag.Auto
+//	func init() {
+//	  flag.Var(&color, "color", color.Help("Colorize output"))
+//	}
+//
+//	  ...
+//	  useColor := color.Bool(isTerminal(os.Stdout))
+//
+// Like a standard boolean flag, a bare "-color" sets the value to True. Because
+// of this, other values must be attached to the flag with "=", for example
+// "-color=auto" or "-color=no".
+package triflag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Value represents a three-state boolean. A *Value satisfies the flag.Getter
+// interface. The zero value is Auto.
+type Value int
+
+// The possible states of a Value.
+const (
+	Auto Value = iota
+	False
+	True
+)
+
+// Help concatenates a human-readable string summarizing the legal values of v
+// to h, for use in generating a documentation string.
+func (v Value) Help(h string) string { return h + " (true|false|auto)" }
+
+// Bool reports whether v is True. If v is Auto, Bool returns defaultWhenAuto.
+func (v Value) Bool(defaultWhenAuto bool) bool {
+	if v == Auto {
+		return defaultWhenAuto
+	}
+	return v == True
+}
+
+// IsAuto reports whether v is Auto.
+func (v Value) IsAuto() bool { return v == Auto }
+
+// String satisfies part of the flag.Value interface.
+func (v Value) String() string {
+	switch v {
+	case False:
+		return "false"
+	case True:
+		return "true"
+	}
+	return "auto"
+}
+
+// Set satisfies part of the flag.Value interface. It accepts the strings
+// "true", "yes", "on", "1", "false", "no", "off", "0", and "auto",
+// without regard to case.
+func (v *Value) Set(s string) error {
+	switch strings.ToLower(s) {
+	case "true", "yes", "on", "1":
+		*v = True
+	case "false", "no", "off", "0":
+		*v = False
+	case "auto":
+		*v = Auto
+	default:
+		return fmt.Errorf("triflag: invalid value %q, expected true|false|auto", s)
+	}
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Value.
+func (v Value) Get() any { return v }
+
+// IsBoolFlag marks the flag as boolean, so that a bare occurrence of the flag
+// sets it to True.
+func (v Value) IsBoolFlag() bool { return true }
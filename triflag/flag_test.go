@@ -0,0 +1,70 @@
+package triflag
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var color, cache, pager Value
+	cache = True
+
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("tri", flag.ContinueOnError)
+	fs.Var(&color, "color", color.Help("Colorize output"))
+	fs.Var(&cache, "cache", cache.Help("Use the cache"))
+	fs.Var(&pager, "pager", pager.Help("Use a pager"))
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+	t.Logf("Tri flag set:\n%s", buf.String())
+
+	if !color.IsAuto() {
+		t.Errorf("Initial value for -color: got %v, want auto", color)
+	}
+
+	if err := fs.Parse([]string{"-color", "-cache=no", "-pager=AUTO", "rest"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	if got := color.Get().(Value); got != True {
+		t.Errorf("Value for -color: got %v, want %v", got, True)
+	}
+	if cache != False {
+		t.Errorf("Value for -cache: got %v, want %v", cache, False)
+	}
+	if pager != Auto {
+		t.Errorf("Value for -pager: got %v, want %v", pager, Auto)
+	}
+	if args := fs.Args(); len(args) != 1 || args[0] != "rest" {
+		t.Errorf("Remaining arguments: got %q, want [rest]", args)
+	}
+
+	if err := fs.Parse([]string{"-color=maybe"}); err == nil {
+		t.Error("Expected error from bogus flag, but got none")
+	} else {
+		t.Logf("Got expected error from bogus -color: %v", err)
+	}
+}
+
+func TestBool(t *testing.T) {
+	tests := []struct {
+		v        Value
+		def      bool
+		want     bool
+		wantText string
+	}{
+		{Auto, true, true, "auto"},
+		{Auto, false, false, "auto"},
+		{True, false, true, "true"},
+		{False, true, false, "false"},
+	}
+	for _, test := range tests {
+		if got := test.v.Bool(test.def); got != test.want {
+			t.Errorf("%v.Bool(%v): got %v, want %v", test.v, test.def, got, test.want)
+		}
+		if got := test.v.String(); got != test.wantText {
+			t.Errorf("String: got %q, want %q", got, test.wantText)
+		}
+	}
+}
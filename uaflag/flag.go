@@ -0,0 +1,247 @@
+// Package uaflag defines a flag.Value implementation for retry and backoff
+// policies written in a compact notation.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/uaflag"
+//	)
+//
+//	var retry = uaflag.Value{Policy: uaflag.MustParse("3x,100ms..5s")}
+//	func init() {
+//	  flag.Var(&retry, "retry", retry.Help("Retry policy"))
+//	}
+//
+//	  ...
+//	  for i := 1; ; i++ {
+//	    err := try()
+//	    if err == nil || i >= retry.Policy.Attempts {
+//	      break
+//	    }
+//	    time.Sleep(retry.Policy.Delay(i, nil))
+//	  }
+//
+// A policy is a comma-separated list of terms, in any order:
+//
+//	5x           at most 5 attempts in total (also "attempts=5")
+//	100ms..30s   the delay starts at 100ms and grows to at most 30s
+//	250ms        a constant delay of 250ms
+//	mult=1.5     the delay grows by a factor of 1.5 per attempt (default 2)
+//	jitter=0.2   each delay is varied randomly by up to ±20%
+//
+// The word "none" denotes a single attempt with no retries.
+package uaflag
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Policy describes when to retry a failed operation.
+type Policy struct {
+	Attempts   int           // the maximum number of attempts, including the first; ≥ 1
+	Min, Max   time.Duration // bounds on the delay between attempts
+	Multiplier float64       // growth factor of the delay per attempt; ≥ 1
+	Jitter     float64       // relative random variation of each delay, in [0, 1]
+}
+
+// Parse parses a policy in the notation described by the package comment.
+// Terms that are not given take their values from the zero Policy, except
+// that Attempts defaults to 1 and Multiplier to 2.
+func Parse(s string) (Policy, error) {
+	p := Policy{Attempts: 1, Multiplier: 2}
+	if strings.EqualFold(strings.TrimSpace(s), "none") {
+		return p, nil
+	}
+	if strings.TrimSpace(s) == "" {
+		return Policy{}, errors.New("uaflag: empty policy")
+	}
+	seen := make(map[string]bool)
+	once := func(term, kind string) error {
+		if seen[kind] {
+			return fmt.Errorf("uaflag: duplicate %s in %q", kind, term)
+		}
+		seen[kind] = true
+		return nil
+	}
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		key, val, isKV := strings.Cut(term, "=")
+		switch {
+		case isKV:
+			key = strings.ToLower(strings.TrimSpace(key))
+			val = strings.TrimSpace(val)
+			switch key {
+			case "attempts":
+				if err := once(term, "attempts"); err != nil {
+					return Policy{}, err
+				}
+				n, err := parseAttempts(val)
+				if err != nil {
+					return Policy{}, err
+				}
+				p.Attempts = n
+			case "mult", "multiplier":
+				if err := once(term, "multiplier"); err != nil {
+					return Policy{}, err
+				}
+				f, err := strconv.ParseFloat(val, 64)
+				if err != nil || f < 1 || math.IsInf(f, 0) {
+					return Policy{}, fmt.Errorf("uaflag: invalid multiplier %q, want a number ≥ 1", val)
+				}
+				p.Multiplier = f
+			case "jitter":
+				if err := once(term, "jitter"); err != nil {
+					return Policy{}, err
+				}
+				f, err := strconv.ParseFloat(val, 64)
+				if err != nil || !(f >= 0 && f <= 1) {
+					return Policy{}, fmt.Errorf("uaflag: invalid jitter %q, want a number in [0, 1]", val)
+				}
+				p.Jitter = f
+			default:
+				return Policy{}, fmt.Errorf("uaflag: unknown setting %q", key)
+			}
+
+		case strings.HasSuffix(term, "x") || strings.HasSuffix(term, "X"):
+			if err := once(term, "attempts"); err != nil {
+				return Policy{}, err
+			}
+			n, err := parseAttempts(term[:len(term)-1])
+			if err != nil {
+				return Policy{}, err
+			}
+			p.Attempts = n
+
+		default:
+			if err := once(term, "delay"); err != nil {
+				return Policy{}, err
+			}
+			lo, hi, isRange := strings.Cut(term, "..")
+			min, err := parseDelay(lo)
+			if err != nil {
+				return Policy{}, err
+			}
+			max := min
+			if isRange {
+				if max, err = parseDelay(hi); err != nil {
+					return Policy{}, err
+				}
+				if max < min {
+					return Policy{}, fmt.Errorf("uaflag: maximum delay %v is less than minimum %v", max, min)
+				}
+			}
+			p.Min, p.Max = min, max
+		}
+	}
+	return p, nil
+}
+
+// MustParse parses a policy, and panics if it is invalid. It is intended for
+// use in defining default values.
+func MustParse(s string) Policy {
+	p, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func parseAttempts(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("uaflag: invalid attempt count %q, want a positive integer", s)
+	}
+	return n, nil
+}
+
+func parseDelay(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "0" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("uaflag: invalid delay %q", s)
+	}
+	return d, nil
+}
+
+// Delay returns the delay to wait after the given attempt (numbered from 1)
+// fails, before making the next one. The delay is Min multiplied by
+// Multiplier once for each previous retry, capped at Max, and then varied by
+// Jitter using r. If r == nil, the global source of math/rand/v2 is used.
+func (p Policy) Delay(attempt int, r *rand.Rand) time.Duration {
+	d := float64(p.Min) * math.Pow(p.Multiplier, float64(max(attempt-1, 0)))
+	if d > float64(p.Max) {
+		d = float64(p.Max)
+	}
+	if p.Jitter > 0 {
+		var f float64
+		if r == nil {
+			f = rand.Float64()
+		} else {
+			f = r.Float64()
+		}
+		d *= 1 + p.Jitter*(2*f-1)
+	}
+	return time.Duration(d)
+}
+
+// String returns p in the notation accepted by Parse.
+func (p Policy) String() string {
+	if p.Attempts <= 1 && p.Min == 0 && p.Max == 0 && p.Jitter == 0 {
+		return "none"
+	}
+	parts := []string{strconv.Itoa(p.Attempts) + "x"}
+	if p.Min == p.Max {
+		parts = append(parts, p.Min.String())
+	} else {
+		parts = append(parts, p.Min.String()+".."+p.Max.String())
+	}
+	if p.Multiplier != 2 {
+		parts = append(parts, "mult="+strconv.FormatFloat(p.Multiplier, 'g', -1, 64))
+	}
+	if p.Jitter != 0 {
+		parts = append(parts, "jitter="+strconv.FormatFloat(p.Jitter, 'g', -1, 64))
+	}
+	return strings.Join(parts, ",")
+}
+
+// A Value represents a retry policy. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces.
+type Value struct {
+	// The policy parsed from the flag.
+	Policy Policy
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string { return h + " (e.g., 5x,100ms..30s,jitter=0.2)" }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string { return v.Policy.String() }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	p, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	v.Policy = p
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Policy.
+func (v *Value) Get() any { return v.Policy }
@@ -0,0 +1,82 @@
+package uaflag
+
+import (
+	"flag"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestFlagBits(t *testing.T) {
+	retry := Value{Policy: MustParse("none")}
+	fs := flag.NewFlagSet("retry", flag.ContinueOnError)
+	fs.Var(&retry, "retry", retry.Help("Retry policy"))
+
+	if got, want := retry.String(), `"none"`; got != want {
+		t.Errorf("Initial -retry: got %s, want %s", got, want)
+	}
+	if err := fs.Parse([]string{"-retry", "5x, 100ms..30s, jitter=0.2"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	want := Policy{Attempts: 5, Min: 100 * time.Millisecond, Max: 30 * time.Second, Multiplier: 2, Jitter: 0.2}
+	if got := retry.Get().(Policy); got != want {
+		t.Errorf("Value for -retry: got %+v, want %+v", got, want)
+	}
+	if got, want := retry.String(), `"5x,100ms..30s,jitter=0.2"`; got != want {
+		t.Errorf("String: got %s, want %s", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Policy
+	}{
+		{"none", Policy{Attempts: 1, Multiplier: 2}},
+		{"3X", Policy{Attempts: 3, Multiplier: 2}},
+		{"attempts=4,250ms", Policy{Attempts: 4, Min: 250 * time.Millisecond, Max: 250 * time.Millisecond, Multiplier: 2}},
+		{"1s..1m,mult=1.5,10x", Policy{Attempts: 10, Min: time.Second, Max: time.Minute, Multiplier: 1.5}},
+		{"0..1s", Policy{Attempts: 1, Max: time.Second, Multiplier: 2}},
+	}
+	for _, test := range tests {
+		got, err := Parse(test.input)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", test.input, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("Parse(%q): got %+v, want %+v", test.input, got, test.want)
+		}
+		if rt, err := Parse(got.String()); err != nil || rt != got {
+			t.Errorf("Parse(%q): got %+v, %v; want %+v", got.String(), rt, err, got)
+		}
+	}
+}
+
+func TestDelay(t *testing.T) {
+	p := MustParse("5x,100ms..1s")
+	for i, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		if got := p.Delay(i+1, nil); got != want*time.Millisecond {
+			t.Errorf("Delay(%d): got %v, want %v", i+1, got, want*time.Millisecond)
+		}
+	}
+
+	j := MustParse("1s,jitter=0.5")
+	r := rand.New(rand.NewPCG(1, 2))
+	for range 100 {
+		if d := j.Delay(1, r); d < 500*time.Millisecond || d > 1500*time.Millisecond {
+			t.Fatalf("Delay with jitter: got %v, want in [500ms, 1.5s]", d)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, bad := range []string{
+		"", "0x", "-1x", "3x,4x", "5s..1s", "1s,2s", "abc", "mult=0.5", "jitter=2",
+		"jitter=x", "color=red", "-1s", "1s..",
+	} {
+		if p, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q): got %+v, wanted error", bad, p)
+		}
+	}
+}
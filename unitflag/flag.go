@@ -0,0 +1,238 @@
+// Package unitflag provides a flag.Value implementation for integer
+// quantities written in a human-readable notation with caller-defined units,
+// generalizing the grammar of sizeflag.
+//
+// A Table defines the units and the number of base units each represents.
+// The grammar of a quantity is then:
+//
+//	quantity = number unit [quantity]
+//	         | digits
+//	number   = digits ['.' digits]
+//	digits   = [0-9]+
+//
+// where unit is one of the names in the table. Whitespace surrounding or
+// separating terms is ignored. If several terms are given, the quantity is
+// their sum, so with a table of time units "1h30m" is 90 minutes. A trailing
+// number without a unit is a number of base units. As in sizeflag,
+// fractional terms are rounded toward zero.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/unitflag"
+//	)
+//
+//	var hertz = unitflag.NewTable(
+//	  unitflag.Unit{Name: "Hz", Scale: 1},
+//	  unitflag.Unit{Name: "kHz", Scale: 1e3},
+//	  unitflag.Unit{Name: "MHz", Scale: 1e6},
+//	  unitflag.Unit{Name: "GHz", Scale: 1e9},
+//	)
+//
+//	var freq = unitflag.Value{Table: hertz, N: 433_920_000}
+//	func init() {
+//	  flag.Var(&freq, "freq", freq.Help("Carrier frequency"))
+//	}
+//
+// With this definition "-freq 2.4GHz" sets freq.N to 2400000000, and the
+// default is displayed as "433MHz 920kHz".
+package unitflag
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A Unit is a named multiple of the base unit of a Table.
+type Unit struct {
+	Name  string // e.g., "kHz"; compared with case
+	Scale int64  // the number of base units in one of this unit, > 0
+}
+
+// A Table is a collection of units for parsing and formatting quantities.
+// A Table is immutable once constructed, and is safe for concurrent use.
+type Table struct {
+	units []Unit // in descending order of scale
+}
+
+// NewTable constructs a Table from the given units, in any order. Several
+// units may have the same scale, as aliases; Format uses the one given
+// first. NewTable panics if no units are given, if a name is empty, contains
+// digits or spaces, or is repeated, or if a scale is not positive.
+func NewTable(units ...Unit) *Table {
+	if len(units) == 0 {
+		panic("unitflag: empty unit table")
+	}
+	us := slices.Clone(units)
+	slices.SortStableFunc(us, func(a, b Unit) int {
+		if a.Scale > b.Scale {
+			return -1
+		} else if a.Scale < b.Scale {
+			return 1
+		}
+		return 0
+	})
+	for i, u := range us {
+		switch {
+		case u.Name == "" || strings.ContainsAny(u.Name, "0123456789. \t"):
+			panic(fmt.Sprintf("unitflag: invalid unit name %q", u.Name))
+		case u.Scale <= 0:
+			panic(fmt.Sprintf("unitflag: invalid scale %d for unit %q", u.Scale, u.Name))
+		}
+		for _, v := range us[:i] {
+			if v.Name == u.Name {
+				panic(fmt.Sprintf("unitflag: duplicate unit name %q", u.Name))
+			}
+		}
+	}
+	return &Table{units: us}
+}
+
+// Units returns the units of t in descending order of scale.
+func (t *Table) Units() []Unit { return slices.Clone(t.units) }
+
+// Parse parses a human-readable quantity and returns the number of base
+// units it represents.
+func (t *Table) Parse(s string) (int64, error) {
+	var total int64
+	ok := false
+	rest := strings.TrimSpace(s)
+	for rest != "" {
+		num, tail := cutNumber(rest)
+		if num == "" {
+			return 0, fmt.Errorf("unitflag: invalid quantity %q", s)
+		}
+		tail = strings.TrimSpace(tail)
+		u, found := t.matchUnit(tail)
+		if !found {
+			if tail != "" {
+				return 0, fmt.Errorf("unitflag: invalid unit in %q", tail)
+			}
+			n, err := strconv.ParseInt(num, 10, 64) // bare base units
+			if err != nil {
+				return 0, fmt.Errorf("unitflag: invalid quantity %q", num)
+			}
+			if total > math.MaxInt64-n {
+				return 0, fmt.Errorf("unitflag: quantity %q out of range", s)
+			}
+			return total + n, nil
+		}
+		f, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unitflag: invalid quantity %q", num)
+		}
+		v := f * float64(u.Scale)
+		if v >= math.MaxInt64 || total > math.MaxInt64-int64(v) {
+			return 0, fmt.Errorf("unitflag: quantity %q out of range", s)
+		}
+		total += int64(v)
+		rest = strings.TrimSpace(tail[len(u.Name):])
+		ok = true
+	}
+	if !ok {
+		return 0, errors.New("unitflag: empty quantity")
+	}
+	return total, nil
+}
+
+// Format renders n as a human-readable quantity that Parse maps back to n.
+// Each term uses the largest unit that fits; a remainder smaller than every
+// unit is written as a bare number.
+func (t *Table) Format(n int64) string {
+	if n < 0 {
+		return "-" + t.Format(-n) // N.B. Parse does not accept this
+	}
+	var parts []string
+	for _, u := range t.units {
+		if q := n / u.Scale; q > 0 {
+			parts = append(parts, strconv.FormatInt(q, 10)+u.Name)
+			n %= u.Scale
+		}
+	}
+	if n > 0 || len(parts) == 0 {
+		if last := t.units[len(t.units)-1]; last.Scale == 1 {
+			parts = append(parts, strconv.FormatInt(n, 10)+last.Name)
+		} else {
+			parts = append(parts, strconv.FormatInt(n, 10))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// matchUnit returns the unit with the longest name that is a prefix of s.
+func (t *Table) matchUnit(s string) (Unit, bool) {
+	var best Unit
+	for _, u := range t.units {
+		if strings.HasPrefix(s, u.Name) && len(u.Name) > len(best.Name) {
+			best = u
+		}
+	}
+	return best, best.Name != ""
+}
+
+// cutNumber splits a leading number matching digits ['.' digits] from s.
+func cutNumber(s string) (num, rest string) {
+	i := 0
+	for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+		i++
+	}
+	if i == 0 {
+		return "", s
+	}
+	if i+1 < len(s) && s[i] == '.' && '0' <= s[i+1] && s[i+1] <= '9' {
+		i++
+		for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+			i++
+		}
+	}
+	return s[:i], s[i:]
+}
+
+// A Value represents a quantity measured in the units of a Table. A pointer
+// to a Value satisfies the flag.Value and flag.Getter interfaces. The Table
+// must be set before the flag is used.
+type Value struct {
+	// The units in which the flag is written.
+	Table *Table
+
+	// The number of base units parsed from the flag.
+	N int64
+}
+
+// Help concatenates a human-readable string summarizing the units of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	names := make([]string, len(v.Table.units))
+	for i, u := range v.Table.units {
+		names[len(names)-1-i] = u.Name
+	}
+	return fmt.Sprintf("%s (units: %s)", h, strings.Join(names, ", "))
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string {
+	if v.Table == nil {
+		return strconv.FormatInt(v.N, 10)
+	}
+	return v.Table.Format(v.N)
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	n, err := v.Table.Parse(s)
+	if err != nil {
+		return err
+	}
+	v.N = n
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type int64.
+func (v *Value) Get() any { return v.N }
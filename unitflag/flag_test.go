@@ -0,0 +1,125 @@
+package unitflag
+
+import (
+	"flag"
+	"testing"
+)
+
+var (
+	hertz = NewTable(
+		Unit{Name: "kHz", Scale: 1e3},
+		Unit{Name: "Hz", Scale: 1},
+		Unit{Name: "GHz", Scale: 1e9},
+		Unit{Name: "MHz", Scale: 1e6},
+	)
+	millis = NewTable(
+		Unit{Name: "ms", Scale: 1},
+		Unit{Name: "s", Scale: 1000},
+		Unit{Name: "min", Scale: 60 * 1000},
+		Unit{Name: "m", Scale: 60 * 1000},
+	)
+	points = NewTable(Unit{Name: "pt", Scale: 20}, Unit{Name: "in", Scale: 1440})
+)
+
+func TestFlagBits(t *testing.T) {
+	freq := Value{Table: hertz, N: 433_920_000}
+	fs := flag.NewFlagSet("unit", flag.ContinueOnError)
+	fs.Var(&freq, "freq", freq.Help("Frequency"))
+
+	if got, want := freq.String(), "433MHz 920kHz"; got != want {
+		t.Errorf("Initial -freq: got %q, want %q", got, want)
+	}
+	if got, want := freq.Help("F"), "F (units: Hz, kHz, MHz, GHz)"; got != want {
+		t.Errorf("Help: got %q, want %q", got, want)
+	}
+	if err := fs.Parse([]string{"-freq", "2.4GHz"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got := freq.Get().(int64); got != 2_400_000_000 {
+		t.Errorf("Value for -freq: got %d, want 2400000000", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		table *Table
+		input string
+		want  int64
+	}{
+		{hertz, "0", 0},
+		{hertz, "15", 15},
+		{hertz, "15Hz", 15},
+		{hertz, "1 kHz 5", 1005},
+		{hertz, "1.5MHz", 1_500_000},
+		{hertz, "1MHz1kHz1Hz", 1_001_001},
+		{millis, "1m30s", 90_000},
+		{millis, "2min 500ms", 120_500},
+		{millis, "0.5s", 500},
+		{millis, "1.0005s", 1000}, // rounded toward zero
+		{points, "1in 6pt", 1560},
+		{points, "7", 7},
+	}
+	for _, test := range tests {
+		got, err := test.table.Parse(test.input)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", test.input, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("Parse(%q): got %d, want %d", test.input, got, test.want)
+		}
+
+		// Verify that formatting round-trips.
+		s := test.table.Format(got)
+		if rt, err := test.table.Parse(s); err != nil || rt != got {
+			t.Errorf("Parse(Format(%d)) = Parse(%q): got %d, %v", got, s, rt, err)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		table *Table
+		input int64
+		want  string
+	}{
+		{hertz, 0, "0Hz"},
+		{hertz, 1_001_001, "1MHz 1kHz 1Hz"},
+		{millis, 90_000, "1min 30s"},
+		{points, 0, "0"},
+		{points, 1561, "1in 6pt 1"},
+	}
+	for _, test := range tests {
+		if got := test.table.Format(test.input); got != test.want {
+			t.Errorf("Format(%d): got %q, want %q", test.input, got, test.want)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, bad := range []string{"", "  ", "Hz", "5 THz", "1.5", "-3Hz", "5Hz x", "1kHz 2.5", "99999999999GHz"} {
+		if n, err := hertz.Parse(bad); err == nil {
+			t.Errorf("Parse(%q): got %d, wanted error", bad, n)
+		}
+	}
+}
+
+func TestNewTablePanics(t *testing.T) {
+	tests := [][]Unit{
+		nil,
+		{{Name: "", Scale: 1}},
+		{{Name: "k2", Scale: 1}},
+		{{Name: "x", Scale: 0}},
+		{{Name: "x", Scale: 1}, {Name: "x", Scale: 2}},
+	}
+	for _, units := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewTable(%v): did not panic", units)
+				}
+			}()
+			NewTable(units...)
+		}()
+	}
+}
@@ -0,0 +1,130 @@
+// Package urlflag defines a flag.Value implementation that parses a
+// *url.URL, with optional constraints on the scheme and other components.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/urlflag"
+//	)
+//
+//	var server = urlflag.Value{
+//	  Schemes:       []string{"https"},
+//	  DefaultScheme: "https",
+//	  RequireHost:   true,
+//	}
+//	func init() {
+//	  flag.Var(&server, "server", server.Help("Server base URL"))
+//	}
+package urlflag
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// A Value represents a URL. A pointer to a Value satisfies the flag.Value and
+// flag.Getter interfaces. The constraints are checked when the flag is set;
+// the zero value accepts any URL accepted by url.Parse.
+type Value struct {
+	// The URL parsed from the flag, or nil if none has been set.
+	URL *url.URL
+
+	// If non-empty, the scheme of the URL must be one of these (compared
+	// without regard to case).
+	Schemes []string
+
+	// If non-empty, and the input has no scheme, this scheme is assumed.
+	DefaultScheme string
+
+	// If true, the URL must have a non-empty host.
+	RequireHost bool
+
+	// If true, the URL must not contain userinfo ("user:pass@").
+	NoUserInfo bool
+}
+
+// Help concatenates a human-readable string summarizing the allowed schemes of
+// v to h, for use in generating a documentation string.
+func (v *Value) Help(h string) string {
+	if len(v.Schemes) == 0 {
+		return h
+	}
+	return fmt.Sprintf("%s (%s)", h, strings.Join(v.Schemes, "|"))
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string {
+	if v.URL == nil {
+		return ""
+	}
+	return v.URL.String()
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	if s == "" {
+		return errors.New("urlflag: empty URL")
+	}
+	if v.DefaultScheme != "" && !hasScheme(s) {
+		s = v.DefaultScheme + "://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return fmt.Errorf("urlflag: %w", err)
+	}
+	if len(v.Schemes) != 0 && !slices.ContainsFunc(v.Schemes, func(t string) bool {
+		return strings.EqualFold(t, u.Scheme)
+	}) {
+		return fmt.Errorf("urlflag: scheme %q not allowed, expected one of (%s)",
+			u.Scheme, strings.Join(v.Schemes, "|"))
+	}
+	if v.RequireHost && u.Host == "" {
+		return fmt.Errorf("urlflag: missing host in %q", s)
+	}
+	if v.NoUserInfo && u.User != nil {
+		return errors.New("urlflag: userinfo not allowed")
+	}
+	v.URL = u
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type *url.URL.
+func (v *Value) Get() any { return v.URL }
+
+// hasScheme reports whether s begins with a URL scheme followed by "://" or
+// ":" and a non-digit. The second case handles "mailto:x" while treating
+// "host:8080" as lacking a scheme.
+func hasScheme(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+			// ok
+		case '0' <= c && c <= '9' || c == '+' || c == '-' || c == '.':
+			if i == 0 {
+				return false
+			}
+		case c == ':':
+			if i == 0 {
+				return false
+			}
+			rest := s[i+1:]
+			if strings.HasPrefix(rest, "//") {
+				return true
+			}
+			return rest != "" && (rest[0] < '0' || rest[0] > '9')
+		default:
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package urlflag
+
+import (
+	"bytes"
+	"flag"
+	"net/url"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var plain Value
+	server := Value{
+		Schemes:       []string{"https"},
+		DefaultScheme: "https",
+		RequireHost:   true,
+	}
+
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("url", flag.PanicOnError)
+	fs.Var(&plain, "plain", "Any URL")
+	fs.Var(&server, "server", server.Help("Server base URL"))
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+	t.Logf("URL flag set:\n%s", buf.String())
+
+	if got, want := plain.String(), `""`; got != want {
+		t.Errorf("Initial value for -plain: got %s, want %s", got, want)
+	}
+
+	if err := fs.Parse([]string{"-plain", "/a/b?c=d", "-server", "example.com:8443/api"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	if got, want := plain.URL.String(), "/a/b?c=d"; got != want {
+		t.Errorf("Value for -plain: got %q, want %q", got, want)
+	}
+	u := server.Get().(*url.URL)
+	if u.Scheme != "https" || u.Host != "example.com:8443" || u.Path != "/api" {
+		t.Errorf("Value for -server: got %q, want https://example.com:8443/api", u)
+	}
+}
+
+func TestConstraints(t *testing.T) {
+	tests := []struct {
+		v     Value
+		input string
+		ok    bool
+	}{
+		{Value{}, "", false},
+		{Value{}, "http://[::1", false},
+		{Value{Schemes: []string{"https"}}, "https://x.com", true},
+		{Value{Schemes: []string{"https"}}, "HTTPS://x.com", true},
+		{Value{Schemes: []string{"https"}}, "http://x.com", false},
+		{Value{Schemes: []string{"https"}}, "x.com", false},
+		{Value{RequireHost: true}, "file:///etc/passwd", false},
+		{Value{RequireHost: true}, "http://x.com/", true},
+		{Value{NoUserInfo: true}, "http://bob:pw@x.com", false},
+		{Value{NoUserInfo: true}, "http://x.com", true},
+		{Value{DefaultScheme: "http"}, "localhost:8080", true},
+		{Value{DefaultScheme: "http", Schemes: []string{"https"}}, "x.com", false},
+	}
+	for _, test := range tests {
+		err := test.v.Set(test.input)
+		if test.ok && err != nil {
+			t.Errorf("Set(%q) with %+v: unexpected error: %v", test.input, test.v, err)
+		} else if !test.ok && err == nil {
+			t.Errorf("Set(%q) with %+v: got %v, wanted error", test.input, test.v, test.v.URL)
+		}
+	}
+}
+
+func TestHasScheme(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"http://x", true},
+		{"mailto:bob@x.com", true},
+		{"git+ssh://host/repo", true},
+		{"localhost:8080", false},
+		{"example.com/path", false},
+		{"/abs/path", false},
+		{"1http://x", false},
+	}
+	for _, test := range tests {
+		if got := hasScheme(test.input); got != test.want {
+			t.Errorf("hasScheme(%q): got %v, want %v", test.input, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package goflags
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// sections records the usage sections declared for each flag set.
+var sections struct {
+	sync.Mutex
+	m map[*flag.FlagSet][]section
+}
+
+type section struct {
+	title string
+	names []string
+}
+
+// Section assigns the named flags of fs to a section of the usage output
+// with the given title, such as "Networking". Sections are printed in the
+// order they are first declared, and the flags of a section in the order
+// they are assigned. A flag assigned to more than one section is listed only
+// in the first.
+func Section(fs *flag.FlagSet, title string, names ...string) {
+	sections.Lock()
+	defer sections.Unlock()
+	if sections.m == nil {
+		sections.m = make(map[*flag.FlagSet][]section)
+	}
+	ss := sections.m[fs]
+	for i, s := range ss {
+		if s.title == title {
+			ss[i].names = append(s.names, names...)
+			return
+		}
+	}
+	sections.m[fs] = append(ss, section{title: title, names: append([]string(nil), names...)})
+}
+
+// forgetSections discards the sections declared for fs, as part of Forget.
+func forgetSections(fs *flag.FlagSet) {
+	sections.Lock()
+	defer sections.Unlock()
+	delete(sections.m, fs)
+}
+
+// Usage formats the help text for a flag set, with the flags listed by
+// section (see Section). For each flag, the help text shows the name and type
+// of its value, its usage string, and its default value if that is not the
+// zero value. The usage strings are wrapped to the width of the output, and
+// aligned in a column.
+//
+// If the value of a flag has a Help method, as most of the value types in
+// this module do, the summary of accepted values it reports is added to the
+// usage string, unless the usage string already includes it. Deprecated
+// flags (see Deprecate) are omitted.
+type Usage struct {
+	// The first line of the help text. If empty, it is "Usage of NAME:", the
+	// same as the default usage of the flag package.
+	Header string
+
+	// The title of the section for flags not assigned to any section. If
+	// empty, it is "Flags".
+	Other string
+
+	// The width of the output, in bytes. If zero, it is 80.
+	Width int
+}
+
+// Install sets the Usage function of fs to print the help text for fs, as
+// formatted by u, to the output of fs.
+func (u Usage) Install(fs *flag.FlagSet) {
+	fs.Usage = func() { u.Write(fs.Output(), fs) }
+}
+
+// Write writes the help text for fs, as formatted by u, to w.
+func (u Usage) Write(w io.Writer, fs *flag.FlagSet) error {
+	width := u.Width
+	if width <= 0 {
+		width = 80
+	}
+	header := u.Header
+	if header == "" {
+		header = "Usage of " + fs.Name() + ":"
+		if fs.Name() == "" {
+			header = "Usage:"
+		}
+	}
+	other := u.Other
+	if other == "" {
+		other = "Flags"
+	}
+
+	sections.Lock()
+	ss := sections.m[fs]
+	sections.Unlock()
+
+	// Assign each flag to its section, with unassigned flags first.
+	seen := make(map[string]bool)
+	groups := make([][]*flag.Flag, len(ss)+1)
+	titles := []string{other}
+	for i, s := range ss {
+		titles = append(titles, s.title)
+		for _, name := range s.names {
+			if f := fs.Lookup(name); f != nil && !seen[name] {
+				seen[name] = true
+				groups[i+1] = append(groups[i+1], f)
+			}
+		}
+	}
+	fs.VisitAll(func(f *flag.Flag) {
+		if !seen[f.Name] {
+			groups[0] = append(groups[0], f)
+		}
+	})
+
+	// Compute the width of the name column, shared by all sections. Flags
+	// whose names do not fit are followed by their usage on the next line.
+	const maxCol = 28
+	col := 0
+	var lines [][]usageLine
+	for _, group := range groups {
+		var gl []usageLine
+		for _, f := range group {
+			if _, ok := Deprecated(f); ok {
+				continue
+			}
+			ul := formatFlag(f)
+			if n := len(ul.name); n <= maxCol && n > col {
+				col = n
+			}
+			gl = append(gl, ul)
+		}
+		lines = append(lines, gl)
+	}
+	col += 4 // the indentation and the space before the usage
+
+	var buf strings.Builder
+	buf.WriteString(header + "\n")
+	for i, gl := range lines {
+		if len(gl) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "\n%s:\n", titles[i])
+		for _, ul := range gl {
+			head := "  " + ul.name
+			text := wrapText(ul.usage, max(width-col, 20))
+			if len(head)+2 > col && len(text) != 0 {
+				buf.WriteString(head + "\n")
+				head = ""
+			}
+			if len(text) == 0 {
+				buf.WriteString(head + "\n")
+			}
+			for _, line := range text {
+				fmt.Fprintf(&buf, "%-*s%s\n", col, head, line)
+				head = ""
+			}
+		}
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+// A usageLine is the formatted name and usage text of a flag.
+type usageLine struct {
+	name  string // e.g., "-addr string"
+	usage string // e.g., "Service address (default \":80\")"
+}
+
+func formatFlag(f *flag.Flag) usageLine {
+	typeName, usage := flag.UnquoteUsage(f)
+	name := "-" + f.Name
+	if typeName != "" {
+		name += " " + typeName
+	}
+	if h, ok := findValue[interface{ Help(string) string }](f.Value); ok {
+		if sum := strings.TrimSpace(h.Help("")); sum != "" && !strings.Contains(usage, sum) {
+			usage = strings.TrimSpace(h.Help(usage))
+		}
+	}
+	if !isZeroValue(f) {
+		def := f.DefValue
+		if reflect.TypeOf(f.Value) == stringValueType {
+			def = fmt.Sprintf("%q", def)
+		}
+		usage += " (default " + def + ")"
+	}
+	return usageLine{name: name, usage: usage}
+}
+
+// stringValueType is the type of the value of a flag defined by fs.String,
+// whose default is quoted, following flag.PrintDefaults.
+var stringValueType = func() reflect.Type {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.String("s", "", "")
+	return reflect.TypeOf(fs.Lookup("s").Value)
+}()
+
+// isZeroValue reports whether the default value of f is the zero value of
+// its type, in which case it is not shown, following flag.PrintDefaults.
+func isZeroValue(f *flag.Flag) (ok bool) {
+	if f.DefValue == "" {
+		return true
+	}
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	t := reflect.TypeOf(f.Value)
+	var z reflect.Value
+	if t.Kind() == reflect.Pointer {
+		z = reflect.New(t.Elem())
+	} else {
+		z = reflect.Zero(t)
+	}
+	v, isValue := z.Interface().(flag.Value)
+	return isValue && f.DefValue == v.String()
+}
+
+// wrapText splits s into lines of at most width bytes, breaking at spaces.
+// A word longer than width is placed on a line by itself.
+func wrapText(s string, width int) []string {
+	var lines []string
+	var cur strings.Builder
+	for _, word := range strings.Fields(s) {
+		if cur.Len() > 0 && cur.Len()+1+len(word) > width {
+			lines = append(lines, cur.String())
+			cur.Reset()
+		}
+		if cur.Len() > 0 {
+			cur.WriteByte(' ')
+		}
+		cur.WriteString(word)
+	}
+	if cur.Len() > 0 {
+		lines = append(lines, cur.String())
+	}
+	return lines
+}
@@ -0,0 +1,112 @@
+package goflags
+
+import (
+	"bytes"
+	"flag"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/goflags/enumflag"
+	"github.com/creachadair/goflags/sizeflag"
+)
+
+func TestUsage(t *testing.T) {
+	fs := flag.NewFlagSet("usage", flag.ContinueOnError)
+	fs.String("addr", ":8080", "Address to `host:port` listen on")
+	fs.Duration("timeout", 0, "Request timeout")
+	fs.Var(enumflag.New("info", "debug", "warn"), "log-level", "Minimum level to log")
+	color := enumflag.New("auto", "always", "never")
+	fs.Var(color, "color", color.Help("Use color"))
+	fs.Bool("v", false, "Verbose logging")
+	fs.Var(sizeflag.Base2(64<<20), "cache-size", "Maximum size of the in-memory cache, "+
+		"beyond which the least recently used entries are evicted to make room for new ones")
+	fs.Bool("a-flag-with-a-very-long-name", true, "Long")
+	fs.String("old-addr", "", "Old")
+	Deprecate(fs, "old-addr", "", "")
+
+	Section(fs, "Networking", "addr", "timeout")
+	Section(fs, "Logging", "log-level", "v", "addr")
+	Section(fs, "Networking", "cache-size")
+
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	Usage{Width: 72}.Install(fs)
+	fs.Usage()
+	got := buf.String()
+	t.Logf("Usage:\n%s", got)
+
+	const want = `Usage of usage:
+
+Flags:
+  -a-flag-with-a-very-long-name
+                     Long (default true)
+  -color value       Use color (auto|always|never) (default "auto")
+
+Networking:
+  -addr host:port    Address to host:port listen on (default ":8080")
+  -timeout duration  Request timeout
+  -cache-size value  Maximum size of the in-memory cache, beyond which
+                     the least recently used entries are evicted to make
+                     room for new ones (default 64M)
+
+Logging:
+  -log-level value   Minimum level to log (info|debug|warn) (default
+                     "info")
+  -v                 Verbose logging
+`
+	if got != want {
+		t.Errorf("Usage:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUsageDefaults(t *testing.T) {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.Int("n", 3, "Count")
+	var buf bytes.Buffer
+	if err := (Usage{Header: "Usage: prog [flags]", Other: "Options"}).Write(&buf, fs); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if got, want := buf.String(), "Usage: prog [flags]\n\nOptions:\n  -n int  Count (default 3)\n"; got != want {
+		t.Errorf("Usage: got %q, want %q", got, want)
+	}
+}
+
+func TestUsageForget(t *testing.T) {
+	fs := flag.NewFlagSet("usage", flag.ContinueOnError)
+	fs.String("addr", "", "Address")
+	Section(fs, "Networking", "addr")
+	Forget(fs)
+
+	var buf bytes.Buffer
+	if err := (Usage{}).Write(&buf, fs); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "Networking") {
+		t.Errorf("Usage after Forget: got %q, want no sections", buf.String())
+	}
+	sections.Lock()
+	defer sections.Unlock()
+	if _, ok := sections.m[fs]; ok {
+		t.Error("Forget did not release the flag set")
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		input string
+		width int
+		want  []string
+	}{
+		{"", 10, nil},
+		{"a b c", 10, []string{"a b c"}},
+		{"aaa bbb ccc", 7, []string{"aaa bbb", "ccc"}},
+		{"  aaa   bbb  ", 3, []string{"aaa", "bbb"}},
+		{"abcdefghij k", 4, []string{"abcdefghij", "k"}},
+	}
+	for _, test := range tests {
+		if got := wrapText(test.input, test.width); !slices.Equal(got, test.want) {
+			t.Errorf("wrapText(%q, %d): got %q, want %q", test.input, test.width, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+// Package userflag defines flag.Value implementations for users and groups,
+// resolved via the os/user package when the flag is parsed.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/userflag"
+//	)
+//
+//	var runAs userflag.User
+//	var owner userflag.Owner
+//
+//	func init() {
+//	  flag.Var(&runAs, "user", "User to run as (name or UID)")
+//	  flag.Var(&owner, "chown", "Owner of output files (user[:group])")
+//	}
+//
+//	  ...
+//	  os.Chown(path, owner.UID(), owner.GID())
+//
+// Users and groups may be given by name or by numeric ID. A numeric ID must
+// exist in the user or group database unless AllowUnknownID is set.
+package userflag
+
+import (
+	"errors"
+	"fmt"
+	"os/user"
+	"strconv"
+	"strings"
+)
+
+// A User represents a user account. A pointer to a User satisfies the
+// flag.Value and flag.Getter interfaces. The zero value has UID -1, meaning
+// no user has been set.
+type User struct {
+	// If true, a numeric UID not present in the user database is accepted.
+	AllowUnknownID bool
+
+	u   *user.User
+	uid int
+	set bool
+}
+
+// UID returns the numeric user ID, or -1 if no user has been set.
+func (v *User) UID() int {
+	if !v.set {
+		return -1
+	}
+	return v.uid
+}
+
+// User returns the user database entry, or nil if no user has been set or
+// the user is not in the database.
+func (v *User) User() *user.User { return v.u }
+
+// String satisfies part of the flag.Value interface.
+func (v *User) String() string {
+	if !v.set {
+		return ""
+	} else if v.u != nil {
+		return v.u.Username
+	}
+	return strconv.Itoa(v.uid)
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *User) Set(s string) error {
+	u, id, err := lookupUser(s, v.AllowUnknownID)
+	if err != nil {
+		return err
+	}
+	v.u, v.uid, v.set = u, id, true
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is the int of the UID.
+func (v *User) Get() any { return v.UID() }
+
+// A Group represents a group. A pointer to a Group satisfies the flag.Value
+// and flag.Getter interfaces. The zero value has GID -1, meaning no group has
+// been set.
+type Group struct {
+	// If true, a numeric GID not present in the group database is accepted.
+	AllowUnknownID bool
+
+	g   *user.Group
+	gid int
+	set bool
+}
+
+// GID returns the numeric group ID, or -1 if no group has been set.
+func (v *Group) GID() int {
+	if !v.set {
+		return -1
+	}
+	return v.gid
+}
+
+// Group returns the group database entry, or nil if no group has been set or
+// the group is not in the database.
+func (v *Group) Group() *user.Group { return v.g }
+
+// String satisfies part of the flag.Value interface.
+func (v *Group) String() string {
+	if !v.set {
+		return ""
+	} else if v.g != nil {
+		return v.g.Name
+	}
+	return strconv.Itoa(v.gid)
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Group) Set(s string) error {
+	g, id, err := lookupGroup(s, v.AllowUnknownID)
+	if err != nil {
+		return err
+	}
+	v.g, v.gid, v.set = g, id, true
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is the int of the GID.
+func (v *Group) Get() any { return v.GID() }
+
+// An Owner represents a user and group in the style of chown(1). A pointer to
+// an Owner satisfies the flag.Value and flag.Getter interfaces.
+//
+// The flag accepts "user", "user:group", "user:", and ":group". With "user:"
+// the group is the primary group of the user. Where the user or group is
+// omitted, UID or GID reports -1, which os.Chown interprets as "unchanged".
+type Owner struct {
+	// If true, numeric IDs not present in the database are accepted.
+	AllowUnknownID bool
+
+	User  User
+	Group Group
+}
+
+// UID returns the numeric user ID, or -1 if no user was given.
+func (o *Owner) UID() int { return o.User.UID() }
+
+// GID returns the numeric group ID, or -1 if no group was given.
+func (o *Owner) GID() int { return o.Group.GID() }
+
+// String satisfies part of the flag.Value interface.
+func (o *Owner) String() string {
+	u, g := o.User.String(), o.Group.String()
+	if g == "" {
+		return u
+	}
+	return u + ":" + g
+}
+
+// Set satisfies part of the flag.Value interface.
+func (o *Owner) Set(s string) error {
+	us, gs, hasGroup := strings.Cut(s, ":")
+	if us == "" && gs == "" {
+		return errors.New("userflag: empty owner")
+	}
+	usr := User{AllowUnknownID: o.AllowUnknownID}
+	grp := Group{AllowUnknownID: o.AllowUnknownID}
+	if us != "" {
+		if err := usr.Set(us); err != nil {
+			return err
+		}
+	}
+	switch {
+	case gs != "":
+		if err := grp.Set(gs); err != nil {
+			return err
+		}
+	case hasGroup:
+		// "user:" selects the primary group of the user.
+		if usr.u == nil {
+			return fmt.Errorf("userflag: unknown primary group for %q", us)
+		}
+		if err := grp.Set(usr.u.Gid); err != nil {
+			return err
+		}
+	}
+	o.User, o.Group = usr, grp
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is a [2]int of the UID and GID.
+func (o *Owner) Get() any { return [2]int{o.UID(), o.GID()} }
+
+func lookupUser(s string, allowUnknown bool) (*user.User, int, error) {
+	if s == "" {
+		return nil, 0, errors.New("userflag: empty user")
+	}
+	if id, err := strconv.Atoi(s); err == nil && id >= 0 {
+		u, err := user.LookupId(s)
+		if err != nil {
+			if allowUnknown {
+				return nil, id, nil
+			}
+			return nil, 0, fmt.Errorf("userflag: unknown user ID %d", id)
+		}
+		return u, id, nil
+	}
+	u, err := user.Lookup(s)
+	if err != nil {
+		return nil, 0, fmt.Errorf("userflag: unknown user %q", s)
+	}
+	id, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return nil, 0, fmt.Errorf("userflag: user %q has non-numeric ID %q", s, u.Uid)
+	}
+	return u, id, nil
+}
+
+func lookupGroup(s string, allowUnknown bool) (*user.Group, int, error) {
+	if s == "" {
+		return nil, 0, errors.New("userflag: empty group")
+	}
+	if id, err := strconv.Atoi(s); err == nil && id >= 0 {
+		g, err := user.LookupGroupId(s)
+		if err != nil {
+			if allowUnknown {
+				return nil, id, nil
+			}
+			return nil, 0, fmt.Errorf("userflag: unknown group ID %d", id)
+		}
+		return g, id, nil
+	}
+	g, err := user.LookupGroup(s)
+	if err != nil {
+		return nil, 0, fmt.Errorf("userflag: unknown group %q", s)
+	}
+	id, err := strconv.Atoi(g.Gid)
+	if err != nil {
+		return nil, 0, fmt.Errorf("userflag: group %q has non-numeric ID %q", s, g.Gid)
+	}
+	return g, id, nil
+}
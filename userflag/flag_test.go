@@ -0,0 +1,121 @@
+package userflag
+
+import (
+	"flag"
+	"os/user"
+	"strconv"
+	"testing"
+)
+
+// currentUser returns the current user and its primary group, or skips the
+// test if they cannot be determined.
+func currentUser(t *testing.T) (*user.User, *user.Group) {
+	t.Helper()
+	u, err := user.Current()
+	if err != nil {
+		t.Skipf("Current user not available: %v", err)
+	}
+	g, err := user.LookupGroupId(u.Gid)
+	if err != nil {
+		t.Skipf("Primary group not available: %v", err)
+	}
+	return u, g
+}
+
+func TestFlagBits(t *testing.T) {
+	cur, grp := currentUser(t)
+	uid, _ := strconv.Atoi(cur.Uid)
+	gid, _ := strconv.Atoi(grp.Gid)
+
+	var runAs User
+	var group Group
+	var owner Owner
+
+	fs := flag.NewFlagSet("user", flag.ContinueOnError)
+	fs.Var(&runAs, "user", "User to run as")
+	fs.Var(&group, "group", "Group to run as")
+	fs.Var(&owner, "chown", "Owner of output files")
+
+	if runAs.UID() != -1 || group.GID() != -1 || owner.UID() != -1 || owner.GID() != -1 {
+		t.Errorf("Initial values: got user %d group %d owner %d:%d, want all -1",
+			runAs.UID(), group.GID(), owner.UID(), owner.GID())
+	}
+	if err := fs.Parse([]string{"-user", cur.Username, "-group", grp.Gid, "-chown", cur.Uid + ":" + grp.Name}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got := runAs.Get().(int); got != uid {
+		t.Errorf("Value for -user: got %d, want %d", got, uid)
+	}
+	if got := group.GID(); got != gid {
+		t.Errorf("Value for -group: got %d, want %d", got, gid)
+	}
+	if got, want := group.String(), grp.Name; got != want {
+		t.Errorf("String for -group: got %q, want %q", got, want)
+	}
+	if got := owner.Get().([2]int); got != [2]int{uid, gid} {
+		t.Errorf("Value for -chown: got %v, want [%d %d]", got, uid, gid)
+	}
+	if got, want := owner.String(), cur.Username+":"+grp.Name; got != want {
+		t.Errorf("String for -chown: got %q, want %q", got, want)
+	}
+}
+
+func TestOwner(t *testing.T) {
+	cur, grp := currentUser(t)
+	uid, _ := strconv.Atoi(cur.Uid)
+	gid, _ := strconv.Atoi(grp.Gid)
+
+	tests := []struct {
+		in       string
+		uid, gid int
+	}{
+		{cur.Username, uid, -1},
+		{cur.Username + ":", uid, gid},
+		{":" + grp.Name, -1, gid},
+		{cur.Uid + ":" + grp.Gid, uid, gid},
+	}
+	for _, test := range tests {
+		var o Owner
+		if err := o.Set(test.in); err != nil {
+			t.Errorf("Set(%q) failed: %v", test.in, err)
+		} else if o.UID() != test.uid || o.GID() != test.gid {
+			t.Errorf("Set(%q): got %d:%d, want %d:%d", test.in, o.UID(), o.GID(), test.uid, test.gid)
+		}
+	}
+}
+
+func TestUnknown(t *testing.T) {
+	const bogusName = "no-such-user-for-userflag-test"
+	const bogusID = "987654321"
+
+	for _, bad := range []string{"", bogusName, bogusID} {
+		var u User
+		if err := u.Set(bad); err == nil {
+			t.Errorf("User.Set(%q): got %v, wanted error", bad, u.UID())
+		}
+		var g Group
+		if err := g.Set(bad); err == nil {
+			t.Errorf("Group.Set(%q): got %v, wanted error", bad, g.GID())
+		}
+	}
+	for _, bad := range []string{"", ":", bogusName + ":", ":" + bogusName} {
+		var o Owner
+		if err := o.Set(bad); err == nil {
+			t.Errorf("Owner.Set(%q): got %v, wanted error", bad, o.String())
+		}
+	}
+
+	u := User{AllowUnknownID: true}
+	if err := u.Set(bogusID); err != nil {
+		t.Errorf("Set(%q) with AllowUnknownID failed: %v", bogusID, err)
+	} else if u.UID() != 987654321 || u.User() != nil || u.String() != bogusID {
+		t.Errorf("Set(%q): got UID %d, user %v", bogusID, u.UID(), u.User())
+	}
+	o := Owner{AllowUnknownID: true}
+	if err := o.Set(bogusID + ":" + bogusID); err != nil {
+		t.Errorf("Owner.Set with AllowUnknownID failed: %v", err)
+	}
+	if err := o.Set(bogusID + ":"); err == nil {
+		t.Error("Owner.Set unknown user's primary group: got nil, wanted error")
+	}
+}
@@ -0,0 +1,120 @@
+// Package uuidflag defines a flag.Value implementation for UUIDs as described
+// by RFC 9562.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/uuidflag"
+//	)
+//
+//	var requestID = uuidflag.Value{Version: 4}
+//	func init() {
+//	  flag.Var(&requestID, "request-id", "Request ID (UUIDv4)")
+//	}
+//
+// The flag accepts the canonical form "f81d4fae-7dec-11d0-a765-00a0c91e6bf6",
+// the same enclosed in braces or prefixed by "urn:uuid:", and 32 bare
+// hexadecimal digits. Hex digits are not case-sensitive.
+package uuidflag
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// A UUID is a 128-bit universally unique identifier.
+type UUID [16]byte
+
+// String renders u in canonical lower-case form.
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// Version reports the version number of u, from the high nibble of octet 6.
+func (u UUID) Version() int { return int(u[6] >> 4) }
+
+// IsRFC reports whether u has the variant defined by RFC 9562 (formerly RFC
+// 4122), that is, whether the top two bits of octet 8 are 10.
+func (u UUID) IsRFC() bool { return u[8]&0xc0 == 0x80 }
+
+// IsNil reports whether u is the nil UUID, with all bits zero.
+func (u UUID) IsNil() bool { return u == UUID{} }
+
+// Parse parses a UUID in any of the forms accepted by the flag.
+func Parse(s string) (UUID, error) {
+	t := s
+	if u, ok := strings.CutPrefix(t, "urn:uuid:"); ok {
+		t = u
+	} else if strings.HasPrefix(t, "{") && strings.HasSuffix(t, "}") {
+		t = t[1 : len(t)-1]
+	}
+	if len(t) == 36 {
+		if t[8] != '-' || t[13] != '-' || t[18] != '-' || t[23] != '-' {
+			return UUID{}, fmt.Errorf("uuidflag: invalid UUID %q", s)
+		}
+		t = t[0:8] + t[9:13] + t[14:18] + t[19:23] + t[24:]
+	}
+	var u UUID
+	if len(t) != 32 {
+		return UUID{}, fmt.Errorf("uuidflag: invalid UUID %q", s)
+	} else if _, err := hex.Decode(u[:], []byte(t)); err != nil {
+		return UUID{}, fmt.Errorf("uuidflag: invalid UUID %q", s)
+	}
+	return u, nil
+}
+
+// A Value represents a UUID. A pointer to a Value satisfies the flag.Value and
+// flag.Getter interfaces.
+type Value struct {
+	// The UUID parsed from the flag.
+	UUID UUID
+
+	// If positive, the UUID must have this version. Setting a version implies
+	// RequireRFC.
+	Version int
+
+	// If true, the UUID must have the RFC 9562 variant.
+	RequireRFC bool
+
+	// If true, the nil UUID is rejected.
+	NoNil bool
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return v.UUID.String() }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	u, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	if v.NoNil && u.IsNil() {
+		return fmt.Errorf("uuidflag: nil UUID not allowed")
+	}
+	if (v.RequireRFC || v.Version > 0) && !u.IsRFC() {
+		return fmt.Errorf("uuidflag: %v does not have the RFC 9562 variant", u)
+	}
+	if v.Version > 0 && u.Version() != v.Version {
+		return fmt.Errorf("uuidflag: %v has version %d, want %d", u, u.Version(), v.Version)
+	}
+	v.UUID = u
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type UUID.
+func (v *Value) Get() any { return v.UUID }
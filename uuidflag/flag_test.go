@@ -0,0 +1,91 @@
+package uuidflag
+
+import (
+	"flag"
+	"testing"
+)
+
+const canon = "f81d4fae-7dec-11d0-a765-00a0c91e6bf6"
+
+func TestFlagBits(t *testing.T) {
+	var id Value
+	v1 := Value{Version: 1}
+
+	fs := flag.NewFlagSet("uuid", flag.ContinueOnError)
+	fs.Var(&id, "id", "An ID")
+	fs.Var(&v1, "v1", "A version 1 ID")
+
+	if got, want := id.String(), "00000000-0000-0000-0000-000000000000"; got != want {
+		t.Errorf("Initial value for -id: got %q, want %q", got, want)
+	}
+	if err := fs.Parse([]string{"-id", "{F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6}", "-v1", canon}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got := id.Get().(UUID).String(); got != canon {
+		t.Errorf("Value for -id: got %q, want %q", got, canon)
+	}
+	if got := v1.UUID.Version(); got != 1 {
+		t.Errorf("Version for -v1: got %d, want 1", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, in := range []string{
+		canon,
+		"F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6",
+		"{" + canon + "}",
+		"urn:uuid:" + canon,
+		"f81d4fae7dec11d0a76500a0c91e6bf6",
+	} {
+		u, err := Parse(in)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", in, err)
+		} else if got := u.String(); got != canon {
+			t.Errorf("Parse(%q): got %q, want %q", in, got, canon)
+		}
+	}
+
+	for _, bad := range []string{
+		"",
+		"f81d4fae-7dec-11d0-a765-00a0c91e6bf",
+		"f81d4fae-7dec-11d0-a765_00a0c91e6bf6",
+		"f81d4fae+7dec-11d0-a765-00a0c91e6bf6",
+		"g81d4fae-7dec-11d0-a765-00a0c91e6bf6",
+		"{" + canon,
+		"f81d4fae7dec11d0a76500a0c91e6bf6aa",
+	} {
+		if u, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q): got %v, wanted error", bad, u)
+		}
+	}
+}
+
+func TestConstraints(t *testing.T) {
+	const (
+		nilUUID = "00000000-0000-0000-0000-000000000000"
+		v4      = "9b2c3e0a-4f1d-4c6b-8a55-3f1e2d7c9b10"
+		msVar   = "9b2c3e0a-4f1d-4c6b-ca55-3f1e2d7c9b10"
+	)
+	tests := []struct {
+		v  Value
+		in string
+		ok bool
+	}{
+		{Value{}, nilUUID, true},
+		{Value{NoNil: true}, nilUUID, false},
+		{Value{Version: 4}, v4, true},
+		{Value{Version: 1}, v4, false},
+		{Value{Version: 4}, msVar, false},
+		{Value{RequireRFC: true}, msVar, false},
+		{Value{RequireRFC: true}, v4, true},
+		{Value{}, msVar, true},
+	}
+	for _, test := range tests {
+		err := test.v.Set(test.in)
+		if test.ok && err != nil {
+			t.Errorf("Set(%q) with %+v: unexpected error: %v", test.in, test.v, err)
+		} else if !test.ok && err == nil {
+			t.Errorf("Set(%q) with %+v: got %v, wanted error", test.in, test.v, test.v.UUID)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package goflags
+
+import "github.com/creachadair/goflags/funcflag"
+
+// NewValue returns a flag value of type T with the given default, which
+// parses its arguments with parse and formats its value with format, as a
+// convenience for one-off flag types:
+//
+//	var port = goflags.NewValue(8080, strconv.Atoi, strconv.Itoa)
+//	func init() {
+//	  flag.Var(port, "port", "Service port")
+//	}
+//
+// The value satisfies the flag.Value and flag.Getter interfaces, and its
+// WasSet method reports whether it was set. If format is nil, the value is
+// formatted as described by funcflag.Value.String.
+func NewValue[T any](def T, parse func(string) (T, error), format func(T) string) *funcflag.Value[T] {
+	return funcflag.New(parse).WithDefault(def).WithFormat(format)
+}
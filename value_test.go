@@ -0,0 +1,42 @@
+package goflags
+
+import (
+	"flag"
+	"io"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNewValue(t *testing.T) {
+	port := NewValue(8080, strconv.Atoi, strconv.Itoa)
+	addr := NewValue(netip.MustParseAddr("::1"), netip.ParseAddr, netip.Addr.String)
+
+	var buf strings.Builder
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&buf)
+	fs.Var(port, "port", "Service port")
+	fs.Var(addr, "addr", "Service address")
+	fs.PrintDefaults()
+	if got := buf.String(); !strings.Contains(got, "(default 8080)") || !strings.Contains(got, "(default ::1)") {
+		t.Errorf("Usage does not mention defaults:\n%s", got)
+	}
+
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse([]string{"-addr", "10.0.0.1"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if port.WasSet() || port.Get().(int) != 8080 {
+		t.Errorf("Value for -port: got %v, set=%v; want 8080, false", port.Get(), port.WasSet())
+	}
+	if !addr.WasSet() || addr.Value() != netip.MustParseAddr("10.0.0.1") {
+		t.Errorf("Value for -addr: got %v, set=%v; want 10.0.0.1, true", addr.Value(), addr.WasSet())
+	}
+	if err := fs.Parse([]string{"-port", "http"}); err == nil {
+		t.Error("Invalid -port was accepted")
+	}
+	if got, want := Args(fs), []string{"-addr=10.0.0.1"}; strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Args: got %q, want %q", got, want)
+	}
+}
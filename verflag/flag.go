@@ -0,0 +1,156 @@
+// Package verflag defines a flag that prints version information for the
+// running binary and exits.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/verflag"
+//	)
+//
+//	func init() { verflag.Define(flag.CommandLine, "version") }
+//
+// With this definition "-version" prints a summary in text, and
+// "-version=json" prints the same information as a JSON object. The
+// information is read from the build info embedded by the Go toolchain.
+package verflag
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Info is a summary of the build information for a binary.
+type Info struct {
+	Path      string `json:"path,omitempty"`     // main module path
+	Version   string `json:"version,omitempty"`  // main module version, e.g., "v1.2.3" or "(devel)"
+	Revision  string `json:"revision,omitempty"` // VCS revision, if known
+	Time      string `json:"time,omitempty"`     // VCS commit time in RFC 3339 format, if known
+	Modified  bool   `json:"modified,omitempty"` // whether the VCS tree had local changes
+	GoVersion string `json:"goVersion"`          // the Go toolchain version
+}
+
+// ReadInfo returns build information for the running binary. If the binary
+// was built without module support, only GoVersion is populated.
+func ReadInfo() Info {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return Info{GoVersion: runtime.Version()}
+	}
+	info := Info{
+		Path:      bi.Main.Path,
+		Version:   bi.Main.Version,
+		GoVersion: bi.GoVersion,
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Revision = s.Value
+		case "vcs.time":
+			info.Time = s.Value
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+	return info
+}
+
+// String renders the information as human-readable text.
+func (i Info) String() string {
+	var sb strings.Builder
+	name := i.Path
+	if name == "" {
+		name = "(unknown)"
+	}
+	fmt.Fprint(&sb, name)
+	if i.Version != "" {
+		fmt.Fprint(&sb, " ", i.Version)
+	}
+	sb.WriteByte('\n')
+	if i.Revision != "" {
+		fmt.Fprintf(&sb, "  revision: %s", i.Revision)
+		if i.Modified {
+			sb.WriteString(" (modified)")
+		}
+		sb.WriteByte('\n')
+	}
+	if i.Time != "" {
+		fmt.Fprintf(&sb, "  time:     %s\n", i.Time)
+	}
+	fmt.Fprintf(&sb, "  go:       %s\n", i.GoVersion)
+	return sb.String()
+}
+
+// A Value is a flag that prints version information when set. A pointer to a
+// Value satisfies the flag.Value interface. It may be given without an
+// argument to print text, or with the argument "json" or "text" to choose the
+// format. The argument "false" does nothing. Because the flag is a boolean
+// flag, the argument must be joined to it, as in "-version=json"; in
+// "-version json" the flag prints text, and "json" is the next argument.
+type Value struct {
+	// The information to print. If nil, the result of ReadInfo is used.
+	Info *Info
+
+	// Where to print the information. If nil, os.Stdout is used.
+	Output io.Writer
+
+	// The function called after printing. If nil, os.Exit is used.
+	Exit func(code int)
+}
+
+// Define defines a version flag with the given name in fs, and returns it.
+// If fs == nil, flag.CommandLine is used.
+func Define(fs *flag.FlagSet, name string) *Value {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	v := new(Value)
+	fs.Var(v, name, fmt.Sprintf("Print version information and exit (-%s=json for JSON)", name))
+	return v
+}
+
+// IsBoolFlag reports true, so that the flag may be given without an argument.
+func (v *Value) IsBoolFlag() bool { return true }
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return "false" }
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	info := v.Info
+	if info == nil {
+		ri := ReadInfo()
+		info = &ri
+	}
+	out := v.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	switch strings.ToLower(s) {
+	case "false":
+		return nil
+	case "true", "text":
+		fmt.Fprint(out, info.String())
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(info); err != nil {
+			return fmt.Errorf("verflag: %w", err)
+		}
+	default:
+		return fmt.Errorf("verflag: invalid format %q, want text or json", s)
+	}
+	exit := v.Exit
+	if exit == nil {
+		exit = os.Exit
+	}
+	exit(0)
+	return nil
+}
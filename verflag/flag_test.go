@@ -0,0 +1,95 @@
+package verflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	info := &Info{
+		Path:      "example.com/tool",
+		Version:   "v1.2.3",
+		Revision:  "abc123",
+		Time:      "2024-01-02T03:04:05Z",
+		Modified:  true,
+		GoVersion: "go1.23.0",
+	}
+
+	var buf bytes.Buffer
+	exited := -1
+	fs := flag.NewFlagSet("ver", flag.ContinueOnError)
+	v := Define(fs, "version")
+	v.Info, v.Output, v.Exit = info, &buf, func(code int) { exited = code }
+
+	if err := fs.Parse([]string{"-version=false"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	} else if exited >= 0 || buf.Len() != 0 {
+		t.Errorf("-version=false: exited %d, printed %q", exited, buf.String())
+	}
+
+	if err := fs.Parse([]string{"-version"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if exited != 0 {
+		t.Errorf("Exit code: got %d, want 0", exited)
+	}
+	want := `example.com/tool v1.2.3
+  revision: abc123 (modified)
+  time:     2024-01-02T03:04:05Z
+  go:       go1.23.0
+`
+	if got := buf.String(); got != want {
+		t.Errorf("Text output: got\n%s\nwant\n%s", got, want)
+	}
+
+	buf.Reset()
+	if err := fs.Parse([]string{"-version=json"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	var got Info
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Invalid JSON output %q: %v", buf.String(), err)
+	}
+	if got != *info {
+		t.Errorf("JSON output: got %+v, want %+v", got, *info)
+	}
+
+	if err := fs.Parse([]string{"-version=yaml"}); err == nil {
+		t.Error("Invalid format was accepted")
+	}
+}
+
+func TestSeparateArg(t *testing.T) {
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("ver", flag.ContinueOnError)
+	v := Define(fs, "version")
+	v.Info, v.Output, v.Exit = &Info{Path: "example.com/tool"}, &buf, func(int) {}
+
+	// A separate argument is not the format, since the flag is boolean.
+	if err := fs.Parse([]string{"-version", "json"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got := buf.String(); strings.HasPrefix(got, "{") || !strings.Contains(got, "example.com/tool") {
+		t.Errorf("Output: got %q, want text", got)
+	}
+	if got := fs.Args(); len(got) != 1 || got[0] != "json" {
+		t.Errorf("Args: got %q, want [json]", got)
+	}
+	if usage := fs.Lookup("version").Usage; !strings.Contains(usage, "-version=json") {
+		t.Errorf("Usage: got %q, want it to mention -version=json", usage)
+	}
+}
+
+func TestReadInfo(t *testing.T) {
+	info := ReadInfo()
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("GoVersion: got %q, want %q", info.GoVersion, runtime.Version())
+	}
+	if s := info.String(); !strings.Contains(s, info.GoVersion) {
+		t.Errorf("String does not mention Go version: %q", s)
+	}
+}
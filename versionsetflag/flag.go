@@ -0,0 +1,203 @@
+// Package versionsetflag defines a repeatable flag.Value implementation that
+// collects Go module path and version pairs.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/versionsetflag"
+//	)
+//
+//	var pins versionsetflag.Value
+//	func init() {
+//	  flag.Var(&pins, "pin", pins.Help("Pin a dependency version"))
+//	}
+//
+// With this definition "-pin golang.org/x/text@v0.14.0" adds one pin. Several
+// pins may also be given in one argument, separated by commas. The module
+// path is checked following the rules of the go command, and the version
+// must be a canonical semantic version with a "v" prefix whose major version
+// agrees with the path, as "example.com/mod/v2@v2.1.0".
+package versionsetflag
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/creachadair/goflags/semverflag"
+)
+
+// A Module is a module path and version.
+type Module struct {
+	Path    string // e.g., "golang.org/x/text"
+	Version string // e.g., "v0.14.0"
+}
+
+// String returns m in the format "path@version".
+func (m Module) String() string { return m.Path + "@" + m.Version }
+
+// Parse parses and checks a module in the format "path@version".
+func Parse(s string) (Module, error) {
+	path, version, ok := strings.Cut(strings.TrimSpace(s), "@")
+	if !ok {
+		return Module{}, fmt.Errorf("versionsetflag: invalid module %q, want path@version", s)
+	}
+	if err := CheckPath(path); err != nil {
+		return Module{}, err
+	}
+	if err := checkVersion(path, version); err != nil {
+		return Module{}, err
+	}
+	return Module{Path: path, Version: version}, nil
+}
+
+// CheckPath reports an error if path is not a valid module path. A valid
+// path consists of slash-separated elements of ASCII letters, digits, and
+// the punctuation "-._~", none of which is empty or begins or ends with a
+// dot. The first element must be a lower-case domain name containing a dot
+// and not beginning with "-".
+func CheckPath(path string) error {
+	if path == "" {
+		return errors.New("versionsetflag: empty module path")
+	}
+	elems := strings.Split(path, "/")
+	for i, elem := range elems {
+		if elem == "" {
+			return fmt.Errorf("versionsetflag: module path %q has an empty element", path)
+		}
+		if elem[0] == '.' || elem[len(elem)-1] == '.' {
+			return fmt.Errorf("versionsetflag: element %q of %q begins or ends with a dot", elem, path)
+		}
+		for j := 0; j < len(elem); j++ {
+			c := elem[j]
+			switch {
+			case 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '.':
+			case 'A' <= c && c <= 'Z', c == '_', c == '~':
+				if i == 0 {
+					return fmt.Errorf("versionsetflag: invalid character %q in domain of %q", c, path)
+				}
+			default:
+				return fmt.Errorf("versionsetflag: invalid character %q in module path %q", c, path)
+			}
+		}
+	}
+	if first := elems[0]; !strings.Contains(first, ".") || first[0] == '-' {
+		return fmt.Errorf("versionsetflag: module path %q must begin with a domain name", path)
+	}
+	return nil
+}
+
+// pathMajor returns the major version suffix of path, such as 2 for
+// "example.com/mod/v2", or 0 if there is none.
+func pathMajor(path string) int {
+	i := strings.LastIndexByte(path, '/')
+	if i < 0 {
+		return 0
+	}
+	suffix, ok := strings.CutPrefix(path[i+1:], "v")
+	if !ok || suffix == "" || suffix[0] == '0' {
+		return 0
+	}
+	n, err := strconv.Atoi(suffix)
+	if err != nil || n < 2 {
+		return 0
+	}
+	return n
+}
+
+func checkVersion(path, version string) error {
+	if !strings.HasPrefix(version, "v") {
+		return fmt.Errorf("versionsetflag: version %q must begin with \"v\"", version)
+	}
+	base, incompat := strings.CutSuffix(version, "+incompatible")
+	v, err := semverflag.Parse(base)
+	if err != nil {
+		return fmt.Errorf("versionsetflag: invalid version %q", version)
+	}
+	if "v"+v.String() != base {
+		return fmt.Errorf("versionsetflag: version %q is not canonical", version)
+	}
+	if len(v.Build) != 0 {
+		return fmt.Errorf("versionsetflag: version %q has build metadata", version)
+	}
+	major := pathMajor(path)
+	switch {
+	case incompat && (major != 0 || v.Major < 2):
+		return fmt.Errorf("versionsetflag: invalid +incompatible version %q for %q", version, path)
+	case incompat:
+		return nil
+	case major == 0 && v.Major >= 2:
+		return fmt.Errorf("versionsetflag: version %q requires a /v%d suffix on %q", version, v.Major, path)
+	case major != 0 && v.Major != major:
+		return fmt.Errorf("versionsetflag: version %q does not match major version of %q", version, path)
+	}
+	return nil
+}
+
+// A Value represents a list of modules. A pointer to a Value satisfies the
+// flag.Value and flag.Getter interfaces. Each module path may occur at most
+// once.
+type Value struct {
+	// The modules parsed from the flag, in order of occurrence.
+	Modules []Module
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string { return h + " (path@version; repeatable)" }
+
+// Lookup returns the version given for the module path, and reports whether
+// it was found.
+func (v *Value) Lookup(path string) (string, bool) {
+	for _, m := range v.Modules {
+		if m.Path == path {
+			return m.Version, true
+		}
+	}
+	return "", false
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string {
+	parts := make([]string, len(v.Modules))
+	for i, m := range v.Modules {
+		parts[i] = m.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	var add []Module
+	for _, elt := range strings.Split(s, ",") {
+		m, err := Parse(elt)
+		if err != nil {
+			return err
+		}
+		if _, dup := v.Lookup(m.Path); dup || containsPath(add, m.Path) {
+			return fmt.Errorf("versionsetflag: duplicate module %q", m.Path)
+		}
+		add = append(add, m)
+	}
+	v.Modules = append(v.Modules, add...)
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type []Module.
+func (v *Value) Get() any { return v.Modules }
+
+func containsPath(ms []Module, path string) bool {
+	for _, m := range ms {
+		if m.Path == path {
+			return true
+		}
+	}
+	return false
+}
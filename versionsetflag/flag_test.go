@@ -0,0 +1,74 @@
+package versionsetflag
+
+import (
+	"flag"
+	"slices"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var pins Value
+	fs := flag.NewFlagSet("versionset", flag.ContinueOnError)
+	fs.Var(&pins, "pin", pins.Help("Pin"))
+
+	if err := fs.Parse([]string{
+		"-pin", "golang.org/x/text@v0.14.0",
+		"-pin", "example.com/mod/v2@v2.1.0, github.com/Foo/bar@v1.0.0-rc.1",
+	}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	want := []Module{
+		{"golang.org/x/text", "v0.14.0"},
+		{"example.com/mod/v2", "v2.1.0"},
+		{"github.com/Foo/bar", "v1.0.0-rc.1"},
+	}
+	if got := pins.Get().([]Module); !slices.Equal(got, want) {
+		t.Errorf("Value for -pin: got %v, want %v", got, want)
+	}
+	if v, ok := pins.Lookup("example.com/mod/v2"); !ok || v != "v2.1.0" {
+		t.Errorf("Lookup: got %q, %v; want v2.1.0, true", v, ok)
+	}
+	if err := fs.Parse([]string{"-pin", "golang.org/x/text@v0.15.0"}); err == nil {
+		t.Error("Duplicate module was accepted")
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, good := range []string{
+		"example.com/m@v0.0.0-20240102030405-abcdefabcdef",
+		"example.com/m@v3.0.0+incompatible",
+		"gopkg.example/x.y~z_w/sub@v1.2.3",
+		"example.com/m/v10@v10.0.1",
+	} {
+		if _, err := Parse(good); err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", good, err)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, bad := range []string{
+		"",
+		"example.com/m",
+		"example.com/m@",
+		"example.com/m@1.2.3",
+		"example.com/m@v1.2",
+		"example.com/m@v01.2.3",
+		"example.com/m@v1.2.3+build",
+		"example.com/m@v2.0.0",
+		"example.com/m/v2@v1.0.0",
+		"example.com/m/v2@v2.0.0+incompatible",
+		"example.com/m@v1.0.0+incompatible",
+		"noDomain/m@v1.0.0",
+		"localhost/m@v1.0.0",
+		"-example.com/m@v1.0.0",
+		"example.com//m@v1.0.0",
+		"example.com/.m@v1.0.0",
+		"example.com/m m@v1.0.0",
+		"Example.com/m@v1.0.0",
+	} {
+		if m, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q): got %v, wanted error", bad, m)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Package weightflag defines a flag.Value implementation that parses a list
+// of weighted names, for traffic splitting and sampling.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//	  "math/rand/v2"
+//
+//	  "github.com/creachadair/goflags/weightflag"
+//	)
+//
+//	var backends weightflag.Value
+//	func init() {
+//	  flag.Var(&backends, "split", backends.Help("Traffic split"))
+//	}
+//
+//	  ...
+//	  target := backends.Pick(rand.New(rand.NewPCG(1, 2)))
+//
+// With this definition "-split a:3,b:1,c:1" directs three fifths of the
+// picks to "a". A name without a weight has weight 1.
+package weightflag
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// An Item is a name with its weight.
+type Item struct {
+	Name   string
+	Weight float64
+}
+
+// A Value represents an ordered list of weighted names. A pointer to a Value
+// satisfies the flag.Value and flag.Getter interfaces. Weights must be
+// non-negative, and at least one must be positive.
+type Value struct {
+	// The items parsed from the flag, in order of occurrence.
+	Items []Item
+}
+
+// Help concatenates a human-readable string summarizing the format of v to h,
+// for use in generating a documentation string.
+func (v *Value) Help(h string) string { return h + " (name:weight,...)" }
+
+// Total returns the sum of the weights.
+func (v *Value) Total() float64 {
+	var sum float64
+	for _, item := range v.Items {
+		sum += item.Weight
+	}
+	return sum
+}
+
+// Normalized returns a copy of the items with weights scaled so that they sum
+// to 1. It returns nil if the total weight is zero.
+func (v *Value) Normalized() []Item {
+	total := v.Total()
+	if total == 0 {
+		return nil
+	}
+	out := make([]Item, len(v.Items))
+	for i, item := range v.Items {
+		out[i] = Item{Name: item.Name, Weight: item.Weight / total}
+	}
+	return out
+}
+
+// Weight returns the weight of the named item, or 0 if it is not present.
+func (v *Value) Weight(name string) float64 {
+	for _, item := range v.Items {
+		if item.Name == name {
+			return item.Weight
+		}
+	}
+	return 0
+}
+
+// Pick returns the name of an item chosen at random using r, with
+// probability proportional to its weight. It returns "" if the total weight
+// is zero. If r == nil, the global source of math/rand/v2 is used.
+func (v *Value) Pick(r *rand.Rand) string {
+	total := v.Total()
+	if total <= 0 {
+		return ""
+	}
+	var f float64
+	if r == nil {
+		f = rand.Float64()
+	} else {
+		f = r.Float64()
+	}
+	x := f * total
+	last := ""
+	for _, item := range v.Items {
+		if item.Weight == 0 {
+			continue
+		}
+		if x < item.Weight {
+			return item.Name
+		}
+		x -= item.Weight
+		last = item.Name
+	}
+	return last // rounding error; choose the last positive item
+}
+
+// String satisfies part of the flag.Value interface.
+func (v *Value) String() string { return fmt.Sprintf("%q", v.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (v *Value) ArgString() string {
+	parts := make([]string, len(v.Items))
+	for i, item := range v.Items {
+		parts[i] = item.Name + ":" + strconv.FormatFloat(item.Weight, 'g', -1, 64)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set satisfies part of the flag.Value interface.
+func (v *Value) Set(s string) error {
+	if s == "" {
+		return errors.New("weightflag: empty list")
+	}
+	var items []Item
+	var total float64
+	seen := make(map[string]bool)
+	for _, elt := range strings.Split(s, ",") {
+		name, ws, ok := strings.Cut(strings.TrimSpace(elt), ":")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return fmt.Errorf("weightflag: missing name in %q", elt)
+		} else if seen[name] {
+			return fmt.Errorf("weightflag: duplicate name %q", name)
+		}
+		seen[name] = true
+
+		w := 1.0
+		if ok {
+			f, err := strconv.ParseFloat(strings.TrimSpace(ws), 64)
+			if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+				return fmt.Errorf("weightflag: invalid weight %q for %q", ws, name)
+			} else if f < 0 {
+				return fmt.Errorf("weightflag: negative weight for %q", name)
+			}
+			w = f
+		}
+		items = append(items, Item{Name: name, Weight: w})
+		total += w
+	}
+	if total == 0 {
+		return errors.New("weightflag: total weight is zero")
+	}
+	v.Items = items
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type []Item.
+func (v *Value) Get() any { return v.Items }
@@ -0,0 +1,67 @@
+package weightflag
+
+import (
+	"flag"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var split Value
+	fs := flag.NewFlagSet("weight", flag.ContinueOnError)
+	fs.Var(&split, "split", split.Help("Traffic split"))
+
+	if err := fs.Parse([]string{"-split", "a:3, b:1,c,d:0"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+
+	want := []Item{{"a", 3}, {"b", 1}, {"c", 1}, {"d", 0}}
+	if got := split.Get().([]Item); !slices.Equal(got, want) {
+		t.Errorf("Value for -split: got %v, want %v", got, want)
+	}
+	if got, want := split.String(), `"a:3,b:1,c:1,d:0"`; got != want {
+		t.Errorf("String: got %s, want %s", got, want)
+	}
+	if got := split.Total(); got != 5 {
+		t.Errorf("Total: got %v, want 5", got)
+	}
+	norm := []Item{{"a", 0.6}, {"b", 0.2}, {"c", 0.2}, {"d", 0}}
+	if got := split.Normalized(); !slices.Equal(got, norm) {
+		t.Errorf("Normalized: got %v, want %v", got, norm)
+	}
+	if got := split.Weight("b"); got != 1 {
+		t.Errorf("Weight(b): got %v, want 1", got)
+	}
+}
+
+func TestPick(t *testing.T) {
+	v := Value{Items: []Item{{"a", 3}, {"b", 1}, {"never", 0}}}
+	r := rand.New(rand.NewPCG(1, 2))
+
+	const n = 10000
+	counts := make(map[string]int)
+	for range n {
+		counts[v.Pick(r)]++
+	}
+	if counts["never"] != 0 {
+		t.Errorf("Picked zero-weight item %d times", counts["never"])
+	}
+	if frac := float64(counts["a"]) / n; frac < 0.7 || frac > 0.8 {
+		t.Errorf("Fraction of a: got %.3f, want about 0.75", frac)
+	}
+
+	var empty Value
+	if got := empty.Pick(nil); got != "" {
+		t.Errorf("Pick on empty value: got %q, want empty", got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, bad := range []string{"", "a:1,", ":2", "a:x", "a:-1", "a:1,a:2", "a:0,b:0", "a:NaN", "a:Inf"} {
+		var v Value
+		if err := v.Set(bad); err == nil {
+			t.Errorf("Set(%q): got %v, wanted error", bad, v.Items)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Package zoneflag defines flag.Value implementations for DNS domain names
+// and resource record types.
+//
+// Example:
+//
+//	import (
+//	  "flag"
+//
+//	  "github.com/creachadair/goflags/zoneflag"
+//	)
+//
+//	var (
+//	  name  zoneflag.Name
+//	  rtype = zoneflag.A
+//	)
+//	func init() {
+//	  flag.Var(&name, "name", name.Help("Name to query"))
+//	  flag.Var(&rtype, "type", rtype.Help("Record type"))
+//	}
+//
+// Names are normalized to lower case and fully qualified, with a trailing
+// dot, so "-name WWW.Example.com" and "-name www.example.com." both give
+// "www.example.com.".
+package zoneflag
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A Name represents a fully-qualified DNS domain name. A pointer to a Name
+// satisfies the flag.Value and flag.Getter interfaces.
+type Name struct {
+	// The normalized name parsed from the flag, e.g., "www.example.com.".
+	Name string
+
+	// If true, each label must follow the hostname rules of RFC 1123:
+	// letters, digits, and "-", not beginning or ending with "-". Otherwise
+	// "_" is also allowed, as used in names like "_dmarc.example.com".
+	Hostname bool
+
+	// If true, the first label may be "*".
+	AllowWildcard bool
+}
+
+// Help concatenates a human-readable string summarizing the format of n to h,
+// for use in generating a documentation string.
+func (n *Name) Help(h string) string { return h + " (DNS name)" }
+
+// String satisfies part of the flag.Value interface.
+func (n *Name) String() string { return fmt.Sprintf("%q", n.ArgString()) }
+
+// ArgString satisfies the goflags.ArgStringer interface.
+func (n *Name) ArgString() string { return n.Name }
+
+// Set satisfies part of the flag.Value interface.
+func (n *Name) Set(s string) error {
+	name, err := ParseName(s)
+	if err != nil {
+		return err
+	}
+	if name != "." {
+		for i, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+			if label == "*" {
+				if i != 0 || !n.AllowWildcard {
+					return fmt.Errorf("zoneflag: wildcard not allowed in %q", s)
+				}
+				continue
+			}
+			if n.Hostname && !isHostLabel(label) {
+				return fmt.Errorf("zoneflag: invalid hostname label %q in %q", label, s)
+			}
+		}
+	}
+	n.Name = name
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value is the string of the normalized name.
+func (n *Name) Get() any { return n.Name }
+
+// Maximum lengths, per RFC 1035.
+const (
+	maxLabel = 63
+	maxName  = 254 // including the trailing dot
+)
+
+// ParseName checks that s is a syntactically valid domain name, and returns
+// it in lower case with a trailing dot. The root is written ".". Labels may
+// contain letters, digits, "-", "_", and "*".
+func ParseName(s string) (string, error) {
+	if s == "" {
+		return "", errors.New("zoneflag: empty name")
+	} else if s == "." {
+		return s, nil
+	}
+	name := strings.ToLower(strings.TrimSuffix(s, ".")) + "."
+	if len(name) > maxName {
+		return "", fmt.Errorf("zoneflag: name %q is longer than %d bytes", s, maxName-1)
+	}
+	for _, label := range strings.Split(name[:len(name)-1], ".") {
+		if label == "" {
+			return "", fmt.Errorf("zoneflag: empty label in %q", s)
+		} else if len(label) > maxLabel {
+			return "", fmt.Errorf("zoneflag: label %q is longer than %d bytes", label, maxLabel)
+		}
+		for i := 0; i < len(label); i++ {
+			if c := label[i]; !isLDH(c) && c != '_' && c != '*' {
+				return "", fmt.Errorf("zoneflag: invalid character %q in %q", c, s)
+			}
+		}
+		if label != "*" && strings.Contains(label, "*") {
+			return "", fmt.Errorf("zoneflag: invalid wildcard label %q", label)
+		}
+	}
+	return name, nil
+}
+
+func isLDH(c byte) bool {
+	return 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-'
+}
+
+func isHostLabel(s string) bool {
+	if s[0] == '-' || s[len(s)-1] == '-' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isLDH(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// A Type is a DNS resource record type. A pointer to a Type satisfies the
+// flag.Value and flag.Getter interfaces.
+//
+// The flag accepts the mnemonic of a known type without regard to case, or the
+// generic "TYPEnnn" syntax of RFC 3597 for any type.
+type Type uint16
+
+// Common record types.
+const (
+	A      Type = 1
+	NS     Type = 2
+	CNAME  Type = 5
+	SOA    Type = 6
+	PTR    Type = 12
+	MX     Type = 15
+	TXT    Type = 16
+	AAAA   Type = 28
+	SRV    Type = 33
+	NAPTR  Type = 35
+	DS     Type = 43
+	SSHFP  Type = 44
+	RRSIG  Type = 46
+	NSEC   Type = 47
+	DNSKEY Type = 48
+	TLSA   Type = 52
+	SVCB   Type = 64
+	HTTPS  Type = 65
+	ANY    Type = 255
+	CAA    Type = 257
+)
+
+var typeNames = map[Type]string{
+	A: "A", NS: "NS", CNAME: "CNAME", SOA: "SOA", PTR: "PTR", MX: "MX",
+	TXT: "TXT", AAAA: "AAAA", SRV: "SRV", NAPTR: "NAPTR", DS: "DS",
+	SSHFP: "SSHFP", RRSIG: "RRSIG", NSEC: "NSEC", DNSKEY: "DNSKEY",
+	TLSA: "TLSA", SVCB: "SVCB", HTTPS: "HTTPS", ANY: "ANY", CAA: "CAA",
+}
+
+var typeCodes = make(map[string]Type)
+
+func init() {
+	for t, name := range typeNames {
+		typeCodes[name] = t
+	}
+}
+
+// Types returns the known record types, in increasing order of code.
+func Types() []Type { return slices.Sorted(maps.Keys(typeNames)) }
+
+// ParseType parses the name of a record type.
+func ParseType(s string) (Type, error) {
+	u := strings.ToUpper(strings.TrimSpace(s))
+	if t, ok := typeCodes[u]; ok {
+		return t, nil
+	}
+	if rest, ok := strings.CutPrefix(u, "TYPE"); ok {
+		if n, err := strconv.ParseUint(rest, 10, 16); err == nil {
+			return Type(n), nil
+		}
+	}
+	return 0, fmt.Errorf("zoneflag: unknown record type %q", s)
+}
+
+// Help concatenates a human-readable string summarizing the legal values of t
+// to h, for use in generating a documentation string.
+func (t Type) Help(h string) string { return h + " (record type, e.g., A|AAAA|MX|TXT)" }
+
+// String satisfies part of the flag.Value interface. Unknown types are
+// rendered in the "TYPEnnn" syntax.
+func (t Type) String() string {
+	if name, ok := typeNames[t]; ok {
+		return name
+	}
+	return "TYPE" + strconv.Itoa(int(t))
+}
+
+// Set satisfies part of the flag.Value interface.
+func (t *Type) Set(s string) error {
+	v, err := ParseType(s)
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// Get satisfies the flag.Getter interface.
+// The concrete value has type Type.
+func (t Type) Get() any { return t }
@@ -0,0 +1,111 @@
+package zoneflag
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestFlagBits(t *testing.T) {
+	var name Name
+	rtype := A
+	fs := flag.NewFlagSet("zone", flag.ContinueOnError)
+	fs.Var(&name, "name", name.Help("Name"))
+	fs.Var(&rtype, "type", rtype.Help("Type"))
+
+	if got, want := rtype.String(), "A"; got != want {
+		t.Errorf("Initial -type: got %q, want %q", got, want)
+	}
+	if err := fs.Parse([]string{"-name", "WWW.Example.com", "-type", "aaaa"}); err != nil {
+		t.Fatalf("Argument parsing failed: %v", err)
+	}
+	if got, want := name.Get().(string), "www.example.com."; got != want {
+		t.Errorf("Value for -name: got %q, want %q", got, want)
+	}
+	if got, want := rtype.Get().(Type), AAAA; got != want {
+		t.Errorf("Value for -type: got %v, want %v", got, want)
+	}
+}
+
+func TestParseName(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{".", "."},
+		{"com", "com."},
+		{"example.com.", "example.com."},
+		{"_dmarc.Example.COM", "_dmarc.example.com."},
+		{"*.example.com", "*.example.com."},
+		{"xn--bcher-kva.example", "xn--bcher-kva.example."},
+	}
+	for _, test := range tests {
+		got, err := ParseName(test.input)
+		if err != nil {
+			t.Errorf("ParseName(%q): unexpected error: %v", test.input, err)
+		} else if got != test.want {
+			t.Errorf("ParseName(%q): got %q, want %q", test.input, got, test.want)
+		}
+	}
+}
+
+func TestNameErrors(t *testing.T) {
+	tests := []struct {
+		n     Name
+		input string
+	}{
+		{Name{}, ""},
+		{Name{}, ".."},
+		{Name{}, "a..b"},
+		{Name{}, ".example.com"},
+		{Name{}, "ex ample.com"},
+		{Name{}, "bücher.example"},
+		{Name{}, strings.Repeat("x", 64) + ".com"},
+		{Name{}, strings.Repeat("abcdefg.", 32) + "com"},
+		{Name{}, "a*.example.com"},
+		{Name{}, "*.example.com"},
+		{Name{AllowWildcard: true}, "www.*.example.com"},
+		{Name{Hostname: true}, "_sip.example.com"},
+		{Name{Hostname: true}, "-bad.example.com"},
+	}
+	for _, test := range tests {
+		if err := test.n.Set(test.input); err == nil {
+			t.Errorf("Set(%q) with %+v: got %q, wanted error", test.input, test.n, test.n.Name)
+		}
+	}
+	ok := Name{AllowWildcard: true, Hostname: true}
+	if err := ok.Set("*.example.com"); err != nil {
+		t.Errorf("Set wildcard: unexpected error: %v", err)
+	}
+}
+
+func TestType(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Type
+		str   string
+	}{
+		{"a", A, "A"},
+		{"Txt", TXT, "TXT"},
+		{"CAA", CAA, "CAA"},
+		{"TYPE65", HTTPS, "HTTPS"},
+		{"type999", Type(999), "TYPE999"},
+	}
+	for _, test := range tests {
+		got, err := ParseType(test.input)
+		if err != nil {
+			t.Errorf("ParseType(%q): unexpected error: %v", test.input, err)
+			continue
+		}
+		if got != test.want || got.String() != test.str {
+			t.Errorf("ParseType(%q): got %v (%d), want %v", test.input, got, got, test.str)
+		}
+	}
+	for _, bad := range []string{"", "AX", "TYPE", "TYPE70000", "TYPE-1"} {
+		if got, err := ParseType(bad); err == nil {
+			t.Errorf("ParseType(%q): got %v, wanted error", bad, got)
+		}
+	}
+	if types := Types(); len(types) != len(typeNames) || types[0] != A {
+		t.Errorf("Types: got %v", types)
+	}
+}